@@ -25,32 +25,46 @@ import (
 
 // HandlerDependencies contains all dependencies needed by handlers
 type HandlerDependencies struct {
-	OpenAiClient                      *clients.OpenAiClient
-	AppConfig                         *config.Config
-	ProfileService                    *services.ProfileService
-	SummarizationService              *services.SummarizationService
-	RandomCoffeeService               *services.RandomCoffeeService
-	MessageSenderService              *services.MessageSenderService
-	PermissionsService                *services.PermissionsService
-	EventRepository                   *repositories.EventRepository
-	TopicRepository                   *repositories.TopicRepository
-	GroupTopicRepository              *repositories.GroupTopicRepository
-	PromptingTemplateRepository       *repositories.PromptingTemplateRepository
-	UserRepository                    *repositories.UserRepository
-	ProfileRepository                 *repositories.ProfileRepository
-	RandomCoffeePollRepository        *repositories.RandomCoffeePollRepository
-	RandomCoffeeParticipantRepository *repositories.RandomCoffeeParticipantRepository
-	RandomCoffeePairRepository        *repositories.RandomCoffeePairRepository
-	GroupMessageRepository            *repositories.GroupMessageRepository
-	RandomCoffeePollAnswersService    *grouphandlersservices.RandomCoffeePollAnswersService
-	JoinLeftService                   *grouphandlersservices.JoinLeftService
-	CleanClosedThreadsService         *grouphandlersservices.CleanClosedThreadsService
-	RepliesFromClosedThreadsService   *grouphandlersservices.RepliesFromClosedThreadsService
-	DeleteJoinLeftMessagesService     *grouphandlersservices.DeleteJoinLeftMessagesService
-	SaveTopicService                  *grouphandlersservices.SaveTopicService
-	AdminSaveMessageService           *grouphandlersservices.AdminSaveMessageService
-	SaveMessageService                *grouphandlersservices.SaveMessageService
-	SaveUpdateMessageService          *grouphandlersservices.SaveUpdateMessageService
+	OpenAiClient                         *clients.OpenAiClient
+	AppConfig                            *config.Config
+	ProfileService                       *services.ProfileService
+	SummarizationService                 *services.SummarizationService
+	RandomCoffeeService                  *services.RandomCoffeeService
+	RandomCoffeeFeedbackService          *services.RandomCoffeeFeedbackService
+	EventAttendanceService               *services.EventAttendanceService
+	EventCalendarService                 *services.EventCalendarService
+	EventSeriesService                   *services.EventSeriesService
+	EventArchiveService                  *services.EventArchiveService
+	MessageSenderService                 *services.MessageSenderService
+	PermissionsService                   *services.PermissionsService
+	EventRepository                      *repositories.EventRepository
+	EventSubscriptionRepository          *repositories.EventSubscriptionRepository
+	TopicRepository                      *repositories.TopicRepository
+	GroupTopicRepository                 *repositories.GroupTopicRepository
+	PromptingTemplateRepository          *repositories.PromptingTemplateRepository
+	UserRepository                       *repositories.UserRepository
+	ProfileRepository                    *repositories.ProfileRepository
+	RandomCoffeePollRepository           *repositories.RandomCoffeePollRepository
+	RandomCoffeeParticipantRepository    *repositories.RandomCoffeeParticipantRepository
+	RandomCoffeePairRepository           *repositories.RandomCoffeePairRepository
+	RandomCoffeePreferenceRepository     *repositories.RandomCoffeePreferenceRepository
+	RandomCoffeeSubscriptionRepository   *repositories.RandomCoffeeSubscriptionRepository
+	RandomCoffeeExclusionRepository      *repositories.RandomCoffeeExclusionRepository
+	RandomCoffeeStatsRepository          *repositories.RandomCoffeeStatsRepository
+	GroupMessageRepository               *repositories.GroupMessageRepository
+	TopicSummarizationSettingsRepository *repositories.TopicSummarizationSettingsRepository
+	RandomCoffeePollAnswersService       *grouphandlersservices.RandomCoffeePollAnswersService
+	JoinLeftService                      *grouphandlersservices.JoinLeftService
+	CleanClosedThreadsService            *grouphandlersservices.CleanClosedThreadsService
+	RepliesFromClosedThreadsService      *grouphandlersservices.RepliesFromClosedThreadsService
+	DeleteJoinLeftMessagesService        *grouphandlersservices.DeleteJoinLeftMessagesService
+	SaveTopicService                     *grouphandlersservices.SaveTopicService
+	AdminSaveMessageService              *grouphandlersservices.AdminSaveMessageService
+	SaveMessageService                   *grouphandlersservices.SaveMessageService
+	SaveUpdateMessageService             *grouphandlersservices.SaveUpdateMessageService
+	TldrService                          *grouphandlersservices.TldrService
+	Scheduler                            *tasks.Scheduler
+	TaskRunRepository                    *repositories.TaskRunRepository
 }
 
 // TgBotClient represents a Telegram bot client with all required dependencies
@@ -91,6 +105,12 @@ func NewTgBotClient(openaiClient *clients.OpenAiClient, appConfig *config.Config
 
 	// Initialize repositories
 	eventRepository := repositories.NewEventRepository(db.DB)
+	eventSubscriptionRepository := repositories.NewEventSubscriptionRepository(db.DB)
+	eventReminderRepository := repositories.NewEventReminderRepository(db.DB)
+	eventAttendeeRepository := repositories.NewEventAttendeeRepository(db.DB)
+	calendarFeedTokenRepository := repositories.NewCalendarFeedTokenRepository(db.DB)
+	eventSeriesRepository := repositories.NewEventSeriesRepository(db.DB)
+	eventMaterialRepository := repositories.NewEventMaterialRepository(db.DB)
 	topicRepository := repositories.NewTopicRepository(db.DB)
 	groupTopicRepository := repositories.NewGroupTopicRepository(db.DB)
 	promptingTemplateRepository := repositories.NewPromptingTemplateRepository(db.DB)
@@ -99,7 +119,17 @@ func NewTgBotClient(openaiClient *clients.OpenAiClient, appConfig *config.Config
 	randomCoffeePollRepository := repositories.NewRandomCoffeePollRepository(db.DB)
 	randomCoffeeParticipantRepository := repositories.NewRandomCoffeeParticipantRepository(db.DB)
 	randomCoffeePairRepository := repositories.NewRandomCoffeePairRepository(db.DB)
+	randomCoffeePreferenceRepository := repositories.NewRandomCoffeePreferenceRepository(db.DB)
+	randomCoffeeSubscriptionRepository := repositories.NewRandomCoffeeSubscriptionRepository(db.DB)
+	randomCoffeeExclusionRepository := repositories.NewRandomCoffeeExclusionRepository(db.DB)
+	randomCoffeeRematchRepository := repositories.NewRandomCoffeeRematchRepository(db.DB)
+	randomCoffeeFeedbackRepository := repositories.NewRandomCoffeeFeedbackRepository(db.DB)
+	randomCoffeeStatsRepository := repositories.NewRandomCoffeeStatsRepository(db.DB)
 	groupMessageRepository := repositories.NewGroupMessageRepository(db.DB)
+	summaryCacheRepository := repositories.NewSummaryCacheRepository(db.DB)
+	topicSummarizationSettingsRepository := repositories.NewTopicSummarizationSettingsRepository(db.DB)
+	taskRunRepository := repositories.NewTaskRunRepository(db.DB)
+	scheduledJobStateRepository := repositories.NewScheduledJobStateRepository(db.DB)
 
 	// Initialize services
 	messageSenderService := services.NewMessageSenderService(bot)
@@ -117,6 +147,9 @@ func NewTgBotClient(openaiClient *clients.OpenAiClient, appConfig *config.Config
 		groupTopicRepository,
 		promptingTemplateRepository,
 		groupMessageRepository,
+		summaryCacheRepository,
+		topicSummarizationSettingsRepository,
+		userRepository,
 	)
 	randomCoffeeService := services.NewRandomCoffeeService(
 		bot,
@@ -128,6 +161,53 @@ func NewTgBotClient(openaiClient *clients.OpenAiClient, appConfig *config.Config
 		profileRepository,
 		randomCoffeePairRepository,
 		userRepository,
+		randomCoffeePreferenceRepository,
+		randomCoffeeFeedbackRepository,
+		randomCoffeeSubscriptionRepository,
+		randomCoffeeExclusionRepository,
+		randomCoffeeRematchRepository,
+		openaiClient,
+		promptingTemplateRepository,
+	)
+	randomCoffeeFeedbackService := services.NewRandomCoffeeFeedbackService(
+		appConfig,
+		messageSenderService,
+		randomCoffeeFeedbackRepository,
+		randomCoffeePairRepository,
+		userRepository,
+	)
+	eventAttendanceService := services.NewEventAttendanceService(
+		appConfig,
+		messageSenderService,
+		eventRepository,
+		eventAttendeeRepository,
+		userRepository,
+	)
+	eventCalendarService := services.NewEventCalendarService(
+		appConfig,
+		eventRepository,
+		calendarFeedTokenRepository,
+		userRepository,
+	)
+	eventSeriesService := services.NewEventSeriesService(
+		appConfig,
+		eventAttendanceService,
+		eventRepository,
+		eventSeriesRepository,
+	)
+	eventLifecycleService := services.NewEventLifecycleService(eventRepository)
+	eventArchiveService := services.NewEventArchiveService(
+		messageSenderService,
+		eventRepository,
+		eventMaterialRepository,
+	)
+	eventReminderService := services.NewEventReminderService(
+		appConfig,
+		messageSenderService,
+		eventAttendanceService,
+		eventRepository,
+		eventSubscriptionRepository,
+		eventReminderRepository,
 	)
 	randomCoffeePollAnswersService := grouphandlersservices.NewRandomCoffeePollAnswersService(
 		messageSenderService,
@@ -135,8 +215,9 @@ func NewTgBotClient(openaiClient *clients.OpenAiClient, appConfig *config.Config
 		randomCoffeePollRepository,
 		randomCoffeeParticipantRepository,
 		userRepository,
+		randomCoffeePreferenceRepository,
 	)
-	joinLeftService := grouphandlersservices.NewJoinLeftService(userRepository)
+	joinLeftService := grouphandlersservices.NewJoinLeftService(userRepository, randomCoffeeParticipantRepository, randomCoffeeService)
 	cleanClosedThreadsService := grouphandlersservices.NewCleanClosedThreadsService(
 		appConfig,
 		messageSenderService,
@@ -168,12 +249,40 @@ func NewTgBotClient(openaiClient *clients.OpenAiClient, appConfig *config.Config
 		saveUpdateMessageService,
 		appConfig,
 	)
+	tldrService := grouphandlersservices.NewTldrService(
+		appConfig,
+		bot,
+		openaiClient,
+		messageSenderService,
+		groupMessageRepository,
+		promptingTemplateRepository,
+		userRepository,
+	)
 
 	// Initialize scheduled tasks
-	scheduledTasks := []tasks.Task{
-		tasks.NewDailySummarizationTask(appConfig, summarizationService),
-		tasks.NewRandomCoffeePollTask(appConfig, randomCoffeeService),
-		tasks.NewRandomCoffeePairsTask(appConfig, randomCoffeeService),
+	scheduler := tasks.NewScheduler(
+		tasks.NewTaskRunner(taskRunRepository),
+		scheduledJobStateRepository,
+		appConfig.ClubTimezone,
+	)
+	scheduler.RegisterProvider(tasks.NewDailySummarizationJobProvider(appConfig, summarizationService))
+	for _, job := range []tasks.Job{
+		tasks.NewRandomCoffeePollJob(appConfig, randomCoffeeService),
+		tasks.NewRandomCoffeePairsJob(appConfig, randomCoffeeService),
+		tasks.NewRandomCoffeeDraftPublishJob(appConfig, randomCoffeeService),
+		tasks.NewRandomCoffeeFeedbackJob(appConfig, randomCoffeeFeedbackService),
+		tasks.NewEventRemindersJob(appConfig, eventReminderService),
+		tasks.NewEventSeriesJob(appConfig, eventSeriesService),
+		tasks.NewEventStatusJob(appConfig, eventLifecycleService),
+		tasks.NewSummaryCacheCleanupJob(summarizationService),
+	} {
+		if err := scheduler.Register(job); err != nil {
+			return nil, err
+		}
+	}
+	scheduledTasks := []tasks.Task{scheduler}
+	if eventCalendarService.IsFeedEnabled() {
+		scheduledTasks = append(scheduledTasks, services.NewCalendarFeedServer(appConfig.CalendarFeedListenAddr, eventCalendarService))
 	}
 
 	// Create bot client
@@ -187,32 +296,46 @@ func NewTgBotClient(openaiClient *clients.OpenAiClient, appConfig *config.Config
 
 	// Create dependencies container
 	deps := &HandlerDependencies{
-		OpenAiClient:                      openaiClient,
-		AppConfig:                         appConfig,
-		ProfileService:                    profileService,
-		SummarizationService:              summarizationService,
-		RandomCoffeeService:               randomCoffeeService,
-		MessageSenderService:              messageSenderService,
-		PermissionsService:                permissionsService,
-		EventRepository:                   eventRepository,
-		TopicRepository:                   topicRepository,
-		GroupTopicRepository:              groupTopicRepository,
-		PromptingTemplateRepository:       promptingTemplateRepository,
-		UserRepository:                    userRepository,
-		ProfileRepository:                 profileRepository,
-		RandomCoffeePollRepository:        randomCoffeePollRepository,
-		RandomCoffeeParticipantRepository: randomCoffeeParticipantRepository,
-		RandomCoffeePairRepository:        randomCoffeePairRepository,
-		GroupMessageRepository:            groupMessageRepository,
-		RandomCoffeePollAnswersService:    randomCoffeePollAnswersService,
-		JoinLeftService:                   joinLeftService,
-		CleanClosedThreadsService:         cleanClosedThreadsService,
-		RepliesFromClosedThreadsService:   repliesFromClosedThreadsService,
-		DeleteJoinLeftMessagesService:     deleteJoinLeftMessagesService,
-		SaveTopicService:                  saveTopicService,
-		AdminSaveMessageService:           adminSaveMessageService,
-		SaveMessageService:                saveMessageService,
-		SaveUpdateMessageService:          saveUpdateMessageService,
+		OpenAiClient:                         openaiClient,
+		AppConfig:                            appConfig,
+		ProfileService:                       profileService,
+		SummarizationService:                 summarizationService,
+		RandomCoffeeService:                  randomCoffeeService,
+		RandomCoffeeFeedbackService:          randomCoffeeFeedbackService,
+		EventAttendanceService:               eventAttendanceService,
+		EventCalendarService:                 eventCalendarService,
+		EventSeriesService:                   eventSeriesService,
+		EventArchiveService:                  eventArchiveService,
+		MessageSenderService:                 messageSenderService,
+		PermissionsService:                   permissionsService,
+		EventRepository:                      eventRepository,
+		EventSubscriptionRepository:          eventSubscriptionRepository,
+		TopicRepository:                      topicRepository,
+		GroupTopicRepository:                 groupTopicRepository,
+		PromptingTemplateRepository:          promptingTemplateRepository,
+		UserRepository:                       userRepository,
+		ProfileRepository:                    profileRepository,
+		RandomCoffeePollRepository:           randomCoffeePollRepository,
+		RandomCoffeeParticipantRepository:    randomCoffeeParticipantRepository,
+		RandomCoffeePairRepository:           randomCoffeePairRepository,
+		RandomCoffeePreferenceRepository:     randomCoffeePreferenceRepository,
+		RandomCoffeeSubscriptionRepository:   randomCoffeeSubscriptionRepository,
+		RandomCoffeeExclusionRepository:      randomCoffeeExclusionRepository,
+		RandomCoffeeStatsRepository:          randomCoffeeStatsRepository,
+		GroupMessageRepository:               groupMessageRepository,
+		TopicSummarizationSettingsRepository: topicSummarizationSettingsRepository,
+		RandomCoffeePollAnswersService:       randomCoffeePollAnswersService,
+		JoinLeftService:                      joinLeftService,
+		CleanClosedThreadsService:            cleanClosedThreadsService,
+		RepliesFromClosedThreadsService:      repliesFromClosedThreadsService,
+		DeleteJoinLeftMessagesService:        deleteJoinLeftMessagesService,
+		SaveTopicService:                     saveTopicService,
+		AdminSaveMessageService:              adminSaveMessageService,
+		SaveMessageService:                   saveMessageService,
+		SaveUpdateMessageService:             saveUpdateMessageService,
+		TldrService:                          tldrService,
+		Scheduler:                            scheduler,
+		TaskRunRepository:                    taskRunRepository,
 	}
 
 	// Register all handlers
@@ -251,19 +374,32 @@ func (b *TgBotClient) registerHandlers(deps *HandlerDependencies) {
 		eventhandlers.NewEventEditHandler(
 			deps.AppConfig,
 			deps.EventRepository,
+			deps.UserRepository,
 			deps.MessageSenderService,
+			deps.EventAttendanceService,
+			deps.EventSeriesService,
 			deps.PermissionsService,
 		),
 		eventhandlers.NewEventSetupHandler(
 			deps.AppConfig,
 			deps.EventRepository,
+			deps.UserRepository,
 			deps.MessageSenderService,
+			deps.EventSeriesService,
 			deps.PermissionsService,
 		),
 		eventhandlers.NewEventStartHandler(
 			deps.AppConfig,
 			deps.EventRepository,
 			deps.MessageSenderService,
+			deps.EventAttendanceService,
+			deps.PermissionsService,
+		),
+		eventhandlers.NewEventMaterialsHandler(
+			deps.AppConfig,
+			deps.EventRepository,
+			deps.EventArchiveService,
+			deps.MessageSenderService,
 			deps.PermissionsService,
 		),
 
@@ -308,6 +444,30 @@ func (b *TgBotClient) registerHandlers(deps *HandlerDependencies) {
 			deps.MessageSenderService,
 			deps.PermissionsService,
 		),
+		adminhandlers.NewSummarySettingsHandler(
+			deps.AppConfig,
+			deps.MessageSenderService,
+			deps.PermissionsService,
+			deps.SummarizationService,
+			deps.TopicSummarizationSettingsRepository,
+		),
+		adminhandlers.NewTasksHandler(
+			deps.AppConfig,
+			deps.MessageSenderService,
+			deps.PermissionsService,
+			deps.Scheduler,
+			deps.TaskRunRepository,
+		),
+		adminhandlers.NewCoffeeStatsHandler(
+			deps.MessageSenderService,
+			deps.PermissionsService,
+			deps.RandomCoffeeStatsRepository,
+			deps.RandomCoffeeExclusionRepository,
+		),
+		adminhandlers.NewCoffeeDraftHandler(
+			deps.AppConfig,
+			deps.RandomCoffeeService,
+		),
 	}
 
 	// Register group chat handlers
@@ -324,6 +484,11 @@ func (b *TgBotClient) registerHandlers(deps *HandlerDependencies) {
 			deps.SaveTopicService,
 			deps.AdminSaveMessageService,
 			deps.SaveMessageService,
+			deps.TldrService,
+		),
+		grouphandlers.NewEventRsvpHandler(
+			deps.EventRepository,
+			deps.EventAttendanceService,
 		),
 	}
 
@@ -349,14 +514,59 @@ func (b *TgBotClient) registerHandlers(deps *HandlerDependencies) {
 			deps.MessageSenderService,
 			deps.PromptingTemplateRepository,
 			deps.GroupMessageRepository,
+			deps.EventArchiveService,
+			deps.PermissionsService,
+		),
+		privatehandlers.NewCatchupHandler(
+			deps.AppConfig,
+			deps.SummarizationService,
+			deps.MessageSenderService,
+			deps.PermissionsService,
+			deps.GroupMessageRepository,
+		),
+		privatehandlers.NewCoffeeHandler(
+			deps.AppConfig,
+			deps.MessageSenderService,
 			deps.PermissionsService,
+			deps.UserRepository,
+			deps.RandomCoffeePreferenceRepository,
+			deps.RandomCoffeeStatsRepository,
+			deps.RandomCoffeeSubscriptionRepository,
+			deps.RandomCoffeeExclusionRepository,
 		),
+		privatehandlers.NewCoffeeFeedbackHandler(deps.RandomCoffeeFeedbackService),
+		privatehandlers.NewCoffeeRematchHandler(deps.RandomCoffeeService),
 		privatehandlers.NewEventsHandler(
 			deps.AppConfig,
 			deps.EventRepository,
+			deps.EventSubscriptionRepository,
+			deps.UserRepository,
 			deps.MessageSenderService,
 			deps.PermissionsService,
 		),
+		privatehandlers.NewEventSubscriptionHandler(
+			deps.AppConfig,
+			deps.EventRepository,
+			deps.EventSubscriptionRepository,
+			deps.UserRepository,
+		),
+		privatehandlers.NewEventCalendarHandler(
+			deps.EventCalendarService,
+			deps.UserRepository,
+			deps.MessageSenderService,
+		),
+		privatehandlers.NewArchiveHandler(
+			deps.AppConfig,
+			deps.EventArchiveService,
+			deps.MessageSenderService,
+			deps.PermissionsService,
+		),
+		privatehandlers.NewArchiveButtonsHandler(
+			deps.AppConfig,
+			deps.EventArchiveService,
+			deps.UserRepository,
+			deps.MessageSenderService,
+		),
 		privatehandlers.NewHelpHandler(
 			deps.AppConfig,
 			deps.MessageSenderService,
@@ -375,6 +585,7 @@ func (b *TgBotClient) registerHandlers(deps *HandlerDependencies) {
 			deps.MessageSenderService,
 			deps.PermissionsService,
 			deps.ProfileService,
+			deps.SummarizationService,
 			deps.UserRepository,
 			deps.ProfileRepository,
 			deps.PromptingTemplateRepository,
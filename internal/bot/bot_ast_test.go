@@ -49,27 +49,37 @@ var expectedConstructors = []string{
 	"NewEventEditHandler",
 	"NewEventSetupHandler",
 	"NewEventStartHandler",
+	"NewEventMaterialsHandler",
 	"NewTryCreateCoffeePoolHandler",
 	"NewTryGenerateCoffeePairsHandler",
 	"NewTrySummarizeHandler",
+	"NewTryLinkToLearnHandler",
 	"NewAdminProfilesHandler",
 	"NewShowTopicsHandler",
+	"NewSummarySettingsHandler",
+	"NewTasksHandler",
+	"NewCoffeeStatsHandler",
+	"NewCoffeeDraftHandler",
 
 	// Group
-	"NewSaveTopicsHandler",
-	"NewAdminMessageControlHandler",
-	"NewSaveMessagesHandler",
-	"NewCleanClosedThreadsHandler",
-	"NewDeleteJoinLeftMessagesHandler",
-	"NewJoinLeftHandler",
-	"NewRandomCoffeePollAnswerHandler",
-	"NewRepliesFromClosedThreadsHandler",
+	"NewChatMemberHandler",
+	"NewPollAnswerHandler",
+	"NewMessageHandler",
+	"NewEventRsvpHandler",
 
 	// Private
 	"NewTopicAddHandler",
 	"NewTopicsHandler",
 	"NewContentHandler",
+	"NewCatchupHandler",
+	"NewCoffeeHandler",
+	"NewCoffeeFeedbackHandler",
+	"NewCoffeeRematchHandler",
 	"NewEventsHandler",
+	"NewEventSubscriptionHandler",
+	"NewEventCalendarHandler",
+	"NewArchiveHandler",
+	"NewArchiveButtonsHandler",
 	"NewHelpHandler",
 	"NewIntroHandler",
 	"NewProfileHandler",
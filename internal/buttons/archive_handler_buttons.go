@@ -0,0 +1,81 @@
+package buttons
+
+import (
+	"fmt"
+
+	"evo-bot-go/internal/constants"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// ArchiveOption is an event type or a document shown in the archive keyboard
+type ArchiveOption struct {
+	// Key is the event type or the material ID
+	Key   string
+	Title string
+}
+
+// ArchiveTypesButtons opens the archive of an event type, two types per row
+func ArchiveTypesButtons(eventTypes []ArchiveOption) gotgbot.InlineKeyboardMarkup {
+	var rows [][]gotgbot.InlineKeyboardButton
+	var row []gotgbot.InlineKeyboardButton
+	for _, eventType := range eventTypes {
+		row = append(row, gotgbot.InlineKeyboardButton{
+			Text:         eventType.Title,
+			CallbackData: fmt.Sprintf("%s%s_%d", constants.ArchiveTypeCallbackPrefix, eventType.Key, 0),
+		})
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	return gotgbot.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// ArchivePageButtons sends the documents of the page, one per row, switches pages of the event type
+// and returns to the event types
+func ArchivePageButtons(documents []ArchiveOption, eventType string, page int, hasMore bool) gotgbot.InlineKeyboardMarkup {
+	rows := archiveDocumentRows(documents)
+
+	var pagesRow []gotgbot.InlineKeyboardButton
+	if page > 0 {
+		pagesRow = append(pagesRow, gotgbot.InlineKeyboardButton{
+			Text:         "⬅️ Новее",
+			CallbackData: fmt.Sprintf("%s%s_%d", constants.ArchiveTypeCallbackPrefix, eventType, page-1),
+		})
+	}
+	if hasMore {
+		pagesRow = append(pagesRow, gotgbot.InlineKeyboardButton{
+			Text:         "Старше ➡️",
+			CallbackData: fmt.Sprintf("%s%s_%d", constants.ArchiveTypeCallbackPrefix, eventType, page+1),
+		})
+	}
+	if len(pagesRow) > 0 {
+		rows = append(rows, pagesRow)
+	}
+
+	rows = append(rows, []gotgbot.InlineKeyboardButton{
+		{Text: "🗂 Типы мероприятий", CallbackData: constants.ArchiveTypesCallback},
+	})
+
+	return gotgbot.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// ArchiveDocumentsButtons sends the documents found in the archive, one per row
+func ArchiveDocumentsButtons(documents []ArchiveOption) gotgbot.InlineKeyboardMarkup {
+	return gotgbot.InlineKeyboardMarkup{InlineKeyboard: archiveDocumentRows(documents)}
+}
+
+func archiveDocumentRows(documents []ArchiveOption) [][]gotgbot.InlineKeyboardButton {
+	var rows [][]gotgbot.InlineKeyboardButton
+	for _, document := range documents {
+		rows = append(rows, []gotgbot.InlineKeyboardButton{
+			{Text: "📎 " + document.Title, CallbackData: constants.ArchiveDocumentCallbackPrefix + document.Key},
+		})
+	}
+	return rows
+}
@@ -0,0 +1,85 @@
+package buttons
+
+import (
+	"fmt"
+
+	"evo-bot-go/internal/constants"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// CatchupTopic is a topic option shown in the catchup topic selection keyboard
+type CatchupTopic struct {
+	ID       int
+	Name     string
+	Selected bool
+}
+
+func CatchupTopicsButtons(topics []CatchupTopic) gotgbot.InlineKeyboardMarkup {
+	var inlineKeyboard [][]gotgbot.InlineKeyboardButton
+
+	for _, topic := range topics {
+		mark := "▫️"
+		if topic.Selected {
+			mark = "✅"
+		}
+		inlineKeyboard = append(inlineKeyboard, []gotgbot.InlineKeyboardButton{
+			{
+				Text:         fmt.Sprintf("%s %s", mark, topic.Name),
+				CallbackData: fmt.Sprintf("%s%d", constants.CatchupTopicCallbackPrefix, topic.ID),
+			},
+		})
+	}
+
+	inlineKeyboard = append(inlineKeyboard,
+		[]gotgbot.InlineKeyboardButton{
+			{
+				Text:         "☑️ Все темы",
+				CallbackData: constants.CatchupAllTopicsCallback,
+			},
+			{
+				Text:         "➡️ Далее",
+				CallbackData: constants.CatchupTopicsDoneCallback,
+			},
+		},
+		[]gotgbot.InlineKeyboardButton{
+			{
+				Text:         "❌ Отмена",
+				CallbackData: constants.CatchupCancelCallback,
+			},
+		},
+	)
+
+	return gotgbot.InlineKeyboardMarkup{InlineKeyboard: inlineKeyboard}
+}
+
+func CatchupPeriodButtons() gotgbot.InlineKeyboardMarkup {
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			{
+				{
+					Text:         "💬 С моего последнего сообщения",
+					CallbackData: constants.CatchupPeriodSinceLastMsg,
+				},
+			},
+			{
+				{
+					Text:         "📆 За последние 3 дня",
+					CallbackData: constants.CatchupPeriodLastThreeDays,
+				},
+			},
+			{
+				{
+					Text:         "✏️ Свой период",
+					CallbackData: constants.CatchupPeriodCustomCallback,
+				},
+			},
+			{
+				{
+					Text:         "❌ Отмена",
+					CallbackData: constants.CatchupCancelCallback,
+				},
+			},
+		},
+	}
+}
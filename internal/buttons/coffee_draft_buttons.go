@@ -0,0 +1,95 @@
+package buttons
+
+import (
+	"fmt"
+
+	"evo-bot-go/internal/constants"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// CoffeeDraftMember is a participant option shown in the draft member selection keyboard
+type CoffeeDraftMember struct {
+	UserID int
+	Name   string
+}
+
+func CoffeeDraftButtons(pollID int) gotgbot.InlineKeyboardMarkup {
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			{
+				{
+					Text:         "🔀 Поменять местами",
+					CallbackData: fmt.Sprintf("%s%d", constants.CoffeeDraftSwapCallbackPrefix, pollID),
+				},
+				{
+					Text:         "🚫 Исключить",
+					CallbackData: fmt.Sprintf("%s%d", constants.CoffeeDraftExcludeCallbackPrefix, pollID),
+				},
+			},
+			{
+				{
+					Text:         "🔄 Сгенерировать заново",
+					CallbackData: fmt.Sprintf("%s%d", constants.CoffeeDraftRegenerateCallbackPrefix, pollID),
+				},
+			},
+			{
+				{
+					Text:         "✅ Опубликовать",
+					CallbackData: fmt.Sprintf("%s%d", constants.CoffeeDraftPublishCallbackPrefix, pollID),
+				},
+			},
+		},
+	}
+}
+
+// CoffeeDraftMembersPageSize is how many participants a page of the draft member selection shows
+const CoffeeDraftMembersPageSize = 10
+
+// CoffeeDraftMembersButtons lists participants of the draft page by page, the user ID of the chosen one is appended
+// to callbackData, pages are switched by appending "_p<page>" to it
+func CoffeeDraftMembersButtons(callbackData string, members []CoffeeDraftMember, pollID int, page int) gotgbot.InlineKeyboardMarkup {
+	var inlineKeyboard [][]gotgbot.InlineKeyboardButton
+
+	from := min(page*CoffeeDraftMembersPageSize, len(members))
+	to := min(from+CoffeeDraftMembersPageSize, len(members))
+	pageMembers := members[from:to]
+
+	// Two members per row to keep the keyboard compact
+	for i := 0; i < len(pageMembers); i += 2 {
+		var row []gotgbot.InlineKeyboardButton
+		for _, member := range pageMembers[i:min(i+2, len(pageMembers))] {
+			row = append(row, gotgbot.InlineKeyboardButton{
+				Text:         member.Name,
+				CallbackData: fmt.Sprintf("%s_%d", callbackData, member.UserID),
+			})
+		}
+		inlineKeyboard = append(inlineKeyboard, row)
+	}
+
+	var pagesRow []gotgbot.InlineKeyboardButton
+	if page > 0 {
+		pagesRow = append(pagesRow, gotgbot.InlineKeyboardButton{
+			Text:         "⬅️ Предыдущие",
+			CallbackData: fmt.Sprintf("%s_p%d", callbackData, page-1),
+		})
+	}
+	if to < len(members) {
+		pagesRow = append(pagesRow, gotgbot.InlineKeyboardButton{
+			Text:         "Следующие ➡️",
+			CallbackData: fmt.Sprintf("%s_p%d", callbackData, page+1),
+		})
+	}
+	if len(pagesRow) > 0 {
+		inlineKeyboard = append(inlineKeyboard, pagesRow)
+	}
+
+	inlineKeyboard = append(inlineKeyboard, []gotgbot.InlineKeyboardButton{
+		{
+			Text:         "◀️ К черновику",
+			CallbackData: fmt.Sprintf("%s%d", constants.CoffeeDraftBackCallbackPrefix, pollID),
+		},
+	})
+
+	return gotgbot.InlineKeyboardMarkup{InlineKeyboard: inlineKeyboard}
+}
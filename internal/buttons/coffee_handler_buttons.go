@@ -0,0 +1,220 @@
+package buttons
+
+import (
+	"fmt"
+
+	"evo-bot-go/internal/constants"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+func CoffeePreferencesButtons(strict bool) gotgbot.InlineKeyboardMarkup {
+	strictText := "⚖️ Сделать условия строгими"
+	if strict {
+		strictText = "⚖️ Сделать условия мягкими"
+	}
+
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			{
+				{
+					Text:         "📍 Формат",
+					CallbackData: constants.CoffeePreferencesFormatCallback,
+				},
+				{
+					Text:         "🏙 Город",
+					CallbackData: constants.CoffeePreferencesCityCallback,
+				},
+			},
+			{
+				{
+					Text:         "🗣 Языки",
+					CallbackData: constants.CoffeePreferencesLanguagesCallback,
+				},
+				{
+					Text:         "💡 Интересы",
+					CallbackData: constants.CoffeePreferencesInterestsCallback,
+				},
+			},
+			{
+				{
+					Text:         strictText,
+					CallbackData: constants.CoffeePreferencesStrictCallback,
+				},
+			},
+			{
+				{
+					Text:         "🚫 С кем не объединять",
+					CallbackData: constants.CoffeeExclusionsCallback,
+				},
+			},
+			{
+				{
+					Text:         "🔔 Подписка",
+					CallbackData: constants.CoffeeSubscriptionCallback,
+				},
+				{
+					Text:         "📊 Моя история",
+					CallbackData: constants.CoffeeHistoryCallback,
+				},
+			},
+			{
+				{
+					Text:         "✅ Готово",
+					CallbackData: constants.CoffeeCloseCallback,
+				},
+			},
+		},
+	}
+}
+
+// CoffeeSubscriptionPauseWeeks are the pause lengths offered in the subscription menu
+var CoffeeSubscriptionPauseWeeks = []int{1, 2, 4}
+
+// CoffeeSubscriptionButtons manages the standing random coffee subscription
+func CoffeeSubscriptionButtons(subscribed bool, paused bool, frequency constants.CoffeeSubscriptionFrequency) gotgbot.InlineKeyboardMarkup {
+	var rows [][]gotgbot.InlineKeyboardButton
+
+	if !subscribed {
+		rows = append(rows, []gotgbot.InlineKeyboardButton{
+			{Text: "🔔 Подписаться", CallbackData: constants.CoffeeSubscribeCallback},
+		})
+	} else {
+		frequencyText := "🔁 Встречаться раз в две недели"
+		if frequency == constants.CoffeeSubscriptionFrequencyBiweekly {
+			frequencyText = "🔁 Встречаться каждую неделю"
+		}
+		rows = append(rows, []gotgbot.InlineKeyboardButton{
+			{Text: frequencyText, CallbackData: constants.CoffeeFrequencyCallback},
+		})
+
+		if paused {
+			rows = append(rows, []gotgbot.InlineKeyboardButton{
+				{Text: "▶️ Снять паузу", CallbackData: constants.CoffeeResumeCallback},
+			})
+		} else {
+			pauseRow := make([]gotgbot.InlineKeyboardButton, 0, len(CoffeeSubscriptionPauseWeeks))
+			for _, weeks := range CoffeeSubscriptionPauseWeeks {
+				pauseRow = append(pauseRow, gotgbot.InlineKeyboardButton{
+					Text:         fmt.Sprintf("⏸ %d нед.", weeks),
+					CallbackData: fmt.Sprintf("%s%d", constants.CoffeePauseCallbackPrefix, weeks),
+				})
+			}
+			rows = append(rows, pauseRow)
+		}
+
+		rows = append(rows, []gotgbot.InlineKeyboardButton{
+			{Text: "🔕 Отписаться", CallbackData: constants.CoffeeUnsubscribeCallback},
+		})
+	}
+
+	rows = append(rows, BackAndCancelButton(constants.CoffeeBackCallback, constants.CoffeeCloseCallback).InlineKeyboard...)
+
+	return gotgbot.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// CoffeeExcludedUser is a user from the personal exclusion list shown as a button
+type CoffeeExcludedUser struct {
+	UserID int
+	Name   string
+}
+
+// CoffeeExclusionsButtons lets the user remove users from the exclusion list or add a new one
+func CoffeeExclusionsButtons(users []CoffeeExcludedUser, canAdd bool) gotgbot.InlineKeyboardMarkup {
+	var rows [][]gotgbot.InlineKeyboardButton
+	for _, user := range users {
+		rows = append(rows, []gotgbot.InlineKeyboardButton{
+			{
+				Text:         fmt.Sprintf("❌ %s", user.Name),
+				CallbackData: fmt.Sprintf("%s%d", constants.CoffeeExclusionsRemovePrefix, user.UserID),
+			},
+		})
+	}
+
+	if canAdd {
+		rows = append(rows, []gotgbot.InlineKeyboardButton{
+			{Text: "➕ Добавить", CallbackData: constants.CoffeeExclusionsAddCallback},
+		})
+	}
+
+	rows = append(rows, BackAndCancelButton(constants.CoffeeBackCallback, constants.CoffeeCloseCallback).InlineKeyboard...)
+
+	return gotgbot.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// CoffeeFeedbackStatusButtons asks whether the pair met, "planned" is hidden once the meeting was planned
+func CoffeeFeedbackStatusButtons(feedbackID int, withPlanned bool) gotgbot.InlineKeyboardMarkup {
+	statusButton := func(text string, status constants.CoffeeFeedbackStatus) gotgbot.InlineKeyboardButton {
+		return gotgbot.InlineKeyboardButton{
+			Text:         text,
+			CallbackData: fmt.Sprintf("%s%d_%s", constants.CoffeeFeedbackStatusCallbackPrefix, feedbackID, status),
+		}
+	}
+
+	firstRow := []gotgbot.InlineKeyboardButton{statusButton("✅ Встретились", constants.CoffeeFeedbackStatusMet)}
+	if withPlanned {
+		firstRow = append(firstRow, statusButton("🗓 Договорились", constants.CoffeeFeedbackStatusPlanned))
+	}
+
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			firstRow,
+			{statusButton("🙊 Пара не отвечает", constants.CoffeeFeedbackStatusNoResponse)},
+		},
+	}
+}
+
+// CoffeeFeedbackRatingButtons asks to rate the meeting from 1 to 5, zero rating means the user skipped it
+func CoffeeFeedbackRatingButtons(feedbackID int) gotgbot.InlineKeyboardMarkup {
+	var ratingRow []gotgbot.InlineKeyboardButton
+	for rating := 1; rating <= 5; rating++ {
+		ratingRow = append(ratingRow, gotgbot.InlineKeyboardButton{
+			Text:         fmt.Sprintf("%d ⭐️", rating),
+			CallbackData: fmt.Sprintf("%s%d_%d", constants.CoffeeFeedbackRateCallbackPrefix, feedbackID, rating),
+		})
+	}
+
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			ratingRow,
+			{
+				{
+					Text:         "⏭ Пропустить",
+					CallbackData: fmt.Sprintf("%s%d_%d", constants.CoffeeFeedbackRateCallbackPrefix, feedbackID, 0),
+				},
+			},
+		},
+	}
+}
+
+// CoffeeRematchButton is attached to a pair introduction to ask for a new partner instead of a silent one
+func CoffeeRematchButton(pairID int) gotgbot.InlineKeyboardMarkup {
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			{
+				{
+					Text:         "🙊 Партнёр молчит",
+					CallbackData: fmt.Sprintf("%s%d", constants.CoffeeRematchAskCallbackPrefix, pairID),
+				},
+			},
+		},
+	}
+}
+
+// CoffeeRematchConfirmButtons confirms that the pair failed and a new partner is needed
+func CoffeeRematchConfirmButtons(pairID int) gotgbot.InlineKeyboardMarkup {
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			{
+				{
+					Text:         "🔄 Подобрать нового",
+					CallbackData: fmt.Sprintf("%s%d", constants.CoffeeRematchConfirmCallbackPrefix, pairID),
+				},
+				{
+					Text:         "❌ Отмена",
+					CallbackData: constants.CoffeeRematchCancelCallback,
+				},
+			},
+		},
+	}
+}
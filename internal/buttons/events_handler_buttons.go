@@ -0,0 +1,109 @@
+package buttons
+
+import (
+	"fmt"
+
+	"evo-bot-go/internal/constants"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// EventSubscriptionOption is an event or an event type the user can get reminders about
+type EventSubscriptionOption struct {
+	// Key is the event ID or the event type
+	Key        string
+	Title      string
+	Subscribed bool
+}
+
+// EventsListButtons toggle reminders about the events, one per row next to the .ics export, and about event types, two per row.
+// The last row exports all upcoming events and, if the feed is enabled, shows the calendar subscription.
+func EventsListButtons(events []EventSubscriptionOption, eventTypes []EventSubscriptionOption, withFeed bool) gotgbot.InlineKeyboardMarkup {
+	toggleButton := func(option EventSubscriptionOption, callbackPrefix string) gotgbot.InlineKeyboardButton {
+		text := "🔔 " + option.Title
+		if option.Subscribed {
+			text = "✅ " + option.Title
+		}
+		return gotgbot.InlineKeyboardButton{Text: text, CallbackData: callbackPrefix + option.Key}
+	}
+
+	var rows [][]gotgbot.InlineKeyboardButton
+	for _, event := range events {
+		rows = append(rows, []gotgbot.InlineKeyboardButton{
+			toggleButton(event, constants.EventsSubscribeEventCallbackPrefix),
+			{Text: "📅 .ics", CallbackData: constants.EventsCalendarEventCallbackPrefix + event.Key},
+		})
+	}
+
+	var typeRow []gotgbot.InlineKeyboardButton
+	for _, eventType := range eventTypes {
+		typeRow = append(typeRow, toggleButton(eventType, constants.EventsSubscribeEventTypeCallbackPrefix))
+		if len(typeRow) == 2 {
+			rows = append(rows, typeRow)
+			typeRow = nil
+		}
+	}
+	if len(typeRow) > 0 {
+		rows = append(rows, typeRow)
+	}
+
+	calendarRow := []gotgbot.InlineKeyboardButton{
+		{Text: "📅 Все в календарь", CallbackData: constants.EventsCalendarAllCallback},
+	}
+	if withFeed {
+		calendarRow = append(calendarRow, gotgbot.InlineKeyboardButton{
+			Text: "🔗 Подписка на календарь", CallbackData: constants.EventsCalendarFeedCallback,
+		})
+	}
+	rows = append(rows, calendarRow)
+
+	return gotgbot.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// EventCalendarFeedButtons replaces the personal feed link with a new one
+func EventCalendarFeedButtons() gotgbot.InlineKeyboardMarkup {
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			{
+				{Text: "🔄 Новая ссылка", CallbackData: constants.EventsCalendarFeedResetCallback},
+			},
+		},
+	}
+}
+
+// EventRsvpCounts holds the numbers shown on the RSVP buttons, zero capacity means unlimited
+type EventRsvpCounts struct {
+	Going      int
+	Capacity   int
+	Waitlisted int
+	Maybe      int
+	NotGoing   int
+}
+
+// EventRsvpButtons lets members answer whether they are going to the event
+func EventRsvpButtons(eventID int, counts EventRsvpCounts) gotgbot.InlineKeyboardMarkup {
+	rsvpButton := func(text string, status constants.EventAttendeeStatus) gotgbot.InlineKeyboardButton {
+		return gotgbot.InlineKeyboardButton{
+			Text:         text,
+			CallbackData: fmt.Sprintf("%s%d_%s", constants.EventRsvpCallbackPrefix, eventID, status),
+		}
+	}
+
+	goingText := fmt.Sprintf("✅ Иду · %d", counts.Going)
+	if counts.Capacity > 0 {
+		goingText = fmt.Sprintf("✅ Иду · %d/%d", counts.Going, counts.Capacity)
+	}
+	if counts.Waitlisted > 0 {
+		goingText += fmt.Sprintf(" (+%d ⏳)", counts.Waitlisted)
+	}
+
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			{
+				rsvpButton(goingText, constants.EventAttendeeStatusGoing),
+				rsvpButton(fmt.Sprintf("🤔 Возможно · %d", counts.Maybe), constants.EventAttendeeStatusMaybe),
+				rsvpButton(fmt.Sprintf("❌ Не иду · %d", counts.NotGoing), constants.EventAttendeeStatusNotGoing),
+			},
+		},
+	}
+}
@@ -51,6 +51,25 @@ func ConfirmAndCancelButton(callbackDataYes string, callbackDataNo string) gotgb
 	return inlineKeyboard
 }
 
+func SkipAndCancelButton(callbackDataSkip string, callbackDataCancel string) gotgbot.InlineKeyboardMarkup {
+	inlineKeyboard := gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			{
+				{
+					Text:         "⏭ Пропустить",
+					CallbackData: callbackDataSkip,
+				},
+				{
+					Text:         "❌ Отмена",
+					CallbackData: callbackDataCancel,
+				},
+			},
+		},
+	}
+
+	return inlineKeyboard
+}
+
 func BackAndCancelButton(callbackDataBack string, callbackDataCancel string) gotgbot.InlineKeyboardMarkup {
 	inlineKeyboard := gotgbot.InlineKeyboardMarkup{
 		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
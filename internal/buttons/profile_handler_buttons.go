@@ -69,7 +69,12 @@ func ProfileBackCancelButtons(backCallbackData string) gotgbot.InlineKeyboardMar
 	}
 }
 
-func ProfileEditButtons(backCallbackData string) gotgbot.InlineKeyboardMarkup {
+func ProfileEditButtons(backCallbackData string, hideInSummaries bool) gotgbot.InlineKeyboardMarkup {
+	hideInSummariesText := "🙈 Не упоминать меня в сводках"
+	if hideInSummaries {
+		hideInSummariesText = "👁 Разрешить упоминать меня в сводках"
+	}
+
 	buttons := [][]gotgbot.InlineKeyboardButton{
 		{
 			{
@@ -85,6 +90,12 @@ func ProfileEditButtons(backCallbackData string) gotgbot.InlineKeyboardMarkup {
 				CallbackData: constants.ProfileEditBioCallback,
 			},
 		},
+		{
+			{
+				Text:         hideInSummariesText,
+				CallbackData: constants.ProfileToggleHideInSummariesCallback,
+			},
+		},
 		{
 			{
 				Text:         "◀️ Назад",
@@ -0,0 +1,81 @@
+package buttons
+
+import (
+	"fmt"
+
+	"evo-bot-go/internal/constants"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// TasksJob is a job option shown in the tasks list keyboard
+type TasksJob struct {
+	Name   string
+	Status string
+}
+
+func TasksListButtons(jobs []TasksJob) gotgbot.InlineKeyboardMarkup {
+	var inlineKeyboard [][]gotgbot.InlineKeyboardButton
+
+	for _, job := range jobs {
+		inlineKeyboard = append(inlineKeyboard, []gotgbot.InlineKeyboardButton{
+			{
+				Text:         fmt.Sprintf("%s %s", job.Status, job.Name),
+				CallbackData: constants.TasksSelectCallbackPrefix + job.Name,
+			},
+		})
+	}
+
+	inlineKeyboard = append(inlineKeyboard, []gotgbot.InlineKeyboardButton{
+		{
+			Text:         "❌ Отмена",
+			CallbackData: constants.TasksCancelCallback,
+		},
+	})
+
+	return gotgbot.InlineKeyboardMarkup{InlineKeyboard: inlineKeyboard}
+}
+
+func TasksJobButtons(jobName string, paused bool, skipNext bool) gotgbot.InlineKeyboardMarkup {
+	pauseText := "⏸ Приостановить"
+	if paused {
+		pauseText = "▶️ Возобновить"
+	}
+	skipText := "⏭ Пропустить следующий запуск"
+	if skipNext {
+		skipText = "↩️ Не пропускать следующий запуск"
+	}
+
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+			{
+				{
+					Text:         "🚀 Запустить сейчас",
+					CallbackData: constants.TasksRunCallbackPrefix + jobName,
+				},
+			},
+			{
+				{
+					Text:         pauseText,
+					CallbackData: constants.TasksPauseCallbackPrefix + jobName,
+				},
+			},
+			{
+				{
+					Text:         skipText,
+					CallbackData: constants.TasksSkipNextCallbackPrefix + jobName,
+				},
+			},
+			{
+				{
+					Text:         "◀️ Назад",
+					CallbackData: constants.TasksBackCallback,
+				},
+				{
+					Text:         "❌ Отмена",
+					CallbackData: constants.TasksCancelCallback,
+				},
+			},
+		},
+	}
+}
@@ -3,9 +3,12 @@ package config
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	// Embedded IANA database, so the club timezone loads on hosts without zoneinfo
+	_ "time/tzdata"
 )
 
 // Config holds the application configuration
@@ -15,6 +18,8 @@ type Config struct {
 	SuperGroupChatID int64
 	OpenAIAPIKey     string
 	AdminUserID      int64
+	// ClubTimezone is used for all schedules, dates shown to members and week boundaries
+	ClubTimezone *time.Location
 
 	// Topics Management
 	ClosedTopicsIDs     []int
@@ -30,6 +35,8 @@ type Config struct {
 	SummaryTopicID           int
 	SummaryTime              time.Time
 	SummarizationTaskEnabled bool
+	// SummarizationCatchUpWindow limits how long ago a missed summarization run may be caught up after downtime
+	SummarizationCatchUpWindow time.Duration
 
 	// Random Coffee Feature
 	RandomCoffeeTopicID int
@@ -37,10 +44,51 @@ type Config struct {
 	RandomCoffeePollTaskEnabled bool
 	RandomCoffeePollTime        time.Time
 	RandomCoffeePollDay         time.Weekday
+	// RandomCoffeeSendPoll is off when only subscribers take part and the round is opened without a poll
+	RandomCoffeeSendPoll bool
 
 	RandomCoffeePairsTaskEnabled bool
 	RandomCoffeePairsTime        time.Time
 	RandomCoffeePairsDay         time.Weekday
+	// RandomCoffeePairsRequireApproval makes the pairs task save a draft and wait for admin approval instead of publishing
+	RandomCoffeePairsRequireApproval bool
+	// RandomCoffeePairsApprovalTimeout publishes a not approved draft automatically after this time, zero means waiting for approval
+	RandomCoffeePairsApprovalTimeout time.Duration
+
+	// RandomCoffeeRepeatPenalty is the matching cost of repeating a pair that met in the previous round
+	RandomCoffeeRepeatPenalty float64
+	// RandomCoffeeHistoryHalfLifeWeeks is how many weeks it takes for the repeat penalty to halve
+	RandomCoffeeHistoryHalfLifeWeeks float64
+	// RandomCoffeeMatchingSeed makes pairing reproducible, zero means a new random seed for every run
+	RandomCoffeeMatchingSeed int64
+	// RandomCoffeeAllowTriples forms one group of three instead of leaving a participant without a pair
+	RandomCoffeeAllowTriples bool
+	// RandomCoffeePreferencePenalty is the matching cost of every broken wish about the meeting format, city or language
+	RandomCoffeePreferencePenalty float64
+	// RandomCoffeeInterestBonus is how much every common interest (up to 3) reduces the matching cost of a pair
+	RandomCoffeeInterestBonus float64
+	// RandomCoffeeUnreliabilityPenalty is the matching cost of a pair whose member never responds to partners
+	RandomCoffeeUnreliabilityPenalty float64
+
+	RandomCoffeeFeedbackTaskEnabled bool
+	RandomCoffeeFeedbackTime        time.Time
+	RandomCoffeeFeedbackDay         time.Weekday
+
+	// Event Reminders Feature
+	EventRemindersTaskEnabled bool
+	// EventReminderOffsets are how long before the event start reminders are sent, in descending order
+	EventReminderOffsets []time.Duration
+
+	// Recurring Events Feature
+	EventSeriesTaskEnabled bool
+
+	// Event Statuses Feature, moves events to live and finished by time
+	EventStatusTaskEnabled bool
+
+	// Calendar Feed Feature, the feed server is disabled when the listen address is empty
+	CalendarFeedListenAddr string
+	// CalendarFeedBaseURL is the public URL of the feed server used in subscription links
+	CalendarFeedBaseURL string
 }
 
 // LoadConfig loads the configuration from environment variables
@@ -69,6 +117,18 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("TG_EVO_BOT_OPENAI_API_KEY environment variable is not set")
 	}
 
+	// Club timezone
+	clubTimezoneStr := os.Getenv("TG_EVO_BOT_TIMEZONE")
+	if clubTimezoneStr == "" {
+		// Default to UTC if not specified
+		clubTimezoneStr = "UTC"
+	}
+	clubTimezone, err := time.LoadLocation(clubTimezoneStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %s", clubTimezoneStr)
+	}
+	config.ClubTimezone = clubTimezone
+
 	// Topics Management
 	closedTopicsIDsStr := os.Getenv("TG_EVO_BOT_CLOSED_TOPICS_IDS")
 	if closedTopicsIDsStr != "" {
@@ -196,6 +256,18 @@ func LoadConfig() (*Config, error) {
 		config.SummarizationTaskEnabled = summarizationTaskEnabled
 	}
 
+	// Summarization catch-up window
+	summarizationCatchUpWindowStr := os.Getenv("TG_EVO_BOT_SUMMARIZATION_CATCH_UP_WINDOW")
+	if summarizationCatchUpWindowStr == "" {
+		// Default to 6 hours if not specified
+		summarizationCatchUpWindowStr = "6h"
+	}
+	summarizationCatchUpWindow, err := time.ParseDuration(summarizationCatchUpWindowStr)
+	if err != nil || summarizationCatchUpWindow < 0 {
+		return nil, fmt.Errorf("invalid summarization catch-up window: %s", summarizationCatchUpWindowStr)
+	}
+	config.SummarizationCatchUpWindow = summarizationCatchUpWindow
+
 	// Random coffee topic ID
 	randomCoffeeTopicIDStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_TOPIC_ID")
 	if randomCoffeeTopicIDStr == "" {
@@ -261,6 +333,19 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// Meeting poll itself, rounds without a poll are only for subscribers
+	randomCoffeeSendPollStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_SEND_POLL")
+	if randomCoffeeSendPollStr == "" {
+		// Default to enabled if not specified
+		config.RandomCoffeeSendPoll = true
+	} else {
+		randomCoffeeSendPoll, err := strconv.ParseBool(randomCoffeeSendPollStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid random coffee send poll value: %s", randomCoffeeSendPollStr)
+		}
+		config.RandomCoffeeSendPoll = randomCoffeeSendPoll
+	}
+
 	// Random Coffee Pairs Feature
 	randomCoffeePairsTaskEnabledStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_PAIRS_TASK_ENABLED")
 	if randomCoffeePairsTaskEnabledStr == "" {
@@ -314,5 +399,229 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// Pairs approval by admins
+	randomCoffeePairsRequireApprovalStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_PAIRS_REQUIRE_APPROVAL")
+	if randomCoffeePairsRequireApprovalStr == "" {
+		// Default to publishing without approval if not specified
+		config.RandomCoffeePairsRequireApproval = false
+	} else {
+		randomCoffeePairsRequireApproval, err := strconv.ParseBool(randomCoffeePairsRequireApprovalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid random coffee pairs require approval value: %s", randomCoffeePairsRequireApprovalStr)
+		}
+		config.RandomCoffeePairsRequireApproval = randomCoffeePairsRequireApproval
+	}
+
+	// Pairs approval timeout
+	randomCoffeePairsApprovalTimeoutStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_PAIRS_APPROVAL_TIMEOUT")
+	if randomCoffeePairsApprovalTimeoutStr == "" {
+		// Default to 3 hours if not specified
+		randomCoffeePairsApprovalTimeoutStr = "3h"
+	}
+	randomCoffeePairsApprovalTimeout, err := time.ParseDuration(randomCoffeePairsApprovalTimeoutStr)
+	if err != nil || randomCoffeePairsApprovalTimeout < 0 {
+		return nil, fmt.Errorf("invalid random coffee pairs approval timeout: %s", randomCoffeePairsApprovalTimeoutStr)
+	}
+	config.RandomCoffeePairsApprovalTimeout = randomCoffeePairsApprovalTimeout
+
+	// Random coffee matching repeat penalty
+	randomCoffeeRepeatPenaltyStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_REPEAT_PENALTY")
+	if randomCoffeeRepeatPenaltyStr == "" {
+		// Default to 100 if not specified
+		randomCoffeeRepeatPenaltyStr = "100"
+	}
+	randomCoffeeRepeatPenalty, err := strconv.ParseFloat(randomCoffeeRepeatPenaltyStr, 64)
+	if err != nil || randomCoffeeRepeatPenalty < 0 {
+		return nil, fmt.Errorf("invalid random coffee repeat penalty: %s", randomCoffeeRepeatPenaltyStr)
+	}
+	config.RandomCoffeeRepeatPenalty = randomCoffeeRepeatPenalty
+
+	// Random coffee matching history half-life
+	randomCoffeeHistoryHalfLifeStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_HISTORY_HALF_LIFE_WEEKS")
+	if randomCoffeeHistoryHalfLifeStr == "" {
+		// Default to 8 weeks if not specified
+		randomCoffeeHistoryHalfLifeStr = "8"
+	}
+	randomCoffeeHistoryHalfLife, err := strconv.ParseFloat(randomCoffeeHistoryHalfLifeStr, 64)
+	if err != nil || randomCoffeeHistoryHalfLife < 0 {
+		return nil, fmt.Errorf("invalid random coffee history half-life: %s", randomCoffeeHistoryHalfLifeStr)
+	}
+	config.RandomCoffeeHistoryHalfLifeWeeks = randomCoffeeHistoryHalfLife
+
+	// Random coffee matching seed
+	randomCoffeeMatchingSeedStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_MATCHING_SEED")
+	if randomCoffeeMatchingSeedStr != "" {
+		randomCoffeeMatchingSeed, err := strconv.ParseInt(randomCoffeeMatchingSeedStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid random coffee matching seed: %s", randomCoffeeMatchingSeedStr)
+		}
+		config.RandomCoffeeMatchingSeed = randomCoffeeMatchingSeed
+	}
+
+	// Random coffee triples enabled/disabled
+	randomCoffeeAllowTriplesStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_ALLOW_TRIPLES")
+	if randomCoffeeAllowTriplesStr == "" {
+		// Default to enabled if not specified
+		config.RandomCoffeeAllowTriples = true
+	} else {
+		randomCoffeeAllowTriples, err := strconv.ParseBool(randomCoffeeAllowTriplesStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid random coffee allow triples value: %s", randomCoffeeAllowTriplesStr)
+		}
+		config.RandomCoffeeAllowTriples = randomCoffeeAllowTriples
+	}
+
+	// Random coffee preferences penalty
+	randomCoffeePreferencePenaltyStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_PREFERENCE_PENALTY")
+	if randomCoffeePreferencePenaltyStr == "" {
+		// Default to 50 if not specified
+		randomCoffeePreferencePenaltyStr = "50"
+	}
+	randomCoffeePreferencePenalty, err := strconv.ParseFloat(randomCoffeePreferencePenaltyStr, 64)
+	if err != nil || randomCoffeePreferencePenalty < 0 {
+		return nil, fmt.Errorf("invalid random coffee preference penalty: %s", randomCoffeePreferencePenaltyStr)
+	}
+	config.RandomCoffeePreferencePenalty = randomCoffeePreferencePenalty
+
+	// Random coffee common interest bonus
+	randomCoffeeInterestBonusStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_INTEREST_BONUS")
+	if randomCoffeeInterestBonusStr == "" {
+		// Default to 10 if not specified
+		randomCoffeeInterestBonusStr = "10"
+	}
+	randomCoffeeInterestBonus, err := strconv.ParseFloat(randomCoffeeInterestBonusStr, 64)
+	if err != nil || randomCoffeeInterestBonus < 0 {
+		return nil, fmt.Errorf("invalid random coffee interest bonus: %s", randomCoffeeInterestBonusStr)
+	}
+	config.RandomCoffeeInterestBonus = randomCoffeeInterestBonus
+
+	// Random coffee unreliability penalty
+	randomCoffeeUnreliabilityPenaltyStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_UNRELIABILITY_PENALTY")
+	if randomCoffeeUnreliabilityPenaltyStr == "" {
+		// Default to 50 if not specified
+		randomCoffeeUnreliabilityPenaltyStr = "50"
+	}
+	randomCoffeeUnreliabilityPenalty, err := strconv.ParseFloat(randomCoffeeUnreliabilityPenaltyStr, 64)
+	if err != nil || randomCoffeeUnreliabilityPenalty < 0 {
+		return nil, fmt.Errorf("invalid random coffee unreliability penalty: %s", randomCoffeeUnreliabilityPenaltyStr)
+	}
+	config.RandomCoffeeUnreliabilityPenalty = randomCoffeeUnreliabilityPenalty
+
+	// Random Coffee Feedback Feature
+	randomCoffeeFeedbackTaskEnabledStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_FEEDBACK_TASK_ENABLED")
+	if randomCoffeeFeedbackTaskEnabledStr == "" {
+		// Default to enabled if not specified
+		config.RandomCoffeeFeedbackTaskEnabled = true
+	} else {
+		randomCoffeeFeedbackTaskEnabled, err := strconv.ParseBool(randomCoffeeFeedbackTaskEnabledStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid random coffee feedback task enabled value: %s", randomCoffeeFeedbackTaskEnabledStr)
+		}
+		config.RandomCoffeeFeedbackTaskEnabled = randomCoffeeFeedbackTaskEnabled
+	}
+
+	// Feedback request time
+	randomCoffeeFeedbackTimeStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_FEEDBACK_TIME")
+	if randomCoffeeFeedbackTimeStr == "" {
+		// Default to 12:00 PM if not specified
+		randomCoffeeFeedbackTimeStr = "12:00"
+	}
+
+	// Parse the time in 24-hour format
+	randomCoffeeFeedbackTime, err := time.Parse("15:04", randomCoffeeFeedbackTimeStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid random coffee feedback time format: %s", randomCoffeeFeedbackTimeStr)
+	}
+	config.RandomCoffeeFeedbackTime = randomCoffeeFeedbackTime
+
+	// Feedback request day
+	randomCoffeeFeedbackDayStr := os.Getenv("TG_EVO_BOT_RANDOM_COFFEE_FEEDBACK_DAY")
+	if randomCoffeeFeedbackDayStr == "" {
+		// Default to Thursday if not specified
+		config.RandomCoffeeFeedbackDay = time.Thursday
+	} else {
+		switch strings.ToLower(randomCoffeeFeedbackDayStr) {
+		case "sunday":
+			config.RandomCoffeeFeedbackDay = time.Sunday
+		case "monday":
+			config.RandomCoffeeFeedbackDay = time.Monday
+		case "tuesday":
+			config.RandomCoffeeFeedbackDay = time.Tuesday
+		case "wednesday":
+			config.RandomCoffeeFeedbackDay = time.Wednesday
+		case "thursday":
+			config.RandomCoffeeFeedbackDay = time.Thursday
+		case "friday":
+			config.RandomCoffeeFeedbackDay = time.Friday
+		case "saturday":
+			config.RandomCoffeeFeedbackDay = time.Saturday
+		default:
+			return nil, fmt.Errorf("invalid random coffee feedback day: %s (valid values: sunday, monday, tuesday, wednesday, thursday, friday, saturday)", randomCoffeeFeedbackDayStr)
+		}
+	}
+
+	// Event Reminders Feature
+	eventRemindersTaskEnabledStr := os.Getenv("TG_EVO_BOT_EVENT_REMINDERS_TASK_ENABLED")
+	if eventRemindersTaskEnabledStr == "" {
+		// Default to enabled if not specified
+		config.EventRemindersTaskEnabled = true
+	} else {
+		eventRemindersTaskEnabled, err := strconv.ParseBool(eventRemindersTaskEnabledStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event reminders task enabled value: %s", eventRemindersTaskEnabledStr)
+		}
+		config.EventRemindersTaskEnabled = eventRemindersTaskEnabled
+	}
+
+	// Event reminder offsets
+	eventReminderOffsetsStr := os.Getenv("TG_EVO_BOT_EVENT_REMINDER_OFFSETS")
+	if eventReminderOffsetsStr == "" {
+		// Default to a day and an hour before the start if not specified
+		eventReminderOffsetsStr = "24h,1h"
+	}
+	for _, offsetStr := range strings.Split(eventReminderOffsetsStr, ",") {
+		offset, err := time.ParseDuration(strings.TrimSpace(offsetStr))
+		if err != nil || offset <= 0 {
+			return nil, fmt.Errorf("invalid event reminder offset in TG_EVO_BOT_EVENT_REMINDER_OFFSETS: %s", offsetStr)
+		}
+		config.EventReminderOffsets = append(config.EventReminderOffsets, offset)
+	}
+	sort.Slice(config.EventReminderOffsets, func(i, j int) bool {
+		return config.EventReminderOffsets[i] > config.EventReminderOffsets[j]
+	})
+
+	// Recurring Events Feature
+	eventSeriesTaskEnabledStr := os.Getenv("TG_EVO_BOT_EVENT_SERIES_TASK_ENABLED")
+	if eventSeriesTaskEnabledStr == "" {
+		// Default to enabled if not specified
+		config.EventSeriesTaskEnabled = true
+	} else {
+		eventSeriesTaskEnabled, err := strconv.ParseBool(eventSeriesTaskEnabledStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event series task enabled value: %s", eventSeriesTaskEnabledStr)
+		}
+		config.EventSeriesTaskEnabled = eventSeriesTaskEnabled
+	}
+
+	// Event Statuses Feature
+	eventStatusTaskEnabledStr := os.Getenv("TG_EVO_BOT_EVENT_STATUS_TASK_ENABLED")
+	if eventStatusTaskEnabledStr == "" {
+		// Default to enabled if not specified
+		config.EventStatusTaskEnabled = true
+	} else {
+		eventStatusTaskEnabled, err := strconv.ParseBool(eventStatusTaskEnabledStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event status task enabled value: %s", eventStatusTaskEnabledStr)
+		}
+		config.EventStatusTaskEnabled = eventStatusTaskEnabled
+	}
+
+	// Calendar Feed Feature
+	config.CalendarFeedListenAddr = os.Getenv("TG_EVO_BOT_CALENDAR_FEED_LISTEN_ADDR")
+	config.CalendarFeedBaseURL = strings.TrimSuffix(os.Getenv("TG_EVO_BOT_CALENDAR_FEED_BASE_URL"), "/")
+	if config.CalendarFeedListenAddr != "" && config.CalendarFeedBaseURL == "" {
+		return nil, fmt.Errorf("TG_EVO_BOT_CALENDAR_FEED_BASE_URL environment variable is required when the calendar feed is enabled")
+	}
+
 	return config, nil
 }
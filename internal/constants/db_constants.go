@@ -23,13 +23,97 @@ var AllEventTypes = []EventType{
 // EventStatus represents the status of event
 type EventStatus string
 
+// An event is planned until its start, live until its end and finished afterwards, the transitions are automatic.
+// A cancelled event stays cancelled.
 const (
-	EventStatusFinished EventStatus = "finished"
-	EventStatusActual   EventStatus = "actual"
+	EventStatusPlanned   EventStatus = "planned"
+	EventStatusLive      EventStatus = "live"
+	EventStatusFinished  EventStatus = "finished"
+	EventStatusCancelled EventStatus = "cancelled"
 )
 
 // AllEventStatuses is a slice containing all possible EventStatus values
 var AllEventStatuses = []EventStatus{
+	EventStatusPlanned,
+	EventStatusLive,
 	EventStatusFinished,
-	EventStatusActual,
+	EventStatusCancelled,
+}
+
+// ActiveEventStatuses are the statuses of events that haven't ended yet
+var ActiveEventStatuses = []EventStatus{
+	EventStatusPlanned,
+	EventStatusLive,
+}
+
+// EventAttendeeStatus represents the RSVP answer of an event attendee
+type EventAttendeeStatus string
+
+const (
+	EventAttendeeStatusGoing      EventAttendeeStatus = "going"
+	EventAttendeeStatusWaitlisted EventAttendeeStatus = "waitlisted"
+	EventAttendeeStatusMaybe      EventAttendeeStatus = "maybe"
+	EventAttendeeStatusNotGoing   EventAttendeeStatus = "not_going"
+)
+
+// RsvpEventAttendeeStatuses are the answers users choose with the RSVP buttons, the waitlist is assigned by the bot
+var RsvpEventAttendeeStatuses = []EventAttendeeStatus{
+	EventAttendeeStatusGoing,
+	EventAttendeeStatusMaybe,
+	EventAttendeeStatusNotGoing,
+}
+
+// EventMaterialType represents the kind of material attached to a finished event
+type EventMaterialType string
+
+const (
+	EventMaterialTypeLink     EventMaterialType = "link"
+	EventMaterialTypeDocument EventMaterialType = "document"
+	EventMaterialTypeNote     EventMaterialType = "note"
+)
+
+// CoffeeMeetingFormat represents the preferred format of random coffee meetings
+type CoffeeMeetingFormat string
+
+const (
+	CoffeeMeetingFormatAny     CoffeeMeetingFormat = "any"
+	CoffeeMeetingFormatOnline  CoffeeMeetingFormat = "online"
+	CoffeeMeetingFormatOffline CoffeeMeetingFormat = "offline"
+)
+
+// AllCoffeeMeetingFormats is a slice containing all possible CoffeeMeetingFormat values
+var AllCoffeeMeetingFormats = []CoffeeMeetingFormat{
+	CoffeeMeetingFormatAny,
+	CoffeeMeetingFormatOnline,
+	CoffeeMeetingFormatOffline,
+}
+
+// CoffeeFeedbackStatus represents the answer of a random coffee participant about the meeting
+type CoffeeFeedbackStatus string
+
+const (
+	CoffeeFeedbackStatusMet        CoffeeFeedbackStatus = "met"
+	CoffeeFeedbackStatusPlanned    CoffeeFeedbackStatus = "planned"
+	CoffeeFeedbackStatusNoResponse CoffeeFeedbackStatus = "no_response"
+)
+
+// AllCoffeeFeedbackStatuses is a slice containing all possible CoffeeFeedbackStatus values
+var AllCoffeeFeedbackStatuses = []CoffeeFeedbackStatus{
+	CoffeeFeedbackStatusMet,
+	CoffeeFeedbackStatusPlanned,
+	CoffeeFeedbackStatusNoResponse,
+}
+
+// CoffeeSubscriptionFrequency represents how often a subscriber takes part in random coffee rounds
+type CoffeeSubscriptionFrequency string
+
+const (
+	CoffeeSubscriptionFrequencyWeekly   CoffeeSubscriptionFrequency = "weekly"
+	CoffeeSubscriptionFrequencyBiweekly CoffeeSubscriptionFrequency = "biweekly"
+)
+
+// AllCoffeeSubscriptionFrequencies is a slice containing all possible CoffeeSubscriptionFrequency values
+var AllCoffeeSubscriptionFrequencies = []CoffeeSubscriptionFrequency{
+	CoffeeSubscriptionFrequencyWeekly,
+	CoffeeSubscriptionFrequencyBiweekly,
 }
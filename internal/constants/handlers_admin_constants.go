@@ -11,6 +11,7 @@ const EventEditGetLastLimit = 10
 const EventSetupCommand = "eventSetup"
 const EventDeleteCommand = "eventDelete"
 const EventStartCommand = "eventStart"
+const EventMaterialsCommand = "eventMaterials"
 
 // Topics Handlers
 const ShowTopicsCommand = "showTopics"
@@ -57,3 +58,31 @@ const (
 	TryGenerateCoffeePairsBackCallback    = TryGenerateCoffeePairsPrefix + "back"
 	TryGenerateCoffeePairsCancelCallback  = TryGenerateCoffeePairsPrefix + "cancel"
 )
+
+// Random Coffee Draft Handler callback constants, the poll ID and user IDs follow the prefixes separated by "_"
+const (
+	CoffeeDraftPrefix                   = "coffee_draft_"
+	CoffeeDraftPublishCallbackPrefix    = CoffeeDraftPrefix + "publish_"
+	CoffeeDraftRegenerateCallbackPrefix = CoffeeDraftPrefix + "regenerate_"
+	CoffeeDraftSwapCallbackPrefix       = CoffeeDraftPrefix + "swap_"
+	CoffeeDraftExcludeCallbackPrefix    = CoffeeDraftPrefix + "exclude_"
+	CoffeeDraftBackCallbackPrefix       = CoffeeDraftPrefix + "back_"
+)
+
+// Random Coffee Stats Handler
+const CoffeeStatsCommand = "coffeeStats"
+
+// Summary Settings Handler
+const SummarySettingsCommand = "summarySettings"
+
+// Tasks Handler callback constants
+const (
+	TasksCommand                = "tasks"
+	TasksPrefix                 = "admin_tasks_"
+	TasksSelectCallbackPrefix   = TasksPrefix + "select_"
+	TasksRunCallbackPrefix      = TasksPrefix + "run_"
+	TasksPauseCallbackPrefix    = TasksPrefix + "pause_"
+	TasksSkipNextCallbackPrefix = TasksPrefix + "skip_next_"
+	TasksBackCallback           = TasksPrefix + "back"
+	TasksCancelCallback         = TasksPrefix + "cancel"
+)
@@ -9,6 +9,9 @@ const HelpCommand = "help"
 const StartCommand = "start"
 const IntroCommand = "intro"
 const ProfileCommand = "profile"
+const CatchupCommand = "catchup"
+const CoffeeCommand = "coffee"
+const ArchiveCommand = "archive"
 const CopyrightString = "<br> © <a href=\"https://t.me/evocoders\">«Эволюция Кода»</a>"
 
 // Callback data constants for profile handler
@@ -21,6 +24,77 @@ const (
 	ProfileEditFirstnameCallback = ProfilePrefix + "edit_firstname"
 	ProfileEditLastnameCallback  = ProfilePrefix + "edit_lastname"
 
+	ProfileToggleHideInSummariesCallback = ProfilePrefix + "toggle_hide_in_summaries"
+
 	ProfileStartCallback = ProfilePrefix + "start"
 	ProfileFullCancel    = "full_cancel" + ProfilePrefix
 )
+
+// Callback data constants for catchup handler
+const (
+	CatchupPrefix               = "catchup_"
+	CatchupTopicCallbackPrefix  = CatchupPrefix + "topic_"
+	CatchupAllTopicsCallback    = CatchupPrefix + "all_topics"
+	CatchupTopicsDoneCallback   = CatchupPrefix + "topics_done"
+	CatchupPeriodSinceLastMsg   = CatchupPrefix + "period_since_last_msg"
+	CatchupPeriodLastThreeDays  = CatchupPrefix + "period_last_three_days"
+	CatchupPeriodCustomCallback = CatchupPrefix + "period_custom"
+	CatchupCancelCallback       = CatchupPrefix + "cancel"
+)
+
+// Callback data constants for random coffee handler
+const (
+	CoffeePrefix                       = "coffee_"
+	CoffeePreferencesFormatCallback    = CoffeePrefix + "prefs_format"
+	CoffeePreferencesCityCallback      = CoffeePrefix + "prefs_city"
+	CoffeePreferencesLanguagesCallback = CoffeePrefix + "prefs_languages"
+	CoffeePreferencesInterestsCallback = CoffeePrefix + "prefs_interests"
+	CoffeePreferencesStrictCallback    = CoffeePrefix + "prefs_strict"
+	CoffeeHistoryCallback              = CoffeePrefix + "history"
+	CoffeeSubscriptionCallback         = CoffeePrefix + "subscription"
+	CoffeeSubscribeCallback            = CoffeePrefix + "subscribe"
+	CoffeeUnsubscribeCallback          = CoffeePrefix + "unsubscribe"
+	CoffeeFrequencyCallback            = CoffeePrefix + "frequency"
+	CoffeePauseCallbackPrefix          = CoffeePrefix + "pause_"
+	CoffeeResumeCallback               = CoffeePrefix + "resume"
+	CoffeeExclusionsCallback           = CoffeePrefix + "exclusions"
+	CoffeeExclusionsAddCallback        = CoffeePrefix + "exclusions_add"
+	CoffeeExclusionsRemovePrefix       = CoffeePrefix + "exclusions_remove_"
+	CoffeeBackCallback                 = CoffeePrefix + "back"
+	CoffeeCloseCallback                = CoffeePrefix + "close"
+
+	CoffeeFeedbackPrefix               = CoffeePrefix + "feedback_"
+	CoffeeFeedbackStatusCallbackPrefix = CoffeeFeedbackPrefix + "status_"
+	CoffeeFeedbackRateCallbackPrefix   = CoffeeFeedbackPrefix + "rate_"
+
+	// The pair ID follows the rematch prefixes
+	CoffeeRematchPrefix                = CoffeePrefix + "rematch_"
+	CoffeeRematchAskCallbackPrefix     = CoffeeRematchPrefix + "ask_"
+	CoffeeRematchConfirmCallbackPrefix = CoffeeRematchPrefix + "confirm_"
+	CoffeeRematchCancelCallback        = CoffeeRematchPrefix + "cancel"
+)
+
+// Events Handler callback constants, the event ID or the event type follows the "event" and "type" prefixes
+const (
+	EventsPrefix                           = "events_"
+	EventsSubscribePrefix                  = EventsPrefix + "sub_"
+	EventsSubscribeEventCallbackPrefix     = EventsSubscribePrefix + "event_"
+	EventsSubscribeEventTypeCallbackPrefix = EventsSubscribePrefix + "type_"
+
+	EventsCalendarPrefix              = EventsPrefix + "ics_"
+	EventsCalendarEventCallbackPrefix = EventsCalendarPrefix + "event_"
+	EventsCalendarAllCallback         = EventsCalendarPrefix + "all"
+	EventsCalendarFeedCallback        = EventsCalendarPrefix + "feed"
+	EventsCalendarFeedResetCallback   = EventsCalendarPrefix + "feed_reset"
+)
+
+// Archive Handler callback constants, "<eventType>_<page>" follows the type prefix, the material ID follows the document prefix
+const (
+	ArchivePrefix                 = "archive_"
+	ArchiveTypesCallback          = ArchivePrefix + "types"
+	ArchiveTypeCallbackPrefix     = ArchivePrefix + "type_"
+	ArchiveDocumentCallbackPrefix = ArchivePrefix + "doc_"
+)
+
+// Event RSVP callback constant, "<eventID>_<status>" follows the prefix
+const EventRsvpCallbackPrefix = "event_rsvp_"
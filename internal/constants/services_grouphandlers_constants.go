@@ -7,3 +7,6 @@ const AdminSaveMessage_ReplyDeleteMessageCommand = "delete"
 // save_message_service
 const ServiceSaveMessage_MessageDeleteEnCommand = "delete"
 const ServiceSaveMessage_MessageDeleteRuCommand = "удалить"
+
+// tldr_service
+const ServiceTldr_Command = "/tldr"
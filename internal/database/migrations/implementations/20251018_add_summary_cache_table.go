@@ -0,0 +1,43 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddSummaryCacheTable struct {
+	BaseMigration
+}
+
+func NewAddSummaryCacheTable() *AddSummaryCacheTable {
+	return &AddSummaryCacheTable{
+		BaseMigration: BaseMigration{
+			name:      "add_summary_cache_table",
+			timestamp: "20251018",
+		},
+	}
+}
+
+func (m *AddSummaryCacheTable) Apply(db *sql.DB) error {
+	sql := `
+	CREATE TABLE IF NOT EXISTS summary_cache (
+		id SERIAL PRIMARY KEY,
+		topic_id BIGINT NOT NULL,
+		period_from TIMESTAMPTZ NOT NULL,
+		period_to TIMESTAMPTZ NOT NULL,
+		first_message_id BIGINT NOT NULL,
+		last_message_id BIGINT NOT NULL,
+		summary TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		UNIQUE(topic_id, period_from, period_to)
+	);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddSummaryCacheTable) Rollback(db *sql.DB) error {
+	sql := `DROP TABLE IF EXISTS summary_cache;`
+	_, err := db.Exec(sql)
+	return err
+}
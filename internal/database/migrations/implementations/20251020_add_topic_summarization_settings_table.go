@@ -0,0 +1,42 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddTopicSummarizationSettingsTable struct {
+	BaseMigration
+}
+
+func NewAddTopicSummarizationSettingsTable() *AddTopicSummarizationSettingsTable {
+	return &AddTopicSummarizationSettingsTable{
+		BaseMigration: BaseMigration{
+			name:      "add_topic_summarization_settings_table",
+			timestamp: "20251020",
+		},
+	}
+}
+
+func (m *AddTopicSummarizationSettingsTable) Apply(db *sql.DB) error {
+	sql := `
+	CREATE TABLE IF NOT EXISTS topic_summarization_settings (
+		id SERIAL PRIMARY KEY,
+		topic_id BIGINT NOT NULL UNIQUE,
+		enabled BOOLEAN NOT NULL DEFAULT TRUE,
+		summary_time TEXT NOT NULL,
+		prompt_template_key TEXT NOT NULL,
+		min_messages INTEGER NOT NULL DEFAULT 1,
+		destination_topic_id BIGINT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddTopicSummarizationSettingsTable) Rollback(db *sql.DB) error {
+	sql := `DROP TABLE IF EXISTS topic_summarization_settings;`
+	_, err := db.Exec(sql)
+	return err
+}
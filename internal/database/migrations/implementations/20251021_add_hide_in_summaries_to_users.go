@@ -0,0 +1,30 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddHideInSummariesToUsers struct {
+	BaseMigration
+}
+
+func NewAddHideInSummariesToUsers() *AddHideInSummariesToUsers {
+	return &AddHideInSummariesToUsers{
+		BaseMigration: BaseMigration{
+			name:      "add_hide_in_summaries_to_users",
+			timestamp: "20251021",
+		},
+	}
+}
+
+func (m *AddHideInSummariesToUsers) Apply(db *sql.DB) error {
+	sql := `ALTER TABLE users ADD COLUMN hide_in_summaries BOOLEAN NOT NULL DEFAULT FALSE`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddHideInSummariesToUsers) Rollback(db *sql.DB) error {
+	sql := `ALTER TABLE users DROP COLUMN IF EXISTS hide_in_summaries`
+	_, err := db.Exec(sql)
+	return err
+}
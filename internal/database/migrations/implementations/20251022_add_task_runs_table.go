@@ -0,0 +1,43 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddTaskRunsTable struct {
+	BaseMigration
+}
+
+func NewAddTaskRunsTable() *AddTaskRunsTable {
+	return &AddTaskRunsTable{
+		BaseMigration: BaseMigration{
+			name:      "add_task_runs_table",
+			timestamp: "20251022",
+		},
+	}
+}
+
+func (m *AddTaskRunsTable) Apply(db *sql.DB) error {
+	sql := `
+	CREATE TABLE IF NOT EXISTS task_runs (
+		id SERIAL PRIMARY KEY,
+		task_name TEXT NOT NULL,
+		scheduled_for TIMESTAMPTZ NOT NULL,
+		started_at TIMESTAMPTZ,
+		finished_at TIMESTAMPTZ,
+		status TEXT NOT NULL,
+		error TEXT,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		UNIQUE(task_name, scheduled_for)
+	);
+	CREATE INDEX IF NOT EXISTS idx_task_runs_task_name_scheduled_for ON task_runs (task_name, scheduled_for DESC);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddTaskRunsTable) Rollback(db *sql.DB) error {
+	sql := `DROP TABLE IF EXISTS task_runs;`
+	_, err := db.Exec(sql)
+	return err
+}
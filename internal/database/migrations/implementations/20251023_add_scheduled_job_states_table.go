@@ -0,0 +1,37 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddScheduledJobStatesTable struct {
+	BaseMigration
+}
+
+func NewAddScheduledJobStatesTable() *AddScheduledJobStatesTable {
+	return &AddScheduledJobStatesTable{
+		BaseMigration: BaseMigration{
+			name:      "add_scheduled_job_states_table",
+			timestamp: "20251023",
+		},
+	}
+}
+
+func (m *AddScheduledJobStatesTable) Apply(db *sql.DB) error {
+	sql := `
+	CREATE TABLE IF NOT EXISTS scheduled_job_states (
+		job_name TEXT PRIMARY KEY,
+		paused BOOLEAN NOT NULL DEFAULT FALSE,
+		skip_scheduled_for TIMESTAMPTZ,
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddScheduledJobStatesTable) Rollback(db *sql.DB) error {
+	sql := `DROP TABLE IF EXISTS scheduled_job_states;`
+	_, err := db.Exec(sql)
+	return err
+}
@@ -0,0 +1,34 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddUser3ToRandomCoffeePairs struct {
+	BaseMigration
+}
+
+func NewAddUser3ToRandomCoffeePairs() *AddUser3ToRandomCoffeePairs {
+	return &AddUser3ToRandomCoffeePairs{
+		BaseMigration: BaseMigration{
+			name:      "add_user3_to_random_coffee_pairs",
+			timestamp: "20251024",
+		},
+	}
+}
+
+func (m *AddUser3ToRandomCoffeePairs) Apply(db *sql.DB) error {
+	// A pair with user3_id set is a group of three, where every two members are considered to have met
+	sql := `ALTER TABLE random_coffee_pairs ADD COLUMN user3_id INTEGER REFERENCES users(id) ON DELETE CASCADE`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddUser3ToRandomCoffeePairs) Rollback(db *sql.DB) error {
+	sql := `
+	DELETE FROM random_coffee_pairs WHERE user3_id IS NOT NULL;
+	ALTER TABLE random_coffee_pairs DROP COLUMN IF EXISTS user3_id;
+	`
+	_, err := db.Exec(sql)
+	return err
+}
@@ -0,0 +1,41 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddRandomCoffeePreferencesTable struct {
+	BaseMigration
+}
+
+func NewAddRandomCoffeePreferencesTable() *AddRandomCoffeePreferencesTable {
+	return &AddRandomCoffeePreferencesTable{
+		BaseMigration: BaseMigration{
+			name:      "add_random_coffee_preferences_table",
+			timestamp: "20251025",
+		},
+	}
+}
+
+func (m *AddRandomCoffeePreferencesTable) Apply(db *sql.DB) error {
+	sql := `
+	CREATE TABLE IF NOT EXISTS random_coffee_preferences (
+		user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		meeting_format TEXT NOT NULL DEFAULT 'any' CHECK (meeting_format IN ('any', 'online', 'offline')),
+		city TEXT NOT NULL DEFAULT '',
+		languages TEXT[] NOT NULL DEFAULT '{}',
+		interests TEXT[] NOT NULL DEFAULT '{}',
+		strict BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddRandomCoffeePreferencesTable) Rollback(db *sql.DB) error {
+	sql := `DROP TABLE IF EXISTS random_coffee_preferences;`
+	_, err := db.Exec(sql)
+	return err
+}
@@ -0,0 +1,41 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddRandomCoffeeFeedbackTable struct {
+	BaseMigration
+}
+
+func NewAddRandomCoffeeFeedbackTable() *AddRandomCoffeeFeedbackTable {
+	return &AddRandomCoffeeFeedbackTable{
+		BaseMigration: BaseMigration{
+			name:      "add_random_coffee_feedback_table",
+			timestamp: "20251026",
+		},
+	}
+}
+
+func (m *AddRandomCoffeeFeedbackTable) Apply(db *sql.DB) error {
+	sql := `
+	CREATE TABLE IF NOT EXISTS random_coffee_feedback (
+		id SERIAL PRIMARY KEY,
+		pair_id INTEGER NOT NULL REFERENCES random_coffee_pairs(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		status TEXT CHECK (status IN ('met', 'planned', 'no_response')),
+		rating SMALLINT CHECK (rating BETWEEN 1 AND 5),
+		requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		answered_at TIMESTAMPTZ,
+		UNIQUE (pair_id, user_id)
+	);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddRandomCoffeeFeedbackTable) Rollback(db *sql.DB) error {
+	sql := `DROP TABLE IF EXISTS random_coffee_feedback;`
+	_, err := db.Exec(sql)
+	return err
+}
@@ -0,0 +1,34 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddIsDraftToRandomCoffeePairs struct {
+	BaseMigration
+}
+
+func NewAddIsDraftToRandomCoffeePairs() *AddIsDraftToRandomCoffeePairs {
+	return &AddIsDraftToRandomCoffeePairs{
+		BaseMigration: BaseMigration{
+			name:      "add_is_draft_to_random_coffee_pairs",
+			timestamp: "20251027",
+		},
+	}
+}
+
+func (m *AddIsDraftToRandomCoffeePairs) Apply(db *sql.DB) error {
+	// Draft pairs wait for admin approval and are not counted as met until published
+	sql := `ALTER TABLE random_coffee_pairs ADD COLUMN is_draft BOOLEAN NOT NULL DEFAULT FALSE`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddIsDraftToRandomCoffeePairs) Rollback(db *sql.DB) error {
+	sql := `
+	DELETE FROM random_coffee_pairs WHERE is_draft = TRUE;
+	ALTER TABLE random_coffee_pairs DROP COLUMN IF EXISTS is_draft;
+	`
+	_, err := db.Exec(sql)
+	return err
+}
@@ -0,0 +1,40 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddRandomCoffeeSubscriptionsTable struct {
+	BaseMigration
+}
+
+func NewAddRandomCoffeeSubscriptionsTable() *AddRandomCoffeeSubscriptionsTable {
+	return &AddRandomCoffeeSubscriptionsTable{
+		BaseMigration: BaseMigration{
+			name:      "add_random_coffee_subscriptions_table",
+			timestamp: "20251028",
+		},
+	}
+}
+
+func (m *AddRandomCoffeeSubscriptionsTable) Apply(db *sql.DB) error {
+	// start_week is the first round of the subscription, biweekly subscribers join every second round from it
+	sql := `
+	CREATE TABLE IF NOT EXISTS random_coffee_subscriptions (
+		user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		frequency TEXT NOT NULL DEFAULT 'weekly' CHECK (frequency IN ('weekly', 'biweekly')),
+		start_week DATE NOT NULL,
+		paused_until DATE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddRandomCoffeeSubscriptionsTable) Rollback(db *sql.DB) error {
+	sql := `DROP TABLE IF EXISTS random_coffee_subscriptions;`
+	_, err := db.Exec(sql)
+	return err
+}
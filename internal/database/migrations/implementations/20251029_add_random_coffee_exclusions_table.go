@@ -0,0 +1,41 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddRandomCoffeeExclusionsTable struct {
+	BaseMigration
+}
+
+func NewAddRandomCoffeeExclusionsTable() *AddRandomCoffeeExclusionsTable {
+	return &AddRandomCoffeeExclusionsTable{
+		BaseMigration: BaseMigration{
+			name:      "add_random_coffee_exclusions_table",
+			timestamp: "20251029",
+		},
+	}
+}
+
+func (m *AddRandomCoffeeExclusionsTable) Apply(db *sql.DB) error {
+	// A user never gets paired with the users from own exclusion list, it works both ways
+	sql := `
+	CREATE TABLE IF NOT EXISTS random_coffee_exclusions (
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		excluded_user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (user_id, excluded_user_id),
+		CHECK (user_id <> excluded_user_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_random_coffee_exclusions_excluded_user_id ON random_coffee_exclusions(excluded_user_id);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddRandomCoffeeExclusionsTable) Rollback(db *sql.DB) error {
+	sql := `DROP TABLE IF EXISTS random_coffee_exclusions;`
+	_, err := db.Exec(sql)
+	return err
+}
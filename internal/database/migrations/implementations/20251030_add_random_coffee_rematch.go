@@ -0,0 +1,53 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddRandomCoffeeRematch struct {
+	BaseMigration
+}
+
+func NewAddRandomCoffeeRematch() *AddRandomCoffeeRematch {
+	return &AddRandomCoffeeRematch{
+		BaseMigration: BaseMigration{
+			name:      "add_random_coffee_rematch",
+			timestamp: "20251030",
+		},
+	}
+}
+
+func (m *AddRandomCoffeeRematch) Apply(db *sql.DB) error {
+	// A failed pair didn't meet because a partner was silent, a supplementary pair replaces it in the middle of the week.
+	// A rematch request waits for a partner until rematch_pair_id is set.
+	sql := `
+	ALTER TABLE random_coffee_pairs
+	ADD COLUMN IF NOT EXISTS is_failed BOOLEAN NOT NULL DEFAULT FALSE,
+	ADD COLUMN IF NOT EXISTS is_supplementary BOOLEAN NOT NULL DEFAULT FALSE;
+
+	CREATE TABLE IF NOT EXISTS random_coffee_rematch_requests (
+		pair_id INTEGER NOT NULL REFERENCES random_coffee_pairs(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		poll_id INTEGER NOT NULL REFERENCES random_coffee_polls(id) ON DELETE CASCADE,
+		rematch_pair_id INTEGER NULL REFERENCES random_coffee_pairs(id) ON DELETE SET NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (pair_id, user_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_random_coffee_rematch_requests_poll_id ON random_coffee_rematch_requests(poll_id);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddRandomCoffeeRematch) Rollback(db *sql.DB) error {
+	sql := `
+	DROP TABLE IF EXISTS random_coffee_rematch_requests;
+
+	ALTER TABLE random_coffee_pairs
+	DROP COLUMN IF EXISTS is_failed,
+	DROP COLUMN IF EXISTS is_supplementary;
+	`
+	_, err := db.Exec(sql)
+	return err
+}
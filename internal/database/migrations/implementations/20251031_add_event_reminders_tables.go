@@ -0,0 +1,55 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddEventRemindersTables struct {
+	BaseMigration
+}
+
+func NewAddEventRemindersTables() *AddEventRemindersTables {
+	return &AddEventRemindersTables{
+		BaseMigration: BaseMigration{
+			name:      "add_event_reminders_tables",
+			timestamp: "20251031",
+		},
+	}
+}
+
+func (m *AddEventRemindersTables) Apply(db *sql.DB) error {
+	// A subscription is either to a single event or to all events of a type.
+	// A reminder row is claimed before sending, so a reminder for the same start is never sent twice.
+	sql := `
+	CREATE TABLE IF NOT EXISTS event_subscriptions (
+		id SERIAL PRIMARY KEY,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		event_id INTEGER NULL REFERENCES events(id) ON DELETE CASCADE,
+		event_type TEXT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CHECK ((event_id IS NULL) <> (event_type IS NULL))
+	);
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_event_subscriptions_user_event ON event_subscriptions(user_id, event_id) WHERE event_id IS NOT NULL;
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_event_subscriptions_user_type ON event_subscriptions(user_id, event_type) WHERE event_type IS NOT NULL;
+
+	CREATE TABLE IF NOT EXISTS event_reminders (
+		event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+		offset_minutes INTEGER NOT NULL,
+		started_at TIMESTAMPTZ NOT NULL,
+		sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (event_id, offset_minutes, started_at)
+	);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddEventRemindersTables) Rollback(db *sql.DB) error {
+	sql := `
+	DROP TABLE IF EXISTS event_reminders;
+	DROP TABLE IF EXISTS event_subscriptions;
+	`
+	_, err := db.Exec(sql)
+	return err
+}
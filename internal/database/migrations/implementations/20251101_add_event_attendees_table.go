@@ -0,0 +1,50 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddEventAttendeesTable struct {
+	BaseMigration
+}
+
+func NewAddEventAttendeesTable() *AddEventAttendeesTable {
+	return &AddEventAttendeesTable{
+		BaseMigration: BaseMigration{
+			name:      "add_event_attendees_table",
+			timestamp: "20251101",
+		},
+	}
+}
+
+func (m *AddEventAttendeesTable) Apply(db *sql.DB) error {
+	// An event without capacity is unlimited.
+	// The waitlist is ordered by responded_at, it's updated only when the status changes.
+	sql := `
+	ALTER TABLE events
+	ADD COLUMN IF NOT EXISTS capacity INTEGER NULL CHECK (capacity > 0);
+
+	CREATE TABLE IF NOT EXISTS event_attendees (
+		event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+		user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+		status TEXT NOT NULL CHECK (status IN ('going', 'waitlisted', 'maybe', 'not_going')),
+		responded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (event_id, user_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_event_attendees_event_status ON event_attendees(event_id, status, responded_at);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddEventAttendeesTable) Rollback(db *sql.DB) error {
+	sql := `
+	DROP TABLE IF EXISTS event_attendees;
+
+	ALTER TABLE events
+	DROP COLUMN IF EXISTS capacity;
+	`
+	_, err := db.Exec(sql)
+	return err
+}
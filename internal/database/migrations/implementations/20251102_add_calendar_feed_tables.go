@@ -0,0 +1,50 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddCalendarFeedTables struct {
+	BaseMigration
+}
+
+func NewAddCalendarFeedTables() *AddCalendarFeedTables {
+	return &AddCalendarFeedTables{
+		BaseMigration: BaseMigration{
+			name:      "add_calendar_feed_tables",
+			timestamp: "20251102",
+		},
+	}
+}
+
+func (m *AddCalendarFeedTables) Apply(db *sql.DB) error {
+	// A feed token is a secret part of the personal calendar feed URL.
+	// A deleted event leaves a cancellation, so calendars subscribed to the feed remove it.
+	sql := `
+	CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+		user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+		token TEXT NOT NULL UNIQUE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+
+	CREATE TABLE IF NOT EXISTS event_cancellations (
+		event_id INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		started_at TIMESTAMPTZ NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL,
+		cancelled_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddCalendarFeedTables) Rollback(db *sql.DB) error {
+	sql := `
+	DROP TABLE IF EXISTS event_cancellations;
+	DROP TABLE IF EXISTS calendar_feed_tokens;
+	`
+	_, err := db.Exec(sql)
+	return err
+}
@@ -0,0 +1,58 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddEventSeriesTable struct {
+	BaseMigration
+}
+
+func NewAddEventSeriesTable() *AddEventSeriesTable {
+	return &AddEventSeriesTable{
+		BaseMigration: BaseMigration{
+			name:      "add_event_series_table",
+			timestamp: "20251103",
+		},
+	}
+}
+
+func (m *AddEventSeriesTable) Apply(db *sql.DB) error {
+	// A series repeats weekly from starts_at until the end date or the number of occurrences.
+	// Occurrences are regular events, generated_count tracks the generated ones, so a deleted occurrence isn't recreated.
+	sql := `
+	CREATE TABLE IF NOT EXISTS event_series (
+		id SERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		type TEXT NOT NULL,
+		starts_at TIMESTAMPTZ NOT NULL,
+		until_date DATE NULL,
+		occurrences_count INTEGER NULL CHECK (occurrences_count > 0),
+		capacity INTEGER NULL CHECK (capacity > 0),
+		generated_count INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CHECK ((until_date IS NULL) <> (occurrences_count IS NULL))
+	);
+
+	ALTER TABLE events
+	ADD COLUMN IF NOT EXISTS series_id INTEGER NULL REFERENCES event_series(id) ON DELETE SET NULL,
+	ADD COLUMN IF NOT EXISTS series_index INTEGER NULL;
+
+	CREATE UNIQUE INDEX IF NOT EXISTS idx_events_series_occurrence ON events(series_id, series_index) WHERE series_id IS NOT NULL;
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddEventSeriesTable) Rollback(db *sql.DB) error {
+	sql := `
+	ALTER TABLE events
+	DROP COLUMN IF EXISTS series_index,
+	DROP COLUMN IF EXISTS series_id;
+
+	DROP TABLE IF EXISTS event_series;
+	`
+	_, err := db.Exec(sql)
+	return err
+}
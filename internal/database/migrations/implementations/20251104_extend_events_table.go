@@ -0,0 +1,75 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type ExtendEventsTable struct {
+	BaseMigration
+}
+
+func NewExtendEventsTable() *ExtendEventsTable {
+	return &ExtendEventsTable{
+		BaseMigration: BaseMigration{
+			name:      "extend_events_table",
+			timestamp: "20251104",
+		},
+	}
+}
+
+func (m *ExtendEventsTable) Apply(db *sql.DB) error {
+	// Event details are copied from a series to its occurrences, so both tables get them.
+	// The 'actual' status becomes 'planned', 'live' and 'cancelled' are added to the lifecycle.
+	sql := `
+	ALTER TABLE events
+	ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS speaker_user_ids INTEGER[] NOT NULL DEFAULT '{}',
+	ADD COLUMN IF NOT EXISTS duration_minutes INTEGER NULL CHECK (duration_minutes > 0),
+	ADD COLUMN IF NOT EXISTS location TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS cover_file_id TEXT NOT NULL DEFAULT '';
+
+	ALTER TABLE event_series
+	ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS speaker_user_ids INTEGER[] NOT NULL DEFAULT '{}',
+	ADD COLUMN IF NOT EXISTS duration_minutes INTEGER NULL CHECK (duration_minutes > 0),
+	ADD COLUMN IF NOT EXISTS location TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS cover_file_id TEXT NOT NULL DEFAULT '';
+
+	ALTER TABLE events DROP CONSTRAINT IF EXISTS events_status_check;
+	UPDATE events SET status = 'planned' WHERE status = 'actual';
+	ALTER TABLE events ALTER COLUMN status SET DEFAULT 'planned';
+	ALTER TABLE events ADD CONSTRAINT events_status_check CHECK (status IN ('planned', 'live', 'finished', 'cancelled'));
+
+	CREATE INDEX IF NOT EXISTS idx_events_status_started_at ON events(status, started_at);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *ExtendEventsTable) Rollback(db *sql.DB) error {
+	sql := `
+	DROP INDEX IF EXISTS idx_events_status_started_at;
+
+	ALTER TABLE events DROP CONSTRAINT IF EXISTS events_status_check;
+	UPDATE events SET status = 'actual' WHERE status IN ('planned', 'live');
+	UPDATE events SET status = 'finished' WHERE status = 'cancelled';
+	ALTER TABLE events ALTER COLUMN status SET DEFAULT 'actual';
+	ALTER TABLE events ADD CONSTRAINT events_status_check CHECK (status IN ('finished', 'actual'));
+
+	ALTER TABLE event_series
+	DROP COLUMN IF EXISTS cover_file_id,
+	DROP COLUMN IF EXISTS location,
+	DROP COLUMN IF EXISTS duration_minutes,
+	DROP COLUMN IF EXISTS speaker_user_ids,
+	DROP COLUMN IF EXISTS description;
+
+	ALTER TABLE events
+	DROP COLUMN IF EXISTS cover_file_id,
+	DROP COLUMN IF EXISTS location,
+	DROP COLUMN IF EXISTS duration_minutes,
+	DROP COLUMN IF EXISTS speaker_user_ids,
+	DROP COLUMN IF EXISTS description;
+	`
+	_, err := db.Exec(sql)
+	return err
+}
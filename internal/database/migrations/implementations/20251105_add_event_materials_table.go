@@ -0,0 +1,50 @@
+package implementations
+
+import (
+	"database/sql"
+)
+
+type AddEventMaterialsTable struct {
+	BaseMigration
+}
+
+func NewAddEventMaterialsTable() *AddEventMaterialsTable {
+	return &AddEventMaterialsTable{
+		BaseMigration: BaseMigration{
+			name:      "add_event_materials_table",
+			timestamp: "20251105",
+		},
+	}
+}
+
+func (m *AddEventMaterialsTable) Apply(db *sql.DB) error {
+	// Materials of finished events: links to recordings, documents sent to the bot and text notes.
+	// A link keeps its URL, a document keeps the Telegram file ID, a note keeps its text.
+	sql := `
+	CREATE TABLE IF NOT EXISTS event_materials (
+		id SERIAL PRIMARY KEY,
+		event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+		type TEXT NOT NULL CHECK (type IN ('link', 'document', 'note')),
+		title TEXT NOT NULL DEFAULT '',
+		url TEXT NOT NULL DEFAULT '',
+		file_id TEXT NOT NULL DEFAULT '',
+		note TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		CHECK (
+			(type = 'link' AND url <> '') OR
+			(type = 'document' AND file_id <> '') OR
+			(type = 'note' AND note <> '')
+		)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_event_materials_event_id ON event_materials(event_id);
+	`
+	_, err := db.Exec(sql)
+	return err
+}
+
+func (m *AddEventMaterialsTable) Rollback(db *sql.DB) error {
+	sql := `DROP TABLE IF EXISTS event_materials;`
+	_, err := db.Exec(sql)
+	return err
+}
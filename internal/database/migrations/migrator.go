@@ -47,6 +47,24 @@ func Registry() []implementations.Migration {
 		implementations.NewAddGroupTopicsTable(),
 		implementations.NewAddGroupMessagesTable(),
 		implementations.NewRemoveTgSessionsTable(),
+		implementations.NewAddSummaryCacheTable(),
+		implementations.NewAddTopicSummarizationSettingsTable(),
+		implementations.NewAddHideInSummariesToUsers(),
+		implementations.NewAddTaskRunsTable(),
+		implementations.NewAddScheduledJobStatesTable(),
+		implementations.NewAddUser3ToRandomCoffeePairs(),
+		implementations.NewAddRandomCoffeePreferencesTable(),
+		implementations.NewAddRandomCoffeeFeedbackTable(),
+		implementations.NewAddIsDraftToRandomCoffeePairs(),
+		implementations.NewAddRandomCoffeeSubscriptionsTable(),
+		implementations.NewAddRandomCoffeeExclusionsTable(),
+		implementations.NewAddRandomCoffeeRematch(),
+		implementations.NewAddEventRemindersTables(),
+		implementations.NewAddEventAttendeesTable(),
+		implementations.NewAddCalendarFeedTables(),
+		implementations.NewAddEventSeriesTable(),
+		implementations.NewExtendEventsTable(),
+		implementations.NewAddEventMaterialsTable(),
 		// Add new migrations here
 	}
 }
@@ -15,6 +15,9 @@ const DailySummarizationPromptDefaultValue = `Ты - ИИ-ассистент, а
     <li>
         'UserID' - уникальный идентификатор пользователя. позволяет отслеживать сообщения от одного и того же пользователя.
     </li>
+    <li>
+        'Author' - имя автора сообщения. Указывается только для участников, разрешивших упоминать себя в сводках. Может отсутствовать.
+    </li>
     <li>
         'Timestamp' - дата и время отправки сообщения.
     </li>
@@ -41,6 +44,9 @@ const DailySummarizationPromptDefaultValue = `Ты - ИИ-ассистент, а
     <li>
         Внутри описания темы выбирай ключевые слова или словосочетания, и оборачивай их HTML-ссылкой, которая ведет на пообщение с началом обсуждения темы. Ссылка должна иметь вид: 'https://t.me/c/%s/%s/{MessageID}"'. Если в теме было несколько ключевых начальных сообщений - можешь добавить их все в описание темы.
     </li>
+    <li>
+        Упоминать участников по имени можно ТОЛЬКО используя значение поля 'Author' (например: "Иван Петров предложил..."). Если у сообщения нет поля 'Author', не называй его автора и не упоминай 'UserID' в ответе, используй обезличенные формулировки ("один из участников", "участники").
+    </li>
     <li>
         Для форматирования текста внутри описания темы разрешено использовать ТОЛЬКО следующие HTML-теги: "b" для выделения полужирным, "i" для выделения курсивом, "a" для ссылок. Никакие другие HTML-теги использовать нельзя
     </li>
@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/utils"
+	"fmt"
+)
+
+// CalendarFeedTokenRepository handles secret tokens of personal calendar feeds
+type CalendarFeedTokenRepository struct {
+	db *sql.DB
+}
+
+// NewCalendarFeedTokenRepository creates a new CalendarFeedTokenRepository
+func NewCalendarFeedTokenRepository(db *sql.DB) *CalendarFeedTokenRepository {
+	return &CalendarFeedTokenRepository{db: db}
+}
+
+// GetByUserID returns the feed token of the user, false if the user has none yet
+func (r *CalendarFeedTokenRepository) GetByUserID(userID int) (string, bool, error) {
+	var token string
+	err := r.db.QueryRow(`SELECT token FROM calendar_feed_tokens WHERE user_id = $1`, userID).Scan(&token)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("%s: failed to get feed token of user %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+	return token, true, nil
+}
+
+// GetUserIDByToken returns the owner of the feed token, false if the token is unknown
+func (r *CalendarFeedTokenRepository) GetUserIDByToken(token string) (int, bool, error) {
+	var userID int
+	err := r.db.QueryRow(`SELECT user_id FROM calendar_feed_tokens WHERE token = $1`, token).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("%s: failed to get user by feed token: %w", utils.GetCurrentTypeName(), err)
+	}
+	return userID, true, nil
+}
+
+// Set saves the feed token of the user, the previous token stops working
+func (r *CalendarFeedTokenRepository) Set(userID int, token string) error {
+	query := `
+		INSERT INTO calendar_feed_tokens (user_id, token)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token = EXCLUDED.token, created_at = NOW()`
+
+	if _, err := r.db.Exec(query, userID, token); err != nil {
+		return fmt.Errorf("%s: failed to set feed token of user %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"time"
+)
+
+// EventAttendee represents a row in the event_attendees table joined with the user
+type EventAttendee struct {
+	UserID      int
+	TgID        int64
+	Firstname   string
+	Lastname    string
+	TgUsername  string
+	Status      constants.EventAttendeeStatus
+	RespondedAt time.Time
+}
+
+// EventAttendeeCounts holds the number of attendees of an event by RSVP status
+type EventAttendeeCounts struct {
+	Going      int
+	Waitlisted int
+	Maybe      int
+	NotGoing   int
+}
+
+// EventAttendeeRepository handles database operations for RSVP answers of event attendees
+type EventAttendeeRepository struct {
+	db *sql.DB
+}
+
+// NewEventAttendeeRepository creates a new EventAttendeeRepository
+func NewEventAttendeeRepository(db *sql.DB) *EventAttendeeRepository {
+	return &EventAttendeeRepository{db: db}
+}
+
+// GetStatus returns the RSVP status of the user for the event, false if the user hasn't answered
+func (r *EventAttendeeRepository) GetStatus(eventID int, userID int) (constants.EventAttendeeStatus, bool, error) {
+	var status constants.EventAttendeeStatus
+	err := r.db.QueryRow(`SELECT status FROM event_attendees WHERE event_id = $1 AND user_id = $2`, eventID, userID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("%s: failed to get status of user %d for event %d: %w", utils.GetCurrentTypeName(), userID, eventID, err)
+	}
+	return status, true, nil
+}
+
+// SetStatus saves the RSVP status of the user, the answer time is kept when the status doesn't change
+func (r *EventAttendeeRepository) SetStatus(eventID int, userID int, status constants.EventAttendeeStatus) error {
+	query := `
+		INSERT INTO event_attendees (event_id, user_id, status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id, user_id) DO UPDATE
+		SET status = EXCLUDED.status, responded_at = NOW()
+		WHERE event_attendees.status <> EXCLUDED.status`
+
+	if _, err := r.db.Exec(query, eventID, userID, status); err != nil {
+		return fmt.Errorf("%s: failed to set status of user %d for event %d: %w", utils.GetCurrentTypeName(), userID, eventID, err)
+	}
+	return nil
+}
+
+// GetCounts returns the number of attendees of the event by status
+func (r *EventAttendeeRepository) GetCounts(eventID int) (EventAttendeeCounts, error) {
+	var counts EventAttendeeCounts
+
+	rows, err := r.db.Query(`SELECT status, COUNT(*) FROM event_attendees WHERE event_id = $1 GROUP BY status`, eventID)
+	if err != nil {
+		return counts, fmt.Errorf("%s: failed to count attendees of event %d: %w", utils.GetCurrentTypeName(), eventID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status constants.EventAttendeeStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return counts, fmt.Errorf("%s: failed to scan attendee count: %w", utils.GetCurrentTypeName(), err)
+		}
+		switch status {
+		case constants.EventAttendeeStatusGoing:
+			counts.Going = count
+		case constants.EventAttendeeStatusWaitlisted:
+			counts.Waitlisted = count
+		case constants.EventAttendeeStatusMaybe:
+			counts.Maybe = count
+		case constants.EventAttendeeStatusNotGoing:
+			counts.NotGoing = count
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return counts, fmt.Errorf("%s: error during rows iteration for attendee counts: %w", utils.GetCurrentTypeName(), err)
+	}
+	return counts, nil
+}
+
+// GetFirstWaitlisted returns up to limit users from the waitlist of the event, the earliest first
+func (r *EventAttendeeRepository) GetFirstWaitlisted(eventID int, limit int) ([]int, error) {
+	query := `
+		SELECT user_id
+		FROM event_attendees
+		WHERE event_id = $1 AND status = $2
+		ORDER BY responded_at ASC, user_id ASC
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, eventID, constants.EventAttendeeStatusWaitlisted, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query waitlist of event %d: %w", utils.GetCurrentTypeName(), eventID, err)
+	}
+	defer rows.Close()
+
+	var userIDs []int
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan waitlisted user: %w", utils.GetCurrentTypeName(), err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error during rows iteration for waitlist: %w", utils.GetCurrentTypeName(), err)
+	}
+	return userIDs, nil
+}
+
+// GetAttendees returns all answers for the event: going first, then the waitlist in its order, then maybe and not going
+func (r *EventAttendeeRepository) GetAttendees(eventID int) ([]EventAttendee, error) {
+	query := `
+		SELECT u.id, u.tg_id, u.firstname, u.lastname, u.tg_username, ea.status, ea.responded_at
+		FROM event_attendees ea
+		JOIN users u ON u.id = ea.user_id
+		WHERE ea.event_id = $1
+		ORDER BY CASE ea.status
+			WHEN 'going' THEN 1
+			WHEN 'waitlisted' THEN 2
+			WHEN 'maybe' THEN 3
+			ELSE 4
+		END, ea.responded_at ASC, u.id ASC`
+
+	rows, err := r.db.Query(query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query attendees of event %d: %w", utils.GetCurrentTypeName(), eventID, err)
+	}
+	defer rows.Close()
+
+	var attendees []EventAttendee
+	for rows.Next() {
+		var a EventAttendee
+		if err := rows.Scan(&a.UserID, &a.TgID, &a.Firstname, &a.Lastname, &a.TgUsername, &a.Status, &a.RespondedAt); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan attendee row: %w", utils.GetCurrentTypeName(), err)
+		}
+		attendees = append(attendees, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error during rows iteration for attendees: %w", utils.GetCurrentTypeName(), err)
+	}
+	return attendees, nil
+}
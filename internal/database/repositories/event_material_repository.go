@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// EventMaterial represents a row in the event_materials table
+type EventMaterial struct {
+	ID      int
+	EventID int
+	Type    constants.EventMaterialType
+	Title   string
+	// URL is set for a link
+	URL string
+	// FileID is the Telegram file ID of a document
+	FileID string
+	// Note is the text of a note
+	Note      string
+	CreatedAt time.Time
+}
+
+// eventMaterialColumns are the columns of the event_materials table in the order scanEventMaterial expects
+const eventMaterialColumns = `id, event_id, type, title, url, file_id, note, created_at`
+
+// EventMaterialRepository handles database operations for materials of finished events
+type EventMaterialRepository struct {
+	db *sql.DB
+}
+
+// NewEventMaterialRepository creates a new EventMaterialRepository
+func NewEventMaterialRepository(db *sql.DB) *EventMaterialRepository {
+	return &EventMaterialRepository{db: db}
+}
+
+// Create inserts a new material of the event, only the field matching the material type has to be set
+func (r *EventMaterialRepository) Create(
+	eventID int,
+	materialType constants.EventMaterialType,
+	title string,
+	url string,
+	fileID string,
+	note string,
+) (int, error) {
+	query := `
+		INSERT INTO event_materials (event_id, type, title, url, file_id, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	var id int
+	err := r.db.QueryRow(query, eventID, materialType, title, url, fileID, note).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to create material of event %d: %w", utils.GetCurrentTypeName(), eventID, err)
+	}
+	return id, nil
+}
+
+// GetByID retrieves a material by its ID
+func (r *EventMaterialRepository) GetByID(id int) (*EventMaterial, error) {
+	query := `SELECT ` + eventMaterialColumns + ` FROM event_materials WHERE id = $1`
+
+	material, err := scanEventMaterial(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%s: no material found with ID %d", utils.GetCurrentTypeName(), id)
+		}
+		return nil, fmt.Errorf("%s: failed to get material by ID %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+	return material, nil
+}
+
+// GetByEventIDs retrieves materials of the events grouped by event ID, in the order they were added
+func (r *EventMaterialRepository) GetByEventIDs(eventIDs []int) (map[int][]EventMaterial, error) {
+	materials := make(map[int][]EventMaterial)
+	if len(eventIDs) == 0 {
+		return materials, nil
+	}
+
+	ids := make([]int64, 0, len(eventIDs))
+	for _, id := range eventIDs {
+		ids = append(ids, int64(id))
+	}
+
+	query := `
+		SELECT ` + eventMaterialColumns + `
+		FROM event_materials
+		WHERE event_id = ANY($1)
+		ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query materials: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		material, err := scanEventMaterial(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan material row: %w", utils.GetCurrentTypeName(), err)
+		}
+		materials[material.EventID] = append(materials[material.EventID], *material)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error during rows iteration for materials: %w", utils.GetCurrentTypeName(), err)
+	}
+	return materials, nil
+}
+
+// Delete removes a material by its ID
+func (r *EventMaterialRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM event_materials WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("%s: failed to delete material %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get rows affected for material %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: no material found with ID %d", utils.GetCurrentTypeName(), id)
+	}
+	return nil
+}
+
+// scanEventMaterial scans a row selected with eventMaterialColumns
+func scanEventMaterial(row interface{ Scan(dest ...any) error }) (*EventMaterial, error) {
+	var material EventMaterial
+	err := row.Scan(
+		&material.ID,
+		&material.EventID,
+		&material.Type,
+		&material.Title,
+		&material.URL,
+		&material.FileID,
+		&material.Note,
+		&material.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &material, nil
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"time"
+)
+
+// EventReminderRepository keeps track of sent event reminders
+type EventReminderRepository struct {
+	db *sql.DB
+}
+
+// NewEventReminderRepository creates a new EventReminderRepository
+func NewEventReminderRepository(db *sql.DB) *EventReminderRepository {
+	return &EventReminderRepository{db: db}
+}
+
+// Claim records the reminder about the event start at the offset before sending it,
+// returns false if it was already claimed. A rescheduled event gets its reminders again.
+func (r *EventReminderRepository) Claim(eventID int, offset time.Duration, startedAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO event_reminders (event_id, offset_minutes, started_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id, offset_minutes, started_at) DO NOTHING`
+
+	result, err := r.db.Exec(query, eventID, int(offset.Minutes()), startedAt)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to claim reminder of event %d: %w", utils.GetCurrentTypeName(), eventID, err)
+	}
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to get number of claimed reminders: %w", utils.GetCurrentTypeName(), err)
+	}
+	return claimed > 0, nil
+}
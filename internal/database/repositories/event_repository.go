@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // Event represents a row in the events table
@@ -16,10 +18,52 @@ type Event struct {
 	Type      string
 	Status    string
 	StartedAt *time.Time
+	// Capacity limits the number of attendees, nil means unlimited
+	Capacity *int
+	// SeriesID and SeriesIndex are set for an occurrence of a recurring event
+	SeriesID    *int
+	SeriesIndex *int
+	EventDetails
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// EventDetails are the optional details shared by events and series, empty values mean not set
+type EventDetails struct {
+	Description string
+	// SpeakerUserIDs are IDs of users (not Telegram IDs) speaking at the event
+	SpeakerUserIDs []int64
+	// DurationMinutes is nil when the duration is unknown
+	DurationMinutes *int
+	// Location is an address or an online meeting link
+	Location string
+	// CoverFileID is the Telegram file ID of the cover image
+	CoverFileID string
+}
+
+// eventColumns are the columns of the events table in the order scanEvent expects
+const eventColumns = `id, name, type, status, started_at, capacity, series_id, series_index,
+		description, speaker_user_ids, duration_minutes, location, cover_file_id, created_at, updated_at`
+
+// activeEventStatuses is the query argument matching events that haven't ended yet
+func activeEventStatuses() any {
+	statuses := make([]string, 0, len(constants.ActiveEventStatuses))
+	for _, status := range constants.ActiveEventStatuses {
+		statuses = append(statuses, string(status))
+	}
+	return pq.Array(statuses)
+}
+
+// EventCancellation represents a row in the event_cancellations table, left by a deleted event
+type EventCancellation struct {
+	EventID     int
+	Name        string
+	Type        string
+	StartedAt   time.Time
+	CreatedAt   time.Time
+	CancelledAt time.Time
+}
+
 // EventRepository handles database operations for events
 type EventRepository struct {
 	db              *sql.DB
@@ -38,7 +82,7 @@ func NewEventRepository(db *sql.DB) *EventRepository {
 func (r *EventRepository) CreateEvent(name string, eventType constants.EventType) (int, error) {
 	var id int
 	query := `INSERT INTO events (name, type, status) VALUES ($1, $2, $3) RETURNING id`
-	err := r.db.QueryRow(query, name, eventType, constants.EventStatusActual).Scan(&id)
+	err := r.db.QueryRow(query, name, eventType, constants.EventStatusPlanned).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("%s: failed to insert event: %w", utils.GetCurrentTypeName(), err)
 	}
@@ -49,23 +93,23 @@ func (r *EventRepository) CreateEvent(name string, eventType constants.EventType
 func (r *EventRepository) CreateEventWithStartedAt(name string, eventType constants.EventType, startedAt time.Time) (int, error) {
 	var id int
 	query := `INSERT INTO events (name, type, status, started_at) VALUES ($1, $2, $3, $4) RETURNING id`
-	err := r.db.QueryRow(query, name, eventType, constants.EventStatusActual, startedAt).Scan(&id)
+	err := r.db.QueryRow(query, name, eventType, constants.EventStatusPlanned, startedAt).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("%s: failed to insert event with started_at: %w", utils.GetCurrentTypeName(), err)
 	}
 	return id, nil
 }
 
-// GetLastActualEvents retrieves the last N actual event records
+// GetLastActualEvents retrieves the last N actual (planned or live) event records
 func (r *EventRepository) GetLastActualEvents(limit int) ([]Event, error) {
 	query := `
-		SELECT id, name, type, status, started_at, created_at, updated_at
+		SELECT ` + eventColumns + `
 		FROM events
-		WHERE status = $1
+		WHERE status = ANY($1)
 		ORDER BY started_at ASC NULLS LAST
 		LIMIT $2`
 
-	rows, err := r.db.Query(query, constants.EventStatusActual, limit)
+	rows, err := r.db.Query(query, activeEventStatuses(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("%s: failed to query last events: %w", utils.GetCurrentTypeName(), err)
 	}
@@ -73,11 +117,41 @@ func (r *EventRepository) GetLastActualEvents(limit int) ([]Event, error) {
 
 	var events []Event
 	for rows.Next() {
-		var e Event
-		if err := rows.Scan(&e.ID, &e.Name, &e.Type, &e.Status, &e.StartedAt, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan event row: %w", utils.GetCurrentTypeName(), err)
+		}
+		events = append(events, *e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error during rows iteration for events: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return events, nil
+}
+
+// GetActualEventsStartingBetween retrieves actual (planned or live) events starting in the (from, to] range, the earliest first
+func (r *EventRepository) GetActualEventsStartingBetween(from, to time.Time) ([]Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE status = ANY($1) AND started_at > $2 AND started_at <= $3
+		ORDER BY started_at ASC`
+
+	rows, err := r.db.Query(query, activeEventStatuses(), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query upcoming events: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
 			return nil, fmt.Errorf("%s: failed to scan event row: %w", utils.GetCurrentTypeName(), err)
 		}
-		events = append(events, e)
+		events = append(events, *e)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -87,10 +161,70 @@ func (r *EventRepository) GetLastActualEvents(limit int) ([]Event, error) {
 	return events, nil
 }
 
+// GetEventsStartingSince retrieves events of any status starting at or after the given time, the earliest first
+func (r *EventRepository) GetEventsStartingSince(since time.Time) ([]Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE started_at >= $1
+		ORDER BY started_at ASC`
+
+	rows, err := r.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query events since %s: %w", utils.GetCurrentTypeName(), since, err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan event row: %w", utils.GetCurrentTypeName(), err)
+		}
+		events = append(events, *e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error during rows iteration for events: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return events, nil
+}
+
+// GetCancellationsSince retrieves cancellations of deleted events that were starting at or after the given time
+func (r *EventRepository) GetCancellationsSince(since time.Time) ([]EventCancellation, error) {
+	query := `
+		SELECT event_id, name, type, started_at, created_at, cancelled_at
+		FROM event_cancellations
+		WHERE started_at >= $1
+		ORDER BY started_at ASC`
+
+	rows, err := r.db.Query(query, since)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query event cancellations since %s: %w", utils.GetCurrentTypeName(), since, err)
+	}
+	defer rows.Close()
+
+	var cancellations []EventCancellation
+	for rows.Next() {
+		var c EventCancellation
+		if err := rows.Scan(&c.EventID, &c.Name, &c.Type, &c.StartedAt, &c.CreatedAt, &c.CancelledAt); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan event cancellation row: %w", utils.GetCurrentTypeName(), err)
+		}
+		cancellations = append(cancellations, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error during rows iteration for event cancellations: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return cancellations, nil
+}
+
 // GetLastEvents retrieves the last N event records
 func (r *EventRepository) GetLastEvents(limit int) ([]Event, error) {
 	query := `
-		SELECT id, name, type, status, started_at, created_at, updated_at
+		SELECT ` + eventColumns + `
 		FROM events
 		ORDER BY started_at DESC NULLS LAST
 		LIMIT $1`
@@ -103,11 +237,88 @@ func (r *EventRepository) GetLastEvents(limit int) ([]Event, error) {
 
 	var events []Event
 	for rows.Next() {
-		var e Event
-		if err := rows.Scan(&e.ID, &e.Name, &e.Type, &e.Status, &e.StartedAt, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan event row: %w", utils.GetCurrentTypeName(), err)
+		}
+		events = append(events, *e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error during rows iteration for events: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return events, nil
+}
+
+// GetFinishedEvents retrieves finished events of the type, the latest first, an empty type matches all types
+func (r *EventRepository) GetFinishedEvents(eventType constants.EventType, limit int, offset int) ([]Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE status = $1 AND ($2 = '' OR type = $2)
+		ORDER BY started_at DESC NULLS LAST, id DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(query, constants.EventStatusFinished, eventType, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query finished events: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan event row: %w", utils.GetCurrentTypeName(), err)
+		}
+		events = append(events, *e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error during rows iteration for events: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return events, nil
+}
+
+// SearchFinishedEventsWithMaterials finds finished events with materials by the words of the query in the event name,
+// description and materials, an event matches if any word matches, the most relevant first
+func (r *EventRepository) SearchFinishedEventsWithMaterials(searchQuery string, limit int) ([]Event, error) {
+	query := `
+		WITH search AS (
+			SELECT replace(plainto_tsquery('russian', $2)::text, ' & ', ' | ')::tsquery AS query
+		),
+		archived AS (
+			SELECT e.id AS event_id, to_tsvector('russian',
+				e.name || ' ' || e.description || ' ' || string_agg(m.title || ' ' || m.note || ' ' || m.url, ' ')
+			) AS document
+			FROM events e
+			JOIN event_materials m ON m.event_id = e.id
+			WHERE e.status = $1
+			GROUP BY e.id
+		)
+		SELECT ` + eventColumns + `
+		FROM events
+		JOIN archived ON archived.event_id = events.id
+		CROSS JOIN search
+		WHERE archived.document @@ search.query
+		ORDER BY ts_rank(archived.document, search.query) DESC, started_at DESC NULLS LAST
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, constants.EventStatusFinished, searchQuery, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to search finished events: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
 			return nil, fmt.Errorf("%s: failed to scan event row: %w", utils.GetCurrentTypeName(), err)
 		}
-		events = append(events, e)
+		events = append(events, *e)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -190,6 +401,74 @@ func (r *EventRepository) UpdateEventType(id int, eventType constants.EventType)
 	return nil
 }
 
+// UpdateEventCapacity updates the capacity of an event record by its ID, nil removes the limit
+func (r *EventRepository) UpdateEventCapacity(id int, capacity *int) error {
+	query := `UPDATE events SET capacity = $1, updated_at = NOW() WHERE id = $2`
+	result, err := r.db.Exec(query, capacity, id)
+	if err != nil {
+		return fmt.Errorf("%s: failed to update event capacity for ID %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("%s: Could not get rows affected after update: %v", utils.GetCurrentTypeName(), err)
+	} else if rowsAffected == 0 {
+		return fmt.Errorf("%s: no event found with ID %d to update capacity", utils.GetCurrentTypeName(), id)
+	}
+
+	return nil
+}
+
+// UpdateEventDetails updates the description, speakers, duration, location and cover of an event record by its ID
+func (r *EventRepository) UpdateEventDetails(id int, details EventDetails) error {
+	query := `
+		UPDATE events
+		SET description = $1, speaker_user_ids = $2, duration_minutes = $3, location = $4, cover_file_id = $5, updated_at = NOW()
+		WHERE id = $6`
+	result, err := r.db.Exec(
+		query,
+		details.Description, pq.Array(details.SpeakerUserIDs), details.DurationMinutes, details.Location, details.CoverFileID, id,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: failed to update event details for ID %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("%s: Could not get rows affected after update: %v", utils.GetCurrentTypeName(), err)
+	} else if rowsAffected == 0 {
+		return fmt.Errorf("%s: no event found with ID %d to update details", utils.GetCurrentTypeName(), id)
+	}
+
+	return nil
+}
+
+// FinishEndedEvents marks planned and live events as finished once their end has passed,
+// defaultDuration is used for events without a duration. Returns the number of finished events.
+func (r *EventRepository) FinishEndedEvents(now time.Time, defaultDuration time.Duration) (int64, error) {
+	// Automatic transitions don't touch updated_at, the event itself doesn't change
+	query := `
+		UPDATE events
+		SET status = $1
+		WHERE status = ANY($2)
+			AND started_at + COALESCE(duration_minutes, $3) * INTERVAL '1 minute' <= $4`
+	result, err := r.db.Exec(query, constants.EventStatusFinished, activeEventStatuses(), int(defaultDuration.Minutes()), now)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to finish ended events: %w", utils.GetCurrentTypeName(), err)
+	}
+	return result.RowsAffected()
+}
+
+// StartDueEvents marks planned events as live once their start has come. Returns the number of started events.
+func (r *EventRepository) StartDueEvents(now time.Time) (int64, error) {
+	query := `UPDATE events SET status = $1 WHERE status = $2 AND started_at <= $3`
+	result, err := r.db.Exec(query, constants.EventStatusLive, constants.EventStatusPlanned, now)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to start due events: %w", utils.GetCurrentTypeName(), err)
+	}
+	return result.RowsAffected()
+}
+
 // DeleteEvent removes an event record from the database by its ID
 func (r *EventRepository) DeleteEvent(id int) error {
 	// First, get all topics related to this event
@@ -205,6 +484,16 @@ func (r *EventRepository) DeleteEvent(id int) error {
 		}
 	}
 
+	// Leave a cancellation, so calendars subscribed to the feed remove the event
+	cancellationQuery := `
+		INSERT INTO event_cancellations (event_id, name, type, started_at, created_at)
+		SELECT id, name, type, started_at, created_at FROM events
+		WHERE id = $1 AND started_at IS NOT NULL
+		ON CONFLICT (event_id) DO NOTHING`
+	if _, err := r.db.Exec(cancellationQuery, id); err != nil {
+		return fmt.Errorf("%s: failed to record cancellation of event with ID %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+
 	// Now delete the event itself
 	query := `DELETE FROM events WHERE id = $1`
 	result, err := r.db.Exec(query, id)
@@ -222,31 +511,127 @@ func (r *EventRepository) DeleteEvent(id int) error {
 	return nil
 }
 
+// CreateSeriesOccurrence inserts an occurrence of a recurring event, false if the occurrence already exists
+func (r *EventRepository) CreateSeriesOccurrence(
+	name string,
+	eventType constants.EventType,
+	startedAt time.Time,
+	capacity *int,
+	details EventDetails,
+	seriesID int,
+	seriesIndex int,
+) (int, bool, error) {
+	query := `
+		INSERT INTO events (name, type, status, started_at, capacity, series_id, series_index,
+			description, speaker_user_ids, duration_minutes, location, cover_file_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (series_id, series_index) WHERE series_id IS NOT NULL DO NOTHING
+		RETURNING id`
+
+	var id int
+	err := r.db.QueryRow(
+		query,
+		name, eventType, constants.EventStatusPlanned, startedAt, capacity, seriesID, seriesIndex,
+		details.Description, pq.Array(details.SpeakerUserIDs), details.DurationMinutes, details.Location, details.CoverFileID,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("%s: failed to insert occurrence %d of event series %d: %w", utils.GetCurrentTypeName(), seriesIndex, seriesID, err)
+	}
+	return id, true, nil
+}
+
+// SetEventSeries attaches an existing event to a series as its occurrence with the index
+func (r *EventRepository) SetEventSeries(id int, seriesID int, seriesIndex int) error {
+	query := `UPDATE events SET series_id = $1, series_index = $2, updated_at = NOW() WHERE id = $3`
+	result, err := r.db.Exec(query, seriesID, seriesIndex, id)
+	if err != nil {
+		return fmt.Errorf("%s: failed to set series for event ID %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("%s: Could not get rows affected after update: %v", utils.GetCurrentTypeName(), err)
+	} else if rowsAffected == 0 {
+		return fmt.Errorf("%s: no event found with ID %d to set series", utils.GetCurrentTypeName(), id)
+	}
+
+	return nil
+}
+
+// GetSeriesOccurrencesFrom retrieves actual (planned or live) occurrences of the series starting from the index, in the series order
+func (r *EventRepository) GetSeriesOccurrencesFrom(seriesID int, fromIndex int) ([]Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE series_id = $1 AND series_index >= $2 AND status = ANY($3)
+		ORDER BY series_index`
+
+	rows, err := r.db.Query(query, seriesID, fromIndex, activeEventStatuses())
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query occurrences of event series %d: %w", utils.GetCurrentTypeName(), seriesID, err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		e, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan event row: %w", utils.GetCurrentTypeName(), err)
+		}
+		events = append(events, *e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error during rows iteration for events: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return events, nil
+}
+
 // GetEventByID retrieves a single event record by its ID
 func (r *EventRepository) GetEventByID(id int) (*Event, error) {
 	query := `
-		SELECT id, name, type, status, started_at, created_at, updated_at
+		SELECT ` + eventColumns + `
 		FROM events
 		WHERE id = $1`
 
+	event, err := scanEvent(r.db.QueryRow(query, id))
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%s: no event found with ID %d", utils.GetCurrentTypeName(), id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get event with ID %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+
+	return event, nil
+}
+
+func scanEvent(row interface{ Scan(dest ...any) error }) (*Event, error) {
 	var event Event
-	err := r.db.QueryRow(query, id).Scan(
+	err := row.Scan(
 		&event.ID,
 		&event.Name,
 		&event.Type,
 		&event.Status,
 		&event.StartedAt,
+		&event.Capacity,
+		&event.SeriesID,
+		&event.SeriesIndex,
+		&event.Description,
+		pq.Array(&event.SpeakerUserIDs),
+		&event.DurationMinutes,
+		&event.Location,
+		&event.CoverFileID,
 		&event.CreatedAt,
 		&event.UpdatedAt,
 	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("%s: no event found with ID %d", utils.GetCurrentTypeName(), id)
-	}
-
 	if err != nil {
-		return nil, fmt.Errorf("%s: failed to get event with ID %d: %w", utils.GetCurrentTypeName(), id, err)
+		return nil, err
 	}
-
 	return &event, nil
 }
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// EventSeries represents a row in the event_series table, a weekly recurring event
+type EventSeries struct {
+	ID   int
+	Name string
+	Type string
+	// StartsAt is the start of the first occurrence, the next ones start on the same weekday and time
+	StartsAt time.Time
+	// Exactly one of UntilDate and OccurrencesCount is set
+	UntilDate        *time.Time
+	OccurrencesCount *int
+	Capacity         *int
+	GeneratedCount   int
+	// EventDetails are copied to every generated occurrence
+	EventDetails
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// eventSeriesColumns are the columns of the event_series table in the order scanEventSeries expects
+const eventSeriesColumns = `id, name, type, starts_at, until_date, occurrences_count, capacity, generated_count,
+		description, speaker_user_ids, duration_minutes, location, cover_file_id, created_at, updated_at`
+
+// EventSeriesRepository handles database operations for recurring events
+type EventSeriesRepository struct {
+	db *sql.DB
+}
+
+// NewEventSeriesRepository creates a new EventSeriesRepository
+func NewEventSeriesRepository(db *sql.DB) *EventSeriesRepository {
+	return &EventSeriesRepository{db: db}
+}
+
+// Create inserts a new series, untilDate is a date in the club timezone
+func (r *EventSeriesRepository) Create(
+	name string,
+	eventType constants.EventType,
+	startsAt time.Time,
+	untilDate *time.Time,
+	occurrencesCount *int,
+	capacity *int,
+	details EventDetails,
+) (int, error) {
+	query := `
+		INSERT INTO event_series (name, type, starts_at, until_date, occurrences_count, capacity,
+			description, speaker_user_ids, duration_minutes, location, cover_file_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id`
+
+	var id int
+	err := r.db.QueryRow(
+		query,
+		name, eventType, startsAt, untilDate, occurrencesCount, capacity,
+		details.Description, pq.Array(details.SpeakerUserIDs), details.DurationMinutes, details.Location, details.CoverFileID,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to insert event series: %w", utils.GetCurrentTypeName(), err)
+	}
+	return id, nil
+}
+
+// GetByID retrieves a series by its ID
+func (r *EventSeriesRepository) GetByID(id int) (*EventSeries, error) {
+	query := `
+		SELECT ` + eventSeriesColumns + `
+		FROM event_series
+		WHERE id = $1`
+
+	series, err := scanEventSeries(r.db.QueryRow(query, id))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get event series %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+	return series, nil
+}
+
+// GetUnfinished retrieves series that haven't generated all their occurrences yet
+func (r *EventSeriesRepository) GetUnfinished() ([]EventSeries, error) {
+	query := `
+		SELECT ` + eventSeriesColumns + `
+		FROM event_series
+		WHERE occurrences_count IS NULL OR generated_count < occurrences_count
+		ORDER BY id`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query unfinished event series: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var seriesList []EventSeries
+	for rows.Next() {
+		series, err := scanEventSeries(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan event series row: %w", utils.GetCurrentTypeName(), err)
+		}
+		seriesList = append(seriesList, *series)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error during rows iteration for event series: %w", utils.GetCurrentTypeName(), err)
+	}
+	return seriesList, nil
+}
+
+// Update saves the name, type, start, capacity and details of the series used for the next occurrences
+func (r *EventSeriesRepository) Update(series EventSeries) error {
+	query := `
+		UPDATE event_series
+		SET name = $1, type = $2, starts_at = $3, capacity = $4,
+			description = $5, speaker_user_ids = $6, duration_minutes = $7, location = $8, cover_file_id = $9, updated_at = NOW()
+		WHERE id = $10`
+
+	_, err := r.db.Exec(
+		query,
+		series.Name, series.Type, series.StartsAt, series.Capacity,
+		series.Description, pq.Array(series.SpeakerUserIDs), series.DurationMinutes, series.Location, series.CoverFileID, series.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: failed to update event series %d: %w", utils.GetCurrentTypeName(), series.ID, err)
+	}
+	return nil
+}
+
+// SetGeneratedCount saves how many occurrences of the series were generated
+func (r *EventSeriesRepository) SetGeneratedCount(id int, generatedCount int) error {
+	query := `UPDATE event_series SET generated_count = $1, updated_at = NOW() WHERE id = $2`
+	if _, err := r.db.Exec(query, generatedCount, id); err != nil {
+		return fmt.Errorf("%s: failed to set generated count of event series %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+	return nil
+}
+
+func scanEventSeries(row interface{ Scan(dest ...any) error }) (*EventSeries, error) {
+	var series EventSeries
+	var untilDate sql.NullTime
+	err := row.Scan(
+		&series.ID,
+		&series.Name,
+		&series.Type,
+		&series.StartsAt,
+		&untilDate,
+		&series.OccurrencesCount,
+		&series.Capacity,
+		&series.GeneratedCount,
+		&series.Description,
+		pq.Array(&series.SpeakerUserIDs),
+		&series.DurationMinutes,
+		&series.Location,
+		&series.CoverFileID,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if untilDate.Valid {
+		series.UntilDate = &untilDate.Time
+	}
+	return &series, nil
+}
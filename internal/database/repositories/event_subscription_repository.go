@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/utils"
+	"fmt"
+)
+
+// EventSubscriptions are the events and event types the user wants reminders about
+type EventSubscriptions struct {
+	EventIDs   map[int]bool
+	EventTypes map[constants.EventType]bool
+}
+
+// EventSubscriptionRepository handles database operations for event reminder subscriptions
+type EventSubscriptionRepository struct {
+	db *sql.DB
+}
+
+// NewEventSubscriptionRepository creates a new EventSubscriptionRepository
+func NewEventSubscriptionRepository(db *sql.DB) *EventSubscriptionRepository {
+	return &EventSubscriptionRepository{db: db}
+}
+
+// GetByUserID retrieves all subscriptions of the user
+func (r *EventSubscriptionRepository) GetByUserID(userID int) (*EventSubscriptions, error) {
+	query := `SELECT event_id, event_type FROM event_subscriptions WHERE user_id = $1`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query event subscriptions of user %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+	defer rows.Close()
+
+	subscriptions := &EventSubscriptions{
+		EventIDs:   make(map[int]bool),
+		EventTypes: make(map[constants.EventType]bool),
+	}
+	for rows.Next() {
+		var eventID sql.NullInt64
+		var eventType sql.NullString
+		if err := rows.Scan(&eventID, &eventType); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan event subscription: %w", utils.GetCurrentTypeName(), err)
+		}
+		if eventID.Valid {
+			subscriptions.EventIDs[int(eventID.Int64)] = true
+		}
+		if eventType.Valid {
+			subscriptions.EventTypes[constants.EventType(eventType.String)] = true
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating event subscriptions rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return subscriptions, nil
+}
+
+// SubscribeToEvent subscribes the user to reminders about the event, subscribing twice does nothing
+func (r *EventSubscriptionRepository) SubscribeToEvent(userID, eventID int) error {
+	query := `
+		INSERT INTO event_subscriptions (user_id, event_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, event_id) WHERE event_id IS NOT NULL DO NOTHING`
+
+	if _, err := r.db.Exec(query, userID, eventID); err != nil {
+		return fmt.Errorf("%s: failed to subscribe user %d to event %d: %w", utils.GetCurrentTypeName(), userID, eventID, err)
+	}
+	return nil
+}
+
+// UnsubscribeFromEvent removes the subscription of the user to the event
+func (r *EventSubscriptionRepository) UnsubscribeFromEvent(userID, eventID int) error {
+	query := `DELETE FROM event_subscriptions WHERE user_id = $1 AND event_id = $2`
+
+	if _, err := r.db.Exec(query, userID, eventID); err != nil {
+		return fmt.Errorf("%s: failed to unsubscribe user %d from event %d: %w", utils.GetCurrentTypeName(), userID, eventID, err)
+	}
+	return nil
+}
+
+// SubscribeToType subscribes the user to reminders about all events of the type, subscribing twice does nothing
+func (r *EventSubscriptionRepository) SubscribeToType(userID int, eventType constants.EventType) error {
+	query := `
+		INSERT INTO event_subscriptions (user_id, event_type)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, event_type) WHERE event_type IS NOT NULL DO NOTHING`
+
+	if _, err := r.db.Exec(query, userID, string(eventType)); err != nil {
+		return fmt.Errorf("%s: failed to subscribe user %d to event type %s: %w", utils.GetCurrentTypeName(), userID, eventType, err)
+	}
+	return nil
+}
+
+// UnsubscribeFromType removes the subscription of the user to the event type
+func (r *EventSubscriptionRepository) UnsubscribeFromType(userID int, eventType constants.EventType) error {
+	query := `DELETE FROM event_subscriptions WHERE user_id = $1 AND event_type = $2`
+
+	if _, err := r.db.Exec(query, userID, string(eventType)); err != nil {
+		return fmt.Errorf("%s: failed to unsubscribe user %d from event type %s: %w", utils.GetCurrentTypeName(), userID, eventType, err)
+	}
+	return nil
+}
+
+// GetSubscribers returns club members subscribed to the event or to its type
+func (r *EventSubscriptionRepository) GetSubscribers(eventID int, eventType constants.EventType) ([]User, error) {
+	query := `
+		SELECT u.id, u.tg_id, u.firstname, u.lastname, u.tg_username
+		FROM users u
+		WHERE u.is_club_member = TRUE
+		AND EXISTS (
+			SELECT 1 FROM event_subscriptions s
+			WHERE s.user_id = u.id AND (s.event_id = $1 OR s.event_type = $2)
+		)
+		ORDER BY u.id`
+
+	rows, err := r.db.Query(query, eventID, string(eventType))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query subscribers of event %d: %w", utils.GetCurrentTypeName(), eventID, err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.TgID, &user.Firstname, &user.Lastname, &user.TgUsername); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan event subscriber: %w", utils.GetCurrentTypeName(), err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating event subscribers rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return users, nil
+}
@@ -267,6 +267,144 @@ func (r *GroupMessageRepository) GetByGroupTopicIdForpreviousTwentyFourHours(gro
 	return messages, nil
 }
 
+// GetByGroupTopicIDForPeriod retrieves group messages by group topic ID created within [from, to)
+func (r *GroupMessageRepository) GetByGroupTopicIDForPeriod(groupTopicID int64, from time.Time, to time.Time) ([]*GroupMessage, error) {
+	query := `
+		SELECT id, message_id, message_text, reply_to_message_id, user_tg_id, group_topic_id, created_at, updated_at
+		FROM group_messages
+		WHERE group_topic_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at ASC`
+
+	rows, err := r.db.Query(query, groupTopicID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get group messages by group topic ID %d for period: %w", utils.GetCurrentTypeName(), groupTopicID, err)
+	}
+	defer rows.Close()
+
+	var messages []*GroupMessage
+	for rows.Next() {
+		var message GroupMessage
+		err := rows.Scan(
+			&message.ID,
+			&message.MessageID,
+			&message.MessageText,
+			&message.ReplyToMessageID,
+			&message.UserTgID,
+			&message.GroupTopicID,
+			&message.CreatedAt,
+			&message.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan group message: %w", utils.GetCurrentTypeName(), err)
+		}
+		messages = append(messages, &message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating group message rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return messages, nil
+}
+
+// GetLastByUserTgID retrieves the most recent group message of the user
+func (r *GroupMessageRepository) GetLastByUserTgID(userTgID int64) (*GroupMessage, error) {
+	query := `
+		SELECT id, message_id, message_text, reply_to_message_id, user_tg_id, group_topic_id, created_at, updated_at
+		FROM group_messages
+		WHERE user_tg_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1`
+
+	var message GroupMessage
+	err := r.db.QueryRow(query, userTgID).Scan(
+		&message.ID,
+		&message.MessageID,
+		&message.MessageText,
+		&message.ReplyToMessageID,
+		&message.UserTgID,
+		&message.GroupTopicID,
+		&message.CreatedAt,
+		&message.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, sql.ErrNoRows
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get last group message of user %d: %w", utils.GetCurrentTypeName(), userTgID, err)
+	}
+
+	return &message, nil
+}
+
+// GetReplyChain retrieves the reply chain around the message within its topic: all its ancestors
+// (messages it replies to, recursively) and all its descendants (replies to it, recursively).
+// The result is ordered by creation time and limited to the given number of the latest messages.
+func (r *GroupMessageRepository) GetReplyChain(messageID int64, groupTopicID int64, limit int) ([]*GroupMessage, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT id, message_id, message_text, reply_to_message_id, user_tg_id, group_topic_id, created_at, updated_at
+			FROM group_messages
+			WHERE message_id = $1 AND group_topic_id = $2
+			UNION
+			SELECT gm.id, gm.message_id, gm.message_text, gm.reply_to_message_id, gm.user_tg_id, gm.group_topic_id, gm.created_at, gm.updated_at
+			FROM group_messages gm
+			INNER JOIN ancestors a ON gm.message_id = a.reply_to_message_id
+			WHERE gm.group_topic_id = $2
+		), descendants AS (
+			SELECT id, message_id, message_text, reply_to_message_id, user_tg_id, group_topic_id, created_at, updated_at
+			FROM group_messages
+			WHERE message_id = $1 AND group_topic_id = $2
+			UNION
+			SELECT gm.id, gm.message_id, gm.message_text, gm.reply_to_message_id, gm.user_tg_id, gm.group_topic_id, gm.created_at, gm.updated_at
+			FROM group_messages gm
+			INNER JOIN descendants d ON gm.reply_to_message_id = d.message_id
+			WHERE gm.group_topic_id = $2
+		)
+		SELECT id, message_id, message_text, reply_to_message_id, user_tg_id, group_topic_id, created_at, updated_at
+		FROM (
+			SELECT * FROM ancestors
+			UNION
+			SELECT * FROM descendants
+			ORDER BY created_at DESC
+			LIMIT $3
+		) chain
+		ORDER BY created_at ASC`
+
+	rows, err := r.db.Query(query, messageID, groupTopicID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get reply chain for message ID %d: %w", utils.GetCurrentTypeName(), messageID, err)
+	}
+	defer rows.Close()
+
+	var messages []*GroupMessage
+	for rows.Next() {
+		var message GroupMessage
+		err := rows.Scan(
+			&message.ID,
+			&message.MessageID,
+			&message.MessageText,
+			&message.ReplyToMessageID,
+			&message.UserTgID,
+			&message.GroupTopicID,
+			&message.CreatedAt,
+			&message.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan group message: %w", utils.GetCurrentTypeName(), err)
+		}
+		messages = append(messages, &message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating group message rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return messages, nil
+}
+
 // Update updates a group message record
 func (r *GroupMessageRepository) Update(id int, messageText string) error {
 	query := `UPDATE group_messages SET message_text = $1, updated_at = NOW() WHERE id = $2`
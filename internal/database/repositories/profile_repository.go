@@ -232,7 +232,7 @@ func (r *ProfileRepository) GetAllActiveWithUserInfo() ([]ProfileWithUser, error
 	query := `
 		SELECT 
 			p.id, p.user_id, p.bio, p.published_message_id, p.created_at, p.updated_at,
-			u.id, u.tg_id, u.firstname, u.lastname, u.tg_username, u.score, u.has_coffee_ban, u.is_club_member, u.created_at, u.updated_at
+			u.id, u.tg_id, u.firstname, u.lastname, u.tg_username, u.score, u.has_coffee_ban, u.is_club_member, u.hide_in_summaries, u.created_at, u.updated_at
 		FROM profiles p
 		INNER JOIN users u ON p.user_id = u.id
 		WHERE p.bio != '' AND p.bio IS NOT NULL AND p.published_message_id IS NOT NULL AND u.is_club_member = true
@@ -264,6 +264,7 @@ func (r *ProfileRepository) GetAllActiveWithUserInfo() ([]ProfileWithUser, error
 			&user.Score,
 			&user.HasCoffeeBan,
 			&user.IsClubMember,
+			&user.HideInSummaries,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
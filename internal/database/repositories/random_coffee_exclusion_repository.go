@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/utils"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// RandomCoffeeExclusionCounts is what admins may know about personal exclusion lists
+type RandomCoffeeExclusionCounts struct {
+	// Users is the number of users with a non-empty exclusion list
+	Users int
+	// Exclusions is the total number of excluded users in all lists
+	Exclusions int
+}
+
+// RandomCoffeeExclusionRepository handles database operations for personal do-not-pair lists
+type RandomCoffeeExclusionRepository struct {
+	db *sql.DB
+}
+
+// NewRandomCoffeeExclusionRepository creates a new RandomCoffeeExclusionRepository
+func NewRandomCoffeeExclusionRepository(db *sql.DB) *RandomCoffeeExclusionRepository {
+	return &RandomCoffeeExclusionRepository{db: db}
+}
+
+// GetExcludedUsers retrieves users from the exclusion list of the user in the order they were added
+func (r *RandomCoffeeExclusionRepository) GetExcludedUsers(userID int) ([]User, error) {
+	query := `
+		SELECT u.id, u.tg_id, u.firstname, u.lastname, u.tg_username
+		FROM random_coffee_exclusions e
+		JOIN users u ON u.id = e.excluded_user_id
+		WHERE e.user_id = $1
+		ORDER BY e.created_at, u.id`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query excluded users of user %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.TgID, &user.Firstname, &user.Lastname, &user.TgUsername); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan excluded user: %w", utils.GetCurrentTypeName(), err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating excluded users rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return users, nil
+}
+
+// Add puts the user into the exclusion list, adding an already excluded user does nothing
+func (r *RandomCoffeeExclusionRepository) Add(userID, excludedUserID int) error {
+	query := `
+		INSERT INTO random_coffee_exclusions (user_id, excluded_user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, excluded_user_id) DO NOTHING`
+
+	if _, err := r.db.Exec(query, userID, excludedUserID); err != nil {
+		return fmt.Errorf("%s: failed to add user %d to exclusions of user %d: %w", utils.GetCurrentTypeName(), excludedUserID, userID, err)
+	}
+	return nil
+}
+
+// Remove deletes the user from the exclusion list
+func (r *RandomCoffeeExclusionRepository) Remove(userID, excludedUserID int) error {
+	query := `DELETE FROM random_coffee_exclusions WHERE user_id = $1 AND excluded_user_id = $2`
+
+	if _, err := r.db.Exec(query, userID, excludedUserID); err != nil {
+		return fmt.Errorf("%s: failed to remove user %d from exclusions of user %d: %w", utils.GetCurrentTypeName(), excludedUserID, userID, err)
+	}
+	return nil
+}
+
+// GetExcludedPairs returns pairs of the given users that must not be matched, keyed by utils.CoffeePairKey
+func (r *RandomCoffeeExclusionRepository) GetExcludedPairs(userIDs []int) (map[[2]int]bool, error) {
+	query := `
+		SELECT user_id, excluded_user_id
+		FROM random_coffee_exclusions
+		WHERE user_id = ANY($1) AND excluded_user_id = ANY($1)`
+
+	rows, err := r.db.Query(query, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query excluded pairs: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	pairs := make(map[[2]int]bool)
+	for rows.Next() {
+		var userID, excludedUserID int
+		if err := rows.Scan(&userID, &excludedUserID); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan excluded pair: %w", utils.GetCurrentTypeName(), err)
+		}
+		pairs[utils.CoffeePairKey(userID, excludedUserID)] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating excluded pairs rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return pairs, nil
+}
+
+// GetCounts returns how many users have exclusion lists and how many exclusions there are, without the contents
+func (r *RandomCoffeeExclusionRepository) GetCounts() (RandomCoffeeExclusionCounts, error) {
+	query := `SELECT COUNT(DISTINCT user_id), COUNT(*) FROM random_coffee_exclusions`
+
+	var counts RandomCoffeeExclusionCounts
+	if err := r.db.QueryRow(query).Scan(&counts.Users, &counts.Exclusions); err != nil {
+		return RandomCoffeeExclusionCounts{}, fmt.Errorf("%s: failed to count exclusions: %w", utils.GetCurrentTypeName(), err)
+	}
+	return counts, nil
+}
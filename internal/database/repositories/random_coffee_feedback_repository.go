@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RandomCoffeeFeedback represents a row in the random_coffee_feedback table
+type RandomCoffeeFeedback struct {
+	ID     int
+	PairID int
+	UserID int
+	// Status is empty until the user answers
+	Status      constants.CoffeeFeedbackStatus
+	Rating      sql.NullInt64
+	RequestedAt time.Time
+	AnsweredAt  sql.NullTime
+}
+
+// RandomCoffeeFeedbackPair is a pair together with the week of its poll
+type RandomCoffeeFeedbackPair struct {
+	RandomCoffeePair
+	WeekStartDate time.Time
+}
+
+// RandomCoffeeReliability counts what partners of a user reported about their meetings
+type RandomCoffeeReliability struct {
+	// Confirmed is the number of reports that the meeting took place or was planned
+	Confirmed int
+	// NoResponse is the number of reports that the user didn't respond
+	NoResponse int
+}
+
+// RandomCoffeeFeedbackRepository handles database operations for random coffee feedback
+type RandomCoffeeFeedbackRepository struct {
+	db *sql.DB
+}
+
+// NewRandomCoffeeFeedbackRepository creates a new RandomCoffeeFeedbackRepository
+func NewRandomCoffeeFeedbackRepository(db *sql.DB) *RandomCoffeeFeedbackRepository {
+	return &RandomCoffeeFeedbackRepository{db: db}
+}
+
+// GetPairsAwaitingFeedback returns pairs of polls with the week start in the given range
+// that have members not asked about the meeting yet
+func (r *RandomCoffeeFeedbackRepository) GetPairsAwaitingFeedback(weekFrom, weekTo time.Time) ([]RandomCoffeeFeedbackPair, error) {
+	query := `
+		SELECT p.id, p.poll_id, p.user1_id, p.user2_id, p.user3_id, p.created_at, poll.week_start_date
+		FROM random_coffee_pairs p
+		JOIN random_coffee_polls poll ON p.poll_id = poll.id
+		WHERE poll.week_start_date BETWEEN $1 AND $2 AND p.is_draft = FALSE AND p.is_failed = FALSE
+		AND EXISTS (
+			SELECT 1 FROM unnest(ARRAY[p.user1_id, p.user2_id, p.user3_id]) AS m(user_id)
+			WHERE m.user_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM random_coffee_feedback f WHERE f.pair_id = p.id AND f.user_id = m.user_id)
+		)
+		ORDER BY p.id`
+
+	rows, err := r.db.Query(query, weekFrom, weekTo)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query pairs awaiting feedback: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var pairs []RandomCoffeeFeedbackPair
+	for rows.Next() {
+		var pair RandomCoffeeFeedbackPair
+		err := rows.Scan(
+			&pair.ID,
+			&pair.PollID,
+			&pair.User1ID,
+			&pair.User2ID,
+			&pair.User3ID,
+			&pair.CreatedAt,
+			&pair.WeekStartDate,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan pair awaiting feedback: %w", utils.GetCurrentTypeName(), err)
+		}
+		pairs = append(pairs, pair)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating pairs awaiting feedback rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return pairs, nil
+}
+
+// CreateRequest records that the user was asked about the meeting, returns 0 if the user was already asked
+func (r *RandomCoffeeFeedbackRepository) CreateRequest(pairID, userID int) (int, error) {
+	query := `
+		INSERT INTO random_coffee_feedback (pair_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (pair_id, user_id) DO NOTHING
+		RETURNING id`
+
+	var id int
+	err := r.db.QueryRow(query, pairID, userID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to create feedback request for pair %d and user %d: %w", utils.GetCurrentTypeName(), pairID, userID, err)
+	}
+	return id, nil
+}
+
+// DeleteRequest removes the feedback request, so the user is asked again on the next run
+func (r *RandomCoffeeFeedbackRepository) DeleteRequest(id int) error {
+	query := `DELETE FROM random_coffee_feedback WHERE id = $1`
+
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("%s: failed to delete feedback request %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+	return nil
+}
+
+// GetByID retrieves feedback by ID
+func (r *RandomCoffeeFeedbackRepository) GetByID(id int) (*RandomCoffeeFeedback, error) {
+	query := `
+		SELECT id, pair_id, user_id, status, rating, requested_at, answered_at
+		FROM random_coffee_feedback
+		WHERE id = $1`
+
+	var feedback RandomCoffeeFeedback
+	var status sql.NullString
+	err := r.db.QueryRow(query, id).Scan(
+		&feedback.ID,
+		&feedback.PairID,
+		&feedback.UserID,
+		&status,
+		&feedback.Rating,
+		&feedback.RequestedAt,
+		&feedback.AnsweredAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get feedback with ID %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+
+	feedback.Status = constants.CoffeeFeedbackStatus(status.String)
+	return &feedback, nil
+}
+
+// SetStatus saves the answer of the user about the meeting
+func (r *RandomCoffeeFeedbackRepository) SetStatus(id int, status constants.CoffeeFeedbackStatus) error {
+	query := `UPDATE random_coffee_feedback SET status = $1, answered_at = NOW() WHERE id = $2`
+
+	if _, err := r.db.Exec(query, string(status), id); err != nil {
+		return fmt.Errorf("%s: failed to set status of feedback %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+	return nil
+}
+
+// SetRating saves the rating of the meeting from 1 to 5
+func (r *RandomCoffeeFeedbackRepository) SetRating(id int, rating int) error {
+	query := `UPDATE random_coffee_feedback SET rating = $1, answered_at = NOW() WHERE id = $2`
+
+	if _, err := r.db.Exec(query, rating, id); err != nil {
+		return fmt.Errorf("%s: failed to set rating of feedback %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+	return nil
+}
+
+// GetReliabilityStats counts reports of partners about the given users keyed by user ID,
+// users without reports are absent
+func (r *RandomCoffeeFeedbackRepository) GetReliabilityStats(userIDs []int) (map[int]RandomCoffeeReliability, error) {
+	query := `
+		WITH members AS (
+			SELECT id AS pair_id, user1_id AS user_id FROM random_coffee_pairs
+			UNION ALL
+			SELECT id, user2_id FROM random_coffee_pairs
+			UNION ALL
+			SELECT id, user3_id FROM random_coffee_pairs WHERE user3_id IS NOT NULL
+		)
+		SELECT m.user_id,
+			COUNT(*) FILTER (WHERE f.status IN ('met', 'planned')),
+			COUNT(*) FILTER (WHERE f.status = 'no_response')
+		FROM members m
+		JOIN random_coffee_feedback f ON f.pair_id = m.pair_id AND f.user_id <> m.user_id
+		WHERE m.user_id = ANY($1) AND f.status IS NOT NULL
+		GROUP BY m.user_id`
+
+	rows, err := r.db.Query(query, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query reliability stats: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	stats := make(map[int]RandomCoffeeReliability, len(userIDs))
+	for rows.Next() {
+		var userID int
+		var reliability RandomCoffeeReliability
+		if err := rows.Scan(&userID, &reliability.Confirmed, &reliability.NoResponse); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan reliability stats: %w", utils.GetCurrentTypeName(), err)
+		}
+		stats[userID] = reliability
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating reliability stats rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return stats, nil
+}
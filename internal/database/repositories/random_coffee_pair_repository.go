@@ -4,14 +4,24 @@ import (
 	"database/sql"
 	"fmt"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type RandomCoffeePair struct {
-	ID        int
-	PollID    int
-	User1ID   int64
-	User2ID   int64
-	CreatedAt time.Time
+	ID      int
+	PollID  int
+	User1ID int64
+	User2ID int64
+	// User3ID is set for a group of three
+	User3ID sql.NullInt64
+	// IsDraft is set until admins approve the pairs of the poll
+	IsDraft bool
+	// IsFailed is set when a member reported a silent partner, the pair doesn't count as met
+	IsFailed bool
+	// IsSupplementary is set for a pair created in the middle of the week instead of a failed one
+	IsSupplementary bool
+	CreatedAt       time.Time
 }
 
 type RandomCoffeePairRepository struct {
@@ -22,78 +32,278 @@ func NewRandomCoffeePairRepository(db *sql.DB) *RandomCoffeePairRepository {
 	return &RandomCoffeePairRepository{db: db}
 }
 
-func (r *RandomCoffeePairRepository) CreatePair(pollID int, user1ID, user2ID int) error {
+// GetByID retrieves a pair or a group of three by ID
+func (r *RandomCoffeePairRepository) GetByID(id int) (*RandomCoffeePair, error) {
 	query := `
-		INSERT INTO random_coffee_pairs (poll_id, user1_id, user2_id)
-		VALUES ($1, $2, $3)
+		SELECT id, poll_id, user1_id, user2_id, user3_id, is_draft, is_failed, is_supplementary, created_at
+		FROM random_coffee_pairs
+		WHERE id = $1
 	`
-	_, err := r.db.Exec(query, pollID, user1ID, user2ID)
+	var pair RandomCoffeePair
+	err := r.db.QueryRow(query, id).Scan(
+		&pair.ID, &pair.PollID, &pair.User1ID, &pair.User2ID, &pair.User3ID,
+		&pair.IsDraft, &pair.IsFailed, &pair.IsSupplementary, &pair.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting random coffee pair %d: %w", id, err)
+	}
+	return &pair, nil
+}
+
+// HasMember reports whether the user is a member of the pair or the group of three
+func (p RandomCoffeePair) HasMember(userID int) bool {
+	for _, memberID := range p.MemberIDs() {
+		if memberID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// MemberIDs returns user IDs of all members of the pair or the group of three
+func (p RandomCoffeePair) MemberIDs() []int {
+	memberIDs := []int{int(p.User1ID), int(p.User2ID)}
+	if p.User3ID.Valid {
+		memberIDs = append(memberIDs, int(p.User3ID.Int64))
+	}
+	return memberIDs
+}
+
+// ReplaceDraftPairs replaces draft pairs of the poll with the given groups of two or three user IDs in one transaction,
+// so a failure leaves the previous draft intact
+func (r *RandomCoffeePairRepository) ReplaceDraftPairs(pollID int, groups [][]int) error {
+	tx, err := r.db.Begin()
 	if err != nil {
-		return fmt.Errorf("error creating random coffee pair: %w", err)
+		return fmt.Errorf("error beginning transaction for draft pairs of poll %d: %w", pollID, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM random_coffee_pairs WHERE poll_id = $1 AND is_draft = TRUE`, pollID); err != nil {
+		return fmt.Errorf("error deleting draft pairs of poll %d: %w", pollID, err)
+	}
+
+	query := `
+		INSERT INTO random_coffee_pairs (poll_id, user1_id, user2_id, user3_id, is_draft)
+		VALUES ($1, $2, $3, $4, TRUE)
+	`
+	for _, userIDs := range groups {
+		if len(userIDs) < 2 || len(userIDs) > 3 {
+			return fmt.Errorf("error creating draft random coffee pair: invalid number of members %d", len(userIDs))
+		}
+
+		var user3ID sql.NullInt64
+		if len(userIDs) == 3 {
+			user3ID = sql.NullInt64{Int64: int64(userIDs[2]), Valid: true}
+		}
+		if _, err := tx.Exec(query, pollID, userIDs[0], userIDs[1], user3ID); err != nil {
+			return fmt.Errorf("error creating draft random coffee pair: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing draft pairs of poll %d: %w", pollID, err)
 	}
 	return nil
 }
 
-// GetPairsHistoryForUsers returns historical pairs for specified users from last N polls
-func (r *RandomCoffeePairRepository) GetPairsHistoryForUsers(userIDs []int, lastNPolls int) (map[string][]int, error) {
-	if len(userIDs) == 0 {
-		return make(map[string][]int), nil
+// GetDraftPairs returns draft pairs of the poll in the order they were created
+func (r *RandomCoffeePairRepository) GetDraftPairs(pollID int) ([]RandomCoffeePair, error) {
+	query := `
+		SELECT id, poll_id, user1_id, user2_id, user3_id, is_draft, is_failed, is_supplementary, created_at
+		FROM random_coffee_pairs
+		WHERE poll_id = $1 AND is_draft = TRUE
+		ORDER BY id
+	`
+	rows, err := r.db.Query(query, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting draft pairs: %w", err)
 	}
+	defer rows.Close()
 
-	// Convert userIDs to a format suitable for SQL IN clause
-	placeholders := ""
-	args := make([]interface{}, len(userIDs)+1)
-	for i, userID := range userIDs {
-		if i > 0 {
-			placeholders += ","
+	var pairs []RandomCoffeePair
+	for rows.Next() {
+		var pair RandomCoffeePair
+		if err := rows.Scan(
+			&pair.ID, &pair.PollID, &pair.User1ID, &pair.User2ID, &pair.User3ID,
+			&pair.IsDraft, &pair.IsFailed, &pair.IsSupplementary, &pair.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning draft pair row: %w", err)
 		}
-		placeholders += fmt.Sprintf("$%d", i+2)
-		args[i+1] = userID
+		pairs = append(pairs, pair)
 	}
-	args[0] = lastNPolls
 
-	query := fmt.Sprintf(`
-		SELECT p.user1_id, p.user2_id, poll.id as poll_id
-		FROM random_coffee_pairs p
-		JOIN random_coffee_polls poll ON p.poll_id = poll.id
-		WHERE (p.user1_id IN (%s) OR p.user2_id IN (%s))
-		ORDER BY poll.week_start_date DESC
-		LIMIT (SELECT COUNT(*) FROM random_coffee_pairs pairs 
-		       JOIN random_coffee_polls polls ON pairs.poll_id = polls.id 
-		       WHERE polls.id IN (
-		           SELECT id FROM random_coffee_polls 
-		           ORDER BY week_start_date DESC 
-		           LIMIT $1
-		       ))
-	`, placeholders, placeholders)
-
-	rows, err := r.db.Query(query, args...)
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration for draft pairs: %w", err)
+	}
+
+	return pairs, nil
+}
+
+// UpdateDraftMembers replaces members of a draft pair, a third member makes it a group of three
+func (r *RandomCoffeePairRepository) UpdateDraftMembers(id int, userIDs []int) error {
+	if len(userIDs) < 2 || len(userIDs) > 3 {
+		return fmt.Errorf("error updating draft random coffee pair %d: invalid number of members %d", id, len(userIDs))
+	}
+
+	var user3ID sql.NullInt64
+	if len(userIDs) == 3 {
+		user3ID = sql.NullInt64{Int64: int64(userIDs[2]), Valid: true}
+	}
+
+	query := `
+		UPDATE random_coffee_pairs
+		SET user1_id = $1, user2_id = $2, user3_id = $3
+		WHERE id = $4 AND is_draft = TRUE
+	`
+	_, err := r.db.Exec(query, userIDs[0], userIDs[1], user3ID, id)
 	if err != nil {
-		return nil, fmt.Errorf("error getting pairs history: %w", err)
+		return fmt.Errorf("error updating draft random coffee pair %d: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteDraftPair deletes a single draft pair
+func (r *RandomCoffeePairRepository) DeleteDraftPair(id int) error {
+	_, err := r.db.Exec(`DELETE FROM random_coffee_pairs WHERE id = $1 AND is_draft = TRUE`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting draft random coffee pair %d: %w", id, err)
+	}
+	return nil
+}
+
+// PublishDraftPairs marks draft pairs of the poll as published, returns the number of published pairs
+func (r *RandomCoffeePairRepository) PublishDraftPairs(pollID int) (int, error) {
+	result, err := r.db.Exec(`UPDATE random_coffee_pairs SET is_draft = FALSE WHERE poll_id = $1 AND is_draft = TRUE`, pollID)
+	if err != nil {
+		return 0, fmt.Errorf("error publishing draft pairs of poll %d: %w", pollID, err)
+	}
+	published, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error getting number of published pairs of poll %d: %w", pollID, err)
+	}
+	return int(published), nil
+}
+
+// GetUnpairedParticipantIDs returns participants of the poll who are in no published pair, failed pairs included
+func (r *RandomCoffeePairRepository) GetUnpairedParticipantIDs(pollID int) ([]int, error) {
+	query := `
+		SELECT rcp.user_id
+		FROM random_coffee_participants rcp
+		WHERE rcp.poll_id = $1 AND rcp.is_participating = TRUE
+		AND NOT EXISTS (
+			SELECT 1 FROM random_coffee_pairs p
+			WHERE p.poll_id = rcp.poll_id AND p.is_draft = FALSE
+			AND rcp.user_id IN (p.user1_id, p.user2_id, p.user3_id)
+		)
+		ORDER BY rcp.user_id
+	`
+	rows, err := r.db.Query(query, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting unpaired participants of poll %d: %w", pollID, err)
 	}
 	defer rows.Close()
 
-	pairHistory := make(map[string][]int)
+	var userIDs []int
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("error scanning unpaired participant: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration for unpaired participants: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+// GetPollIDsWithDraftsCreatedBefore returns polls whose draft pairs were created before the given time
+func (r *RandomCoffeePairRepository) GetPollIDsWithDraftsCreatedBefore(before time.Time) ([]int, error) {
+	query := `
+		SELECT poll_id
+		FROM random_coffee_pairs
+		WHERE is_draft = TRUE
+		GROUP BY poll_id
+		HAVING MIN(created_at) < $1
+		ORDER BY poll_id
+	`
+	rows, err := r.db.Query(query, before)
+	if err != nil {
+		return nil, fmt.Errorf("error getting polls with expired drafts: %w", err)
+	}
+	defer rows.Close()
 
+	var pollIDs []int
 	for rows.Next() {
-		var user1ID, user2ID, pollID int
-		if err := rows.Scan(&user1ID, &user2ID, &pollID); err != nil {
-			return nil, fmt.Errorf("error scanning pair history row: %w", err)
+		var pollID int
+		if err := rows.Scan(&pollID); err != nil {
+			return nil, fmt.Errorf("error scanning poll with expired drafts: %w", err)
 		}
+		pollIDs = append(pollIDs, pollID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration for polls with expired drafts: %w", err)
+	}
+
+	return pollIDs, nil
+}
+
+// RandomCoffeePairHistoryEntry is a past pair together with the week of its poll
+type RandomCoffeePairHistoryEntry struct {
+	PollID        int
+	User1ID       int
+	User2ID       int
+	WeekStartDate time.Time
+}
+
+// GetPairsHistoryForUsers returns all past pairs in which both users are among the specified ones.
+// Every two members of a group of three are returned as a separate pair, failed pairs are skipped.
+func (r *RandomCoffeePairRepository) GetPairsHistoryForUsers(userIDs []int) ([]RandomCoffeePairHistoryEntry, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
 
-		// Create keys for both directions (user1-user2 and user2-user1)
-		key1 := fmt.Sprintf("%d-%d", user1ID, user2ID)
-		key2 := fmt.Sprintf("%d-%d", user2ID, user1ID)
+	query := `
+		WITH met AS (
+			SELECT poll_id, user1_id AS a_id, user2_id AS b_id FROM random_coffee_pairs WHERE is_draft = FALSE AND is_failed = FALSE
+			UNION ALL
+			SELECT poll_id, user1_id, user3_id FROM random_coffee_pairs WHERE user3_id IS NOT NULL AND is_draft = FALSE AND is_failed = FALSE
+			UNION ALL
+			SELECT poll_id, user2_id, user3_id FROM random_coffee_pairs WHERE user3_id IS NOT NULL AND is_draft = FALSE AND is_failed = FALSE
+		)
+		SELECT m.poll_id, m.a_id, m.b_id, poll.week_start_date
+		FROM met m
+		JOIN random_coffee_polls poll ON m.poll_id = poll.id
+		WHERE m.a_id = ANY($1) AND m.b_id = ANY($1)
+		ORDER BY poll.week_start_date DESC
+	`
 
-		pairHistory[key1] = append(pairHistory[key1], pollID)
-		pairHistory[key2] = append(pairHistory[key2], pollID)
+	rows, err := r.db.Query(query, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error getting pairs history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []RandomCoffeePairHistoryEntry
+	for rows.Next() {
+		var entry RandomCoffeePairHistoryEntry
+		if err := rows.Scan(&entry.PollID, &entry.User1ID, &entry.User2ID, &entry.WeekStartDate); err != nil {
+			return nil, fmt.Errorf("error scanning pair history row: %w", err)
+		}
+		history = append(history, entry)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("error during rows iteration for pair history: %w", err)
 	}
 
-	return pairHistory, nil
+	return history, nil
 }
 
 // GetMostRecentPairPoll returns the most recent poll ID where two users were paired, or 0 if never paired
@@ -102,7 +312,8 @@ func (r *RandomCoffeePairRepository) GetMostRecentPairPoll(user1ID, user2ID int,
 		SELECT poll.id
 		FROM random_coffee_pairs p
 		JOIN random_coffee_polls poll ON p.poll_id = poll.id
-		WHERE ((p.user1_id = $1 AND p.user2_id = $2) OR (p.user1_id = $2 AND p.user2_id = $1))
+		WHERE $1 IN (p.user1_id, p.user2_id, p.user3_id) AND $2 IN (p.user1_id, p.user2_id, p.user3_id)
+		AND p.is_draft = FALSE AND p.is_failed = FALSE
 		AND poll.id IN (
 			SELECT id FROM random_coffee_polls 
 			ORDER BY week_start_date DESC 
@@ -36,12 +36,51 @@ func (r *RandomCoffeeParticipantRepository) UpsertParticipant(participant Random
 	return err
 }
 
+// AddParticipantIfAbsent adds the user as participating unless the user has already answered the poll,
+// returns whether the user was added
+func (r *RandomCoffeeParticipantRepository) AddParticipantIfAbsent(pollID int64, userID int64) (bool, error) {
+	query := `
+		INSERT INTO random_coffee_participants (poll_id, user_id, is_participating, created_at, updated_at)
+		VALUES ($1, $2, TRUE, NOW(), NOW())
+		ON CONFLICT (poll_id, user_id) DO NOTHING
+	`
+	result, err := r.db.Exec(query, pollID, userID)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to add participant: %w", utils.GetCurrentTypeName(), err)
+	}
+	added, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to get number of added participants: %w", utils.GetCurrentTypeName(), err)
+	}
+	return added > 0, nil
+}
+
 func (r *RandomCoffeeParticipantRepository) RemoveParticipant(pollID int64, userID int64) error {
 	query := "DELETE FROM random_coffee_participants WHERE poll_id = $1 AND user_id = $2"
 	_, err := r.db.Exec(query, pollID, userID)
 	return err
 }
 
+// RemoveFromOpenPoll retracts the vote of the user in the latest poll unless pairs were already published for it,
+// returns whether the vote was retracted. Draft pairs are not updated, see RandomCoffeeService.ExcludeFromLatestDraft
+func (r *RandomCoffeeParticipantRepository) RemoveFromOpenPoll(userID int64) (bool, error) {
+	query := `
+		DELETE FROM random_coffee_participants rcp
+		WHERE rcp.user_id = $1
+		AND rcp.poll_id = (SELECT id FROM random_coffee_polls ORDER BY week_start_date DESC, id DESC LIMIT 1)
+		AND NOT EXISTS (SELECT 1 FROM random_coffee_pairs p WHERE p.poll_id = rcp.poll_id AND p.is_draft = FALSE)
+	`
+	result, err := r.db.Exec(query, userID)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to remove participant from open poll: %w", utils.GetCurrentTypeName(), err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to get number of removed participants: %w", utils.GetCurrentTypeName(), err)
+	}
+	return removed > 0, nil
+}
+
 func (r *RandomCoffeeParticipantRepository) GetParticipant(pollID int64, userID int64) (*RandomCoffeeParticipant, error) {
 	query := "SELECT id, poll_id, user_id, is_participating, created_at, updated_at FROM random_coffee_participants WHERE poll_id = $1 AND user_id = $2"
 	row := r.db.QueryRow(query, pollID, userID)
@@ -7,6 +7,7 @@ import (
 	"time"
 )
 
+// RandomCoffeePoll is a weekly round, TelegramPollID is empty for rounds opened without a poll
 type RandomCoffeePoll struct {
 	ID             int64     `db:"id"`
 	MessageID      int64     `db:"message_id"`
@@ -26,7 +27,7 @@ func NewRandomCoffeePollRepository(db *sql.DB) *RandomCoffeePollRepository {
 func (r *RandomCoffeePollRepository) CreatePoll(poll RandomCoffeePoll) (int64, error) {
 	query := `
 		INSERT INTO random_coffee_polls (message_id, week_start_date, telegram_poll_id, created_at)
-		VALUES ($1, $2, $3, $4)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
 		RETURNING id
 	`
 	var id int64
@@ -49,7 +50,7 @@ func (r *RandomCoffeePollRepository) CreatePoll(poll RandomCoffeePoll) (int64, e
 
 func (r *RandomCoffeePollRepository) GetPollByTelegramPollID(telegramPollID string) (*RandomCoffeePoll, error) {
 	query := `
-		SELECT id, message_id, week_start_date, telegram_poll_id, created_at
+		SELECT id, message_id, week_start_date, COALESCE(telegram_poll_id, ''), created_at
 		FROM random_coffee_polls
 		WHERE telegram_poll_id = $1
 	`
@@ -73,7 +74,7 @@ func (r *RandomCoffeePollRepository) GetPollByTelegramPollID(telegramPollID stri
 // GetLatestPoll retrieves the latest poll globally
 func (r *RandomCoffeePollRepository) GetLatestPoll() (*RandomCoffeePoll, error) {
 	query := `
-		SELECT id, message_id, week_start_date, telegram_poll_id, created_at
+		SELECT id, message_id, week_start_date, COALESCE(telegram_poll_id, ''), created_at
 		FROM random_coffee_polls
 		ORDER BY week_start_date DESC, id DESC 
 		LIMIT 1
@@ -94,3 +95,27 @@ func (r *RandomCoffeePollRepository) GetLatestPoll() (*RandomCoffeePoll, error)
 	}
 	return poll, nil
 }
+
+// GetPollByID retrieves a poll by its ID, returns nil if not found
+func (r *RandomCoffeePollRepository) GetPollByID(id int64) (*RandomCoffeePoll, error) {
+	query := `
+		SELECT id, message_id, week_start_date, COALESCE(telegram_poll_id, ''), created_at
+		FROM random_coffee_polls
+		WHERE id = $1
+	`
+	poll := &RandomCoffeePoll{}
+	err := r.db.QueryRow(query, id).Scan(
+		&poll.ID,
+		&poll.MessageID,
+		&poll.WeekStartDate,
+		&poll.TelegramPollID,
+		&poll.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: failed to get poll by ID %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+	return poll, nil
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RandomCoffeePreference represents a row in the random_coffee_preferences table
+type RandomCoffeePreference struct {
+	UserID        int
+	MeetingFormat constants.CoffeeMeetingFormat
+	City          string
+	Languages     []string
+	Interests     []string
+	// Strict makes the format, city and languages hard constraints instead of wishes
+	Strict    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RandomCoffeePreferenceRepository handles database operations for random coffee preferences
+type RandomCoffeePreferenceRepository struct {
+	db *sql.DB
+}
+
+// NewRandomCoffeePreferenceRepository creates a new RandomCoffeePreferenceRepository
+func NewRandomCoffeePreferenceRepository(db *sql.DB) *RandomCoffeePreferenceRepository {
+	return &RandomCoffeePreferenceRepository{db: db}
+}
+
+// GetByUserID retrieves preferences of the user, returns sql.ErrNoRows if the user has none
+func (r *RandomCoffeePreferenceRepository) GetByUserID(userID int) (*RandomCoffeePreference, error) {
+	query := `
+		SELECT user_id, meeting_format, city, languages, interests, strict, created_at, updated_at
+		FROM random_coffee_preferences
+		WHERE user_id = $1`
+
+	preference, err := scanRandomCoffeePreference(r.db.QueryRow(query, userID))
+	if err == sql.ErrNoRows {
+		return nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get random coffee preferences of user %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+
+	return preference, nil
+}
+
+// GetOrCreate retrieves preferences of the user or creates default ones
+func (r *RandomCoffeePreferenceRepository) GetOrCreate(userID int) (*RandomCoffeePreference, bool, error) {
+	preference, err := r.GetByUserID(userID)
+	if err == nil {
+		return preference, false, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, false, err
+	}
+
+	query := `INSERT INTO random_coffee_preferences (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING`
+	if _, err := r.db.Exec(query, userID); err != nil {
+		return nil, false, fmt.Errorf("%s: failed to create random coffee preferences of user %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+
+	preference, err = r.GetByUserID(userID)
+	if err != nil {
+		return nil, false, err
+	}
+	return preference, true, nil
+}
+
+// GetByUserIDs retrieves preferences of the given users keyed by user ID, users without preferences are absent
+func (r *RandomCoffeePreferenceRepository) GetByUserIDs(userIDs []int) (map[int]RandomCoffeePreference, error) {
+	query := `
+		SELECT user_id, meeting_format, city, languages, interests, strict, created_at, updated_at
+		FROM random_coffee_preferences
+		WHERE user_id = ANY($1)`
+
+	rows, err := r.db.Query(query, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query random coffee preferences: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	preferences := make(map[int]RandomCoffeePreference, len(userIDs))
+	for rows.Next() {
+		preference, err := scanRandomCoffeePreference(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan random coffee preferences: %w", utils.GetCurrentTypeName(), err)
+		}
+		preferences[preference.UserID] = *preference
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating random coffee preferences rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return preferences, nil
+}
+
+// Upsert creates or replaces preferences of the user
+func (r *RandomCoffeePreferenceRepository) Upsert(preference RandomCoffeePreference) error {
+	query := `
+		INSERT INTO random_coffee_preferences (user_id, meeting_format, city, languages, interests, strict)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET
+			meeting_format = EXCLUDED.meeting_format,
+			city = EXCLUDED.city,
+			languages = EXCLUDED.languages,
+			interests = EXCLUDED.interests,
+			strict = EXCLUDED.strict,
+			updated_at = NOW()`
+
+	languages, interests := preference.Languages, preference.Interests
+	if languages == nil {
+		languages = []string{}
+	}
+	if interests == nil {
+		interests = []string{}
+	}
+
+	_, err := r.db.Exec(query,
+		preference.UserID,
+		string(preference.MeetingFormat),
+		preference.City,
+		pq.Array(languages),
+		pq.Array(interests),
+		preference.Strict,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: failed to save random coffee preferences of user %d: %w", utils.GetCurrentTypeName(), preference.UserID, err)
+	}
+	return nil
+}
+
+func scanRandomCoffeePreference(row interface{ Scan(dest ...any) error }) (*RandomCoffeePreference, error) {
+	var preference RandomCoffeePreference
+	var meetingFormat string
+	err := row.Scan(
+		&preference.UserID,
+		&meetingFormat,
+		&preference.City,
+		pq.Array(&preference.Languages),
+		pq.Array(&preference.Interests),
+		&preference.Strict,
+		&preference.CreatedAt,
+		&preference.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	preference.MeetingFormat = constants.CoffeeMeetingFormat(meetingFormat)
+	return &preference, nil
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"time"
+)
+
+// RandomCoffeeRematchRequest is a request for a new partner instead of a silent one
+type RandomCoffeeRematchRequest struct {
+	// PairID is the failed pair the request was made from
+	PairID int
+	UserID int
+	PollID int
+	// RematchPairID is the supplementary pair, it's not valid while the request waits for a partner
+	RematchPairID sql.NullInt64
+	CreatedAt     time.Time
+}
+
+// RandomCoffeeRematch is a new request for a partner instead of a silent one, with the new partner if found
+type RandomCoffeeRematch struct {
+	// FailedPairID is the pair the user reported a silent partner in
+	FailedPairID int
+	UserID       int
+	PollID       int
+	// PartnerID is the new partner, zero if the request waits for one
+	PartnerID int
+	// PartnerRequest is the waiting request of the new partner, nil if the partner had no pair at all
+	PartnerRequest *RandomCoffeeRematchRequest
+}
+
+// RandomCoffeeRematchRepository handles database operations for random coffee rematch requests
+type RandomCoffeeRematchRepository struct {
+	db *sql.DB
+}
+
+// NewRandomCoffeeRematchRepository creates a new RandomCoffeeRematchRepository
+func NewRandomCoffeeRematchRepository(db *sql.DB) *RandomCoffeeRematchRepository {
+	return &RandomCoffeeRematchRepository{db: db}
+}
+
+func scanRandomCoffeeRematchRequest(row interface{ Scan(dest ...any) error }) (*RandomCoffeeRematchRequest, error) {
+	var request RandomCoffeeRematchRequest
+	if err := row.Scan(&request.PairID, &request.UserID, &request.PollID, &request.RematchPairID, &request.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetRequest retrieves the request of the user made from the pair, returns nil if there is none
+func (r *RandomCoffeeRematchRepository) GetRequest(pairID, userID int) (*RandomCoffeeRematchRequest, error) {
+	query := `
+		SELECT pair_id, user_id, poll_id, rematch_pair_id, created_at
+		FROM random_coffee_rematch_requests
+		WHERE pair_id = $1 AND user_id = $2`
+
+	request, err := scanRandomCoffeeRematchRequest(r.db.QueryRow(query, pairID, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get rematch request for pair %d and user %d: %w", utils.GetCurrentTypeName(), pairID, userID, err)
+	}
+	return request, nil
+}
+
+// GetPendingRequests returns requests of the poll still waiting for a partner, the oldest first
+func (r *RandomCoffeeRematchRepository) GetPendingRequests(pollID int) ([]RandomCoffeeRematchRequest, error) {
+	query := `
+		SELECT pair_id, user_id, poll_id, rematch_pair_id, created_at
+		FROM random_coffee_rematch_requests
+		WHERE poll_id = $1 AND rematch_pair_id IS NULL
+		ORDER BY created_at, user_id`
+
+	rows, err := r.db.Query(query, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query pending rematch requests of poll %d: %w", utils.GetCurrentTypeName(), pollID, err)
+	}
+	defer rows.Close()
+
+	var requests []RandomCoffeeRematchRequest
+	for rows.Next() {
+		request, err := scanRandomCoffeeRematchRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan rematch request: %w", utils.GetCurrentTypeName(), err)
+		}
+		requests = append(requests, *request)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating rematch requests rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return requests, nil
+}
+
+// CreateRequest marks the failed pair, records that the partner didn't respond and saves the request.
+// If the new partner is set, the supplementary pair is created and the requests of both users are linked to it.
+// Everything is saved in one transaction, so a failed request can be retried.
+// Returns the ID of the supplementary pair, zero if the request waits for a partner.
+func (r *RandomCoffeeRematchRepository) CreateRequest(rematch RandomCoffeeRematch) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to begin transaction: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE random_coffee_pairs SET is_failed = TRUE WHERE id = $1 AND is_draft = FALSE`, rematch.FailedPairID); err != nil {
+		return 0, fmt.Errorf("%s: failed to mark pair %d as failed: %w", utils.GetCurrentTypeName(), rematch.FailedPairID, err)
+	}
+
+	// The report lowers the reliability of the silent partners in the next rounds
+	feedbackQuery := `
+		INSERT INTO random_coffee_feedback (pair_id, user_id, status, answered_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (pair_id, user_id) DO UPDATE SET status = EXCLUDED.status, answered_at = NOW()`
+	if _, err := tx.Exec(feedbackQuery, rematch.FailedPairID, rematch.UserID, constants.CoffeeFeedbackStatusNoResponse); err != nil {
+		return 0, fmt.Errorf("%s: failed to save feedback for pair %d and user %d: %w",
+			utils.GetCurrentTypeName(), rematch.FailedPairID, rematch.UserID, err)
+	}
+
+	requestQuery := `
+		INSERT INTO random_coffee_rematch_requests (pair_id, user_id, poll_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (pair_id, user_id) DO NOTHING`
+	if _, err := tx.Exec(requestQuery, rematch.FailedPairID, rematch.UserID, rematch.PollID); err != nil {
+		return 0, fmt.Errorf("%s: failed to create rematch request for pair %d and user %d: %w",
+			utils.GetCurrentTypeName(), rematch.FailedPairID, rematch.UserID, err)
+	}
+
+	rematchPairID := 0
+	if rematch.PartnerID != 0 {
+		pairQuery := `
+			INSERT INTO random_coffee_pairs (poll_id, user1_id, user2_id, is_supplementary)
+			VALUES ($1, $2, $3, TRUE)
+			RETURNING id`
+		user1ID, user2ID := min(rematch.UserID, rematch.PartnerID), max(rematch.UserID, rematch.PartnerID)
+		if err := tx.QueryRow(pairQuery, rematch.PollID, user1ID, user2ID).Scan(&rematchPairID); err != nil {
+			return 0, fmt.Errorf("%s: failed to create supplementary pair: %w", utils.GetCurrentTypeName(), err)
+		}
+
+		linkQuery := `
+			UPDATE random_coffee_rematch_requests
+			SET rematch_pair_id = $3
+			WHERE pair_id = $1 AND user_id = $2`
+		requests := [][2]int{{rematch.FailedPairID, rematch.UserID}}
+		if rematch.PartnerRequest != nil {
+			requests = append(requests, [2]int{rematch.PartnerRequest.PairID, rematch.PartnerRequest.UserID})
+		}
+		for _, request := range requests {
+			if _, err := tx.Exec(linkQuery, request[0], request[1], rematchPairID); err != nil {
+				return 0, fmt.Errorf("%s: failed to set rematch pair for pair %d and user %d: %w",
+					utils.GetCurrentTypeName(), request[0], request[1], err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: failed to commit rematch request: %w", utils.GetCurrentTypeName(), err)
+	}
+	return rematchPairID, nil
+}
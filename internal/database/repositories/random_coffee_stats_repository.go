@@ -0,0 +1,261 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"time"
+)
+
+// RandomCoffeeMeeting is a pair or a group of three of the user with the other members
+type RandomCoffeeMeeting struct {
+	PairID        int
+	WeekStartDate time.Time
+	Partners      []User
+}
+
+// RandomCoffeeWeekParticipation is the number of participants of a random coffee poll
+type RandomCoffeeWeekParticipation struct {
+	PollID        int
+	WeekStartDate time.Time
+	Participants  int
+}
+
+// RandomCoffeeRound is a random coffee poll with the groups formed in it
+type RandomCoffeeRound struct {
+	PollID int
+	utils.CoffeeWeekGroups
+}
+
+// RandomCoffeeActiveUser is a user with the number of random coffee meetings
+type RandomCoffeeActiveUser struct {
+	User
+	Meetings int
+}
+
+// RandomCoffeeStatsRepository reads random coffee statistics from polls, participants and pairs
+type RandomCoffeeStatsRepository struct {
+	db *sql.DB
+}
+
+// NewRandomCoffeeStatsRepository creates a new RandomCoffeeStatsRepository
+func NewRandomCoffeeStatsRepository(db *sql.DB) *RandomCoffeeStatsRepository {
+	return &RandomCoffeeStatsRepository{db: db}
+}
+
+// GetUserMeetings returns all meetings of the user, the most recent first
+func (r *RandomCoffeeStatsRepository) GetUserMeetings(userID int) ([]RandomCoffeeMeeting, error) {
+	query := `
+		SELECT p.id, poll.week_start_date, u.id, u.tg_id, u.firstname, u.lastname, u.tg_username
+		FROM random_coffee_pairs p
+		JOIN random_coffee_polls poll ON p.poll_id = poll.id
+		JOIN users u ON u.id IN (p.user1_id, p.user2_id, p.user3_id) AND u.id <> $1
+		WHERE $1 IN (p.user1_id, p.user2_id, p.user3_id) AND p.is_draft = FALSE AND p.is_failed = FALSE
+		ORDER BY poll.week_start_date DESC, p.id, u.id`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query meetings of user %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+	defer rows.Close()
+
+	var meetings []RandomCoffeeMeeting
+	for rows.Next() {
+		var pairID int
+		var weekStartDate time.Time
+		var partner User
+		err := rows.Scan(&pairID, &weekStartDate, &partner.ID, &partner.TgID, &partner.Firstname, &partner.Lastname, &partner.TgUsername)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan meeting: %w", utils.GetCurrentTypeName(), err)
+		}
+
+		// Rows of the same pair go one after another, one row per partner
+		if len(meetings) == 0 || meetings[len(meetings)-1].PairID != pairID {
+			meetings = append(meetings, RandomCoffeeMeeting{PairID: pairID, WeekStartDate: weekStartDate})
+		}
+		last := &meetings[len(meetings)-1]
+		last.Partners = append(last.Partners, partner)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating meetings rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return meetings, nil
+}
+
+// GetRoundWeeks returns week start dates of all polls that produced pairs, the most recent first
+func (r *RandomCoffeeStatsRepository) GetRoundWeeks() ([]time.Time, error) {
+	query := `
+		SELECT DISTINCT poll.week_start_date
+		FROM random_coffee_pairs p
+		JOIN random_coffee_polls poll ON p.poll_id = poll.id
+		WHERE p.is_draft = FALSE
+		ORDER BY poll.week_start_date DESC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query round weeks: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var weeks []time.Time
+	for rows.Next() {
+		var week time.Time
+		if err := rows.Scan(&week); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan round week: %w", utils.GetCurrentTypeName(), err)
+		}
+		weeks = append(weeks, week)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating round weeks rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return weeks, nil
+}
+
+// GetNeverMetUsers returns club members who took part in random coffee but were never paired with the user
+func (r *RandomCoffeeStatsRepository) GetNeverMetUsers(userID int) ([]User, error) {
+	query := `
+		SELECT u.id, u.tg_id, u.firstname, u.lastname, u.tg_username
+		FROM users u
+		WHERE u.id <> $1 AND u.is_club_member = TRUE AND u.has_coffee_ban = FALSE
+		AND EXISTS (
+			SELECT 1 FROM random_coffee_participants rcp
+			WHERE rcp.user_id = u.id AND rcp.is_participating = TRUE
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM random_coffee_pairs p
+			WHERE $1 IN (p.user1_id, p.user2_id, p.user3_id) AND u.id IN (p.user1_id, p.user2_id, p.user3_id)
+			AND p.is_draft = FALSE AND p.is_failed = FALSE
+		)
+		ORDER BY u.firstname, u.lastname`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query never met users of user %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.TgID, &user.Firstname, &user.Lastname, &user.TgUsername); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan never met user: %w", utils.GetCurrentTypeName(), err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating never met users rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return users, nil
+}
+
+// GetWeeklyParticipation returns the number of participants of the last polls, the most recent first
+func (r *RandomCoffeeStatsRepository) GetWeeklyParticipation(limit int) ([]RandomCoffeeWeekParticipation, error) {
+	query := `
+		SELECT poll.id, poll.week_start_date, COUNT(rcp.user_id) FILTER (WHERE rcp.is_participating = TRUE)
+		FROM random_coffee_polls poll
+		LEFT JOIN random_coffee_participants rcp ON rcp.poll_id = poll.id
+		GROUP BY poll.id, poll.week_start_date
+		ORDER BY poll.week_start_date DESC
+		LIMIT $1`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query weekly participation: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var weeks []RandomCoffeeWeekParticipation
+	for rows.Next() {
+		var week RandomCoffeeWeekParticipation
+		if err := rows.Scan(&week.PollID, &week.WeekStartDate, &week.Participants); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan weekly participation: %w", utils.GetCurrentTypeName(), err)
+		}
+		weeks = append(weeks, week)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating weekly participation rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return weeks, nil
+}
+
+// GetAllRounds returns groups of every poll in chronological order
+func (r *RandomCoffeeStatsRepository) GetAllRounds() ([]RandomCoffeeRound, error) {
+	query := `
+		SELECT p.poll_id, poll.week_start_date, p.user1_id, p.user2_id, p.user3_id
+		FROM random_coffee_pairs p
+		JOIN random_coffee_polls poll ON p.poll_id = poll.id
+		WHERE p.is_draft = FALSE AND p.is_failed = FALSE
+		ORDER BY poll.week_start_date, p.poll_id, p.id`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query rounds: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var rounds []RandomCoffeeRound
+	for rows.Next() {
+		var pair RandomCoffeePair
+		var weekStartDate time.Time
+		if err := rows.Scan(&pair.PollID, &weekStartDate, &pair.User1ID, &pair.User2ID, &pair.User3ID); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan round pair: %w", utils.GetCurrentTypeName(), err)
+		}
+
+		if len(rounds) == 0 || rounds[len(rounds)-1].PollID != pair.PollID {
+			rounds = append(rounds, RandomCoffeeRound{
+				PollID:           pair.PollID,
+				CoffeeWeekGroups: utils.CoffeeWeekGroups{WeekStartDate: weekStartDate},
+			})
+		}
+		last := &rounds[len(rounds)-1]
+		last.Groups = append(last.Groups, pair.MemberIDs())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating rounds rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return rounds, nil
+}
+
+// GetMostActiveUsers returns users with the largest number of meetings
+func (r *RandomCoffeeStatsRepository) GetMostActiveUsers(limit int) ([]RandomCoffeeActiveUser, error) {
+	query := `
+		SELECT u.id, u.tg_id, u.firstname, u.lastname, u.tg_username, COUNT(*) AS meetings
+		FROM random_coffee_pairs p
+		JOIN users u ON u.id IN (p.user1_id, p.user2_id, p.user3_id)
+		WHERE p.is_draft = FALSE AND p.is_failed = FALSE
+		GROUP BY u.id, u.tg_id, u.firstname, u.lastname, u.tg_username
+		ORDER BY meetings DESC, u.firstname
+		LIMIT $1`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query most active users: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var users []RandomCoffeeActiveUser
+	for rows.Next() {
+		var user RandomCoffeeActiveUser
+		err := rows.Scan(&user.ID, &user.TgID, &user.Firstname, &user.Lastname, &user.TgUsername, &user.Meetings)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan most active user: %w", utils.GetCurrentTypeName(), err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating most active users rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return users, nil
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"time"
+)
+
+// RandomCoffeeSubscription represents a row in the random_coffee_subscriptions table
+type RandomCoffeeSubscription struct {
+	UserID    int
+	Frequency constants.CoffeeSubscriptionFrequency
+	// StartWeek is the first round of the subscription, biweekly subscribers join every second round from it
+	StartWeek time.Time
+	// PausedUntil is the first round after the pause, zero if the subscription is not paused
+	PausedUntil time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IsActive reports whether the subscriber takes part in the round of the given week
+func (s RandomCoffeeSubscription) IsActive(weekStartDate time.Time) bool {
+	everyWeeks := 1
+	if s.Frequency == constants.CoffeeSubscriptionFrequencyBiweekly {
+		everyWeeks = 2
+	}
+	return utils.IsCoffeeSubscriptionActive(s.StartWeek, everyWeeks, s.PausedUntil, weekStartDate)
+}
+
+// IsPaused reports whether the subscription is paused for the round of the given week
+func (s RandomCoffeeSubscription) IsPaused(weekStartDate time.Time) bool {
+	return !s.PausedUntil.IsZero() && weekStartDate.Before(s.PausedUntil)
+}
+
+// RandomCoffeeSubscriptionRepository handles database operations for standing random coffee subscriptions
+type RandomCoffeeSubscriptionRepository struct {
+	db *sql.DB
+}
+
+// NewRandomCoffeeSubscriptionRepository creates a new RandomCoffeeSubscriptionRepository
+func NewRandomCoffeeSubscriptionRepository(db *sql.DB) *RandomCoffeeSubscriptionRepository {
+	return &RandomCoffeeSubscriptionRepository{db: db}
+}
+
+// GetByUserID retrieves the subscription of the user, returns nil if the user is not subscribed
+func (r *RandomCoffeeSubscriptionRepository) GetByUserID(userID int) (*RandomCoffeeSubscription, error) {
+	query := `
+		SELECT user_id, frequency, start_week, paused_until, created_at, updated_at
+		FROM random_coffee_subscriptions
+		WHERE user_id = $1`
+
+	subscription, err := scanRandomCoffeeSubscription(r.db.QueryRow(query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get random coffee subscription of user %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+
+	return subscription, nil
+}
+
+// GetAllowedSubscriptions retrieves subscriptions of club members who are not banned from random coffee
+func (r *RandomCoffeeSubscriptionRepository) GetAllowedSubscriptions() ([]RandomCoffeeSubscription, error) {
+	query := `
+		SELECT s.user_id, s.frequency, s.start_week, s.paused_until, s.created_at, s.updated_at
+		FROM random_coffee_subscriptions s
+		JOIN users u ON u.id = s.user_id
+		WHERE u.is_club_member = TRUE AND u.has_coffee_ban = FALSE
+		ORDER BY s.user_id`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query random coffee subscriptions: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var subscriptions []RandomCoffeeSubscription
+	for rows.Next() {
+		subscription, err := scanRandomCoffeeSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan random coffee subscription: %w", utils.GetCurrentTypeName(), err)
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating random coffee subscriptions rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return subscriptions, nil
+}
+
+// Upsert creates or replaces the subscription of the user
+func (r *RandomCoffeeSubscriptionRepository) Upsert(subscription RandomCoffeeSubscription) error {
+	query := `
+		INSERT INTO random_coffee_subscriptions (user_id, frequency, start_week, paused_until)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET
+			frequency = EXCLUDED.frequency,
+			start_week = EXCLUDED.start_week,
+			paused_until = EXCLUDED.paused_until,
+			updated_at = NOW()`
+
+	var pausedUntil sql.NullTime
+	if !subscription.PausedUntil.IsZero() {
+		pausedUntil = sql.NullTime{Time: subscription.PausedUntil, Valid: true}
+	}
+
+	_, err := r.db.Exec(query,
+		subscription.UserID,
+		string(subscription.Frequency),
+		subscription.StartWeek,
+		pausedUntil,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: failed to save random coffee subscription of user %d: %w", utils.GetCurrentTypeName(), subscription.UserID, err)
+	}
+	return nil
+}
+
+// Delete removes the subscription of the user
+func (r *RandomCoffeeSubscriptionRepository) Delete(userID int) error {
+	_, err := r.db.Exec(`DELETE FROM random_coffee_subscriptions WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("%s: failed to delete random coffee subscription of user %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+	return nil
+}
+
+func scanRandomCoffeeSubscription(row interface{ Scan(dest ...any) error }) (*RandomCoffeeSubscription, error) {
+	var subscription RandomCoffeeSubscription
+	var frequency string
+	var pausedUntil sql.NullTime
+	err := row.Scan(
+		&subscription.UserID,
+		&frequency,
+		&subscription.StartWeek,
+		&pausedUntil,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Frequency = constants.CoffeeSubscriptionFrequency(frequency)
+	if pausedUntil.Valid {
+		subscription.PausedUntil = pausedUntil.Time
+	}
+	return &subscription, nil
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"time"
+)
+
+// ScheduledJobState represents a row in the scheduled_job_states table
+type ScheduledJobState struct {
+	JobName string
+	Paused  bool
+	// SkipScheduledFor is the occurrence of the job that must not run
+	SkipScheduledFor sql.NullTime
+	UpdatedAt        time.Time
+}
+
+// ScheduledJobStateRepository handles database operations for admin controlled state of scheduled jobs
+type ScheduledJobStateRepository struct {
+	db *sql.DB
+}
+
+// NewScheduledJobStateRepository creates a new ScheduledJobStateRepository
+func NewScheduledJobStateRepository(db *sql.DB) *ScheduledJobStateRepository {
+	return &ScheduledJobStateRepository{db: db}
+}
+
+// GetAll retrieves states of all jobs that have one
+func (r *ScheduledJobStateRepository) GetAll() ([]ScheduledJobState, error) {
+	query := `SELECT job_name, paused, skip_scheduled_for, updated_at FROM scheduled_job_states`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query scheduled job states: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var states []ScheduledJobState
+	for rows.Next() {
+		var state ScheduledJobState
+		if err := rows.Scan(&state.JobName, &state.Paused, &state.SkipScheduledFor, &state.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan scheduled job state: %w", utils.GetCurrentTypeName(), err)
+		}
+		states = append(states, state)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating scheduled job states rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return states, nil
+}
+
+// SetPaused pauses or resumes the job
+func (r *ScheduledJobStateRepository) SetPaused(jobName string, paused bool) error {
+	query := `
+		INSERT INTO scheduled_job_states (job_name, paused)
+		VALUES ($1, $2)
+		ON CONFLICT (job_name) DO UPDATE SET paused = EXCLUDED.paused, updated_at = NOW()`
+
+	if _, err := r.db.Exec(query, jobName, paused); err != nil {
+		return fmt.Errorf("%s: failed to set paused state of job %s: %w", utils.GetCurrentTypeName(), jobName, err)
+	}
+	return nil
+}
+
+// SetSkipScheduledFor sets the occurrence of the job to skip, an invalid value clears it
+func (r *ScheduledJobStateRepository) SetSkipScheduledFor(jobName string, skipScheduledFor sql.NullTime) error {
+	query := `
+		INSERT INTO scheduled_job_states (job_name, skip_scheduled_for)
+		VALUES ($1, $2)
+		ON CONFLICT (job_name) DO UPDATE SET skip_scheduled_for = EXCLUDED.skip_scheduled_for, updated_at = NOW()`
+
+	if _, err := r.db.Exec(query, jobName, skipScheduledFor); err != nil {
+		return fmt.Errorf("%s: failed to set skipped occurrence of job %s: %w", utils.GetCurrentTypeName(), jobName, err)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"time"
+)
+
+// SummaryCache represents a row in the summary_cache table
+type SummaryCache struct {
+	ID             int
+	TopicID        int64
+	PeriodFrom     time.Time
+	PeriodTo       time.Time
+	FirstMessageID int64
+	LastMessageID  int64
+	Summary        string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// SummaryCacheRepository handles database operations for cached summaries
+type SummaryCacheRepository struct {
+	db *sql.DB
+}
+
+// NewSummaryCacheRepository creates a new SummaryCacheRepository
+func NewSummaryCacheRepository(db *sql.DB) *SummaryCacheRepository {
+	return &SummaryCacheRepository{db: db}
+}
+
+// Get retrieves a cached summary for the topic and period, returns nil if not found
+func (r *SummaryCacheRepository) Get(topicID int64, periodFrom time.Time, periodTo time.Time) (*SummaryCache, error) {
+	query := `
+		SELECT id, topic_id, period_from, period_to, first_message_id, last_message_id, summary, created_at, updated_at
+		FROM summary_cache
+		WHERE topic_id = $1 AND period_from = $2 AND period_to = $3`
+
+	var cache SummaryCache
+	err := r.db.QueryRow(query, topicID, periodFrom, periodTo).Scan(
+		&cache.ID,
+		&cache.TopicID,
+		&cache.PeriodFrom,
+		&cache.PeriodTo,
+		&cache.FirstMessageID,
+		&cache.LastMessageID,
+		&cache.Summary,
+		&cache.CreatedAt,
+		&cache.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get cached summary for topic %d: %w", utils.GetCurrentTypeName(), topicID, err)
+	}
+
+	return &cache, nil
+}
+
+// Upsert stores the summary for the topic and period, replacing a previous one
+func (r *SummaryCacheRepository) Upsert(
+	topicID int64,
+	periodFrom time.Time,
+	periodTo time.Time,
+	firstMessageID int64,
+	lastMessageID int64,
+	summary string,
+) error {
+	query := `
+		INSERT INTO summary_cache (topic_id, period_from, period_to, first_message_id, last_message_id, summary)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (topic_id, period_from, period_to)
+		DO UPDATE SET first_message_id = EXCLUDED.first_message_id, last_message_id = EXCLUDED.last_message_id,
+			summary = EXCLUDED.summary, updated_at = NOW()`
+
+	_, err := r.db.Exec(query, topicID, periodFrom, periodTo, firstMessageID, lastMessageID, summary)
+	if err != nil {
+		return fmt.Errorf("%s: failed to upsert cached summary for topic %d: %w", utils.GetCurrentTypeName(), topicID, err)
+	}
+
+	return nil
+}
+
+// DeleteByTopicID removes all cached summaries of the topic
+func (r *SummaryCacheRepository) DeleteByTopicID(topicID int64) error {
+	query := `DELETE FROM summary_cache WHERE topic_id = $1`
+	if _, err := r.db.Exec(query, topicID); err != nil {
+		return fmt.Errorf("%s: failed to delete cached summaries for topic %d: %w", utils.GetCurrentTypeName(), topicID, err)
+	}
+	return nil
+}
+
+// DeleteAll removes all cached summaries
+func (r *SummaryCacheRepository) DeleteAll() error {
+	if _, err := r.db.Exec(`DELETE FROM summary_cache`); err != nil {
+		return fmt.Errorf("%s: failed to delete cached summaries: %w", utils.GetCurrentTypeName(), err)
+	}
+	return nil
+}
+
+// DeleteOlderThan removes cached summaries created before the given time
+func (r *SummaryCacheRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	query := `DELETE FROM summary_cache WHERE created_at < $1`
+	result, err := r.db.Exec(query, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to delete old cached summaries: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: could not get rows affected after delete: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return rowsAffected, nil
+}
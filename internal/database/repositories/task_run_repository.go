@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Task run statuses
+const (
+	TaskRunStatusRunning = "running"
+	TaskRunStatusSuccess = "success"
+	TaskRunStatusFailed  = "failed"
+	TaskRunStatusSkipped = "skipped"
+)
+
+// TaskRun represents a row in the task_runs table
+type TaskRun struct {
+	ID           int
+	TaskName     string
+	ScheduledFor time.Time
+	StartedAt    sql.NullTime
+	FinishedAt   sql.NullTime
+	Status       string
+	Error        sql.NullString
+	CreatedAt    time.Time
+}
+
+// TaskRunRepository handles database operations for scheduled task runs
+type TaskRunRepository struct {
+	db *sql.DB
+}
+
+// NewTaskRunRepository creates a new TaskRunRepository
+func NewTaskRunRepository(db *sql.DB) *TaskRunRepository {
+	return &TaskRunRepository{db: db}
+}
+
+// TryLock tries to take a Postgres advisory lock for the given name without waiting.
+// The lock lives on a dedicated connection, so the returned release func must be called when acquired is true.
+func (r *TaskRunRepository) TryLock(ctx context.Context, lockName string) (release func(), acquired bool, err error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: failed to get connection for lock %s: %w", utils.GetCurrentTypeName(), lockName, err)
+	}
+
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, lockName).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("%s: failed to take lock %s: %w", utils.GetCurrentTypeName(), lockName, err)
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release = func() {
+		// Use a fresh context, the caller's one may be already cancelled
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, lockName); err != nil {
+			log.Printf("%s: Failed to release lock %s: %v", utils.GetCurrentTypeName(), lockName, err)
+		}
+		conn.Close()
+	}
+	return release, true, nil
+}
+
+// Start records the start of the task run for the scheduled time.
+// Returns nil if the run for this time has already succeeded or was skipped by an admin. Unfinished and failed runs are restarted,
+// callers must hold the task lock, so a "running" row can only be left by a crashed instance.
+func (r *TaskRunRepository) Start(taskName string, scheduledFor time.Time) (*TaskRun, error) {
+	query := `
+		INSERT INTO task_runs (task_name, scheduled_for, started_at, status)
+		VALUES ($1, $2, NOW(), $3)
+		ON CONFLICT (task_name, scheduled_for) DO UPDATE SET
+			started_at = NOW(),
+			finished_at = NULL,
+			status = EXCLUDED.status,
+			error = NULL
+		WHERE task_runs.status NOT IN ($4, $5)
+		RETURNING id, task_name, scheduled_for, started_at, finished_at, status, error, created_at`
+
+	var run TaskRun
+	err := r.db.QueryRow(query, taskName, scheduledFor, TaskRunStatusRunning, TaskRunStatusSuccess, TaskRunStatusSkipped).Scan(
+		&run.ID,
+		&run.TaskName,
+		&run.ScheduledFor,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&run.Status,
+		&run.Error,
+		&run.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to start run of task %s: %w", utils.GetCurrentTypeName(), taskName, err)
+	}
+
+	return &run, nil
+}
+
+// Finish records the result of the task run
+func (r *TaskRunRepository) Finish(id int, status string, runErr error) error {
+	var errText sql.NullString
+	if runErr != nil {
+		errText = sql.NullString{String: runErr.Error(), Valid: true}
+	}
+
+	query := `UPDATE task_runs SET finished_at = NOW(), status = $1, error = $2 WHERE id = $3`
+	result, err := r.db.Exec(query, status, errText, id)
+	if err != nil {
+		return fmt.Errorf("%s: failed to finish task run %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("%s: Could not get rows affected after update: %v", utils.GetCurrentTypeName(), err)
+	} else if rowsAffected == 0 {
+		return fmt.Errorf("%s: no task run found with ID %d to finish", utils.GetCurrentTypeName(), id)
+	}
+
+	return nil
+}
+
+// RecordSkipped records that the run for the scheduled time was skipped, does nothing if the run is already recorded
+func (r *TaskRunRepository) RecordSkipped(taskName string, scheduledFor time.Time) error {
+	query := `
+		INSERT INTO task_runs (task_name, scheduled_for, status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (task_name, scheduled_for) DO NOTHING`
+
+	if _, err := r.db.Exec(query, taskName, scheduledFor, TaskRunStatusSkipped); err != nil {
+		return fmt.Errorf("%s: failed to record skipped run of task %s: %w", utils.GetCurrentTypeName(), taskName, err)
+	}
+	return nil
+}
+
+// GetLastByTaskName retrieves the latest run of the task, returns nil if the task has never run
+func (r *TaskRunRepository) GetLastByTaskName(taskName string) (*TaskRun, error) {
+	query := `
+		SELECT id, task_name, scheduled_for, started_at, finished_at, status, error, created_at
+		FROM task_runs
+		WHERE task_name = $1
+		ORDER BY scheduled_for DESC
+		LIMIT 1`
+
+	var run TaskRun
+	err := r.db.QueryRow(query, taskName).Scan(
+		&run.ID,
+		&run.TaskName,
+		&run.ScheduledFor,
+		&run.StartedAt,
+		&run.FinishedAt,
+		&run.Status,
+		&run.Error,
+		&run.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get last run of task %s: %w", utils.GetCurrentTypeName(), taskName, err)
+	}
+
+	return &run, nil
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"database/sql"
+	"evo-bot-go/internal/utils"
+	"fmt"
+	"log"
+	"time"
+)
+
+// TopicSummarizationSettings represents a row in the topic_summarization_settings table
+type TopicSummarizationSettings struct {
+	ID                 int
+	TopicID            int64
+	Enabled            bool
+	SummaryTime        string // "HH:MM" in the club timezone
+	PromptTemplateKey  string
+	MinMessages        int
+	DestinationTopicID int64
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// TopicSummarizationSettingsRepository handles database operations for per-topic summarization settings
+type TopicSummarizationSettingsRepository struct {
+	db *sql.DB
+}
+
+// NewTopicSummarizationSettingsRepository creates a new TopicSummarizationSettingsRepository
+func NewTopicSummarizationSettingsRepository(db *sql.DB) *TopicSummarizationSettingsRepository {
+	return &TopicSummarizationSettingsRepository{db: db}
+}
+
+// GetAll retrieves settings for all topics ordered by topic ID
+func (r *TopicSummarizationSettingsRepository) GetAll() ([]TopicSummarizationSettings, error) {
+	query := `
+		SELECT id, topic_id, enabled, summary_time, prompt_template_key, min_messages, destination_topic_id, created_at, updated_at
+		FROM topic_summarization_settings
+		ORDER BY topic_id ASC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query topic summarization settings: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	var settings []TopicSummarizationSettings
+	for rows.Next() {
+		var s TopicSummarizationSettings
+		if err := rows.Scan(
+			&s.ID,
+			&s.TopicID,
+			&s.Enabled,
+			&s.SummaryTime,
+			&s.PromptTemplateKey,
+			&s.MinMessages,
+			&s.DestinationTopicID,
+			&s.CreatedAt,
+			&s.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan topic summarization settings: %w", utils.GetCurrentTypeName(), err)
+		}
+		settings = append(settings, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating topic summarization settings rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return settings, nil
+}
+
+// GetByTopicID retrieves settings for the topic, returns nil if the topic has no own settings
+func (r *TopicSummarizationSettingsRepository) GetByTopicID(topicID int64) (*TopicSummarizationSettings, error) {
+	query := `
+		SELECT id, topic_id, enabled, summary_time, prompt_template_key, min_messages, destination_topic_id, created_at, updated_at
+		FROM topic_summarization_settings
+		WHERE topic_id = $1`
+
+	var s TopicSummarizationSettings
+	err := r.db.QueryRow(query, topicID).Scan(
+		&s.ID,
+		&s.TopicID,
+		&s.Enabled,
+		&s.SummaryTime,
+		&s.PromptTemplateKey,
+		&s.MinMessages,
+		&s.DestinationTopicID,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get summarization settings for topic %d: %w", utils.GetCurrentTypeName(), topicID, err)
+	}
+
+	return &s, nil
+}
+
+// Upsert creates or updates settings for the topic
+func (r *TopicSummarizationSettingsRepository) Upsert(settings TopicSummarizationSettings) error {
+	query := `
+		INSERT INTO topic_summarization_settings (topic_id, enabled, summary_time, prompt_template_key, min_messages, destination_topic_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (topic_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			summary_time = EXCLUDED.summary_time,
+			prompt_template_key = EXCLUDED.prompt_template_key,
+			min_messages = EXCLUDED.min_messages,
+			destination_topic_id = EXCLUDED.destination_topic_id,
+			updated_at = NOW()`
+
+	_, err := r.db.Exec(query,
+		settings.TopicID,
+		settings.Enabled,
+		settings.SummaryTime,
+		settings.PromptTemplateKey,
+		settings.MinMessages,
+		settings.DestinationTopicID,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: failed to upsert summarization settings for topic %d: %w", utils.GetCurrentTypeName(), settings.TopicID, err)
+	}
+
+	return nil
+}
+
+// DeleteByTopicID removes settings of the topic, so it falls back to the defaults
+func (r *TopicSummarizationSettingsRepository) DeleteByTopicID(topicID int64) error {
+	query := `DELETE FROM topic_summarization_settings WHERE topic_id = $1`
+	result, err := r.db.Exec(query, topicID)
+	if err != nil {
+		return fmt.Errorf("%s: failed to delete summarization settings for topic %d: %w", utils.GetCurrentTypeName(), topicID, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("%s: Could not get rows affected after delete: %v", utils.GetCurrentTypeName(), err)
+	} else if rowsAffected == 0 {
+		return fmt.Errorf("%s: no summarization settings found for topic %d to delete", utils.GetCurrentTypeName(), topicID)
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/lib/pq"
 )
 
 // User represents a row in the users table
@@ -20,8 +21,10 @@ type User struct {
 	Score        int
 	HasCoffeeBan bool
 	IsClubMember bool
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	// HideInSummaries opts the user out of being named in chat summaries
+	HideInSummaries bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 // UserRepository handles database operations for users
@@ -42,7 +45,7 @@ func (r *UserRepository) GetDB() *sql.DB {
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(id int) (*User, error) {
 	query := `
-		SELECT id, tg_id, firstname, lastname, tg_username, score, has_coffee_ban, is_club_member, created_at, updated_at
+		SELECT id, tg_id, firstname, lastname, tg_username, score, has_coffee_ban, is_club_member, hide_in_summaries, created_at, updated_at
 		FROM users
 		WHERE id = $1`
 
@@ -56,6 +59,7 @@ func (r *UserRepository) GetByID(id int) (*User, error) {
 		&user.Score,
 		&user.HasCoffeeBan,
 		&user.IsClubMember,
+		&user.HideInSummaries,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -74,7 +78,7 @@ func (r *UserRepository) GetByID(id int) (*User, error) {
 // GetByTelegramID retrieves a user by Telegram ID
 func (r *UserRepository) GetByTelegramID(tgID int64) (*User, error) {
 	query := `
-		SELECT id, tg_id, firstname, lastname, tg_username, score, has_coffee_ban, is_club_member, created_at, updated_at
+		SELECT id, tg_id, firstname, lastname, tg_username, score, has_coffee_ban, is_club_member, hide_in_summaries, created_at, updated_at
 		FROM users
 		WHERE tg_id = $1`
 
@@ -88,6 +92,7 @@ func (r *UserRepository) GetByTelegramID(tgID int64) (*User, error) {
 		&user.Score,
 		&user.HasCoffeeBan,
 		&user.IsClubMember,
+		&user.HideInSummaries,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -106,7 +111,7 @@ func (r *UserRepository) GetByTelegramID(tgID int64) (*User, error) {
 // GetByTelegramUsername retrieves a user by Telegram username
 func (r *UserRepository) GetByTelegramUsername(tgUsername string) (*User, error) {
 	query := `
-		SELECT id, tg_id, firstname, lastname, tg_username, score, has_coffee_ban, is_club_member, created_at, updated_at
+		SELECT id, tg_id, firstname, lastname, tg_username, score, has_coffee_ban, is_club_member, hide_in_summaries, created_at, updated_at
 		FROM users
 		WHERE tg_username = $1`
 
@@ -120,6 +125,7 @@ func (r *UserRepository) GetByTelegramUsername(tgUsername string) (*User, error)
 		&user.Score,
 		&user.HasCoffeeBan,
 		&user.IsClubMember,
+		&user.HideInSummaries,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -254,6 +260,98 @@ func (r *UserRepository) SetClubMemberStatus(id int, isMember bool) error {
 	return nil
 }
 
+// SetHideInSummaries sets whether the user may be named in chat summaries
+func (r *UserRepository) SetHideInSummaries(id int, hide bool) error {
+	query := `UPDATE users SET hide_in_summaries = $1, updated_at = NOW() WHERE id = $2`
+	result, err := r.db.Exec(query, hide, id)
+	if err != nil {
+		return fmt.Errorf("%s: failed to update summaries visibility for user with ID %d: %w", utils.GetCurrentTypeName(), id, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("%s: Could not get rows affected after update: %v", utils.GetCurrentTypeName(), err)
+	} else if rowsAffected == 0 {
+		return fmt.Errorf("%s: no user found with ID %d to update summaries visibility", utils.GetCurrentTypeName(), id)
+	}
+
+	return nil
+}
+
+// GetSummaryDisplayNames returns display names of the given Telegram users who allow being named in summaries.
+// Users who opted out or are unknown are not present in the result.
+func (r *UserRepository) GetSummaryDisplayNames(tgIDs []int64) (map[int64]string, error) {
+	names := make(map[int64]string)
+	if len(tgIDs) == 0 {
+		return names, nil
+	}
+
+	query := `
+		SELECT tg_id, firstname, lastname, tg_username
+		FROM users
+		WHERE tg_id = ANY($1) AND hide_in_summaries = FALSE`
+
+	rows, err := r.db.Query(query, pq.Array(tgIDs))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query users display names: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tgID int64
+		var firstname, lastname, tgUsername string
+		if err := rows.Scan(&tgID, &firstname, &lastname, &tgUsername); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan user display name: %w", utils.GetCurrentTypeName(), err)
+		}
+		if name := utils.FormatUserDisplayName(firstname, lastname, tgUsername); name != "" {
+			names[tgID] = name
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating users display names rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return names, nil
+}
+
+// GetDisplayNamesByIDs returns display names of the given users by their IDs, e.g. of event speakers.
+// Unknown users are not present in the result.
+func (r *UserRepository) GetDisplayNamesByIDs(ids []int64) (map[int64]string, error) {
+	names := make(map[int64]string)
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	query := `
+		SELECT id, firstname, lastname, tg_username
+		FROM users
+		WHERE id = ANY($1)`
+
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to query users display names: %w", utils.GetCurrentTypeName(), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var firstname, lastname, tgUsername string
+		if err := rows.Scan(&id, &firstname, &lastname, &tgUsername); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan user display name: %w", utils.GetCurrentTypeName(), err)
+		}
+		if name := utils.FormatUserDisplayName(firstname, lastname, tgUsername); name != "" {
+			names[id] = name
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: error iterating users display names rows: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	return names, nil
+}
+
 // UpdateTelegramUsername updates a user's telegram username
 func (r *UserRepository) UpdateTelegramUsername(id int, username string) error {
 	query := `UPDATE users SET tg_username = $1, updated_at = NOW() WHERE id = $2`
@@ -394,7 +492,7 @@ func (h *UserRepository) GetOrFullCreate(user *gotgbot.User) (*User, *Profile, e
 // SearchByName searches for users with matching first and last name
 func (r *UserRepository) SearchByName(firstname, lastname string) (*User, error) {
 	query := `
-		SELECT id, tg_id, firstname, lastname, tg_username, score, has_coffee_ban, is_club_member, created_at, updated_at
+		SELECT id, tg_id, firstname, lastname, tg_username, score, has_coffee_ban, is_club_member, hide_in_summaries, created_at, updated_at
 		FROM users
 		WHERE LOWER(firstname) = LOWER($1) AND LOWER(lastname) = LOWER($2)
 		LIMIT 1`
@@ -409,6 +507,7 @@ func (r *UserRepository) SearchByName(firstname, lastname string) (*User, error)
 		&user.Score,
 		&user.HasCoffeeBan,
 		&user.IsClubMember,
+		&user.HideInSummaries,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -0,0 +1,103 @@
+package formatters
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/utils"
+)
+
+// eventArchiveNoteLimit keeps a page of the archive within the Telegram message limit, in UTF-16 code units
+const eventArchiveNoteLimit = 600
+
+// eventMaterialTitleLimit is the length of a material title made from a note, in UTF-16 code units
+const eventMaterialTitleLimit = 40
+
+// GetMaterialEmoji returns the emoji of the event material type
+func GetMaterialEmoji(materialType constants.EventMaterialType) string {
+	switch materialType {
+	case constants.EventMaterialTypeLink:
+		return "🔗"
+	case constants.EventMaterialTypeDocument:
+		return "📎"
+	case constants.EventMaterialTypeNote:
+		return "📝"
+	default:
+		return "📦"
+	}
+}
+
+// FormatEventMaterialTitle returns the plain text title of the material, a link or a note without a title
+// is shown by its URL or its beginning
+func FormatEventMaterialTitle(material repositories.EventMaterial) string {
+	if material.Title != "" {
+		return material.Title
+	}
+
+	switch material.Type {
+	case constants.EventMaterialTypeLink:
+		return material.URL
+	case constants.EventMaterialTypeNote:
+		firstLine, _, _ := strings.Cut(material.Note, "\n")
+		if cut := utils.CutStringByUTF16Units(firstLine, eventMaterialTitleLimit); cut != firstLine {
+			return cut + "…"
+		}
+		return firstLine
+	default:
+		return "Документ"
+	}
+}
+
+// FormatHtmlEventArchive formats finished events with their materials, documents are sent by the buttons
+func FormatHtmlEventArchive(
+	events []repositories.Event,
+	materials map[int][]repositories.EventMaterial,
+	title string,
+	location *time.Location,
+) string {
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("<b>%s</b>\n", title))
+
+	for _, event := range events {
+		typeEmoji := GetTypeEmoji(constants.EventType(event.Type))
+		response.WriteString(fmt.Sprintf("\n%s <b>%s</b>\n", typeEmoji, html.EscapeString(event.Name)))
+		if event.StartedAt != nil {
+			response.WriteString(fmt.Sprintf("└ 🗓 %s\n", event.StartedAt.In(location).Format("02.01.2006")))
+		}
+
+		eventMaterials := materials[event.ID]
+		if len(eventMaterials) == 0 {
+			response.WriteString("└ <i>Материалов пока нет</i>\n")
+			continue
+		}
+
+		for _, material := range eventMaterials {
+			emoji := GetMaterialEmoji(material.Type)
+			switch material.Type {
+			case constants.EventMaterialTypeLink:
+				response.WriteString(fmt.Sprintf("└ %s <a href=\"%s\">%s</a>\n",
+					emoji, html.EscapeString(material.URL), html.EscapeString(FormatEventMaterialTitle(material))))
+			case constants.EventMaterialTypeDocument:
+				response.WriteString(fmt.Sprintf("└ %s %s <i>(кнопка ниже)</i>\n",
+					emoji, html.EscapeString(FormatEventMaterialTitle(material))))
+			case constants.EventMaterialTypeNote:
+				note := utils.CutStringByUTF16Units(material.Note, eventArchiveNoteLimit)
+				if note != material.Note {
+					note += "…"
+				}
+				if material.Title != "" {
+					response.WriteString(fmt.Sprintf("└ %s %s\n", emoji, html.EscapeString(material.Title)))
+				} else {
+					response.WriteString(fmt.Sprintf("└ %s Заметка\n", emoji))
+				}
+				response.WriteString(fmt.Sprintf("<blockquote expandable>%s</blockquote>\n", html.EscapeString(note)))
+			}
+		}
+	}
+
+	return response.String()
+}
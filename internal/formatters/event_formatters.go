@@ -2,6 +2,7 @@ package formatters
 
 import (
 	"fmt"
+	"html"
 	"strings"
 	"time"
 
@@ -47,14 +48,155 @@ func GetStatusEmoji(status constants.EventStatus) string {
 	switch status {
 	case constants.EventStatusFinished:
 		return "✅"
-	case constants.EventStatusActual:
+	case constants.EventStatusPlanned:
 		return "🔄"
+	case constants.EventStatusLive:
+		return "🔴"
+	case constants.EventStatusCancelled:
+		return "❌"
 	default:
 		return "🔄"
 	}
 }
 
-func FormatEventListForTopicsView(events []repositories.Event, title string) string {
+// GetStatusInRussian returns the event status as shown to users
+func GetStatusInRussian(status constants.EventStatus) string {
+	switch status {
+	case constants.EventStatusPlanned:
+		return "запланировано"
+	case constants.EventStatusLive:
+		return "идёт сейчас"
+	case constants.EventStatusFinished:
+		return "завершено"
+	case constants.EventStatusCancelled:
+		return "отменено"
+	default:
+		return string(status)
+	}
+}
+
+// FormatEventDuration formats the event duration, e.g. "1ч 30мин"
+func FormatEventDuration(minutes int) string {
+	hours, mins := minutes/60, minutes%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dмин", mins)
+	case mins == 0:
+		return fmt.Sprintf("%dч", hours)
+	default:
+		return fmt.Sprintf("%dч %dмин", hours, mins)
+	}
+}
+
+// FormatEventSpeakers joins the names of the event speakers, unknown users are skipped
+func FormatEventSpeakers(speakerUserIDs []int64, speakerNames map[int64]string) string {
+	var names []string
+	for _, userID := range speakerUserIDs {
+		if name, ok := speakerNames[userID]; ok {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// GetWeekdayInRussian returns the weekday name in Russian, e.g. "понедельник"
+func GetWeekdayInRussian(weekday time.Weekday) string {
+	switch weekday {
+	case time.Monday:
+		return "понедельник"
+	case time.Tuesday:
+		return "вторник"
+	case time.Wednesday:
+		return "среда"
+	case time.Thursday:
+		return "четверг"
+	case time.Friday:
+		return "пятница"
+	case time.Saturday:
+		return "суббота"
+	default:
+		return "воскресенье"
+	}
+}
+
+// FormatEventStartedAt formats the event start in the club timezone with the zone abbreviation, e.g. "MSK"
+func FormatEventStartedAt(startedAt time.Time, location *time.Location) string {
+	return startedAt.In(location).Format("02.01.2006 в 15:04 MST")
+}
+
+// FormatEventTimeUntil formats the time left before the event start, e.g. "2ч 15мин",
+// returns an empty string if it's more than 7 days
+func FormatEventTimeUntil(timeUntil time.Duration) string {
+	switch {
+	case timeUntil <= 24*time.Hour:
+		// Less than 24 hours
+		hours := int(timeUntil.Hours())
+		mins := int(timeUntil.Minutes()) % 60
+		if hours > 0 {
+			return fmt.Sprintf("%dч %dмин", hours, mins)
+		}
+		return fmt.Sprintf("%dмин", mins)
+	case timeUntil <= 7*24*time.Hour:
+		// Less than 7 days
+		days := int(timeUntil.Hours() / 24)
+		hours := int(timeUntil.Hours()) % 24
+		return fmt.Sprintf("%dд %dч", days, hours)
+	default:
+		return ""
+	}
+}
+
+// FormatHtmlEventReminder formats a reminder about the upcoming event for the announcement topic and subscribers
+func FormatHtmlEventReminder(event repositories.Event, timeUntil time.Duration, location *time.Location) string {
+	typeEmoji := GetTypeEmoji(constants.EventType(event.Type))
+	typeInRussian := GetTypeInRussian(constants.EventType(event.Type))
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("⏰ <b>Напоминание</b>: через %s начнётся мероприятие\n\n", FormatEventTimeUntil(timeUntil)))
+	response.WriteString(fmt.Sprintf("%s <i>%s</i>: <b>%s</b>\n", typeEmoji, typeInRussian, html.EscapeString(event.Name)))
+	if event.StartedAt != nil {
+		response.WriteString(fmt.Sprintf("🗓 %s\n", FormatEventStartedAt(*event.StartedAt, location)))
+	}
+	response.WriteString(fmt.Sprintf("\nТемы и вопросы к мероприятию можно добавить через /%s.", constants.TopicAddCommand))
+
+	return response.String()
+}
+
+// GetAttendeeStatusInRussian returns the RSVP answer as shown to organizers
+func GetAttendeeStatusInRussian(status constants.EventAttendeeStatus) string {
+	switch status {
+	case constants.EventAttendeeStatusGoing:
+		return "идёт"
+	case constants.EventAttendeeStatusWaitlisted:
+		return "лист ожидания"
+	case constants.EventAttendeeStatusMaybe:
+		return "возможно"
+	case constants.EventAttendeeStatusNotGoing:
+		return "не идёт"
+	default:
+		return string(status)
+	}
+}
+
+// FormatHtmlEventAttendanceSummary formats the RSVP numbers of the event for organizers
+func FormatHtmlEventAttendanceSummary(event repositories.Event, counts repositories.EventAttendeeCounts) string {
+	capacity := "без ограничений"
+	if event.Capacity != nil {
+		capacity = fmt.Sprintf("%d", *event.Capacity)
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("👥 <b>Участники мероприятия</b> «%s» <i>(ID: %d)</i>\n\n", html.EscapeString(event.Name), event.ID))
+	response.WriteString(fmt.Sprintf("🎟 Мест: %s\n", capacity))
+	response.WriteString(fmt.Sprintf("✅ Идут: %d\n", counts.Going))
+	response.WriteString(fmt.Sprintf("⏳ В листе ожидания: %d\n", counts.Waitlisted))
+	response.WriteString(fmt.Sprintf("🤔 Возможно: %d\n", counts.Maybe))
+	response.WriteString(fmt.Sprintf("❌ Не идут: %d", counts.NotGoing))
+
+	return response.String()
+}
+
+func FormatEventListForTopicsView(events []repositories.Event, title string, location *time.Location) string {
 	var response strings.Builder
 	response.WriteString(fmt.Sprintf("%s:\n", title))
 
@@ -62,7 +204,7 @@ func FormatEventListForTopicsView(events []repositories.Event, title string) str
 		// Handle optional started_at field
 		startedAtStr := "не указано"
 		if event.StartedAt != nil && !event.StartedAt.IsZero() {
-			startedAtStr = event.StartedAt.Format("02.01.2006 в 15:04")
+			startedAtStr = event.StartedAt.In(location).Format("02.01.2006 в 15:04")
 		}
 
 		typeEmoji := GetTypeEmoji(constants.EventType(event.Type))
@@ -76,7 +218,9 @@ func FormatEventListForTopicsView(events []repositories.Event, title string) str
 	return response.String()
 }
 
-func FormatEventListForEventsView(events []repositories.Event, title string) string {
+// FormatEventListForEventsView formats the list of events for members with all details set for each event,
+// speakerNames are display names of speakers by user ID
+func FormatEventListForEventsView(events []repositories.Event, title string, location *time.Location, speakerNames map[int64]string) string {
 	var response strings.Builder
 	response.WriteString(fmt.Sprintf("%s:\n", title))
 
@@ -84,43 +228,59 @@ func FormatEventListForEventsView(events []repositories.Event, title string) str
 		// Handle optional started_at field
 		startedAtStr := "не указано"
 		if event.StartedAt != nil && !event.StartedAt.IsZero() {
-			startedAtStr = event.StartedAt.Format("02.01.2006 в 15:04 UTC")
+			startedAtStr = FormatEventStartedAt(*event.StartedAt, location)
 
 			// Add time remaining if event is in the future
 			utcNow := time.Now().UTC()
 			if event.StartedAt.After(utcNow) {
-				timeUntil := event.StartedAt.Sub(utcNow)
-
-				switch {
-				case timeUntil <= 24*time.Hour:
-					// Less than 24 hours
-					hours := int(timeUntil.Hours())
-					mins := int(timeUntil.Minutes()) % 60
-					if hours > 0 {
-						startedAtStr += fmt.Sprintf(" _(через %dч %dмин)_", hours, mins)
-					} else {
-						startedAtStr += fmt.Sprintf(" _(через %dмин)_", mins)
-					}
-				case timeUntil <= 7*24*time.Hour:
-					// Less than 7 days
-					days := int(timeUntil.Hours() / 24)
-					hours := int(timeUntil.Hours()) % 24
-					startedAtStr += fmt.Sprintf(" _(через %dд %dч)_", days, hours)
+				if timeUntil := FormatEventTimeUntil(event.StartedAt.Sub(utcNow)); timeUntil != "" {
+					startedAtStr += fmt.Sprintf(" _(через %s)_", timeUntil)
 				}
 			}
 		}
+		if event.Status == string(constants.EventStatusLive) {
+			startedAtStr += fmt.Sprintf(" %s _%s_", GetStatusEmoji(constants.EventStatusLive), GetStatusInRussian(constants.EventStatusLive))
+		}
 
 		typeEmoji := GetTypeEmoji(constants.EventType(event.Type))
 		typeInRussian := GetTypeInRussian(constants.EventType(event.Type))
 
-		response.WriteString(fmt.Sprintf("\n%s _%s_: *%s*\n", typeEmoji, typeInRussian, event.Name))
-		response.WriteString(fmt.Sprintf("└   _когда_: %s\n", startedAtStr))
+		response.WriteString(fmt.Sprintf("\n%s _%s_: *%s*%s\n", typeEmoji, typeInRussian, event.Name, getSeriesMark(event)))
+
+		lines := []string{fmt.Sprintf("_когда_: %s", startedAtStr)}
+		if event.DurationMinutes != nil {
+			lines = append(lines, fmt.Sprintf("_длительность_: %s", FormatEventDuration(*event.DurationMinutes)))
+		}
+		if event.Location != "" {
+			lines = append(lines, fmt.Sprintf("_где_: %s", escapeMarkdown(event.Location)))
+		}
+		if speakers := FormatEventSpeakers(event.SpeakerUserIDs, speakerNames); speakers != "" {
+			lines = append(lines, fmt.Sprintf("_спикеры_: %s", escapeMarkdown(speakers)))
+		}
+		if event.Description != "" {
+			// Multiline descriptions stay aligned with the tree
+			lines = append(lines, strings.ReplaceAll(escapeMarkdown(event.Description), "\n", "\n    "))
+		}
+
+		for i, line := range lines {
+			prefix := "├"
+			if i == len(lines)-1 {
+				prefix = "└"
+			}
+			response.WriteString(fmt.Sprintf("%s   %s\n", prefix, line))
+		}
 	}
 
 	return response.String()
 }
 
-func FormatEventListForAdmin(events []repositories.Event, title string, cancelCommand string, actionDescription string) string {
+// escapeMarkdown escapes user input for the legacy Markdown parse mode
+func escapeMarkdown(text string) string {
+	replacer := strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
+	return replacer.Replace(text)
+}
+
+func FormatEventListForAdmin(events []repositories.Event, title string, cancelCommand string, actionDescription string, location *time.Location) string {
 	var response strings.Builder
 	response.WriteString(fmt.Sprintf("*%s*\n", title))
 
@@ -128,13 +288,13 @@ func FormatEventListForAdmin(events []repositories.Event, title string, cancelCo
 		// Handle optional started_at field
 		startedAtStr := "не указано"
 		if event.StartedAt != nil && !event.StartedAt.IsZero() {
-			startedAtStr = event.StartedAt.Format("02.01.2006 в 15:04 UTC")
+			startedAtStr = FormatEventStartedAt(*event.StartedAt, location)
 		}
 
 		statusEmoji := GetStatusEmoji(constants.EventStatus(event.Status))
 		typeEmoji := GetTypeEmoji(constants.EventType(event.Type))
 
-		response.WriteString(fmt.Sprintf("\n%s ID /%d: *%s*\n", typeEmoji, event.ID, event.Name))
+		response.WriteString(fmt.Sprintf("\n%s ID /%d: *%s*%s\n", typeEmoji, event.ID, event.Name, getSeriesMark(event)))
 		response.WriteString(fmt.Sprintf("└ %s _когда_: *%s*\n",
 			statusEmoji, startedAtStr))
 	}
@@ -144,6 +304,14 @@ func FormatEventListForAdmin(events []repositories.Event, title string, cancelCo
 	return response.String()
 }
 
+// getSeriesMark marks an occurrence of a recurring event
+func getSeriesMark(event repositories.Event) string {
+	if event.SeriesID == nil {
+		return ""
+	}
+	return " 🔁"
+}
+
 func FormatHtmlTopicListForUsers(topics []repositories.Topic, eventName string, eventType string) string {
 	var response strings.Builder
 
@@ -17,18 +17,22 @@ func FormatHelpMessage(isAdmin bool, config *config.Config) string {
 		"└ /profile - Управление своим профилем, поиск профилей клубчан, публикация и обновление информации о себе в канале «Интро»\n\n" +
 		"<b>🔍 Поиск</b>\n" +
 		"└ /tools - Найти инструменты из канала «Инструменты»\n" +
-		"└ /content - Найти видео из канала «Видео-контент»\n" +
-		"└ /intro - Найти информацию об участниках клуба из канала «Интро» (умный поиск по профилям клубчан)\n\n" +
+		"└ /content - Найти видео из канала «Видео-контент» и материалы прошедших мероприятий\n" +
+		"└ /intro - Найти информацию об участниках клуба из канала «Интро» (умный поиск по профилям клубчан)\n" +
+		fmt.Sprintf("└ /%s - Получить сводку обсуждений в клубе за выбранный период (что я пропустил?)\n", constants.CatchupCommand) +
+		fmt.Sprintf("└ %s - Ответь этой командой на сообщение в клубном чате, чтобы получить краткое содержание всей ветки обсуждения\n\n", constants.ServiceTldr_Command) +
 		"<b>📅 Мероприятия</b>\n" +
 		"└ /events - Показать список предстоящих мероприятий\n" +
 		"└ /topics - Просмотреть темы и вопросы к предстоящим мероприятиям\n" +
-		"└ /topicAdd - Предложить тему или вопрос к предстоящему мероприятию"
+		"└ /topicAdd - Предложить тему или вопрос к предстоящему мероприятию\n" +
+		fmt.Sprintf("└ /%s - Архив прошедших мероприятий: записи, слайды и заметки", constants.ArchiveCommand)
 
 	featuresDescription := "\n\n<b>☕️ Random Coffee</b>\n" +
 		"Я создаю еженедельные опросы для участия в клубных встречах. " +
 		"Используй опрос, чтобы поучаствовать в созвонах и познакомиться с другими клубчанами. " +
 		fmt.Sprintf("Пары для созвонов объявляются в начале недели в канале <a href=\"https://t.me/c/%d/%d\">«Random Coffee»</a>.",
-			config.SuperGroupChatID, config.RandomCoffeeTopicID)
+			config.SuperGroupChatID, config.RandomCoffeeTopicID) +
+		fmt.Sprintf("\n└ /%s - Указать пожелания к встречам (формат, город, языки, интересы), с кем не объединять, управлять подпиской и посмотреть историю встреч", constants.CoffeeCommand)
 
 	helpText += featuresDescription
 
@@ -41,14 +45,18 @@ func FormatHelpMessage(isAdmin bool, config *config.Config) string {
 			fmt.Sprintf("└ /%s - Создать новое мероприятие\n", constants.EventSetupCommand) +
 			fmt.Sprintf("└ /%s - Редактировать мероприятие\n", constants.EventEditCommand) +
 			fmt.Sprintf("└ /%s - Удалить мероприятие\n", constants.EventDeleteCommand) +
+			fmt.Sprintf("└ /%s - Материалы прошедшего мероприятия: ссылки, документы и заметки\n", constants.EventMaterialsCommand) +
 			fmt.Sprintf("└ /%s - Просмотреть темы и вопросы к предстоящим мероприятиям <b>с возможностью удаления</b>\n", constants.ShowTopicsCommand) +
 			fmt.Sprintf("└ /%s - Ввести код для авторизации TG-клиента (задом наперед)\n", constants.CodeCommand) +
-			fmt.Sprintf("└ /%s - Управление профилями клубчан", constants.AdminProfilesCommand)
+			fmt.Sprintf("└ /%s - Управление профилями клубчан\n", constants.AdminProfilesCommand) +
+			fmt.Sprintf("└ /%s - Настройки саммаризации по топикам (время, шаблон промпта, минимум сообщений, топик для публикации)\n", constants.SummarySettingsCommand) +
+			fmt.Sprintf("└ /%s - Запланированные задачи: статус, ручной запуск, пауза и пропуск следующего запуска\n", constants.TasksCommand) +
+			fmt.Sprintf("└ /%s - Статистика Random Coffee: участие по неделям, доля повторных пар и самые активные участники", constants.CoffeeStatsCommand)
 
 		testCommandsHelpText := "\n\n<b>⚙️ Команды для тестирования</b>\n" +
 			fmt.Sprintf("└ /%s - Ручная генерация саммаризации общения в клубе\n", constants.TrySummarizeCommand) +
 			fmt.Sprintf("└ /%s - Ручное создание нового опроса по Random Coffee\n", constants.TryCreateCoffeePoolCommand) +
-			fmt.Sprintf("└ /%s - Ручная генерация пар для Random Coffee\n", constants.TryGenerateCoffeePairsCommand) +
+			fmt.Sprintf("└ /%s - Генерация черновика пар для Random Coffee с предпросмотром\n", constants.TryGenerateCoffeePairsCommand) +
 			fmt.Sprintf("└ /%s - Отправить ссылку на базу знаний в ЛС\n", constants.TryLinkToLearnCommand)
 
 		helpText += adminHelpText
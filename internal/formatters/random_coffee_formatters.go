@@ -0,0 +1,284 @@
+package formatters
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/utils"
+)
+
+// GetCoffeeMeetingFormatInRussian returns a readable name of the meeting format
+func GetCoffeeMeetingFormatInRussian(format constants.CoffeeMeetingFormat) string {
+	switch format {
+	case constants.CoffeeMeetingFormatOnline:
+		return "только онлайн"
+	case constants.CoffeeMeetingFormatOffline:
+		return "только офлайн"
+	default:
+		return "онлайн или офлайн"
+	}
+}
+
+// FormatCoffeePreferencesView formats random coffee preferences of the user for the /coffee menu
+func FormatCoffeePreferencesView(preference *repositories.RandomCoffeePreference) string {
+	notSet := "<i>не указано</i>"
+	formatList := func(items []string) string {
+		if len(items) == 0 {
+			return notSet
+		}
+		return html.EscapeString(strings.Join(items, ", "))
+	}
+
+	city := notSet
+	if preference.City != "" {
+		city = html.EscapeString(preference.City)
+	}
+
+	strictText := "пожелания (при необходимости бот может их нарушить)"
+	if preference.Strict {
+		strictText = "строгие условия (лучше остаться без пары, чем нарушить их)"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("☕️ <b>Твои пожелания для Random Coffee</b>\n\n")
+	sb.WriteString(fmt.Sprintf("📍 <b>Формат:</b> %s\n", GetCoffeeMeetingFormatInRussian(preference.MeetingFormat)))
+	sb.WriteString(fmt.Sprintf("🏙 <b>Город:</b> %s\n", city))
+	sb.WriteString(fmt.Sprintf("🗣 <b>Языки:</b> %s\n", formatList(preference.Languages)))
+	sb.WriteString(fmt.Sprintf("💡 <b>Интересы:</b> %s\n", formatList(preference.Interests)))
+	sb.WriteString(fmt.Sprintf("⚖️ <b>Формат, город и языки — это</b> %s\n", strictText))
+	sb.WriteString("\nБот учитывает пожелания при подборе пар, а в анонсе пишет, что вас объединяет.")
+
+	return sb.String()
+}
+
+// FormatCoffeeMatchReason explains why members of a group were matched, returns an empty string if nothing is in common
+func FormatCoffeeMatchReason(overlap utils.CoffeePreferencesOverlap, groupSize int) string {
+	everyone := "оба"
+	if groupSize > 2 {
+		everyone = "все"
+	}
+
+	var reasons []string
+	if overlap.City != "" {
+		reasons = append(reasons, fmt.Sprintf("%s в городе %s", everyone, html.EscapeString(overlap.City)))
+	} else if overlap.Online {
+		reasons = append(reasons, fmt.Sprintf("%s за онлайн", everyone))
+	}
+	if len(overlap.Languages) > 0 {
+		reasons = append(reasons, fmt.Sprintf("язык: %s", html.EscapeString(strings.Join(overlap.Languages, ", "))))
+	}
+	switch {
+	case len(overlap.Interests) == 1:
+		reasons = append(reasons, fmt.Sprintf("интерес: %s", html.EscapeString(overlap.Interests[0])))
+	case len(overlap.Interests) > 1:
+		reasons = append(reasons, fmt.Sprintf("интересы: %s", html.EscapeString(strings.Join(overlap.Interests, ", "))))
+	}
+
+	return strings.Join(reasons, ", ")
+}
+
+// FormatCoffeeHistoryView formats random coffee history of the user for the /coffee menu
+func FormatCoffeeHistoryView(meetings []repositories.RandomCoffeeMeeting, streak int, neverMet []repositories.User) string {
+	const maxMeetings = 10
+	const maxNeverMet = 15
+
+	formatUsers := func(users []repositories.User) string {
+		names := make([]string, 0, len(users))
+		for _, user := range users {
+			names = append(names, html.EscapeString(utils.FormatUserDisplayName(user.Firstname, user.Lastname, user.TgUsername)))
+		}
+		return strings.Join(names, ", ")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("☕️ <b>Твоя история Random Coffee</b>\n\n")
+	sb.WriteString(fmt.Sprintf("🤝 <b>Встреч:</b> %d\n", len(meetings)))
+	sb.WriteString(fmt.Sprintf("🔥 <b>Раундов подряд со встречей:</b> %d\n", streak))
+
+	if len(meetings) == 0 {
+		sb.WriteString("\nУ тебя пока не было встреч. Проголосуй в еженедельном опросе, чтобы получить пару!")
+	} else {
+		sb.WriteString("\n📅 <b>Последние встречи:</b>\n")
+		for i, meeting := range meetings {
+			if i == maxMeetings {
+				sb.WriteString(fmt.Sprintf("<i>…и ещё %d</i>\n", len(meetings)-maxMeetings))
+				break
+			}
+			sb.WriteString(fmt.Sprintf("└ %s — %s\n", meeting.WeekStartDate.Format("02.01.2006"), formatUsers(meeting.Partners)))
+		}
+	}
+
+	if len(neverMet) > 0 {
+		sb.WriteString(fmt.Sprintf("\n🆕 <b>С кем ещё не было встреч (%d):</b>\n", len(neverMet)))
+		shown := neverMet
+		if len(shown) > maxNeverMet {
+			shown = shown[:maxNeverMet]
+		}
+		sb.WriteString(formatUsers(shown))
+		if len(neverMet) > maxNeverMet {
+			sb.WriteString(fmt.Sprintf(" <i>и ещё %d</i>", len(neverMet)-maxNeverMet))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// FormatCoffeeClubStatsView formats random coffee statistics for admins, weeks go in chronological order.
+// Only counts of personal exclusion lists are shown, their contents stay private.
+func FormatCoffeeClubStatsView(
+	weeks []utils.CoffeeWeekStats,
+	mostActive []repositories.RandomCoffeeActiveUser,
+	exclusions repositories.RandomCoffeeExclusionCounts,
+) string {
+	var sb strings.Builder
+	sb.WriteString("📊 <b>Статистика Random Coffee</b>\n\n")
+
+	if len(weeks) == 0 {
+		sb.WriteString("Опросов пока не было.")
+		return sb.String()
+	}
+
+	totalGroups, totalRepeats := 0, 0
+	sb.WriteString("📅 <b>По неделям:</b>\n")
+	for _, week := range weeks {
+		totalGroups += week.Groups
+		totalRepeats += week.RepeatGroups
+		sb.WriteString(fmt.Sprintf(
+			"└ %s — участников: %d, пар: %d, повторных: %d\n",
+			week.WeekStartDate.Format("02.01.2006"), week.Participants, week.Groups, week.RepeatGroups,
+		))
+	}
+
+	if totalGroups > 0 {
+		sb.WriteString(fmt.Sprintf(
+			"\n🔁 <b>Доля повторных пар:</b> %d%% (%d из %d)\n",
+			totalRepeats*100/totalGroups, totalRepeats, totalGroups,
+		))
+	}
+
+	if len(mostActive) > 0 {
+		sb.WriteString("\n🏆 <b>Самые активные участники:</b>\n")
+		for i, user := range mostActive {
+			sb.WriteString(fmt.Sprintf(
+				"%d. %s — встреч: %d\n",
+				i+1,
+				html.EscapeString(utils.FormatUserDisplayName(user.Firstname, user.Lastname, user.TgUsername)),
+				user.Meetings,
+			))
+		}
+	}
+
+	if exclusions.Exclusions > 0 {
+		sb.WriteString(fmt.Sprintf(
+			"\n🚫 <b>Личные ограничения:</b> участников со списком: %d, всего ограничений: %d\n",
+			exclusions.Users, exclusions.Exclusions,
+		))
+	}
+
+	return sb.String()
+}
+
+// FormatCoffeeDraftView formats draft pairs for admin approval, a zero autoPublishAt means there is no timeout
+func FormatCoffeeDraftView(
+	weekStartDate time.Time,
+	groups [][]repositories.User,
+	unpaired []repositories.User,
+	autoPublishAt time.Time,
+	timezone *time.Location,
+) string {
+	formatUser := func(user repositories.User) string {
+		return html.EscapeString(utils.FormatUserDisplayName(user.Firstname, user.Lastname, user.TgUsername))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📝 <b>Черновик пар Random Coffee</b> ➪ <b><i>неделя %s</i></b>\n\n", weekStartDate.Format("Mon, Jan 2")))
+
+	for i, group := range groups {
+		names := make([]string, 0, len(group))
+		for _, user := range group {
+			names = append(names, formatUser(user))
+		}
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, strings.Join(names, " x ")))
+	}
+
+	if len(unpaired) > 0 {
+		names := make([]string, 0, len(unpaired))
+		for _, user := range unpaired {
+			names = append(names, formatUser(user))
+		}
+		sb.WriteString(fmt.Sprintf("\n😔 <b>Без пары:</b> %s\n", strings.Join(names, ", ")))
+	}
+
+	sb.WriteString("\nПары увидят участники только после публикации.")
+	if !autoPublishAt.IsZero() {
+		sb.WriteString(fmt.Sprintf(
+			" Если не подтвердить черновик, он будет опубликован автоматически <b>%s</b>.",
+			autoPublishAt.In(timezone).Format("02.01 в 15:04"),
+		))
+	}
+
+	return sb.String()
+}
+
+// FormatCoffeeSubscriptionView formats the standing random coffee subscription of the user,
+// nextWeek is the week of the upcoming round
+func FormatCoffeeSubscriptionView(subscription *repositories.RandomCoffeeSubscription, nextWeek time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("🔔 <b>Подписка на Random Coffee</b>\n\n")
+
+	if subscription == nil {
+		sb.WriteString("У тебя пока нет подписки. Подпишись один раз — и бот будет подбирать тебе пару без голосования в еженедельном опросе. ")
+		sb.WriteString("Если на какой-то неделе не получится, поставь подписку на паузу.")
+		return sb.String()
+	}
+
+	frequency := "каждую неделю"
+	if subscription.Frequency == constants.CoffeeSubscriptionFrequencyBiweekly {
+		frequency = "раз в две недели"
+	}
+	sb.WriteString(fmt.Sprintf("🔁 <b>Частота:</b> %s\n", frequency))
+
+	if subscription.IsPaused(nextWeek) {
+		sb.WriteString(fmt.Sprintf("⏸ <b>Пауза:</b> до недели с %s\n", subscription.PausedUntil.Format("02.01.2006")))
+	}
+
+	nextRound := "не участвуешь"
+	for i := 0; i < 6; i++ {
+		week := nextWeek.AddDate(0, 0, 7*i)
+		if subscription.IsActive(week) {
+			nextRound = fmt.Sprintf("неделя с %s", week.Format("02.01.2006"))
+			break
+		}
+	}
+	sb.WriteString(fmt.Sprintf("📅 <b>Ближайший раунд:</b> %s\n", nextRound))
+	sb.WriteString("\nОтвет «Не в этот раз» в опросе исключает тебя только из одного раунда, подписка при этом сохраняется.")
+
+	return sb.String()
+}
+
+// FormatCoffeeExclusionsView formats the personal list of users the user doesn't want to be paired with
+func FormatCoffeeExclusionsView(users []repositories.User) string {
+	var sb strings.Builder
+	sb.WriteString("🚫 <b>С кем не объединять в пару</b>\n\n")
+	sb.WriteString("Бот никогда не подберёт тебе в пару участников из этого списка. ")
+	sb.WriteString("Список видишь только ты: ни участники, ни администраторы не узнают, кто в нём.\n\n")
+
+	if len(users) == 0 {
+		sb.WriteString("<i>Список пуст.</i>")
+		return sb.String()
+	}
+
+	for i, user := range users {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, html.EscapeString(utils.FormatUserDisplayName(user.Firstname, user.Lastname, user.TgUsername))))
+	}
+	if len(users) >= utils.MaxCoffeeExclusions {
+		sb.WriteString(fmt.Sprintf("\n<i>В списке может быть не больше %d участников.</i>", utils.MaxCoffeeExclusions))
+	}
+
+	return sb.String()
+}
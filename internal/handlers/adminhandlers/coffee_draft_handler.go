@@ -0,0 +1,229 @@
+package adminhandlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+)
+
+type coffeeDraftHandler struct {
+	config              *config.Config
+	randomCoffeeService *services.RandomCoffeeService
+}
+
+// NewCoffeeDraftHandler handles the buttons of the random coffee draft preview sent to admins
+func NewCoffeeDraftHandler(
+	config *config.Config,
+	randomCoffeeService *services.RandomCoffeeService,
+) ext.Handler {
+	h := &coffeeDraftHandler{
+		config:              config,
+		randomCoffeeService: randomCoffeeService,
+	}
+
+	return handlers.NewCallback(callbackquery.Prefix(constants.CoffeeDraftPrefix), h.handleCallback)
+}
+
+func (h *coffeeDraftHandler) handleCallback(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+
+	if !utils.IsUserAdminOrCreator(b, ctx.EffectiveUser.Id, h.config) {
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "Доступно только администраторам"})
+		return nil
+	}
+	_, _ = cb.Answer(b, nil)
+
+	for _, action := range []struct {
+		prefix string
+		handle func(b *gotgbot.Bot, ctx *ext.Context, pollID int, userIDs []int, page int) error
+	}{
+		{constants.CoffeeDraftPublishCallbackPrefix, h.handlePublish},
+		{constants.CoffeeDraftRegenerateCallbackPrefix, h.handleRegenerate},
+		{constants.CoffeeDraftSwapCallbackPrefix, h.handleSwap},
+		{constants.CoffeeDraftExcludeCallbackPrefix, h.handleExclude},
+		{constants.CoffeeDraftBackCallbackPrefix, h.handleBack},
+	} {
+		data, ok := strings.CutPrefix(cb.Data, action.prefix)
+		if !ok {
+			continue
+		}
+
+		ids, page, err := parseCoffeeDraftIDs(data)
+		if err != nil {
+			return fmt.Errorf("%s: invalid callback data %q: %w", utils.GetCurrentTypeName(), cb.Data, err)
+		}
+		return action.handle(b, ctx, ids[0], ids[1:], page)
+	}
+	return nil
+}
+
+// handlePublish publishes the draft after approval
+func (h *coffeeDraftHandler) handlePublish(b *gotgbot.Bot, ctx *ext.Context, pollID int, _ []int, _ int) error {
+	if err := h.randomCoffeeService.PublishDraft(context.Background(), pollID); err != nil {
+		log.Printf("%s: Error publishing draft of poll %d: %v", utils.GetCurrentTypeName(), pollID, err)
+		return h.editText(b, ctx, fmt.Sprintf("❌ Не удалось опубликовать пары: <code>%s</code>", html.EscapeString(err.Error())), nil)
+	}
+	return h.editText(b, ctx, "✅ Пары опубликованы в супергруппе!", nil)
+}
+
+// handleRegenerate replaces the draft with a new matching
+func (h *coffeeDraftHandler) handleRegenerate(b *gotgbot.Bot, ctx *ext.Context, pollID int, _ []int, _ int) error {
+	if err := h.randomCoffeeService.RegenerateDraft(pollID); err != nil {
+		log.Printf("%s: Error regenerating draft of poll %d: %v", utils.GetCurrentTypeName(), pollID, err)
+		return h.showDraft(b, ctx, pollID, fmt.Sprintf("❌ Не удалось сгенерировать пары заново: %s", err.Error()))
+	}
+	return h.showDraft(b, ctx, pollID, "")
+}
+
+// handleSwap asks for the first member, then for the second one, then swaps them
+func (h *coffeeDraftHandler) handleSwap(b *gotgbot.Bot, ctx *ext.Context, pollID int, userIDs []int, page int) error {
+	switch len(userIDs) {
+	case 0:
+		return h.showMembers(b, ctx, pollID, fmt.Sprintf("%s%d", constants.CoffeeDraftSwapCallbackPrefix, pollID),
+			"🔀 Выбери первого участника для обмена:", 0, page)
+	case 1:
+		return h.showMembers(b, ctx, pollID, fmt.Sprintf("%s%d_%d", constants.CoffeeDraftSwapCallbackPrefix, pollID, userIDs[0]),
+			"🔀 Выбери, с кем поменять его местами:", userIDs[0], page)
+	}
+
+	if err := h.randomCoffeeService.SwapDraftMembers(pollID, userIDs[0], userIDs[1]); err != nil {
+		log.Printf("%s: Error swapping draft members of poll %d: %v", utils.GetCurrentTypeName(), pollID, err)
+		if errors.Is(err, services.ErrCoffeeExcludedPair) {
+			return h.showDraft(b, ctx, pollID, fmt.Sprintf("❌ Нельзя поменять участников местами: %s.", err.Error()))
+		}
+		return h.showDraft(b, ctx, pollID, "❌ Не удалось поменять участников местами.")
+	}
+	return h.showDraft(b, ctx, pollID, "")
+}
+
+// handleExclude asks for the member and removes them from this round
+func (h *coffeeDraftHandler) handleExclude(b *gotgbot.Bot, ctx *ext.Context, pollID int, userIDs []int, page int) error {
+	if len(userIDs) == 0 {
+		return h.showMembers(b, ctx, pollID, fmt.Sprintf("%s%d", constants.CoffeeDraftExcludeCallbackPrefix, pollID),
+			"🚫 Выбери участника, которого нужно исключить из этого раунда:", 0, page)
+	}
+
+	if err := h.randomCoffeeService.ExcludeFromDraft(pollID, userIDs[0]); err != nil {
+		log.Printf("%s: Error excluding draft member of poll %d: %v", utils.GetCurrentTypeName(), pollID, err)
+		return h.showDraft(b, ctx, pollID, "❌ Не удалось исключить участника.")
+	}
+	return h.showDraft(b, ctx, pollID, "")
+}
+
+// handleBack returns to the draft preview
+func (h *coffeeDraftHandler) handleBack(b *gotgbot.Bot, ctx *ext.Context, pollID int, _ []int, _ int) error {
+	return h.showDraft(b, ctx, pollID, "")
+}
+
+// showDraft refreshes the draft preview in place, the notice is shown above it
+func (h *coffeeDraftHandler) showDraft(b *gotgbot.Bot, ctx *ext.Context, pollID int, notice string) error {
+	draft, err := h.randomCoffeeService.GetDraft(pollID)
+	if err != nil {
+		log.Printf("%s: Error getting draft of poll %d: %v", utils.GetCurrentTypeName(), pollID, err)
+		return h.editText(b, ctx, fmt.Sprintf("❌ %s", html.EscapeString(err.Error())), nil)
+	}
+
+	text := h.randomCoffeeService.FormatDraft(draft)
+	if notice != "" {
+		text = html.EscapeString(notice) + "\n\n" + text
+	}
+	markup := buttons.CoffeeDraftButtons(pollID)
+	return h.editText(b, ctx, text, &markup)
+}
+
+// showMembers shows a page of participants of the draft as buttons, skipping the already chosen one.
+// Button labels start with the pair number, so the full draft isn't repeated in the message.
+func (h *coffeeDraftHandler) showMembers(
+	b *gotgbot.Bot,
+	ctx *ext.Context,
+	pollID int,
+	callbackData string,
+	title string,
+	skipUserID int,
+	page int,
+) error {
+	draft, err := h.randomCoffeeService.GetDraft(pollID)
+	if err != nil {
+		log.Printf("%s: Error getting draft of poll %d: %v", utils.GetCurrentTypeName(), pollID, err)
+		return h.editText(b, ctx, fmt.Sprintf("❌ %s", html.EscapeString(err.Error())), nil)
+	}
+
+	var members []buttons.CoffeeDraftMember
+	addMember := func(id int, name string) {
+		if id != skipUserID {
+			members = append(members, buttons.CoffeeDraftMember{UserID: id, Name: name})
+		}
+	}
+	for i, pair := range draft.Pairs {
+		for _, member := range pair.Members {
+			addMember(member.ID, fmt.Sprintf("%d. %s", i+1, utils.FormatUserDisplayName(member.Firstname, member.Lastname, "")))
+		}
+	}
+	for _, user := range draft.Unpaired {
+		addMember(user.ID, fmt.Sprintf("😔 %s", utils.FormatUserDisplayName(user.Firstname, user.Lastname, "")))
+	}
+
+	pages := max(1, (len(members)+buttons.CoffeeDraftMembersPageSize-1)/buttons.CoffeeDraftMembersPageSize)
+	page = min(page, pages-1)
+
+	text := title
+	if pages > 1 {
+		text += fmt.Sprintf("\n\n<i>Страница %d из %d</i>", page+1, pages)
+	}
+	markup := buttons.CoffeeDraftMembersButtons(callbackData, members, pollID, page)
+	return h.editText(b, ctx, text, &markup)
+}
+
+func (h *coffeeDraftHandler) editText(b *gotgbot.Bot, ctx *ext.Context, text string, markup *gotgbot.InlineKeyboardMarkup) error {
+	opts := &gotgbot.EditMessageTextOpts{ParseMode: "HTML"}
+	if markup != nil {
+		opts.ReplyMarkup = *markup
+	}
+
+	if _, _, err := ctx.EffectiveMessage.EditText(b, text, opts); err != nil {
+		return fmt.Errorf("%s: failed to edit draft message: %w", utils.GetCurrentTypeName(), err)
+	}
+	return nil
+}
+
+// parseCoffeeDraftIDs parses "<pollID>[_<userID>...][_p<page>]" from the callback data, the page is 0 if absent
+func parseCoffeeDraftIDs(data string) ([]int, int, error) {
+	parts := strings.Split(data, "_")
+
+	page := 0
+	if pageText, ok := strings.CutPrefix(parts[len(parts)-1], "p"); ok {
+		var err error
+		if page, err = strconv.Atoi(pageText); err != nil || page < 0 {
+			return nil, 0, fmt.Errorf("invalid page %q", pageText)
+		}
+		parts = parts[:len(parts)-1]
+	}
+
+	ids := make([]int, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, 0, err
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, 0, fmt.Errorf("missing poll ID")
+	}
+	return ids, page, nil
+}
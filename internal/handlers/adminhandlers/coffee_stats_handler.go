@@ -0,0 +1,165 @@
+package adminhandlers
+
+import (
+	"fmt"
+	"log"
+
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/formatters"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+)
+
+const (
+	// Number of the last polls shown in the statistics
+	coffeeStatsWeeksLimit = 12
+	// Number of the most active members shown in the statistics
+	coffeeStatsMostActiveLimit = 10
+)
+
+type coffeeStatsHandler struct {
+	messageSenderService *services.MessageSenderService
+	permissionsService   *services.PermissionsService
+	statsRepository      *repositories.RandomCoffeeStatsRepository
+	exclusionRepository  *repositories.RandomCoffeeExclusionRepository
+}
+
+func NewCoffeeStatsHandler(
+	messageSenderService *services.MessageSenderService,
+	permissionsService *services.PermissionsService,
+	statsRepository *repositories.RandomCoffeeStatsRepository,
+	exclusionRepository *repositories.RandomCoffeeExclusionRepository,
+) ext.Handler {
+	h := &coffeeStatsHandler{
+		messageSenderService: messageSenderService,
+		permissionsService:   permissionsService,
+		statsRepository:      statsRepository,
+		exclusionRepository:  exclusionRepository,
+	}
+
+	return handlers.NewCommand(constants.CoffeeStatsCommand, h.handleCommand)
+}
+
+func (h *coffeeStatsHandler) handleCommand(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	if !h.permissionsService.CheckAdminAndPrivateChat(msg, constants.CoffeeStatsCommand) {
+		log.Printf("%s: User %d (%s) tried to use /%s without admin permissions.",
+			utils.GetCurrentTypeName(),
+			ctx.EffectiveUser.Id,
+			ctx.EffectiveUser.Username,
+			constants.CoffeeStatsCommand,
+		)
+		return nil
+	}
+
+	weeks, err := h.getWeeklyStats()
+	if err != nil {
+		log.Printf("%s: Error getting random coffee statistics: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении статистики Random Coffee.", nil)
+		return nil
+	}
+
+	mostActive, err := h.statsRepository.GetMostActiveUsers(coffeeStatsMostActiveLimit)
+	if err != nil {
+		log.Printf("%s: Error getting most active users: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении статистики Random Coffee.", nil)
+		return nil
+	}
+
+	exclusions, err := h.exclusionRepository.GetCounts()
+	if err != nil {
+		log.Printf("%s: Error getting exclusion counts: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении статистики Random Coffee.", nil)
+		return nil
+	}
+
+	h.messageSenderService.ReplyHtml(msg, formatters.FormatCoffeeClubStatsView(weeks, mostActive, exclusions), nil)
+
+	if len(weeks) > 0 {
+		h.sendCharts(msg.Chat.Id, weeks)
+	}
+	return nil
+}
+
+// getWeeklyStats combines participation of the last polls with the groups formed in them, in chronological order
+func (h *coffeeStatsHandler) getWeeklyStats() ([]utils.CoffeeWeekStats, error) {
+	participation, err := h.statsRepository.GetWeeklyParticipation(coffeeStatsWeeksLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repeats are counted over the whole history, not only the shown weeks
+	rounds, err := h.statsRepository.GetAllRounds()
+	if err != nil {
+		return nil, err
+	}
+	weekGroups := make([]utils.CoffeeWeekGroups, len(rounds))
+	for i, round := range rounds {
+		weekGroups[i] = round.CoffeeWeekGroups
+	}
+	repeats := utils.CoffeeRepeatGroups(weekGroups)
+
+	statsByPoll := make(map[int]utils.CoffeeWeekStats, len(rounds))
+	for i, round := range rounds {
+		statsByPoll[round.PollID] = utils.CoffeeWeekStats{
+			Groups:       len(round.Groups),
+			RepeatGroups: repeats[i],
+		}
+	}
+
+	weeks := make([]utils.CoffeeWeekStats, 0, len(participation))
+	for i := len(participation) - 1; i >= 0; i-- {
+		week := statsByPoll[participation[i].PollID]
+		week.WeekStartDate = participation[i].WeekStartDate
+		week.Participants = participation[i].Participants
+		weeks = append(weeks, week)
+	}
+	return weeks, nil
+}
+
+func (h *coffeeStatsHandler) sendCharts(chatID int64, weeks []utils.CoffeeWeekStats) {
+	participantsBars := make([]utils.ChartBar, 0, len(weeks))
+	repeatBars := make([]utils.ChartBar, 0, len(weeks))
+	for _, week := range weeks {
+		label := week.WeekStartDate.Format("02.01")
+		participantsBars = append(participantsBars, utils.ChartBar{
+			Label:      label,
+			Value:      float64(week.Participants),
+			ValueLabel: fmt.Sprintf("%d", week.Participants),
+		})
+
+		repeatRatio := 0
+		if week.Groups > 0 {
+			repeatRatio = week.RepeatGroups * 100 / week.Groups
+		}
+		repeatBars = append(repeatBars, utils.ChartBar{
+			Label:      label,
+			Value:      float64(repeatRatio),
+			ValueLabel: fmt.Sprintf("%d%%", repeatRatio),
+		})
+	}
+
+	charts := []struct {
+		fileName string
+		caption  string
+		bars     []utils.ChartBar
+	}{
+		{"coffee_participants.png", "📈 <b>Участники Random Coffee по неделям</b>", participantsBars},
+		{"coffee_repeats.png", "🔁 <b>Доля повторных пар по неделям</b>", repeatBars},
+	}
+
+	for _, chart := range charts {
+		data, err := utils.RenderBarChartPNG(chart.bars)
+		if err != nil {
+			log.Printf("%s: Error rendering chart %s: %v", utils.GetCurrentTypeName(), chart.fileName, err)
+			continue
+		}
+		_ = h.messageSenderService.SendPngWithCaption(chatID, chart.fileName, data, chart.caption)
+	}
+}
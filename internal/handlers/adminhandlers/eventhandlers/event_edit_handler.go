@@ -25,14 +25,23 @@ import (
 const (
 	// Conversation states names
 	eventEditStateSelectEvent   = "event_edit_state_select_event"
+	eventEditStateAskScope      = "event_edit_state_ask_scope"
 	eventEditStateAskEditType   = "event_edit_state_ask_edit_type"
 	eventEditStateEditName      = "event_edit_state_edit_name"
 	eventEditStateEditStartedAt = "event_edit_state_edit_started_at"
 	eventEditStateEditType      = "event_edit_state_edit_type"
+	eventEditStateEditCapacity  = "event_edit_state_edit_capacity"
+	eventEditStateEditDuration  = "event_edit_state_edit_duration"
+	eventEditStateEditLocation  = "event_edit_state_edit_location"
+	eventEditStateEditDescr     = "event_edit_state_edit_description"
+	eventEditStateEditSpeakers  = "event_edit_state_edit_speakers"
+	eventEditStateEditCover     = "event_edit_state_edit_cover"
+	eventEditStateEditStatus    = "event_edit_state_edit_status"
 
 	// Context data keys
 	eventEditCtxDataKeySelectedEventID   = "event_edit_ctx_data_selected_event_id"
 	eventEditCtxDataKeyEditType          = "event_edit_ctx_data_edit_type"
+	eventEditCtxDataKeyAllFuture         = "event_edit_ctx_data_all_future"
 	eventEditCtxDataKeyPreviousMessageID = "event_edit_ctx_data_previous_message_id"
 	eventEditCtxDataKeyPreviousChatID    = "event_edit_ctx_data_previous_chat_id"
 
@@ -43,28 +52,47 @@ const (
 	eventEditTypeName      = "name"
 	eventEditTypeStartDate = "startDate"
 	eventEditTypeType      = "type"
+	eventEditTypeCapacity  = "capacity"
+	eventEditTypeDuration  = "duration"
+	eventEditTypeLocation  = "location"
+	eventEditTypeDescr     = "description"
+	eventEditTypeSpeakers  = "speakers"
+	eventEditTypeCover     = "cover"
+	eventEditTypeStatus    = "status"
+
+	// eventEditClearValue removes an optional detail
+	eventEditClearValue = "-"
 )
 
 type eventEditHandler struct {
-	config               *config.Config
-	eventRepository      *repositories.EventRepository
-	messageSenderService *services.MessageSenderService
-	userStore            *utils.UserDataStore
-	permissionsService   *services.PermissionsService
+	config                 *config.Config
+	eventRepository        *repositories.EventRepository
+	userRepository         *repositories.UserRepository
+	messageSenderService   *services.MessageSenderService
+	eventAttendanceService *services.EventAttendanceService
+	eventSeriesService     *services.EventSeriesService
+	userStore              *utils.UserDataStore
+	permissionsService     *services.PermissionsService
 }
 
 func NewEventEditHandler(
 	config *config.Config,
 	eventRepository *repositories.EventRepository,
+	userRepository *repositories.UserRepository,
 	messageSenderService *services.MessageSenderService,
+	eventAttendanceService *services.EventAttendanceService,
+	eventSeriesService *services.EventSeriesService,
 	permissionsService *services.PermissionsService,
 ) ext.Handler {
 	h := &eventEditHandler{
-		config:               config,
-		eventRepository:      eventRepository,
-		messageSenderService: messageSenderService,
-		userStore:            utils.NewUserDataStore(),
-		permissionsService:   permissionsService,
+		config:                 config,
+		eventRepository:        eventRepository,
+		userRepository:         userRepository,
+		messageSenderService:   messageSenderService,
+		eventAttendanceService: eventAttendanceService,
+		eventSeriesService:     eventSeriesService,
+		userStore:              utils.NewUserDataStore(),
+		permissionsService:     permissionsService,
 	}
 
 	return handlers.NewConversation(
@@ -76,6 +104,10 @@ func NewEventEditHandler(
 				handlers.NewMessage(message.Text, h.handleSelectEvent),
 				handlers.NewCallback(callbackquery.Equal(eventEditCallbackConfirmCancel), h.handleCallbackCancel),
 			},
+			eventEditStateAskScope: {
+				handlers.NewMessage(message.Text, h.handleSelectScope),
+				handlers.NewCallback(callbackquery.Equal(eventEditCallbackConfirmCancel), h.handleCallbackCancel),
+			},
 			eventEditStateAskEditType: {
 				handlers.NewMessage(message.Text, h.handleSelectEditType),
 				handlers.NewCallback(callbackquery.Equal(eventEditCallbackConfirmCancel), h.handleCallbackCancel),
@@ -92,6 +124,35 @@ func NewEventEditHandler(
 				handlers.NewMessage(message.Text, h.handleEditType),
 				handlers.NewCallback(callbackquery.Equal(eventEditCallbackConfirmCancel), h.handleCallbackCancel),
 			},
+			eventEditStateEditCapacity: {
+				handlers.NewMessage(message.Text, h.handleEditCapacity),
+				handlers.NewCallback(callbackquery.Equal(eventEditCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventEditStateEditDuration: {
+				handlers.NewMessage(message.Text, h.handleEditDuration),
+				handlers.NewCallback(callbackquery.Equal(eventEditCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventEditStateEditLocation: {
+				handlers.NewMessage(message.Text, h.handleEditLocation),
+				handlers.NewCallback(callbackquery.Equal(eventEditCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventEditStateEditDescr: {
+				handlers.NewMessage(message.Text, h.handleEditDescription),
+				handlers.NewCallback(callbackquery.Equal(eventEditCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventEditStateEditSpeakers: {
+				handlers.NewMessage(message.Text, h.handleEditSpeakers),
+				handlers.NewCallback(callbackquery.Equal(eventEditCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventEditStateEditCover: {
+				handlers.NewMessage(message.Photo, h.handleEditCover),
+				handlers.NewMessage(message.Text, h.handleEditCoverText),
+				handlers.NewCallback(callbackquery.Equal(eventEditCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventEditStateEditStatus: {
+				handlers.NewMessage(message.Text, h.handleEditStatus),
+				handlers.NewCallback(callbackquery.Equal(eventEditCallbackConfirmCancel), h.handleCallbackCancel),
+			},
 		},
 		&handlers.ConversationOpts{
 			Exits: []ext.Handler{handlers.NewCommand(constants.CancelCommand, h.handleCancel)},
@@ -130,7 +191,7 @@ func (h *eventEditHandler) startEdit(b *gotgbot.Bot, ctx *ext.Context) error {
 	// Create a list of events to display
 	title := fmt.Sprintf("Последние %d мероприятия:", len(events))
 	actionDescription := "которое ты хочешь отредактировать"
-	formattedResponse := formatters.FormatEventListForAdmin(events, title, constants.CancelCommand, actionDescription)
+	formattedResponse := formatters.FormatEventListForAdmin(events, title, constants.CancelCommand, actionDescription, h.config.ClubTimezone)
 
 	sentMsg, _ := h.messageSenderService.ReplyMarkdownWithReturnMessage(
 		msg,
@@ -156,7 +217,7 @@ func (h *eventEditHandler) handleSelectEvent(b *gotgbot.Bot, ctx *ext.Context) e
 	}
 
 	// Check if content with this ID exists
-	_, err = h.eventRepository.GetEventByID(eventID)
+	event, err := h.eventRepository.GetEventByID(eventID)
 	if err != nil {
 		log.Printf("%s: Error checking content with ID %d: %v", utils.GetCurrentTypeName(), eventID, err)
 		h.messageSenderService.Reply(
@@ -172,10 +233,49 @@ func (h *eventEditHandler) handleSelectEvent(b *gotgbot.Bot, ctx *ext.Context) e
 	// Store the selected event ID
 	h.userStore.Set(ctx.EffectiveUser.Id, eventEditCtxDataKeySelectedEventID, eventID)
 
+	// An occurrence of a recurring event can be edited alone or together with the following ones
+	if event.SeriesID != nil {
+		sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(
+			msg,
+			"🔁 Это мероприятие повторяется каждую неделю. Что редактировать?\n/1. Только это мероприятие\n/2. Это и все следующие\n\nВведи номер:",
+			&gotgbot.SendMessageOpts{
+				ReplyMarkup: buttons.CancelButton(eventEditCallbackConfirmCancel),
+			},
+		)
+
+		h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+		return handlers.NextConversationState(eventEditStateAskScope)
+	}
+
+	return h.askEditType(ctx)
+}
+
+// 2.1. handleSelectScope processes the choice between the occurrence and all following occurrences
+func (h *eventEditHandler) handleSelectScope(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	selectionText := strings.TrimSpace(strings.Replace(msg.Text, "/", "", 1))
+
+	selection, err := strconv.Atoi(selectionText)
+	if err != nil || selection < 1 || selection > 2 {
+		h.messageSenderService.Reply(msg, "Неверный выбор. Пожалуйста, введи 1 или 2, или используй кнопку для отмены", nil)
+		return nil // Stay in the same state
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+
+	h.userStore.Set(ctx.EffectiveUser.Id, eventEditCtxDataKeyAllFuture, selection == 2)
+
+	return h.askEditType(ctx)
+}
+
+// askEditType asks what the user wants to edit
+func (h *eventEditHandler) askEditType(ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
 	// Ask what the user wants to edit
 	sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(
 		msg,
-		fmt.Sprintf("Что ты хочешь отредактировать?\n/1. Название\n/2. Дату начала\n/3. Тип\n\nВведи номер:"),
+		fmt.Sprintf("Что ты хочешь отредактировать?\n/1. Название\n/2. Дату начала\n/3. Тип\n/4. Количество мест\n/5. Длительность\n/6. Место или ссылку\n/7. Описание\n/8. Спикеров\n/9. Обложку\n/10. Статус\n/11. Выгрузить список участников\n\nВведи номер:"),
 		&gotgbot.SendMessageOpts{
 			ReplyMarkup: buttons.CancelButton(eventEditCallbackConfirmCancel),
 		},
@@ -219,15 +319,26 @@ func (h *eventEditHandler) handleSelectEditType(b *gotgbot.Bot, ctx *ext.Context
 
 	// Parse the selection
 	selection, err := strconv.Atoi(selectionText)
-	if err != nil || selection < 1 || selection > 3 {
+	if err != nil || selection < 1 || selection > 11 {
 		h.messageSenderService.Reply(msg, fmt.Sprintf(
-			"Неверный выбор. Пожалуйста, введи число от 1 до 3, или используй кнопку для отмены",
+			"Неверный выбор. Пожалуйста, введи число от 1 до 11, или используй кнопку для отмены",
 		), nil)
 		return nil // Stay in the same state
 	}
 
 	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
 
+	// The export needs no new value, it's sent right away
+	if selection == 11 {
+		if err := h.eventAttendanceService.SendAttendeesExport(msg.Chat.Id, eventID); err != nil {
+			h.messageSenderService.Reply(msg, "Произошла ошибка при выгрузке списка участников.", nil)
+			log.Printf("%s: Error during attendees export: %v", utils.GetCurrentTypeName(), err)
+		}
+
+		h.userStore.Clear(ctx.EffectiveUser.Id)
+		return handlers.EndConversation()
+	}
+
 	var editType string
 	var nextState string
 	var message string
@@ -242,13 +353,13 @@ func (h *eventEditHandler) handleSelectEditType(b *gotgbot.Bot, ctx *ext.Context
 		nextState = eventEditStateEditStartedAt
 		var currentStartedAt string
 		if event.StartedAt != nil {
-			currentStartedAt = event.StartedAt.Format("02.01.2006 15:04")
+			currentStartedAt = event.StartedAt.In(h.config.ClubTimezone).Format("02.01.2006 15:04")
 		} else {
 			currentStartedAt = "не задана"
 		}
 		message = fmt.Sprintf(
-			"Текущая дата старта: `%s` (UTC)\nВведи новую дату и время в формате DD.MM.YYYY HH:MM (UTC):",
-			currentStartedAt,
+			"Текущая дата старта: `%s` (%s)\nВведи новую дату и время в формате DD.MM.YYYY HH:MM (%s):",
+			currentStartedAt, h.config.ClubTimezone, h.config.ClubTimezone,
 		)
 	case 3:
 		editType = eventEditTypeType
@@ -264,6 +375,80 @@ func (h *eventEditHandler) handleSelectEditType(b *gotgbot.Bot, ctx *ext.Context
 			"Текущий тип: *%s*\n\nДоступные типы:\n%s\nВведи новый тип или его номер:",
 			event.Type, availableTypes,
 		)
+	case 4:
+		editType = eventEditTypeCapacity
+		nextState = eventEditStateEditCapacity
+		currentCapacity := "без ограничений"
+		if event.Capacity != nil {
+			currentCapacity = strconv.Itoa(*event.Capacity)
+		}
+		message = fmt.Sprintf(
+			"Текущее количество мест: *%s*\n\nВведи новое количество мест или 0, чтобы снять ограничение.\nЕсли мест станет больше, их займут участники из листа ожидания.",
+			currentCapacity,
+		)
+	case 5:
+		editType = eventEditTypeDuration
+		nextState = eventEditStateEditDuration
+		currentDuration := "не задана"
+		if event.DurationMinutes != nil {
+			currentDuration = formatters.FormatEventDuration(*event.DurationMinutes)
+		}
+		message = fmt.Sprintf(
+			"Текущая длительность: *%s*\n\nВведи новую длительность в минутах (например, 90) или в формате H:MM, или 0, чтобы убрать её.\nБез длительности мероприятие считается идущим %s.",
+			currentDuration, formatters.FormatEventDuration(int(services.EventDefaultDuration.Minutes())),
+		)
+	case 6:
+		editType = eventEditTypeLocation
+		nextState = eventEditStateEditLocation
+		message = fmt.Sprintf(
+			"Текущее место: %s\n\nВведи адрес или ссылку на онлайн-встречу, или «%s», чтобы убрать их:",
+			formatEventEditCurrentValue(event.Location), eventEditClearValue,
+		)
+	case 7:
+		editType = eventEditTypeDescr
+		nextState = eventEditStateEditDescr
+		message = fmt.Sprintf(
+			"Текущее описание: %s\n\nВведи новое описание или «%s», чтобы убрать его:",
+			formatEventEditCurrentValue(event.Description), eventEditClearValue,
+		)
+	case 8:
+		editType = eventEditTypeSpeakers
+		nextState = eventEditStateEditSpeakers
+		speakerNames, err := h.userRepository.GetDisplayNamesByIDs(event.SpeakerUserIDs)
+		if err != nil {
+			log.Printf("%s: Error getting speaker names: %v", utils.GetCurrentTypeName(), err)
+		}
+		message = fmt.Sprintf(
+			"Текущие спикеры: %s\n\nВведи юзернеймы спикеров через пробел (например, @ivan @maria) или «%s», чтобы убрать их:",
+			formatEventEditCurrentValue(formatters.FormatEventSpeakers(event.SpeakerUserIDs, speakerNames)), eventEditClearValue,
+		)
+	case 9:
+		editType = eventEditTypeCover
+		nextState = eventEditStateEditCover
+		currentCover := "не задана"
+		if event.CoverFileID != "" {
+			currentCover = "задана"
+		}
+		message = fmt.Sprintf(
+			"Обложка: *%s*\n\nОтправь новую картинку для обложки или «%s», чтобы убрать её:",
+			currentCover, eventEditClearValue,
+		)
+	case 10:
+		editType = eventEditTypeStatus
+		nextState = eventEditStateEditStatus
+
+		// Prepare available statuses for display
+		var availableStatuses string
+		for i, status := range constants.AllEventStatuses {
+			availableStatuses += fmt.Sprintf("/%d. %s %s\n", i+1, formatters.GetStatusEmoji(status), formatters.GetStatusInRussian(status))
+		}
+
+		currentStatus := constants.EventStatus(event.Status)
+		message = fmt.Sprintf(
+			"Текущий статус: %s *%s*\n\nДоступные статусы:\n%s\nВведи номер статуса. Статус меняется только у этого мероприятия, "+
+				"дальше запланированные и идущие мероприятия переходят в следующий статус автоматически по времени:",
+			formatters.GetStatusEmoji(currentStatus), formatters.GetStatusInRussian(currentStatus), availableStatuses,
+		)
 	}
 
 	// Store the edit type
@@ -317,7 +502,14 @@ func (h *eventEditHandler) handleEditName(b *gotgbot.Bot, ctx *ext.Context) erro
 	}
 
 	// Update the event name
-	err := h.eventRepository.UpdateEventName(eventID, newName)
+	var err error
+	if h.isAllFuture(ctx.EffectiveUser.Id) {
+		err = h.updateFuture(eventID, func(event repositories.Event) error {
+			return h.eventSeriesService.RenameFuture(event, newName)
+		})
+	} else {
+		err = h.eventRepository.UpdateEventName(eventID, newName)
+	}
 	if err != nil {
 		h.messageSenderService.Reply(msg, "Произошла ошибка при обновлении названия мероприятия.", nil)
 		log.Printf("%s: Error during event update: %v", utils.GetCurrentTypeName(), err)
@@ -328,8 +520,8 @@ func (h *eventEditHandler) handleEditName(b *gotgbot.Bot, ctx *ext.Context) erro
 	h.messageSenderService.ReplyMarkdown(
 		msg,
 		fmt.Sprintf(
-			"Название мероприятия с ID `%d` успешно обновлено на *\"%s\"* \n\nДля продолжения редактирования мероприятия используй команду /%s.\nДля просмотра всех команд используй команду /%s",
-			eventID, newName, constants.EventEditCommand, constants.HelpCommand,
+			"Название мероприятия с ID `%d` успешно обновлено на *\"%s\"* %s\n\nДля продолжения редактирования мероприятия используй команду /%s.\nДля просмотра всех команд используй команду /%s",
+			eventID, newName, h.scopeNote(ctx.EffectiveUser.Id), constants.EventEditCommand, constants.HelpCommand,
 		),
 		nil,
 	)
@@ -345,11 +537,12 @@ func (h *eventEditHandler) handleEditStartedAt(b *gotgbot.Bot, ctx *ext.Context)
 	msg := ctx.EffectiveMessage
 	dateTimeStr := strings.TrimSpace(msg.Text)
 
-	// Parse the start date
-	startedAt, err := time.Parse("02.01.2006 15:04", dateTimeStr)
+	// Parse the start date in the club timezone
+	startedAt, err := time.ParseInLocation("02.01.2006 15:04", dateTimeStr, h.config.ClubTimezone)
 	if err != nil {
 		h.messageSenderService.ReplyMarkdown(msg, fmt.Sprintf(
-			"Неверный формат даты. Пожалуйста, введи дату и время в формате *DD.MM.YYYY HH:MM* (UTC) или используй кнопку для отмены.",
+			"Неверный формат даты. Пожалуйста, введи дату и время в формате *DD.MM.YYYY HH:MM* (%s) или используй кнопку для отмены.",
+			h.config.ClubTimezone,
 		), nil)
 		return nil // Stay in the same state
 	}
@@ -376,8 +569,14 @@ func (h *eventEditHandler) handleEditStartedAt(b *gotgbot.Bot, ctx *ext.Context)
 		return handlers.EndConversation()
 	}
 
-	// Update the event start date
-	err = h.eventRepository.UpdateEventStartedAt(eventID, startedAt)
+	// Update the event start date, following occurrences keep the weekly step from the new date
+	if h.isAllFuture(ctx.EffectiveUser.Id) {
+		err = h.updateFuture(eventID, func(event repositories.Event) error {
+			return h.eventSeriesService.RescheduleFuture(event, startedAt)
+		})
+	} else {
+		err = h.eventRepository.UpdateEventStartedAt(eventID, startedAt)
+	}
 	if err != nil {
 		h.messageSenderService.Reply(msg, "Произошла ошибка при обновлении даты начала мероприятия.", nil)
 		log.Printf("%s: Error during event update: %v", utils.GetCurrentTypeName(), err)
@@ -386,8 +585,8 @@ func (h *eventEditHandler) handleEditStartedAt(b *gotgbot.Bot, ctx *ext.Context)
 
 	// Confirmation message
 	h.messageSenderService.ReplyMarkdown(msg, fmt.Sprintf(
-		"Дата начала мероприятия с ID %d успешно обновлена на *%s* \n\nДля продолжения редактирования мероприятия используй команду /%s.\nДля просмотра всех команд используй команду /%s",
-		eventID, startedAt.Format("02.01.2006 15:04 UTC"), constants.EventEditCommand, constants.HelpCommand,
+		"Дата начала мероприятия с ID %d успешно обновлена на *%s* %s\n\nДля продолжения редактирования мероприятия используй команду /%s.\nДля просмотра всех команд используй команду /%s",
+		eventID, formatters.FormatEventStartedAt(startedAt, h.config.ClubTimezone), h.scopeNote(ctx.EffectiveUser.Id), constants.EventEditCommand, constants.HelpCommand,
 	), nil)
 
 	// Clean up user data
@@ -462,7 +661,13 @@ func (h *eventEditHandler) handleEditType(b *gotgbot.Bot, ctx *ext.Context) erro
 	}
 
 	// Update the event type
-	err = h.eventRepository.UpdateEventType(eventID, validEventType)
+	if h.isAllFuture(ctx.EffectiveUser.Id) {
+		err = h.updateFuture(eventID, func(event repositories.Event) error {
+			return h.eventSeriesService.ChangeTypeFuture(event, validEventType)
+		})
+	} else {
+		err = h.eventRepository.UpdateEventType(eventID, validEventType)
+	}
 	if err != nil {
 		h.messageSenderService.Reply(msg, "Произошла ошибка при обновлении типа мероприятия.", nil)
 		log.Printf("%s: Error during event update: %v", utils.GetCurrentTypeName(), err)
@@ -473,10 +678,235 @@ func (h *eventEditHandler) handleEditType(b *gotgbot.Bot, ctx *ext.Context) erro
 	h.messageSenderService.ReplyMarkdown(
 		msg,
 		fmt.Sprintf(
-			"Тип мероприятия с ID %d успешно обновлен на %s *'%s'* \n\nДля продолжения редактирования мероприятия используй команду /%s.\nДля просмотра всех команд используй команду /%s",
+			"Тип мероприятия с ID %d успешно обновлен на %s *'%s'* %s\n\nДля продолжения редактирования мероприятия используй команду /%s.\nДля просмотра всех команд используй команду /%s",
 			eventID,
 			formatters.GetTypeEmoji(validEventType),
 			validEventType,
+			h.scopeNote(ctx.EffectiveUser.Id),
+			constants.EventEditCommand,
+			constants.HelpCommand,
+		),
+		nil,
+	)
+
+	// Clean up user data
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+// 4.4. handleEditCapacity processes the new capacity input and updates the event
+func (h *eventEditHandler) handleEditCapacity(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	input := strings.TrimSpace(strings.Replace(msg.Text, "/", "", 1))
+
+	value, err := strconv.Atoi(input)
+	if err != nil || value < 0 {
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Неверное количество мест. Пожалуйста, введи целое число (0 — без ограничений) или используй кнопку для отмены:",
+		), nil)
+		return nil // Stay in the same state
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+
+	// Get the selected event ID
+	eventIDVal, ok := h.userStore.Get(ctx.EffectiveUser.Id, eventEditCtxDataKeySelectedEventID)
+	if !ok {
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Произошла ошибка при получении выбранного мероприятия. Пожалуйста, начни заново с /%s",
+			constants.EventEditCommand,
+		), nil)
+		return handlers.EndConversation()
+	}
+
+	eventID, ok := eventIDVal.(int)
+	if !ok {
+		log.Println("Invalid event ID type:", eventIDVal)
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Произошла внутренняя ошибка (неверный тип ID). Пожалуйста, начни заново с /%s",
+			constants.EventEditCommand,
+		), nil)
+		return handlers.EndConversation()
+	}
+
+	var capacity *int
+	capacityText := "без ограничений"
+	if value > 0 {
+		capacity = &value
+		capacityText = strconv.Itoa(value)
+	}
+
+	// Update the event capacity, users from the waitlist take the added spots
+	if h.isAllFuture(ctx.EffectiveUser.Id) {
+		err = h.updateFuture(eventID, func(event repositories.Event) error {
+			return h.eventSeriesService.SetCapacityFuture(event, capacity)
+		})
+	} else {
+		err = h.eventAttendanceService.SetCapacity(eventID, capacity)
+	}
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при обновлении количества мест.", nil)
+		log.Printf("%s: Error during event update: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	// Confirmation message
+	h.messageSenderService.ReplyMarkdown(
+		msg,
+		fmt.Sprintf(
+			"Количество мест мероприятия с ID %d успешно обновлено: *%s* %s\n\nДля продолжения редактирования мероприятия используй команду /%s.\nДля просмотра всех команд используй команду /%s",
+			eventID, capacityText, h.scopeNote(ctx.EffectiveUser.Id), constants.EventEditCommand, constants.HelpCommand,
+		),
+		nil,
+	)
+
+	// Clean up user data
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+// 4.5. handleEditDuration processes the new duration input and updates the event
+func (h *eventEditHandler) handleEditDuration(b *gotgbot.Bot, ctx *ext.Context) error {
+	minutes, ok := parseEventDuration(ctx.EffectiveMessage.Text)
+	if !ok {
+		h.messageSenderService.Reply(ctx.EffectiveMessage,
+			"Неверная длительность. Пожалуйста, введи число минут, H:MM или 0, чтобы убрать её, или используй кнопку для отмены:",
+			nil,
+		)
+		return nil // Stay in the same state
+	}
+
+	var duration *int
+	if minutes > 0 {
+		duration = &minutes
+	}
+	return h.updateDetails(b, ctx, "длительность", func(details *repositories.EventDetails) {
+		details.DurationMinutes = duration
+	})
+}
+
+// 4.6. handleEditLocation processes the new location input and updates the event
+func (h *eventEditHandler) handleEditLocation(b *gotgbot.Bot, ctx *ext.Context) error {
+	location := parseEventEditTextValue(ctx.EffectiveMessage.Text)
+	return h.updateDetails(b, ctx, "место", func(details *repositories.EventDetails) {
+		details.Location = location
+	})
+}
+
+// 4.7. handleEditDescription processes the new description input and updates the event
+func (h *eventEditHandler) handleEditDescription(b *gotgbot.Bot, ctx *ext.Context) error {
+	description := parseEventEditTextValue(ctx.EffectiveMessage.Text)
+	return h.updateDetails(b, ctx, "описание", func(details *repositories.EventDetails) {
+		details.Description = description
+	})
+}
+
+// 4.8. handleEditSpeakers processes the new speakers input and updates the event
+func (h *eventEditHandler) handleEditSpeakers(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	var speakerUserIDs []int64
+	if parseEventEditTextValue(msg.Text) != "" {
+		var unknown []string
+		var err error
+		speakerUserIDs, unknown, err = parseEventSpeakers(h.userRepository, msg.Text)
+		if err != nil {
+			h.messageSenderService.Reply(msg, "Произошла ошибка при поиске спикеров.", nil)
+			log.Printf("%s: Error during speakers lookup: %v", utils.GetCurrentTypeName(), err)
+			return nil // Stay in the same state
+		}
+		if len(unknown) > 0 || len(speakerUserIDs) == 0 {
+			h.messageSenderService.Reply(msg, formatUnknownSpeakersMessage(unknown), nil)
+			return nil // Stay in the same state
+		}
+	}
+
+	return h.updateDetails(b, ctx, "спикеры", func(details *repositories.EventDetails) {
+		details.SpeakerUserIDs = speakerUserIDs
+	})
+}
+
+// 4.9. handleEditCover processes the new cover image and updates the event
+func (h *eventEditHandler) handleEditCover(b *gotgbot.Bot, ctx *ext.Context) error {
+	photos := ctx.EffectiveMessage.Photo
+	// The last size is the largest one
+	coverFileID := photos[len(photos)-1].FileId
+
+	return h.updateDetails(b, ctx, "обложка", func(details *repositories.EventDetails) {
+		details.CoverFileID = coverFileID
+	})
+}
+
+// handleEditCoverText removes the cover or reminds that the cover must be an image
+func (h *eventEditHandler) handleEditCoverText(b *gotgbot.Bot, ctx *ext.Context) error {
+	if parseEventEditTextValue(ctx.EffectiveMessage.Text) != "" {
+		h.messageSenderService.Reply(ctx.EffectiveMessage, fmt.Sprintf(
+			"Обложка должна быть картинкой. Отправь картинку, «%s», чтобы убрать обложку, или используй кнопку для отмены:",
+			eventEditClearValue,
+		), nil)
+		return nil // Stay in the same state
+	}
+
+	return h.updateDetails(b, ctx, "обложка", func(details *repositories.EventDetails) {
+		details.CoverFileID = ""
+	})
+}
+
+// 4.10. handleEditStatus processes the new status selection and updates the event
+func (h *eventEditHandler) handleEditStatus(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	input := strings.TrimSpace(strings.Replace(msg.Text, "/", "", 1))
+
+	index, err := strconv.Atoi(input)
+	if err != nil || index < 1 || index > len(constants.AllEventStatuses) {
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Неверный выбор. Пожалуйста, введи число от 1 до %d, или используй кнопку для отмены",
+			len(constants.AllEventStatuses),
+		), nil)
+		return nil // Stay in the same state
+	}
+	status := constants.AllEventStatuses[index-1]
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+
+	// Get the selected event ID
+	eventIDVal, ok := h.userStore.Get(ctx.EffectiveUser.Id, eventEditCtxDataKeySelectedEventID)
+	if !ok {
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Произошла ошибка при получении выбранного мероприятия. Пожалуйста, начни заново с /%s",
+			constants.EventEditCommand,
+		), nil)
+		return handlers.EndConversation()
+	}
+
+	eventID, ok := eventIDVal.(int)
+	if !ok {
+		log.Println("Invalid event ID type:", eventIDVal)
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Произошла внутренняя ошибка (неверный тип ID). Пожалуйста, начни заново с /%s",
+			constants.EventEditCommand,
+		), nil)
+		return handlers.EndConversation()
+	}
+
+	// Update the event status, it applies to the selected occurrence only
+	err = h.eventRepository.UpdateEventStatus(eventID, status)
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при обновлении статуса мероприятия.", nil)
+		log.Printf("%s: Error during event update: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	// Confirmation message
+	h.messageSenderService.ReplyMarkdown(
+		msg,
+		fmt.Sprintf(
+			"Статус мероприятия с ID %d успешно обновлен на %s *%s*\n\nДля продолжения редактирования мероприятия используй команду /%s.\nДля просмотра всех команд используй команду /%s",
+			eventID,
+			formatters.GetStatusEmoji(status),
+			formatters.GetStatusInRussian(status),
 			constants.EventEditCommand,
 			constants.HelpCommand,
 		),
@@ -489,6 +919,96 @@ func (h *eventEditHandler) handleEditType(b *gotgbot.Bot, ctx *ext.Context) erro
 	return handlers.EndConversation()
 }
 
+// updateDetails applies the update to the details of the selected event or of the following occurrences too,
+// field names the updated detail in the confirmation
+func (h *eventEditHandler) updateDetails(
+	b *gotgbot.Bot,
+	ctx *ext.Context,
+	field string,
+	update func(details *repositories.EventDetails),
+) error {
+	msg := ctx.EffectiveMessage
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+
+	// Get the selected event ID
+	eventIDVal, ok := h.userStore.Get(ctx.EffectiveUser.Id, eventEditCtxDataKeySelectedEventID)
+	if !ok {
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Произошла ошибка при получении выбранного мероприятия. Пожалуйста, начни заново с /%s",
+			constants.EventEditCommand,
+		), nil)
+		return handlers.EndConversation()
+	}
+
+	eventID, ok := eventIDVal.(int)
+	if !ok {
+		log.Println("Invalid event ID type:", eventIDVal)
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Произошла внутренняя ошибка (неверный тип ID). Пожалуйста, начни заново с /%s",
+			constants.EventEditCommand,
+		), nil)
+		return handlers.EndConversation()
+	}
+
+	// Update the event details
+	allFuture := h.isAllFuture(ctx.EffectiveUser.Id)
+	err := h.updateFuture(eventID, func(event repositories.Event) error {
+		if allFuture {
+			return h.eventSeriesService.UpdateDetailsFuture(event, update)
+		}
+		update(&event.EventDetails)
+		return h.eventRepository.UpdateEventDetails(event.ID, event.EventDetails)
+	})
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при обновлении мероприятия.", nil)
+		log.Printf("%s: Error during event update: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	// Confirmation message
+	h.messageSenderService.ReplyMarkdown(
+		msg,
+		fmt.Sprintf(
+			"Мероприятие с ID %d успешно обновлено: *%s* %s\n\nДля продолжения редактирования мероприятия используй команду /%s.\nДля просмотра всех команд используй команду /%s",
+			eventID, field, h.scopeNote(ctx.EffectiveUser.Id), constants.EventEditCommand, constants.HelpCommand,
+		),
+		nil,
+	)
+
+	// Clean up user data
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+// isAllFuture reports whether the edit applies to the selected occurrence and all following ones
+func (h *eventEditHandler) isAllFuture(userID int64) bool {
+	allFutureVal, ok := h.userStore.Get(userID, eventEditCtxDataKeyAllFuture)
+	if !ok {
+		return false
+	}
+	allFuture, ok := allFutureVal.(bool)
+	return ok && allFuture
+}
+
+// updateFuture applies the series update starting from the selected occurrence
+func (h *eventEditHandler) updateFuture(eventID int, update func(event repositories.Event) error) error {
+	event, err := h.eventRepository.GetEventByID(eventID)
+	if err != nil {
+		return err
+	}
+	return update(*event)
+}
+
+// scopeNote is added to the confirmation when the following occurrences were edited too
+func (h *eventEditHandler) scopeNote(userID int64) string {
+	if !h.isAllFuture(userID) {
+		return ""
+	}
+	return "\n🔁 Изменение применено и ко всем следующим повторениям."
+}
+
 // handleCallbackCancel processes the cancel button click
 func (h *eventEditHandler) handleCallbackCancel(b *gotgbot.Bot, ctx *ext.Context) error {
 	// Answer the callback query to remove the loading state on the button
@@ -536,3 +1056,20 @@ func (h *eventEditHandler) SavePreviousMessageInfo(userID int64, sentMsg *gotgbo
 	h.userStore.SetPreviousMessageInfo(userID, sentMsg.MessageId, sentMsg.Chat.Id,
 		eventEditCtxDataKeyPreviousMessageID, eventEditCtxDataKeyPreviousChatID)
 }
+
+// parseEventEditTextValue trims the input, the clear value gives an empty string
+func parseEventEditTextValue(input string) string {
+	input = strings.TrimSpace(input)
+	if input == eventEditClearValue {
+		return ""
+	}
+	return input
+}
+
+// formatEventEditCurrentValue shows the current value of a text detail in a Markdown prompt
+func formatEventEditCurrentValue(value string) string {
+	if value == "" {
+		return "*не задано*"
+	}
+	return fmt.Sprintf("\n```\n%s\n```", strings.ReplaceAll(value, "`", "'"))
+}
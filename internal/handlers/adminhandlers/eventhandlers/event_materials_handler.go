@@ -0,0 +1,486 @@
+package eventhandlers
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/formatters"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/message"
+)
+
+const (
+	// Conversation states names
+	eventMaterialsStateSelectEvent  = "event_materials_state_select_event"
+	eventMaterialsStateSelectAction = "event_materials_state_select_action"
+	eventMaterialsStateAddLink      = "event_materials_state_add_link"
+	eventMaterialsStateAddDocument  = "event_materials_state_add_document"
+	eventMaterialsStateAddNote      = "event_materials_state_add_note"
+	eventMaterialsStateDelete       = "event_materials_state_delete"
+
+	// Context data keys
+	eventMaterialsCtxDataKeySelectedEventID   = "event_materials_ctx_data_selected_event_id"
+	eventMaterialsCtxDataKeyPreviousMessageID = "event_materials_ctx_data_previous_message_id"
+	eventMaterialsCtxDataKeyPreviousChatID    = "event_materials_ctx_data_previous_chat_id"
+
+	// Callback data
+	eventMaterialsCallbackConfirmCancel = "event_materials_callback_confirm_cancel"
+)
+
+type eventMaterialsHandler struct {
+	config               *config.Config
+	eventRepository      *repositories.EventRepository
+	eventArchiveService  *services.EventArchiveService
+	messageSenderService *services.MessageSenderService
+	userStore            *utils.UserDataStore
+	permissionsService   *services.PermissionsService
+}
+
+func NewEventMaterialsHandler(
+	config *config.Config,
+	eventRepository *repositories.EventRepository,
+	eventArchiveService *services.EventArchiveService,
+	messageSenderService *services.MessageSenderService,
+	permissionsService *services.PermissionsService,
+) ext.Handler {
+	h := &eventMaterialsHandler{
+		config:               config,
+		eventRepository:      eventRepository,
+		eventArchiveService:  eventArchiveService,
+		messageSenderService: messageSenderService,
+		userStore:            utils.NewUserDataStore(),
+		permissionsService:   permissionsService,
+	}
+
+	return handlers.NewConversation(
+		[]ext.Handler{
+			handlers.NewCommand(constants.EventMaterialsCommand, h.startMaterials),
+		},
+		map[string][]ext.Handler{
+			eventMaterialsStateSelectEvent: {
+				handlers.NewMessage(message.Text, h.handleSelectEvent),
+				handlers.NewCallback(callbackquery.Equal(eventMaterialsCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventMaterialsStateSelectAction: {
+				handlers.NewMessage(message.Text, h.handleSelectAction),
+				handlers.NewCallback(callbackquery.Equal(eventMaterialsCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventMaterialsStateAddLink: {
+				handlers.NewMessage(message.Text, h.handleAddLink),
+				handlers.NewCallback(callbackquery.Equal(eventMaterialsCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventMaterialsStateAddDocument: {
+				handlers.NewMessage(message.Document, h.handleAddDocument),
+				handlers.NewMessage(message.Text, h.handleAddDocumentText),
+				handlers.NewCallback(callbackquery.Equal(eventMaterialsCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventMaterialsStateAddNote: {
+				handlers.NewMessage(message.Text, h.handleAddNote),
+				handlers.NewCallback(callbackquery.Equal(eventMaterialsCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventMaterialsStateDelete: {
+				handlers.NewMessage(message.Text, h.handleDelete),
+				handlers.NewCallback(callbackquery.Equal(eventMaterialsCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+		},
+		&handlers.ConversationOpts{
+			Exits: []ext.Handler{handlers.NewCommand(constants.CancelCommand, h.handleCancel)},
+		},
+	)
+}
+
+// 1. startMaterials is the entry point handler for the materials conversation
+func (h *eventMaterialsHandler) startMaterials(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	// Check if user has admin permissions and is in a private chat
+	if !h.permissionsService.CheckAdminAndPrivateChat(msg, constants.EventMaterialsCommand) {
+		log.Printf("%s: User %d (%s) tried to use /%s without admin permissions.",
+			utils.GetCurrentTypeName(),
+			ctx.EffectiveUser.Id,
+			ctx.EffectiveUser.Username,
+			constants.EventMaterialsCommand,
+		)
+		return handlers.EndConversation()
+	}
+
+	// Get a list of the last N finished events of all types
+	events, err := h.eventRepository.GetFinishedEvents("", constants.EventEditGetLastLimit, 0)
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении списка мероприятий.", nil)
+		log.Printf("%s: Error during event retrieval: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	if len(events) == 0 {
+		h.messageSenderService.Reply(msg, "Нет завершённых мероприятий, к которым можно прикрепить материалы.", nil)
+		return handlers.EndConversation()
+	}
+
+	title := fmt.Sprintf("Последние %d завершённых мероприятий:", len(events))
+	actionDescription := "материалы которого ты хочешь изменить"
+	formattedResponse := formatters.FormatEventListForAdmin(events, title, constants.CancelCommand, actionDescription, h.config.ClubTimezone)
+
+	sentMsg, _ := h.messageSenderService.ReplyMarkdownWithReturnMessage(
+		msg,
+		formattedResponse,
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.CancelButton(eventMaterialsCallbackConfirmCancel),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(eventMaterialsStateSelectEvent)
+}
+
+// 2. handleSelectEvent processes the user's selection of a finished event
+func (h *eventMaterialsHandler) handleSelectEvent(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	eventIDStr := strings.TrimSpace(strings.Replace(msg.Text, "/", "", 1))
+	eventID, err := strconv.Atoi(eventIDStr)
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Некорректный ID. Пожалуйста, введи числовой ID или используй кнопку для отмены.", nil)
+		return nil // Stay in the same state
+	}
+
+	// Any finished event can be chosen, not only the listed ones
+	event, err := h.eventRepository.GetEventByID(eventID)
+	if err != nil {
+		h.messageSenderService.Reply(
+			msg,
+			fmt.Sprintf("Мероприятие с ID %d не найдено. Пожалуйста, введи корректный ID или используй кнопку для отмены.", eventID),
+			nil,
+		)
+		return nil // Stay in the same state
+	}
+	if event.Status != string(constants.EventStatusFinished) {
+		h.messageSenderService.Reply(
+			msg,
+			"Материалы можно прикрепить только к завершённому мероприятию. Пожалуйста, введи ID другого мероприятия или используй кнопку для отмены.",
+			nil,
+		)
+		return nil // Stay in the same state
+	}
+
+	materials, err := h.eventArchiveService.GetMaterials(eventID)
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении материалов мероприятия.", nil)
+		log.Printf("%s: Error during materials retrieval: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+
+	h.userStore.Set(ctx.EffectiveUser.Id, eventMaterialsCtxDataKeySelectedEventID, eventID)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("Мероприятие: <b>%s</b>\n\n", html.EscapeString(event.Name)))
+	if len(materials) == 0 {
+		response.WriteString("Материалов пока нет.\n")
+	} else {
+		response.WriteString("Материалы:\n")
+		for i, material := range materials {
+			response.WriteString(fmt.Sprintf("%d. %s %s\n",
+				i+1, formatters.GetMaterialEmoji(material.Type), html.EscapeString(formatters.FormatEventMaterialTitle(material))))
+		}
+	}
+	response.WriteString("\nЧто ты хочешь сделать?\n/1. Добавить ссылку (запись, слайды)\n/2. Добавить документ\n/3. Добавить заметку\n")
+	if len(materials) > 0 {
+		response.WriteString("/4. Удалить материал\n")
+	}
+	response.WriteString("\nВведи номер:")
+
+	sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(
+		msg,
+		response.String(),
+		&gotgbot.SendMessageOpts{
+			ParseMode:   "HTML",
+			ReplyMarkup: buttons.CancelButton(eventMaterialsCallbackConfirmCancel),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(eventMaterialsStateSelectAction)
+}
+
+// 3. handleSelectAction processes the user's selection of what to do with the materials
+func (h *eventMaterialsHandler) handleSelectAction(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	selectionText := strings.TrimSpace(strings.Replace(msg.Text, "/", "", 1))
+
+	selection, err := strconv.Atoi(selectionText)
+	if err != nil || selection < 1 || selection > 4 {
+		h.messageSenderService.Reply(msg, "Неверный выбор. Пожалуйста, введи число от 1 до 4, или используй кнопку для отмены", nil)
+		return nil // Stay in the same state
+	}
+
+	var nextState string
+	var prompt string
+
+	switch selection {
+	case 1:
+		nextState = eventMaterialsStateAddLink
+		prompt = "Отправь ссылку и, через пробел, её название, например:\nhttps://youtu.be/example Запись воркшопа"
+	case 2:
+		nextState = eventMaterialsStateAddDocument
+		prompt = "Отправь документ (например, слайды в PDF). Подпись к документу станет его названием, без подписи будет использовано имя файла."
+	case 3:
+		nextState = eventMaterialsStateAddNote
+		prompt = "Отправь текст заметки (например, конспект или ответы на вопросы):"
+	case 4:
+		eventID, ok := h.selectedEventID(ctx)
+		if !ok {
+			return handlers.EndConversation()
+		}
+		materials, err := h.eventArchiveService.GetMaterials(eventID)
+		if err != nil {
+			h.messageSenderService.Reply(msg, "Произошла ошибка при получении материалов мероприятия.", nil)
+			log.Printf("%s: Error during materials retrieval: %v", utils.GetCurrentTypeName(), err)
+			return handlers.EndConversation()
+		}
+		if len(materials) == 0 {
+			h.messageSenderService.Reply(msg, "У мероприятия нет материалов для удаления. Пожалуйста, выбери другое действие или используй кнопку для отмены", nil)
+			return nil // Stay in the same state
+		}
+		nextState = eventMaterialsStateDelete
+		prompt = "Введи номер материала из списка выше, который ты хочешь удалить:"
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+
+	sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(
+		msg,
+		prompt,
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.CancelButton(eventMaterialsCallbackConfirmCancel),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(nextState)
+}
+
+// 4.1. handleAddLink processes the link input and attaches it to the event
+func (h *eventMaterialsHandler) handleAddLink(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	rawURL, title, _ := strings.Cut(strings.TrimSpace(msg.Text), " ")
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		h.messageSenderService.Reply(
+			msg,
+			"Неверная ссылка. Ссылка должна начинаться с http:// или https://. Попробуй ещё раз или используй кнопку для отмены:",
+			nil,
+		)
+		return nil // Stay in the same state
+	}
+
+	return h.addMaterial(b, ctx, constants.EventMaterialTypeLink, strings.TrimSpace(title), rawURL, "", "")
+}
+
+// 4.2. handleAddDocument processes the document and attaches it to the event
+func (h *eventMaterialsHandler) handleAddDocument(b *gotgbot.Bot, ctx *ext.Context) error {
+	document := ctx.EffectiveMessage.Document
+
+	title := strings.TrimSpace(ctx.EffectiveMessage.Caption)
+	if title == "" {
+		title = document.FileName
+	}
+
+	return h.addMaterial(b, ctx, constants.EventMaterialTypeDocument, title, "", document.FileId, "")
+}
+
+// handleAddDocumentText reminds that a document is expected
+func (h *eventMaterialsHandler) handleAddDocumentText(b *gotgbot.Bot, ctx *ext.Context) error {
+	h.messageSenderService.Reply(
+		ctx.EffectiveMessage,
+		"Пожалуйста, отправь документ файлом или используй кнопку для отмены.",
+		nil,
+	)
+	return nil // Stay in the same state
+}
+
+// 4.3. handleAddNote processes the note text and attaches it to the event
+func (h *eventMaterialsHandler) handleAddNote(b *gotgbot.Bot, ctx *ext.Context) error {
+	note := strings.TrimSpace(ctx.EffectiveMessage.Text)
+	if note == "" {
+		h.messageSenderService.Reply(ctx.EffectiveMessage, "Заметка не может быть пустой. Пожалуйста, отправь текст или используй кнопку для отмены:", nil)
+		return nil // Stay in the same state
+	}
+
+	return h.addMaterial(b, ctx, constants.EventMaterialTypeNote, "", "", "", note)
+}
+
+// 4.4. handleDelete processes the number of the material to delete
+func (h *eventMaterialsHandler) handleDelete(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	eventID, ok := h.selectedEventID(ctx)
+	if !ok {
+		return handlers.EndConversation()
+	}
+
+	materials, err := h.eventArchiveService.GetMaterials(eventID)
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении материалов мероприятия.", nil)
+		log.Printf("%s: Error during materials retrieval: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	index, err := strconv.Atoi(strings.TrimSpace(strings.Replace(msg.Text, "/", "", 1)))
+	if err != nil || index < 1 || index > len(materials) {
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Неверный номер. Пожалуйста, введи число от 1 до %d, или используй кнопку для отмены",
+			len(materials),
+		), nil)
+		return nil // Stay in the same state
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+
+	if err := h.eventArchiveService.DeleteMaterial(materials[index-1].ID); err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при удалении материала.", nil)
+		log.Printf("%s: Error during material deletion: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	h.messageSenderService.Reply(
+		msg,
+		fmt.Sprintf(
+			"Материал удалён из архива.\n\nДля продолжения работы с материалами используй команду /%s.\nДля просмотра всех команд используй команду /%s",
+			constants.EventMaterialsCommand, constants.HelpCommand,
+		),
+		nil,
+	)
+
+	// Clean up user data
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+// addMaterial attaches the material to the selected event and ends the conversation
+func (h *eventMaterialsHandler) addMaterial(
+	b *gotgbot.Bot,
+	ctx *ext.Context,
+	materialType constants.EventMaterialType,
+	title string,
+	linkURL string,
+	fileID string,
+	note string,
+) error {
+	msg := ctx.EffectiveMessage
+
+	eventID, ok := h.selectedEventID(ctx)
+	if !ok {
+		return handlers.EndConversation()
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+
+	err := h.eventArchiveService.AddMaterial(eventID, materialType, title, linkURL, fileID, note)
+	if errors.Is(err, services.ErrEventNotFinished) {
+		h.messageSenderService.Reply(msg, services.ErrEventNotFinished.Error(), nil)
+		return handlers.EndConversation()
+	}
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при добавлении материала.", nil)
+		log.Printf("%s: Error during material creation: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	h.messageSenderService.Reply(
+		msg,
+		fmt.Sprintf(
+			"%s Материал добавлен в архив, его можно найти в /%s и /%s.\n\nДля добавления ещё одного материала используй команду /%s.\nДля просмотра всех команд используй команду /%s",
+			formatters.GetMaterialEmoji(materialType),
+			constants.ArchiveCommand,
+			constants.ContentCommand,
+			constants.EventMaterialsCommand,
+			constants.HelpCommand,
+		),
+		nil,
+	)
+
+	// Clean up user data
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+// selectedEventID returns the event chosen at the start, the user is asked to start over if it's lost
+func (h *eventMaterialsHandler) selectedEventID(ctx *ext.Context) (int, bool) {
+	eventIDVal, _ := h.userStore.Get(ctx.EffectiveUser.Id, eventMaterialsCtxDataKeySelectedEventID)
+	eventID, ok := eventIDVal.(int)
+	if !ok {
+		h.messageSenderService.Reply(ctx.EffectiveMessage, fmt.Sprintf(
+			"Произошла ошибка при получении выбранного мероприятия. Пожалуйста, начни заново с /%s",
+			constants.EventMaterialsCommand,
+		), nil)
+		h.userStore.Clear(ctx.EffectiveUser.Id)
+	}
+	return eventID, ok
+}
+
+// handleCallbackCancel processes the cancel button click
+func (h *eventMaterialsHandler) handleCallbackCancel(b *gotgbot.Bot, ctx *ext.Context) error {
+	// Answer the callback query to remove the loading state on the button
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	return h.handleCancel(b, ctx)
+}
+
+// 5. handleCancel handles the /cancel command
+func (h *eventMaterialsHandler) handleCancel(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+	h.messageSenderService.Reply(msg, "Операция работы с материалами мероприятия отменена.", nil)
+
+	// Clean up user data
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+func (h *eventMaterialsHandler) MessageRemoveInlineKeyboard(b *gotgbot.Bot, userID *int64) {
+	var chatID, messageID int64
+
+	// If userID provided, get stored message info using the utility method
+	if userID != nil {
+		messageID, chatID = h.userStore.GetPreviousMessageInfo(
+			*userID,
+			eventMaterialsCtxDataKeyPreviousMessageID,
+			eventMaterialsCtxDataKeyPreviousChatID,
+		)
+	}
+
+	// Skip if we don't have valid chat and message IDs
+	if chatID == 0 || messageID == 0 {
+		return
+	}
+
+	// Use message sender service to remove the inline keyboard
+	_ = h.messageSenderService.RemoveInlineKeyboard(chatID, messageID)
+}
+
+func (h *eventMaterialsHandler) SavePreviousMessageInfo(userID int64, sentMsg *gotgbot.Message) {
+	h.userStore.SetPreviousMessageInfo(userID, sentMsg.MessageId, sentMsg.Chat.Id,
+		eventMaterialsCtxDataKeyPreviousMessageID, eventMaterialsCtxDataKeyPreviousChatID)
+}
@@ -1,8 +1,10 @@
 package eventhandlers
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"evo-bot-go/internal/config"
 	"evo-bot-go/internal/constants"
 	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/formatters"
 	"evo-bot-go/internal/services"
 	"evo-bot-go/internal/utils"
 
@@ -26,21 +29,36 @@ const (
 	eventSetupStateAskEventName      = "event_setup_state_ask_event_name"
 	eventSetupStateAskEventType      = "event_setup_state_ask_event_type"
 	eventSetupStateAskEventStartedAt = "event_setup_state_ask_event_started_at"
+	eventSetupStateAskDuration       = "event_setup_state_ask_duration"
+	eventSetupStateAskLocation       = "event_setup_state_ask_location"
+	eventSetupStateAskDescription    = "event_setup_state_ask_description"
+	eventSetupStateAskSpeakers       = "event_setup_state_ask_speakers"
+	eventSetupStateAskCover          = "event_setup_state_ask_cover"
+	eventSetupStateAskRecurrence     = "event_setup_state_ask_recurrence"
+	eventSetupStateAskRecurrenceEnd  = "event_setup_state_ask_recurrence_end"
 
 	// Context data keys
 	eventSetupCtxDataKeyEventName         = "event_setup_ctx_data_event_name"
 	eventSetupCtxDataKeyEventID           = "event_setup_ctx_data_event_id"
+	eventSetupCtxDataKeyStartedAt         = "event_setup_ctx_data_started_at"
+	eventSetupCtxDataKeyDetails           = "event_setup_ctx_data_details"
 	eventSetupCtxDataKeyPreviousMessageID = "event_setup_ctx_data_previous_message_id"
 	eventSetupCtxDataKeyPreviousChatID    = "event_setup_ctx_data_previous_chat_id"
 
 	// Callback data
 	eventSetupCallbackConfirmCancel = "event_setup_callback_confirm_cancel"
+	eventSetupCallbackSkip          = "event_setup_callback_skip"
+
+	// eventSetupMaxOccurrences limits the number of weekly repetitions, about two years
+	eventSetupMaxOccurrences = 104
 )
 
 type eventSetupHandler struct {
 	config               *config.Config
 	eventRepository      *repositories.EventRepository
+	userRepository       *repositories.UserRepository
 	messageSenderService *services.MessageSenderService
+	eventSeriesService   *services.EventSeriesService
 	userStore            *utils.UserDataStore
 	permissionsService   *services.PermissionsService
 }
@@ -48,13 +66,17 @@ type eventSetupHandler struct {
 func NewEventSetupHandler(
 	config *config.Config,
 	eventRepository *repositories.EventRepository,
+	userRepository *repositories.UserRepository,
 	messageSenderService *services.MessageSenderService,
+	eventSeriesService *services.EventSeriesService,
 	permissionsService *services.PermissionsService,
 ) ext.Handler {
 	h := &eventSetupHandler{
 		config:               config,
 		eventRepository:      eventRepository,
+		userRepository:       userRepository,
 		messageSenderService: messageSenderService,
+		eventSeriesService:   eventSeriesService,
 		userStore:            utils.NewUserDataStore(),
 		permissionsService:   permissionsService,
 	}
@@ -76,6 +98,40 @@ func NewEventSetupHandler(
 				handlers.NewMessage(message.Text, h.handleEventStartedAt),
 				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackConfirmCancel), h.handleCallbackCancel),
 			},
+			eventSetupStateAskDuration: {
+				handlers.NewMessage(message.Text, h.handleDuration),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackSkip), h.skipTo(h.askLocation)),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventSetupStateAskLocation: {
+				handlers.NewMessage(message.Text, h.handleLocation),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackSkip), h.skipTo(h.askDescription)),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventSetupStateAskDescription: {
+				handlers.NewMessage(message.Text, h.handleDescription),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackSkip), h.skipTo(h.askSpeakers)),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventSetupStateAskSpeakers: {
+				handlers.NewMessage(message.Text, h.handleSpeakers),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackSkip), h.skipTo(h.askCover)),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventSetupStateAskCover: {
+				handlers.NewMessage(message.Photo, h.handleCover),
+				handlers.NewMessage(message.Text, h.handleCoverText),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackSkip), h.skipTo(h.askRecurrence)),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventSetupStateAskRecurrence: {
+				handlers.NewMessage(message.Text, h.handleRecurrence),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			eventSetupStateAskRecurrenceEnd: {
+				handlers.NewMessage(message.Text, h.handleRecurrenceEnd),
+				handlers.NewCallback(callbackquery.Equal(eventSetupCallbackConfirmCancel), h.handleCallbackCancel),
+			},
 		},
 		&handlers.ConversationOpts{
 			Exits: []ext.Handler{handlers.NewCommand(constants.CancelCommand, h.handleCancel)},
@@ -214,7 +270,7 @@ func (h *eventSetupHandler) handleEventType(b *gotgbot.Bot, ctx *ext.Context) er
 	// Ask for start date
 	sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(
 		msg,
-		"Когда стартует мероприятие? Введи дату и время в формате DD.MM.YYYY HH:MM:",
+		fmt.Sprintf("Когда стартует мероприятие? Введи дату и время в формате DD.MM.YYYY HH:MM (%s):", h.config.ClubTimezone),
 		&gotgbot.SendMessageOpts{
 			ReplyMarkup: buttons.CancelButton(eventSetupCallbackConfirmCancel),
 		},
@@ -229,8 +285,8 @@ func (h *eventSetupHandler) handleEventStartedAt(b *gotgbot.Bot, ctx *ext.Contex
 	msg := ctx.EffectiveMessage
 	dateTimeStr := strings.TrimSpace(msg.Text)
 
-	// Parse the start date
-	startedAt, err := time.Parse("02.01.2006 15:04", dateTimeStr)
+	// Parse the start date in the club timezone
+	startedAt, err := time.ParseInLocation("02.01.2006 15:04", dateTimeStr, h.config.ClubTimezone)
 	if err != nil {
 		h.messageSenderService.Reply(
 			msg,
@@ -275,36 +331,362 @@ func (h *eventSetupHandler) handleEventStartedAt(b *gotgbot.Bot, ctx *ext.Contex
 		return handlers.EndConversation()
 	}
 
-	// Get event name for the success message
-	eventNameVal, ok := h.userStore.Get(ctx.EffectiveUser.Id, eventSetupCtxDataKeyEventName)
+	// Store the start date
+	h.userStore.Set(ctx.EffectiveUser.Id, eventSetupCtxDataKeyStartedAt, startedAt)
+
+	return h.askDuration(ctx)
+}
+
+// askDuration asks for the optional duration
+func (h *eventSetupHandler) askDuration(ctx *ext.Context) error {
+	return h.askOptional(ctx, "Сколько длится мероприятие? Введи длительность в минутах (например, 90) или в формате H:MM:", eventSetupStateAskDuration)
+}
+
+// 5.1. handleDuration processes the duration input
+func (h *eventSetupHandler) handleDuration(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	minutes, ok := parseEventDuration(msg.Text)
+	if !ok || minutes == 0 {
+		h.messageSenderService.Reply(msg, "Неверная длительность. Введи число минут или H:MM, или используй кнопки ниже предыдущего сообщения.", nil)
+		return nil // Stay in the same state
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+	if !h.saveDetails(ctx, func(details *repositories.EventDetails) { details.DurationMinutes = &minutes }) {
+		return handlers.EndConversation()
+	}
+	return h.askLocation(ctx)
+}
+
+// askLocation asks for the optional address or online link
+func (h *eventSetupHandler) askLocation(ctx *ext.Context) error {
+	return h.askOptional(ctx, "Где проходит мероприятие? Введи адрес или ссылку на онлайн-встречу:", eventSetupStateAskLocation)
+}
+
+// 5.2. handleLocation processes the location input
+func (h *eventSetupHandler) handleLocation(b *gotgbot.Bot, ctx *ext.Context) error {
+	location := strings.TrimSpace(ctx.EffectiveMessage.Text)
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+	if !h.saveDetails(ctx, func(details *repositories.EventDetails) { details.Location = location }) {
+		return handlers.EndConversation()
+	}
+	return h.askDescription(ctx)
+}
+
+// askDescription asks for the optional description
+func (h *eventSetupHandler) askDescription(ctx *ext.Context) error {
+	return h.askOptional(ctx, "Введи описание мероприятия:", eventSetupStateAskDescription)
+}
+
+// 5.3. handleDescription processes the description input
+func (h *eventSetupHandler) handleDescription(b *gotgbot.Bot, ctx *ext.Context) error {
+	description := strings.TrimSpace(ctx.EffectiveMessage.Text)
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+	if !h.saveDetails(ctx, func(details *repositories.EventDetails) { details.Description = description }) {
+		return handlers.EndConversation()
+	}
+	return h.askSpeakers(ctx)
+}
+
+// askSpeakers asks for the optional speakers
+func (h *eventSetupHandler) askSpeakers(ctx *ext.Context) error {
+	return h.askOptional(ctx, "Кто выступает? Введи юзернеймы спикеров через пробел (например, @ivan @maria):", eventSetupStateAskSpeakers)
+}
+
+// 5.4. handleSpeakers processes the speakers input
+func (h *eventSetupHandler) handleSpeakers(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	speakerUserIDs, unknown, err := parseEventSpeakers(h.userRepository, msg.Text)
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при поиске спикеров.", nil)
+		log.Printf("%s: Error during speakers lookup: %v", utils.GetCurrentTypeName(), err)
+		return nil // Stay in the same state
+	}
+	if len(unknown) > 0 || len(speakerUserIDs) == 0 {
+		h.messageSenderService.Reply(msg, formatUnknownSpeakersMessage(unknown), nil)
+		return nil // Stay in the same state
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+	if !h.saveDetails(ctx, func(details *repositories.EventDetails) { details.SpeakerUserIDs = speakerUserIDs }) {
+		return handlers.EndConversation()
+	}
+	return h.askCover(ctx)
+}
+
+// askCover asks for the optional cover image
+func (h *eventSetupHandler) askCover(ctx *ext.Context) error {
+	return h.askOptional(ctx, "Отправь картинку для обложки мероприятия:", eventSetupStateAskCover)
+}
+
+// 5.5. handleCover processes the cover image
+func (h *eventSetupHandler) handleCover(b *gotgbot.Bot, ctx *ext.Context) error {
+	photos := ctx.EffectiveMessage.Photo
+	// The last size is the largest one
+	coverFileID := photos[len(photos)-1].FileId
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+	if !h.saveDetails(ctx, func(details *repositories.EventDetails) { details.CoverFileID = coverFileID }) {
+		return handlers.EndConversation()
+	}
+	return h.askRecurrence(ctx)
+}
+
+// handleCoverText reminds that the cover must be an image
+func (h *eventSetupHandler) handleCoverText(b *gotgbot.Bot, ctx *ext.Context) error {
+	h.messageSenderService.Reply(ctx.EffectiveMessage, "Обложка должна быть картинкой. Отправь картинку или используй кнопки ниже предыдущего сообщения.", nil)
+	return nil // Stay in the same state
+}
+
+// askOptional asks for an optional detail of the event, it can be skipped with the button
+func (h *eventSetupHandler) askOptional(ctx *ext.Context, text string, state string) error {
+	sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(
+		ctx.EffectiveMessage,
+		text,
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.SkipAndCancelButton(eventSetupCallbackSkip, eventSetupCallbackConfirmCancel),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(state)
+}
+
+// skipTo handles the skip button by going to the next question
+func (h *eventSetupHandler) skipTo(ask func(ctx *ext.Context) error) handlers.Response {
+	return func(b *gotgbot.Bot, ctx *ext.Context) error {
+		// Answer the callback query to remove the loading state on the button
+		_, _ = ctx.CallbackQuery.Answer(b, nil)
+
+		h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+		return ask(ctx)
+	}
+}
+
+// saveDetails applies the update to the collected details and saves them to the event, false on failure
+func (h *eventSetupHandler) saveDetails(ctx *ext.Context, update func(details *repositories.EventDetails)) bool {
+	msg := ctx.EffectiveMessage
+
+	eventIDVal, _ := h.userStore.Get(ctx.EffectiveUser.Id, eventSetupCtxDataKeyEventID)
+	eventID, ok := eventIDVal.(int)
 	if !ok {
-		h.messageSenderService.Reply(msg, "Мероприятие успешно создано с датой старта.", nil)
+		h.messageSenderService.Reply(
+			msg,
+			fmt.Sprintf("Произошла внутренняя ошибка. Не удалось найти ID мероприятия. Попробуй начать заново с /%s.",
+				constants.EventSetupCommand,
+			),
+			nil,
+		)
+		h.userStore.Clear(ctx.EffectiveUser.Id)
+		return false
+	}
+
+	detailsVal, _ := h.userStore.Get(ctx.EffectiveUser.Id, eventSetupCtxDataKeyDetails)
+	details, _ := detailsVal.(repositories.EventDetails)
+	update(&details)
+
+	if err := h.eventRepository.UpdateEventDetails(eventID, details); err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при сохранении деталей мероприятия.", nil)
+		log.Printf("%s: Error during event update: %v", utils.GetCurrentTypeName(), err)
+		h.userStore.Clear(ctx.EffectiveUser.Id)
+		return false
+	}
+
+	h.userStore.Set(ctx.EffectiveUser.Id, eventSetupCtxDataKeyDetails, details)
+	return true
+}
+
+// askRecurrence asks whether the event repeats weekly
+func (h *eventSetupHandler) askRecurrence(ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	startedAtVal, _ := h.userStore.Get(ctx.EffectiveUser.Id, eventSetupCtxDataKeyStartedAt)
+	startedAt, ok := startedAtVal.(time.Time)
+	if !ok {
+		h.messageSenderService.Reply(
+			msg,
+			fmt.Sprintf("Произошла внутренняя ошибка. Не удалось найти дату старта мероприятия. Попробуй начать заново с /%s.",
+				constants.EventSetupCommand,
+			),
+			nil,
+		)
 		h.userStore.Clear(ctx.EffectiveUser.Id)
 		return handlers.EndConversation()
 	}
 
-	eventName, ok := eventNameVal.(string)
+	// Ask whether the event repeats
+	sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(
+		msg,
+		fmt.Sprintf(
+			"Повторять мероприятие каждую неделю (%s, %s)?\n/1. Нет\n/2. Да\n\nВведи номер:",
+			formatters.GetWeekdayInRussian(startedAt.In(h.config.ClubTimezone).Weekday()),
+			startedAt.In(h.config.ClubTimezone).Format("15:04"),
+		),
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.CancelButton(eventSetupCallbackConfirmCancel),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(eventSetupStateAskRecurrence)
+}
+
+// 6. handleRecurrence processes the choice whether the event repeats weekly
+func (h *eventSetupHandler) handleRecurrence(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	selectionText := strings.TrimSpace(strings.Replace(msg.Text, "/", "", 1))
+
+	selection, err := strconv.Atoi(selectionText)
+	if err != nil || selection < 1 || selection > 2 {
+		h.messageSenderService.Reply(msg, "Неверный выбор. Пожалуйста, введи 1 или 2, или используй кнопку для отмены.", nil)
+		return nil // Stay in the same state
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+
+	if selection == 1 {
+		return h.finishSetup(ctx, "")
+	}
+
+	// Ask for the end of the repetitions
+	sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(
+		msg,
+		fmt.Sprintf(
+			"До какой даты повторять? Введи дату последнего мероприятия в формате DD.MM.YYYY или количество мероприятий в серии числом (от 2 до %d):",
+			eventSetupMaxOccurrences,
+		),
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.CancelButton(eventSetupCallbackConfirmCancel),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(eventSetupStateAskRecurrenceEnd)
+}
+
+// 7. handleRecurrenceEnd processes the end date or the number of occurrences and creates the series
+func (h *eventSetupHandler) handleRecurrenceEnd(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	input := strings.TrimSpace(msg.Text)
+
+	startedAtVal, _ := h.userStore.Get(ctx.EffectiveUser.Id, eventSetupCtxDataKeyStartedAt)
+	startedAt, ok := startedAtVal.(time.Time)
 	if !ok {
-		h.messageSenderService.Reply(msg, "Мероприятие успешно создано с датой старта.", nil)
+		h.messageSenderService.Reply(
+			msg,
+			fmt.Sprintf("Произошла внутренняя ошибка. Не удалось найти дату старта мероприятия. Попробуй начать заново с /%s.",
+				constants.EventSetupCommand,
+			),
+			nil,
+		)
+		return handlers.EndConversation()
+	}
+
+	var untilDate *time.Time
+	var occurrencesCount *int
+	var recurrence string
+	if count, err := strconv.Atoi(input); err == nil {
+		if count < 2 || count > eventSetupMaxOccurrences {
+			h.messageSenderService.Reply(
+				msg,
+				fmt.Sprintf("Количество мероприятий должно быть от 2 до %d. Попробуй ещё раз или используй кнопку для отмены.", eventSetupMaxOccurrences),
+				nil,
+			)
+			return nil // Stay in the same state
+		}
+		occurrencesCount = &count
+		recurrence = fmt.Sprintf("каждую неделю, всего %d раз", count)
+	} else {
+		date, err := time.Parse("02.01.2006", input)
+		firstDate := startedAt.In(h.config.ClubTimezone).Format("2006-01-02")
+		if err != nil || date.Format("2006-01-02") <= firstDate {
+			h.messageSenderService.Reply(
+				msg,
+				"Неверный ввод. Введи дату позже даты старта в формате DD.MM.YYYY или количество мероприятий числом, или используй кнопку для отмены.",
+				nil,
+			)
+			return nil // Stay in the same state
+		}
+		if date.After(startedAt.AddDate(0, 0, 7*(eventSetupMaxOccurrences-1))) {
+			h.messageSenderService.Reply(
+				msg,
+				fmt.Sprintf("Серия не может быть длиннее %d мероприятий. Введи дату раньше или используй кнопку для отмены.", eventSetupMaxOccurrences),
+				nil,
+			)
+			return nil // Stay in the same state
+		}
+		untilDate = &date
+		recurrence = fmt.Sprintf("каждую неделю до %s", date.Format("02.01.2006"))
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+
+	eventIDVal, _ := h.userStore.Get(ctx.EffectiveUser.Id, eventSetupCtxDataKeyEventID)
+	eventID, ok := eventIDVal.(int)
+	if !ok {
+		h.messageSenderService.Reply(
+			msg,
+			fmt.Sprintf("Произошла внутренняя ошибка. Не удалось найти ID мероприятия. Попробуй начать заново с /%s.",
+				constants.EventSetupCommand,
+			),
+			nil,
+		)
+		return handlers.EndConversation()
+	}
+
+	// Create the series, the upcoming occurrences are generated right away
+	if _, err := h.eventSeriesService.CreateSeries(eventID, untilDate, occurrencesCount); err != nil {
+		h.messageSenderService.Reply(msg, "Мероприятие создано, но не удалось сделать его повторяющимся.", nil)
+		log.Printf("%s: Error during event series creation: %v", utils.GetCurrentTypeName(), err)
 		h.userStore.Clear(ctx.EffectiveUser.Id)
 		return handlers.EndConversation()
 	}
 
+	return h.finishSetup(ctx, recurrence)
+}
+
+// finishSetup sends the success message, recurrence describes the repetitions of a recurring event
+func (h *eventSetupHandler) finishSetup(ctx *ext.Context, recurrence string) error {
+	msg := ctx.EffectiveMessage
+
+	// Clean up user data when done
+	defer h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	eventIDVal, _ := h.userStore.Get(ctx.EffectiveUser.Id, eventSetupCtxDataKeyEventID)
+	eventNameVal, _ := h.userStore.Get(ctx.EffectiveUser.Id, eventSetupCtxDataKeyEventName)
+	startedAtVal, _ := h.userStore.Get(ctx.EffectiveUser.Id, eventSetupCtxDataKeyStartedAt)
+
+	eventID, okID := eventIDVal.(int)
+	eventName, okName := eventNameVal.(string)
+	startedAt, okStartedAt := startedAtVal.(time.Time)
+	if !okID || !okName || !okStartedAt {
+		h.messageSenderService.Reply(msg, "Мероприятие успешно создано с датой старта.", nil)
+		return handlers.EndConversation()
+	}
+
+	recurrenceLine := ""
+	if recurrence != "" {
+		recurrenceLine = fmt.Sprintf("\n🔁 Повторяется %s. Следующие мероприятия серии создаются автоматически за %d дней до старта.",
+			recurrence, int(services.EventSeriesGenerationHorizon.Hours()/24),
+		)
+	}
+
 	// Success message
 	h.messageSenderService.Reply(
 		msg,
 		fmt.Sprintf(
-			"Запись о мероприятии '*%s*' успешно создана с ID: %d и датой старта: *%s*\n\nДля редактирования мероприятия используй команду /%s.\nДля просмотра всех команд используй команду /%s",
-			eventName, eventID, startedAt.Format("02.01.2006 15:04"), constants.EventEditCommand, constants.HelpCommand,
+			"Запись о мероприятии '*%s*' успешно создана с ID: %d и датой старта: *%s*%s\n\nДля редактирования мероприятия используй команду /%s.\nДля просмотра всех команд используй команду /%s",
+			eventName, eventID, formatters.FormatEventStartedAt(startedAt, h.config.ClubTimezone), recurrenceLine, constants.EventEditCommand, constants.HelpCommand,
 		),
 		&gotgbot.SendMessageOpts{
 			ParseMode: "Markdown",
 		},
 	)
 
-	// Clean up user data
-	h.userStore.Clear(ctx.EffectiveUser.Id)
-
 	return handlers.EndConversation()
 }
 
@@ -317,7 +699,7 @@ func (h *eventSetupHandler) handleCallbackCancel(b *gotgbot.Bot, ctx *ext.Contex
 	return h.handleCancel(b, ctx)
 }
 
-// 5. handleCancel handles the /cancel command
+// 8. handleCancel handles the /cancel command
 func (h *eventSetupHandler) handleCancel(b *gotgbot.Bot, ctx *ext.Context) error {
 	msg := ctx.EffectiveMessage
 
@@ -355,3 +737,66 @@ func (h *eventSetupHandler) SavePreviousMessageInfo(userID int64, sentMsg *gotgb
 	h.userStore.SetPreviousMessageInfo(userID, sentMsg.MessageId, sentMsg.Chat.Id,
 		eventSetupCtxDataKeyPreviousMessageID, eventSetupCtxDataKeyPreviousChatID)
 }
+
+// parseEventDuration parses the duration in minutes, e.g. "90" or "1:30"
+func parseEventDuration(input string) (int, bool) {
+	input = strings.TrimSpace(input)
+
+	if hoursStr, minutesStr, ok := strings.Cut(input, ":"); ok {
+		hours, err := strconv.Atoi(hoursStr)
+		if err != nil || hours < 0 {
+			return 0, false
+		}
+		minutes, err := strconv.Atoi(minutesStr)
+		if err != nil || minutes < 0 || minutes >= 60 || len(minutesStr) != 2 {
+			return 0, false
+		}
+		return hours*60 + minutes, true
+	}
+
+	minutes, err := strconv.Atoi(input)
+	if err != nil || minutes < 0 {
+		return 0, false
+	}
+	return minutes, true
+}
+
+// parseEventSpeakers finds the users by the usernames separated by spaces or commas,
+// returns the user IDs and the usernames not found among the users
+func parseEventSpeakers(userRepository *repositories.UserRepository, input string) ([]int64, []string, error) {
+	var speakerUserIDs []int64
+	var unknown []string
+
+	usernames := strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' || r == '\n' })
+	for _, username := range usernames {
+		username = strings.TrimPrefix(username, "@")
+		if username == "" {
+			continue
+		}
+
+		user, err := userRepository.GetByTelegramUsername(username)
+		if err == sql.ErrNoRows {
+			unknown = append(unknown, "@"+username)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if !slices.Contains(speakerUserIDs, int64(user.ID)) {
+			speakerUserIDs = append(speakerUserIDs, int64(user.ID))
+		}
+	}
+
+	return speakerUserIDs, unknown, nil
+}
+
+// formatUnknownSpeakersMessage explains why the speakers input was rejected
+func formatUnknownSpeakersMessage(unknown []string) string {
+	if len(unknown) == 0 {
+		return "Не удалось найти юзернеймы. Введи юзернеймы спикеров через пробел, например: @ivan @maria"
+	}
+	return fmt.Sprintf(
+		"Не удалось найти пользователей: %s. Спикер должен хотя бы раз написать боту или в чат клуба. Проверь юзернеймы и введи их ещё раз.",
+		strings.Join(unknown, ", "),
+	)
+}
@@ -45,25 +45,28 @@ const (
 )
 
 type eventStartHandler struct {
-	config               *config.Config
-	eventRepository      *repositories.EventRepository
-	messageSenderService *services.MessageSenderService
-	userStore            *utils.UserDataStore
-	permissionsService   *services.PermissionsService
+	config                 *config.Config
+	eventRepository        *repositories.EventRepository
+	messageSenderService   *services.MessageSenderService
+	eventAttendanceService *services.EventAttendanceService
+	userStore              *utils.UserDataStore
+	permissionsService     *services.PermissionsService
 }
 
 func NewEventStartHandler(
 	config *config.Config,
 	eventRepository *repositories.EventRepository,
 	messageSenderService *services.MessageSenderService,
+	eventAttendanceService *services.EventAttendanceService,
 	permissionsService *services.PermissionsService,
 ) ext.Handler {
 	h := &eventStartHandler{
-		config:               config,
-		eventRepository:      eventRepository,
-		messageSenderService: messageSenderService,
-		userStore:            utils.NewUserDataStore(),
-		permissionsService:   permissionsService,
+		config:                 config,
+		eventRepository:        eventRepository,
+		messageSenderService:   messageSenderService,
+		eventAttendanceService: eventAttendanceService,
+		userStore:              utils.NewUserDataStore(),
+		permissionsService:     permissionsService,
 	}
 
 	return handlers.NewConversation(
@@ -121,7 +124,7 @@ func (h *eventStartHandler) startEvent(b *gotgbot.Bot, ctx *ext.Context) error {
 
 	title := fmt.Sprintf("Последние %d мероприятия:", len(events))
 	actionDescription := "которое ты хочешь запустить"
-	formattedResponse := formatters.FormatEventListForAdmin(events, title, constants.CancelCommand, actionDescription)
+	formattedResponse := formatters.FormatEventListForAdmin(events, title, constants.CancelCommand, actionDescription, h.config.ClubTimezone)
 
 	sentMsg, _ := h.messageSenderService.ReplyMarkdownWithReturnMessage(msg, formattedResponse, &gotgbot.SendMessageOpts{
 		ReplyMarkup: buttons.CancelButton(eventStartCallbackConfirmCancel),
@@ -287,8 +290,8 @@ func (h *eventStartHandler) handleCallbackYes(b *gotgbot.Bot, ctx *ext.Context)
 		return handlers.EndConversation()
 	}
 
-	// Update the event status to active (or use the appropriate constant)
-	err = h.eventRepository.UpdateEventStatus(eventID, constants.EventStatusFinished) // When ivent already started in DB we need to set status to finished
+	// The event is live from now on, it gets finished automatically after its duration
+	err = h.eventRepository.UpdateEventStatus(eventID, constants.EventStatusLive)
 	if err != nil {
 		h.messageSenderService.Reply(ctx.EffectiveMessage, "Произошла ошибка при обновлении статуса мероприятия.", nil)
 		log.Printf("%s: Error during event update: %v", utils.GetCurrentTypeName(), err)
@@ -306,6 +309,14 @@ func (h *eventStartHandler) handleCallbackYes(b *gotgbot.Bot, ctx *ext.Context)
 		},
 	}
 
+	// RSVP buttons go below the link, so members can mark that they are joining
+	rsvpMarkup, err := h.eventAttendanceService.RsvpButtons(*event)
+	if err != nil {
+		log.Printf("%s: Error getting RSVP buttons: %v", utils.GetCurrentTypeName(), err)
+	} else {
+		buttonWithLink.InlineKeyboard = append(buttonWithLink.InlineKeyboard, rsvpMarkup.InlineKeyboard...)
+	}
+
 	// Send announcement message with the event link to the announcement topic if configured
 	announcementMsg := fmt.Sprintf(
 		"🔴 *НАЧИНАЕМ ИВЕНТ!* 🔴\n\n%s *%s*\n",
@@ -319,6 +330,18 @@ func (h *eventStartHandler) handleCallbackYes(b *gotgbot.Bot, ctx *ext.Context)
 
 	announcementMsg += fmt.Sprintf("\nИспользуй кнопку ниже, чтобы присоединиться ⬇️")
 
+	// The cover goes right before the announcement
+	if event.CoverFileID != "" {
+		err = h.messageSenderService.SendPhotoByFileID(
+			utils.ChatIdToFullChatId(h.config.SuperGroupChatID),
+			event.CoverFileID,
+			&gotgbot.SendPhotoOpts{MessageThreadId: int64(h.config.AnnouncementTopicID)},
+		)
+		if err != nil {
+			log.Printf("%s: Error sending event cover: %v", utils.GetCurrentTypeName(), err)
+		}
+	}
+
 	sentAnnouncementMsg, err := h.messageSenderService.SendMarkdownWithReturnMessage(
 		utils.ChatIdToFullChatId(h.config.SuperGroupChatID),
 		announcementMsg,
@@ -116,6 +116,7 @@ func (h *showTopicsHandler) startShowTopics(b *gotgbot.Bot, ctx *ext.Context) er
 		"Список мероприятий",
 		constants.CancelCommand,
 		"для которого ты хочешь увидеть темы и вопросы",
+		h.config.ClubTimezone,
 	)
 
 	sentMsg, _ := h.messageSenderService.ReplyMarkdownWithReturnMessage(
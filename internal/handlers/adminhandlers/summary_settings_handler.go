@@ -0,0 +1,500 @@
+package adminhandlers
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/prompts"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/message"
+)
+
+const (
+	// Conversation states names
+	summarySettingsStateSelectTopic = "admin_summary_settings_state_select_topic"
+	summarySettingsStateSelectField = "admin_summary_settings_state_select_field"
+	summarySettingsStateEditValue   = "admin_summary_settings_state_edit_value"
+
+	// Context data keys
+	summarySettingsCtxDataKeyTopicID           = "admin_summary_settings_ctx_data_topic_id"
+	summarySettingsCtxDataKeyField             = "admin_summary_settings_ctx_data_field"
+	summarySettingsCtxDataKeyPreviousMessageID = "admin_summary_settings_ctx_data_previous_message_id"
+	summarySettingsCtxDataKeyPreviousChatID    = "admin_summary_settings_ctx_data_previous_chat_id"
+
+	// Callback data
+	summarySettingsCallbackConfirmCancel = "admin_summary_settings_callback_confirm_cancel"
+
+	// Editable fields
+	summarySettingsFieldEnabled     = 1
+	summarySettingsFieldTime        = 2
+	summarySettingsFieldPrompt      = 3
+	summarySettingsFieldMinMessages = 4
+	summarySettingsFieldDestination = 5
+	summarySettingsFieldReset       = 6
+)
+
+type summarySettingsHandler struct {
+	config                  *config.Config
+	messageSenderService    *services.MessageSenderService
+	permissionsService      *services.PermissionsService
+	summarizationService    *services.SummarizationService
+	topicSettingsRepository *repositories.TopicSummarizationSettingsRepository
+	userStore               *utils.UserDataStore
+}
+
+func NewSummarySettingsHandler(
+	config *config.Config,
+	messageSenderService *services.MessageSenderService,
+	permissionsService *services.PermissionsService,
+	summarizationService *services.SummarizationService,
+	topicSettingsRepository *repositories.TopicSummarizationSettingsRepository,
+) ext.Handler {
+	h := &summarySettingsHandler{
+		config:                  config,
+		messageSenderService:    messageSenderService,
+		permissionsService:      permissionsService,
+		summarizationService:    summarizationService,
+		topicSettingsRepository: topicSettingsRepository,
+		userStore:               utils.NewUserDataStore(),
+	}
+
+	return handlers.NewConversation(
+		[]ext.Handler{
+			handlers.NewCommand(constants.SummarySettingsCommand, h.startSettings),
+		},
+		map[string][]ext.Handler{
+			summarySettingsStateSelectTopic: {
+				handlers.NewMessage(message.Text, h.handleSelectTopic),
+				handlers.NewCallback(callbackquery.Equal(summarySettingsCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			summarySettingsStateSelectField: {
+				handlers.NewMessage(message.Text, h.handleSelectField),
+				handlers.NewCallback(callbackquery.Equal(summarySettingsCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+			summarySettingsStateEditValue: {
+				handlers.NewMessage(message.Text, h.handleEditValue),
+				handlers.NewCallback(callbackquery.Equal(summarySettingsCallbackConfirmCancel), h.handleCallbackCancel),
+			},
+		},
+		&handlers.ConversationOpts{
+			Exits: []ext.Handler{handlers.NewCommand(constants.CancelCommand, h.handleCancel)},
+		},
+	)
+}
+
+// 1. startSettings shows settings of all summarized topics and asks for the topic to edit
+func (h *summarySettingsHandler) startSettings(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	// Check if user has admin permissions and is in a private chat
+	if !h.permissionsService.CheckAdminAndPrivateChat(msg, constants.SummarySettingsCommand) {
+		log.Printf("%s: User %d (%s) tried to use /%s without admin permissions.",
+			utils.GetCurrentTypeName(),
+			ctx.EffectiveUser.Id,
+			ctx.EffectiveUser.Username,
+			constants.SummarySettingsCommand,
+		)
+		return handlers.EndConversation()
+	}
+
+	topicsSettings, err := h.summarizationService.GetTopicsSettings()
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении настроек саммаризации.", nil)
+		log.Printf("%s: Error during settings retrieval: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	var builder strings.Builder
+	builder.WriteString("Настройки саммаризации по топикам:\n\n")
+	if len(topicsSettings) == 0 {
+		builder.WriteString("Нет топиков для саммаризации.\n")
+	}
+	for _, settings := range topicsSettings {
+		builder.WriteString(fmt.Sprintf("/%d - %s\n%s\n\n",
+			settings.TopicID,
+			h.summarizationService.GetTopicName(int(settings.TopicID)),
+			h.formatSettingsShort(settings),
+		))
+	}
+	builder.WriteString("Введи ID топика, настройки которого хочешь изменить (можно указать и топик не из списка), " +
+		fmt.Sprintf("или /%s для отмены.", constants.CancelCommand))
+
+	sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(
+		msg,
+		builder.String(),
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.CancelButton(summarySettingsCallbackConfirmCancel),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(summarySettingsStateSelectTopic)
+}
+
+// 2. handleSelectTopic processes the selected topic and shows the edit menu
+func (h *summarySettingsHandler) handleSelectTopic(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	topicIDStr := strings.TrimSpace(strings.Replace(msg.Text, "/", "", 1))
+
+	topicID, err := strconv.ParseInt(topicIDStr, 10, 64)
+	if err != nil || topicID < 0 {
+		h.messageSenderService.Reply(msg, "Неверный ID. Пожалуйста, введи числовой ID топика или используй кнопку для отмены.", nil)
+		return nil // Stay in the same state
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+	h.userStore.Set(ctx.EffectiveUser.Id, summarySettingsCtxDataKeyTopicID, topicID)
+
+	return h.showFieldsMenu(ctx, topicID)
+}
+
+// 3. handleSelectField processes the selected setting to edit
+func (h *summarySettingsHandler) handleSelectField(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	selectionText := strings.TrimSpace(strings.Replace(msg.Text, "/", "", 1))
+
+	topicID, ok := h.getSelectedTopicID(ctx)
+	if !ok {
+		return handlers.EndConversation()
+	}
+
+	selection, err := strconv.Atoi(selectionText)
+	if err != nil || selection < summarySettingsFieldEnabled || selection > summarySettingsFieldReset {
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Неверный выбор. Пожалуйста, введи число от %d до %d, или используй кнопку для отмены",
+			summarySettingsFieldEnabled, summarySettingsFieldReset,
+		), nil)
+		return nil // Stay in the same state
+	}
+
+	settings, err := h.summarizationService.GetTopicSettings(topicID)
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении настроек саммаризации.", nil)
+		log.Printf("%s: Error during settings retrieval: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+
+	var promptText string
+	switch selection {
+	case summarySettingsFieldEnabled:
+		settings.Enabled = !settings.Enabled
+		return h.saveSettings(ctx, settings)
+	case summarySettingsFieldReset:
+		return h.resetSettings(ctx, topicID)
+	case summarySettingsFieldTime:
+		promptText = fmt.Sprintf("Текущее время: %s (%s)\n\nВведи новое время в формате HH:MM (%s):",
+			settings.SummaryTime, h.config.ClubTimezone, h.config.ClubTimezone)
+	case summarySettingsFieldPrompt:
+		promptText = fmt.Sprintf("Текущий шаблон промпта: %s\n\nДоступные шаблоны:\n", settings.PromptTemplateKey)
+		for i, key := range h.knownPromptKeys() {
+			promptText += fmt.Sprintf("/%d. %s\n", i+1, key)
+		}
+		promptText += "\nВведи номер или ключ шаблона:"
+	case summarySettingsFieldMinMessages:
+		promptText = fmt.Sprintf("Текущий минимум сообщений: %d\n\nВведи новое значение:", settings.MinMessages)
+	case summarySettingsFieldDestination:
+		promptText = fmt.Sprintf("Текущий топик для публикации: %d (%s)\n\nВведи ID топика, куда публиковать сводку:",
+			settings.DestinationTopicID, h.summarizationService.GetTopicName(int(settings.DestinationTopicID)))
+	}
+
+	h.userStore.Set(ctx.EffectiveUser.Id, summarySettingsCtxDataKeyField, selection)
+
+	sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(
+		msg,
+		promptText,
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.CancelButton(summarySettingsCallbackConfirmCancel),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(summarySettingsStateEditValue)
+}
+
+// 4. handleEditValue validates the new value and saves the settings
+func (h *summarySettingsHandler) handleEditValue(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	input := strings.TrimSpace(msg.Text)
+
+	topicID, ok := h.getSelectedTopicID(ctx)
+	if !ok {
+		return handlers.EndConversation()
+	}
+
+	fieldVal, ok := h.userStore.Get(ctx.EffectiveUser.Id, summarySettingsCtxDataKeyField)
+	field, isInt := fieldVal.(int)
+	if !ok || !isInt {
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Произошла ошибка при получении выбранной настройки. Пожалуйста, начни заново с /%s",
+			constants.SummarySettingsCommand,
+		), nil)
+		return handlers.EndConversation()
+	}
+
+	settings, err := h.summarizationService.GetTopicSettings(topicID)
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении настроек саммаризации.", nil)
+		log.Printf("%s: Error during settings retrieval: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	switch field {
+	case summarySettingsFieldTime:
+		summaryTime, err := time.Parse("15:04", input)
+		if err != nil {
+			h.messageSenderService.Reply(msg, fmt.Sprintf(
+				"Неверный формат времени. Пожалуйста, введи время в формате HH:MM (%s) или используй кнопку для отмены.",
+				h.config.ClubTimezone,
+			), nil)
+			return nil // Stay in the same state
+		}
+		settings.SummaryTime = summaryTime.Format("15:04")
+	case summarySettingsFieldPrompt:
+		promptKey := strings.TrimSpace(strings.Replace(input, "/", "", 1))
+		knownPromptKeys := h.knownPromptKeys()
+		if index, err := strconv.Atoi(promptKey); err == nil {
+			if index < 1 || index > len(knownPromptKeys) {
+				h.messageSenderService.Reply(msg, fmt.Sprintf(
+					"Неверный номер. Пожалуйста, введи число от 1 до %d или ключ шаблона.", len(knownPromptKeys),
+				), nil)
+				return nil // Stay in the same state
+			}
+			promptKey = knownPromptKeys[index-1]
+		} else if !slices.Contains(knownPromptKeys, promptKey) {
+			// Other templates aren't summarization prompts and don't take the summary arguments
+			h.messageSenderService.Reply(msg, "Неизвестный шаблон. Пожалуйста, выбери шаблон из списка.", nil)
+			return nil // Stay in the same state
+		}
+		settings.PromptTemplateKey = promptKey
+	case summarySettingsFieldMinMessages:
+		minMessages, err := strconv.Atoi(input)
+		if err != nil || minMessages < 1 {
+			h.messageSenderService.Reply(msg, "Неверное значение. Пожалуйста, введи целое число больше 0.", nil)
+			return nil // Stay in the same state
+		}
+		settings.MinMessages = minMessages
+	case summarySettingsFieldDestination:
+		destinationTopicID, err := strconv.ParseInt(strings.Replace(input, "/", "", 1), 10, 64)
+		if err != nil || destinationTopicID < 0 {
+			h.messageSenderService.Reply(msg, "Неверный ID. Пожалуйста, введи числовой ID топика.", nil)
+			return nil // Stay in the same state
+		}
+		settings.DestinationTopicID = destinationTopicID
+	default:
+		h.messageSenderService.Reply(msg, fmt.Sprintf(
+			"Произошла внутренняя ошибка (неизвестная настройка). Пожалуйста, начни заново с /%s",
+			constants.SummarySettingsCommand,
+		), nil)
+		return handlers.EndConversation()
+	}
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+	return h.saveSettings(ctx, settings)
+}
+
+// handleCallbackCancel processes the cancel button click
+func (h *summarySettingsHandler) handleCallbackCancel(b *gotgbot.Bot, ctx *ext.Context) error {
+	// Answer the callback query to remove the loading state on the button
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	return h.handleCancel(b, ctx)
+}
+
+// 5. handleCancel handles the /cancel command
+func (h *summarySettingsHandler) handleCancel(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+	h.messageSenderService.Reply(msg, "Настройка саммаризации отменена.", nil)
+
+	// Clean up user data
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+// showFieldsMenu shows the current topic settings and the list of editable settings
+func (h *summarySettingsHandler) showFieldsMenu(ctx *ext.Context, topicID int64) error {
+	msg := ctx.EffectiveMessage
+
+	settings, err := h.summarizationService.GetTopicSettings(topicID)
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении настроек саммаризации.", nil)
+		log.Printf("%s: Error during settings retrieval: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	toggleText := "Выключить"
+	if !settings.Enabled {
+		toggleText = "Включить"
+	}
+
+	text := fmt.Sprintf("Топик %d (%s)\n%s\n\n", topicID, h.summarizationService.GetTopicName(int(topicID)), h.formatSettingsShort(settings)) +
+		"Что ты хочешь изменить?\n" +
+		fmt.Sprintf("/%d. %s саммаризацию\n", summarySettingsFieldEnabled, toggleText) +
+		fmt.Sprintf("/%d. Время публикации\n", summarySettingsFieldTime) +
+		fmt.Sprintf("/%d. Шаблон промпта\n", summarySettingsFieldPrompt) +
+		fmt.Sprintf("/%d. Минимум сообщений\n", summarySettingsFieldMinMessages) +
+		fmt.Sprintf("/%d. Топик для публикации\n", summarySettingsFieldDestination) +
+		fmt.Sprintf("/%d. Сбросить к настройкам по умолчанию\n\n", summarySettingsFieldReset) +
+		"Введи номер:"
+
+	sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(
+		msg,
+		text,
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.CancelButton(summarySettingsCallbackConfirmCancel),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(summarySettingsStateSelectField)
+}
+
+// saveSettings stores the topic settings and finishes the conversation
+func (h *summarySettingsHandler) saveSettings(ctx *ext.Context, settings repositories.TopicSummarizationSettings) error {
+	msg := ctx.EffectiveMessage
+
+	if err := h.topicSettingsRepository.Upsert(settings); err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при сохранении настроек саммаризации.", nil)
+		log.Printf("%s: Error during settings update: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+	h.invalidateCachedSummaries(settings.TopicID)
+
+	h.messageSenderService.Reply(msg, fmt.Sprintf(
+		"Настройки саммаризации топика %d сохранены:\n%s\n\nДля продолжения настройки используй команду /%s.",
+		settings.TopicID, h.formatSettingsShort(settings), constants.SummarySettingsCommand,
+	), nil)
+
+	// Clean up user data
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+// resetSettings removes own settings of the topic, so the defaults are used
+func (h *summarySettingsHandler) resetSettings(ctx *ext.Context, topicID int64) error {
+	msg := ctx.EffectiveMessage
+
+	settings, err := h.topicSettingsRepository.GetByTopicID(topicID)
+	if err != nil {
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении настроек саммаризации.", nil)
+		log.Printf("%s: Error during settings retrieval: %v", utils.GetCurrentTypeName(), err)
+		return handlers.EndConversation()
+	}
+
+	if settings != nil {
+		if err := h.topicSettingsRepository.DeleteByTopicID(topicID); err != nil {
+			h.messageSenderService.Reply(msg, "Произошла ошибка при сбросе настроек саммаризации.", nil)
+			log.Printf("%s: Error during settings deletion: %v", utils.GetCurrentTypeName(), err)
+			return handlers.EndConversation()
+		}
+		h.invalidateCachedSummaries(topicID)
+	}
+
+	resultText := fmt.Sprintf("Настройки саммаризации топика %d сброшены к значениям по умолчанию.", topicID)
+	if !slices.Contains(h.config.MonitoredTopicsIDs, int(topicID)) {
+		resultText += "\nТопик не входит в список отслеживаемых, поэтому сводка по нему больше не публикуется."
+	}
+	h.messageSenderService.Reply(msg, resultText, nil)
+
+	// Clean up user data
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+// invalidateCachedSummaries drops on-demand summaries of the topic made with its previous settings
+func (h *summarySettingsHandler) invalidateCachedSummaries(topicID int64) {
+	if err := h.summarizationService.InvalidateTopicSummaries(topicID); err != nil {
+		log.Printf("%s: Error invalidating cached summaries of topic %d: %v", utils.GetCurrentTypeName(), topicID, err)
+	}
+}
+
+// getSelectedTopicID returns the topic ID selected in the conversation
+func (h *summarySettingsHandler) getSelectedTopicID(ctx *ext.Context) (int64, bool) {
+	topicIDVal, ok := h.userStore.Get(ctx.EffectiveUser.Id, summarySettingsCtxDataKeyTopicID)
+	topicID, isInt64 := topicIDVal.(int64)
+	if !ok || !isInt64 {
+		h.messageSenderService.Reply(ctx.EffectiveMessage, fmt.Sprintf(
+			"Произошла ошибка при получении выбранного топика. Пожалуйста, начни заново с /%s",
+			constants.SummarySettingsCommand,
+		), nil)
+		return 0, false
+	}
+	return topicID, true
+}
+
+// knownPromptKeys returns the sorted keys of the built-in summarization prompts
+func (h *summarySettingsHandler) knownPromptKeys() []string {
+	keys := make([]string, 0, len(prompts.SummarizationPromptDefaultValues))
+	for key := range prompts.SummarizationPromptDefaultValues {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// formatSettingsShort formats the topic settings into a short multiline description
+func (h *summarySettingsHandler) formatSettingsShort(settings repositories.TopicSummarizationSettings) string {
+	status := "✅ включена"
+	if !settings.Enabled {
+		status = "⏸ выключена"
+	}
+	return fmt.Sprintf("└ %s, в %s (%s)\n└ шаблон: %s\n└ минимум сообщений: %d\n└ публикация в топик: %d (%s)",
+		status,
+		settings.SummaryTime,
+		h.config.ClubTimezone,
+		settings.PromptTemplateKey,
+		settings.MinMessages,
+		settings.DestinationTopicID,
+		h.summarizationService.GetTopicName(int(settings.DestinationTopicID)),
+	)
+}
+
+func (h *summarySettingsHandler) MessageRemoveInlineKeyboard(b *gotgbot.Bot, userID *int64) {
+	var chatID, messageID int64
+
+	// If userID provided, get stored message info using the utility method
+	if userID != nil {
+		messageID, chatID = h.userStore.GetPreviousMessageInfo(
+			*userID,
+			summarySettingsCtxDataKeyPreviousMessageID,
+			summarySettingsCtxDataKeyPreviousChatID,
+		)
+	}
+
+	// Skip if we don't have valid chat and message IDs
+	if chatID == 0 || messageID == 0 {
+		return
+	}
+
+	// Use message sender service to remove the inline keyboard
+	_ = h.messageSenderService.RemoveInlineKeyboard(chatID, messageID)
+}
+
+func (h *summarySettingsHandler) SavePreviousMessageInfo(userID int64, sentMsg *gotgbot.Message) {
+	if sentMsg == nil {
+		return
+	}
+	h.userStore.SetPreviousMessageInfo(userID, sentMsg.MessageId, sentMsg.Chat.Id,
+		summarySettingsCtxDataKeyPreviousMessageID, summarySettingsCtxDataKeyPreviousChatID)
+}
@@ -0,0 +1,400 @@
+package adminhandlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/tasks"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/message"
+)
+
+const (
+	// Conversation states names
+	tasksStateSelectJob = "admin_tasks_state_select_job"
+
+	// Context data keys
+	tasksCtxDataKeyPreviousMessageID = "admin_tasks_ctx_data_previous_message_id"
+	tasksCtxDataKeyPreviousChatID    = "admin_tasks_ctx_data_previous_chat_id"
+
+	// Maximum length of the last run error shown to the admin
+	tasksErrorMaxLength = 500
+)
+
+type tasksHandler struct {
+	config               *config.Config
+	messageSenderService *services.MessageSenderService
+	permissionsService   *services.PermissionsService
+	scheduler            *tasks.Scheduler
+	taskRunRepository    *repositories.TaskRunRepository
+	userStore            *utils.UserDataStore
+}
+
+func NewTasksHandler(
+	config *config.Config,
+	messageSenderService *services.MessageSenderService,
+	permissionsService *services.PermissionsService,
+	scheduler *tasks.Scheduler,
+	taskRunRepository *repositories.TaskRunRepository,
+) ext.Handler {
+	h := &tasksHandler{
+		config:               config,
+		messageSenderService: messageSenderService,
+		permissionsService:   permissionsService,
+		scheduler:            scheduler,
+		taskRunRepository:    taskRunRepository,
+		userStore:            utils.NewUserDataStore(),
+	}
+
+	return handlers.NewConversation(
+		[]ext.Handler{
+			handlers.NewCommand(constants.TasksCommand, h.startTasks),
+		},
+		map[string][]ext.Handler{
+			tasksStateSelectJob: {
+				handlers.NewCallback(callbackquery.Prefix(constants.TasksSelectCallbackPrefix), h.handleSelectJob),
+				handlers.NewCallback(callbackquery.Prefix(constants.TasksRunCallbackPrefix), h.handleRunNow),
+				handlers.NewCallback(callbackquery.Prefix(constants.TasksPauseCallbackPrefix), h.handleTogglePause),
+				handlers.NewCallback(callbackquery.Prefix(constants.TasksSkipNextCallbackPrefix), h.handleToggleSkipNext),
+				handlers.NewCallback(callbackquery.Equal(constants.TasksBackCallback), h.handleBack),
+				handlers.NewCallback(callbackquery.Equal(constants.TasksCancelCallback), h.handleCallbackCancel),
+				handlers.NewMessage(message.All, h.handleTextDuringSelection),
+			},
+		},
+		&handlers.ConversationOpts{
+			Exits: []ext.Handler{
+				handlers.NewCommand(constants.CancelCommand, h.handleCancel),
+				handlers.NewCallback(callbackquery.Equal(constants.TasksCancelCallback), h.handleCallbackCancel),
+			},
+		},
+	)
+}
+
+// 1. startTasks shows the list of all scheduled jobs
+func (h *tasksHandler) startTasks(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	// Check if user has admin permissions and is in a private chat
+	if !h.permissionsService.CheckAdminAndPrivateChat(msg, constants.TasksCommand) {
+		log.Printf("%s: User %d (%s) tried to use /%s without admin permissions.",
+			utils.GetCurrentTypeName(),
+			ctx.EffectiveUser.Id,
+			ctx.EffectiveUser.Username,
+			constants.TasksCommand,
+		)
+		return handlers.EndConversation()
+	}
+
+	text, markup := h.buildJobsList()
+	sentMsg, _ := h.messageSenderService.ReplyWithReturnMessage(msg, text, &gotgbot.SendMessageOpts{
+		ReplyMarkup: markup,
+	})
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(tasksStateSelectJob)
+}
+
+// 2. handleSelectJob shows details and actions of the selected job
+func (h *tasksHandler) handleSelectJob(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	jobName := strings.TrimPrefix(cb.Data, constants.TasksSelectCallbackPrefix)
+	h.showJob(b, ctx, jobName)
+	return nil
+}
+
+// handleRunNow starts the job immediately
+func (h *tasksHandler) handleRunNow(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	jobName := strings.TrimPrefix(cb.Data, constants.TasksRunCallbackPrefix)
+
+	if err := h.scheduler.RunNow(jobName); err != nil {
+		log.Printf("%s: Error running job %s: %v", utils.GetCurrentTypeName(), jobName, err)
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "Не удалось запустить задачу: возможно, она уже выполняется."})
+	} else {
+		log.Printf("%s: User %d started job %s manually", utils.GetCurrentTypeName(), ctx.EffectiveUser.Id, jobName)
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "Задача запущена."})
+	}
+
+	h.showJob(b, ctx, jobName)
+	return nil
+}
+
+// handleTogglePause pauses or resumes scheduled runs of the job
+func (h *tasksHandler) handleTogglePause(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	jobName := strings.TrimPrefix(cb.Data, constants.TasksPauseCallbackPrefix)
+
+	job, ok := h.scheduler.Job(jobName)
+	if !ok {
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "Задача не найдена."})
+		return nil
+	}
+
+	if err := h.scheduler.SetPaused(jobName, !job.Paused); err != nil {
+		log.Printf("%s: Error changing pause of job %s: %v", utils.GetCurrentTypeName(), jobName, err)
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "Не удалось изменить состояние задачи."})
+		return nil
+	}
+	_, _ = cb.Answer(b, nil)
+
+	h.showJob(b, ctx, jobName)
+	return nil
+}
+
+// handleToggleSkipNext marks the next occurrence of the job to be skipped, or removes the mark
+func (h *tasksHandler) handleToggleSkipNext(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	jobName := strings.TrimPrefix(cb.Data, constants.TasksSkipNextCallbackPrefix)
+
+	job, ok := h.scheduler.Job(jobName)
+	if !ok {
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "Задача не найдена."})
+		return nil
+	}
+
+	if err := h.scheduler.SetSkipNext(jobName, !job.SkipNext); err != nil {
+		log.Printf("%s: Error changing skip of job %s: %v", utils.GetCurrentTypeName(), jobName, err)
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "Не удалось изменить состояние задачи."})
+		return nil
+	}
+	_, _ = cb.Answer(b, nil)
+
+	h.showJob(b, ctx, jobName)
+	return nil
+}
+
+// handleBack returns to the list of jobs
+func (h *tasksHandler) handleBack(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	text, markup := h.buildJobsList()
+	h.editMessage(b, ctx, text, markup)
+	return nil
+}
+
+// handleTextDuringSelection reminds the user to use the buttons
+func (h *tasksHandler) handleTextDuringSelection(b *gotgbot.Bot, ctx *ext.Context) error {
+	h.messageSenderService.Reply(
+		ctx.EffectiveMessage,
+		fmt.Sprintf("Пожалуйста, воспользуйся кнопками выше или используй /%s для отмены.", constants.CancelCommand),
+		nil,
+	)
+	return nil
+}
+
+// handleCallbackCancel processes the cancel button click
+func (h *tasksHandler) handleCallbackCancel(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	return h.handleCancel(b, ctx)
+}
+
+// handleCancel handles the /cancel command
+func (h *tasksHandler) handleCancel(b *gotgbot.Bot, ctx *ext.Context) error {
+	h.MessageRemoveInlineKeyboard(b, &ctx.EffectiveUser.Id)
+	h.messageSenderService.Send(ctx.EffectiveChat.Id, "Управление задачами завершено.", nil)
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+func (h *tasksHandler) showJob(b *gotgbot.Bot, ctx *ext.Context, jobName string) {
+	job, ok := h.scheduler.Job(jobName)
+	if !ok {
+		text, markup := h.buildJobsList()
+		h.editMessage(b, ctx, "Задача не найдена.\n\n"+text, markup)
+		return
+	}
+
+	h.editMessage(b, ctx, h.formatJobDetails(job), buttons.TasksJobButtons(job.Name, job.Paused, job.SkipNext))
+}
+
+func (h *tasksHandler) buildJobsList() (string, gotgbot.InlineKeyboardMarkup) {
+	jobs := h.scheduler.Jobs()
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Запланированные задачи (время в %s):\n\n", h.config.ClubTimezone))
+	if len(jobs) == 0 {
+		builder.WriteString("Нет зарегистрированных задач.\n")
+	}
+
+	jobButtons := make([]buttons.TasksJob, 0, len(jobs))
+	for _, job := range jobs {
+		status := h.formatJobStatusIcon(job)
+		builder.WriteString(fmt.Sprintf("%s %s\nСледующий запуск: %s\nПоследний запуск: %s\n\n",
+			status,
+			job.Name,
+			h.formatNextRun(job),
+			h.formatLastRunShort(job.Name),
+		))
+		jobButtons = append(jobButtons, buttons.TasksJob{Name: job.Name, Status: status})
+	}
+	builder.WriteString("Выбери задачу для управления:")
+
+	return builder.String(), buttons.TasksListButtons(jobButtons)
+}
+
+func (h *tasksHandler) formatJobDetails(job tasks.JobInfo) string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%s %s\n\n", h.formatJobStatusIcon(job), job.Name))
+	builder.WriteString(fmt.Sprintf("Расписание (cron, %s): %s\n", h.config.ClubTimezone, job.Schedule))
+	builder.WriteString(fmt.Sprintf("Включена: %s\n", formatYesNo(job.Enabled)))
+	builder.WriteString(fmt.Sprintf("Приостановлена: %s\n", formatYesNo(job.Paused)))
+	builder.WriteString(fmt.Sprintf("Выполняется сейчас: %s\n", formatYesNo(job.Running)))
+	builder.WriteString(fmt.Sprintf("Следующий запуск: %s\n\n", h.formatNextRun(job)))
+
+	lastRun, err := h.taskRunRepository.GetLastByTaskName(job.Name)
+	switch {
+	case err != nil:
+		log.Printf("%s: Error getting last run of job %s: %v", utils.GetCurrentTypeName(), job.Name, err)
+		builder.WriteString("Последний запуск: не удалось получить\n")
+	case lastRun == nil:
+		builder.WriteString("Последний запуск: ещё не запускалась\n")
+	default:
+		builder.WriteString(fmt.Sprintf("Последний запуск: %s\n", formatTaskRunStatus(lastRun.Status)))
+		builder.WriteString(fmt.Sprintf("Запланирован на: %s\n", lastRun.ScheduledFor.In(h.config.ClubTimezone).Format(time.DateTime)))
+		if lastRun.StartedAt.Valid {
+			builder.WriteString(fmt.Sprintf("Начат: %s\n", lastRun.StartedAt.Time.In(h.config.ClubTimezone).Format(time.DateTime)))
+		}
+		if duration, ok := taskRunDuration(lastRun); ok {
+			builder.WriteString(fmt.Sprintf("Длительность: %s\n", duration))
+		}
+		if lastRun.Error.Valid && lastRun.Error.String != "" {
+			errorText := lastRun.Error.String
+			if len([]rune(errorText)) > tasksErrorMaxLength {
+				errorText = string([]rune(errorText)[:tasksErrorMaxLength]) + "..."
+			}
+			builder.WriteString(fmt.Sprintf("Ошибка: %s\n", errorText))
+		}
+	}
+
+	return builder.String()
+}
+
+func (h *tasksHandler) formatLastRunShort(jobName string) string {
+	lastRun, err := h.taskRunRepository.GetLastByTaskName(jobName)
+	if err != nil {
+		log.Printf("%s: Error getting last run of job %s: %v", utils.GetCurrentTypeName(), jobName, err)
+		return "не удалось получить"
+	}
+	if lastRun == nil {
+		return "ещё не запускалась"
+	}
+
+	text := fmt.Sprintf("%s, %s", formatTaskRunStatus(lastRun.Status), lastRun.ScheduledFor.In(h.config.ClubTimezone).Format(time.DateTime))
+	if duration, ok := taskRunDuration(lastRun); ok {
+		text += fmt.Sprintf(", %s", duration)
+	}
+	return text
+}
+
+func (h *tasksHandler) formatNextRun(job tasks.JobInfo) string {
+	switch {
+	case !job.Enabled:
+		return "отключена в конфигурации"
+	case job.Paused:
+		return "приостановлена"
+	case job.NextRun.IsZero():
+		return "не запланирован"
+	case job.SkipNext:
+		return fmt.Sprintf("%s (будет пропущен)", job.NextRun.In(h.config.ClubTimezone).Format(time.DateTime))
+	default:
+		return job.NextRun.In(h.config.ClubTimezone).Format(time.DateTime)
+	}
+}
+
+func (h *tasksHandler) formatJobStatusIcon(job tasks.JobInfo) string {
+	switch {
+	case job.Running:
+		return "🔄"
+	case !job.Enabled:
+		return "🚫"
+	case job.Paused:
+		return "⏸"
+	case job.SkipNext:
+		return "⏭"
+	default:
+		return "✅"
+	}
+}
+
+func (h *tasksHandler) editMessage(b *gotgbot.Bot, ctx *ext.Context, text string, markup gotgbot.InlineKeyboardMarkup) {
+	_, _, err := ctx.EffectiveMessage.EditText(b, text, &gotgbot.EditMessageTextOpts{
+		ReplyMarkup: markup,
+	})
+	if err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		log.Printf("%s: Error editing tasks message: %v", utils.GetCurrentTypeName(), err)
+	}
+}
+
+func formatYesNo(value bool) string {
+	if value {
+		return "да"
+	}
+	return "нет"
+}
+
+func formatTaskRunStatus(status string) string {
+	switch status {
+	case repositories.TaskRunStatusRunning:
+		return "🔄 выполняется"
+	case repositories.TaskRunStatusSuccess:
+		return "✅ успешно"
+	case repositories.TaskRunStatusFailed:
+		return "❌ ошибка"
+	case repositories.TaskRunStatusSkipped:
+		return "⏭ пропущен"
+	default:
+		return status
+	}
+}
+
+// taskRunDuration returns how long the finished run took
+func taskRunDuration(run *repositories.TaskRun) (time.Duration, bool) {
+	if !run.StartedAt.Valid || !run.FinishedAt.Valid {
+		return 0, false
+	}
+	return run.FinishedAt.Time.Sub(run.StartedAt.Time).Round(time.Second), true
+}
+
+func (h *tasksHandler) MessageRemoveInlineKeyboard(b *gotgbot.Bot, userID *int64) {
+	var chatID, messageID int64
+
+	if userID != nil {
+		messageID, chatID = h.userStore.GetPreviousMessageInfo(
+			*userID,
+			tasksCtxDataKeyPreviousMessageID,
+			tasksCtxDataKeyPreviousChatID,
+		)
+	}
+
+	if chatID == 0 || messageID == 0 {
+		return
+	}
+
+	_ = h.messageSenderService.RemoveInlineKeyboard(chatID, messageID)
+}
+
+func (h *tasksHandler) SavePreviousMessageInfo(userID int64, sentMsg *gotgbot.Message) {
+	if sentMsg == nil {
+		return
+	}
+	h.userStore.SetPreviousMessageInfo(userID, sentMsg.MessageId, sentMsg.Chat.Id,
+		tasksCtxDataKeyPreviousMessageID, tasksCtxDataKeyPreviousChatID)
+}
@@ -1,6 +1,7 @@
 package testhandlers
 
 import (
+	"context"
 	"evo-bot-go/internal/buttons"
 	"evo-bot-go/internal/config"
 	"evo-bot-go/internal/constants"
@@ -130,7 +131,7 @@ func (h *tryGenerateCoffeePairsHandler) showConfirmationMenu(b *gotgbot.Bot, msg
 			"\n\nВы уверены, что хотите сгенерировать пары для текущего опроса?"+
 			fmt.Sprintf("\n\n📊 Опрос: неделя %s", latestPoll.WeekStartDate.Format("2006-01-02"))+
 			fmt.Sprintf("\n👥 Участников: %d", len(participants))+
-			"\n\n⚠️ Пары будут отправлены в сообщество.",
+			"\n\n📝 Пары будут сохранены как черновик: ты сможешь отредактировать их и опубликовать в сообществе.",
 		&gotgbot.SendMessageOpts{
 			ReplyMarkup: buttons.ConfirmAndCancelButton(
 				constants.TryGenerateCoffeePairsConfirmCallback,
@@ -163,8 +164,11 @@ func (h *tryGenerateCoffeePairsHandler) handleConfirmCallback(b *gotgbot.Bot, ct
 		return fmt.Errorf("%s: failed to send processing message: %w", utils.GetCurrentTypeName(), err)
 	}
 
-	// Execute the pairs generation logic
-	err = h.randomCoffeeService.GenerateAndSendPairs()
+	// Execute the pairs generation logic, the draft is published from its preview
+	poll, err := h.randomCoffeeService.GenerateDraftPairs(context.Background())
+	if err == nil {
+		err = h.randomCoffeeService.SendDraftPreview(msg.Chat.Id, int(poll.ID))
+	}
 	if err != nil {
 		h.RemovePreviousMessage(b, &userId)
 
@@ -190,18 +194,6 @@ func (h *tryGenerateCoffeePairsHandler) handleConfirmCallback(b *gotgbot.Bot, ct
 	}
 
 	h.RemovePreviousMessage(b, &userId)
-
-	// Send success message
-	err = h.sender.SendHtml(
-		msg.Chat.Id,
-		fmt.Sprintf("<b>%s</b>", tryGenerateCoffeePairsMenuHeader)+
-			"\n\n✅ Пары успешно сгенерированы и отправлены в супергруппу!",
-		nil)
-
-	if err != nil {
-		return fmt.Errorf("%s: failed to send success message: %w", utils.GetCurrentTypeName(), err)
-	}
-
 	h.userStore.Clear(userId)
 	return handlers.EndConversation()
 }
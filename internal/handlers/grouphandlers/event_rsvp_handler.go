@@ -0,0 +1,113 @@
+package grouphandlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+)
+
+type EventRsvpHandler struct {
+	eventRepository        *repositories.EventRepository
+	eventAttendanceService *services.EventAttendanceService
+}
+
+// NewEventRsvpHandler handles the RSVP buttons of event announcements and reminders
+func NewEventRsvpHandler(
+	eventRepository *repositories.EventRepository,
+	eventAttendanceService *services.EventAttendanceService,
+) ext.Handler {
+	h := &EventRsvpHandler{
+		eventRepository:        eventRepository,
+		eventAttendanceService: eventAttendanceService,
+	}
+	return handlers.NewCallback(callbackquery.Prefix(constants.EventRsvpCallbackPrefix), h.handleCallback)
+}
+
+func (h *EventRsvpHandler) handleCallback(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+
+	eventIDStr, statusStr, _ := strings.Cut(strings.TrimPrefix(cb.Data, constants.EventRsvpCallbackPrefix), "_")
+	eventID, err := strconv.Atoi(eventIDStr)
+	if err != nil {
+		_, _ = cb.Answer(b, nil)
+		return fmt.Errorf("%s: invalid callback data %q: %w", utils.GetCurrentTypeName(), cb.Data, err)
+	}
+	if !slices.Contains(constants.RsvpEventAttendeeStatuses, constants.EventAttendeeStatus(statusStr)) {
+		_, _ = cb.Answer(b, nil)
+		return fmt.Errorf("%s: invalid RSVP status in callback data %q", utils.GetCurrentTypeName(), cb.Data)
+	}
+
+	status, err := h.eventAttendanceService.Respond(ctx.EffectiveUser, eventID, constants.EventAttendeeStatus(statusStr))
+	if errors.Is(err, services.ErrEventRsvpClosed) {
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "ℹ️ Мероприятие уже прошло или отменено — ответы больше не принимаются."})
+		return nil
+	}
+	if err != nil {
+		log.Printf("%s: Failed to save RSVP %q of user %d: %v", utils.GetCurrentTypeName(), cb.Data, ctx.EffectiveUser.Id, err)
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "❌ Не удалось сохранить ответ. Попробуй позже."})
+		return nil
+	}
+
+	var answer string
+	switch status {
+	case constants.EventAttendeeStatusGoing:
+		answer = "✅ Ты в списке участников!"
+	case constants.EventAttendeeStatusWaitlisted:
+		answer = "⏳ Мест больше нет — ты в листе ожидания. Я напишу в личку, когда место освободится."
+	case constants.EventAttendeeStatusMaybe:
+		answer = "🤔 Отметили, что ты, возможно, придёшь"
+	case constants.EventAttendeeStatusNotGoing:
+		answer = "❌ Отметили, что ты не придёшь"
+	}
+	_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: answer, ShowAlert: status == constants.EventAttendeeStatusWaitlisted})
+
+	return h.refreshButtons(b, ctx, eventID)
+}
+
+// refreshButtons updates the numbers on the RSVP buttons, other buttons of the message (e.g. the link) are kept
+func (h *EventRsvpHandler) refreshButtons(b *gotgbot.Bot, ctx *ext.Context, eventID int) error {
+	msg := ctx.EffectiveMessage
+	if msg == nil {
+		return nil
+	}
+
+	event, err := h.eventRepository.GetEventByID(eventID)
+	if err != nil {
+		return err
+	}
+	rsvpMarkup, err := h.eventAttendanceService.RsvpButtons(*event)
+	if err != nil {
+		return err
+	}
+
+	var rows [][]gotgbot.InlineKeyboardButton
+	if msg.ReplyMarkup != nil {
+		for _, row := range msg.ReplyMarkup.InlineKeyboard {
+			if len(row) > 0 && !strings.HasPrefix(row[0].CallbackData, constants.EventRsvpCallbackPrefix) {
+				rows = append(rows, row)
+			}
+		}
+	}
+	rows = append(rows, rsvpMarkup.InlineKeyboard...)
+
+	_, _, err = msg.EditReplyMarkup(b, &gotgbot.EditMessageReplyMarkupOpts{
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{InlineKeyboard: rows},
+	})
+	if err != nil && !strings.Contains(err.Error(), "message is not modified") {
+		return fmt.Errorf("%s: failed to update RSVP buttons: %w", utils.GetCurrentTypeName(), err)
+	}
+	return nil
+}
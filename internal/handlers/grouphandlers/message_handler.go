@@ -19,6 +19,7 @@ type MessageHandler struct {
 	saveTopicService                *grouphandlersservices.SaveTopicService
 	adminSaveMessageService         *grouphandlersservices.AdminSaveMessageService
 	saveMessageService              *grouphandlersservices.SaveMessageService
+	tldrService                     *grouphandlersservices.TldrService
 }
 
 func NewMessageHandler(
@@ -29,6 +30,7 @@ func NewMessageHandler(
 	saveTopicService *grouphandlersservices.SaveTopicService,
 	adminSaveMessageService *grouphandlersservices.AdminSaveMessageService,
 	saveMessageService *grouphandlersservices.SaveMessageService,
+	tldrService *grouphandlersservices.TldrService,
 ) ext.Handler {
 	h := &MessageHandler{
 		messageSenderService:            messageSenderService,
@@ -38,6 +40,7 @@ func NewMessageHandler(
 		saveTopicService:                saveTopicService,
 		adminSaveMessageService:         adminSaveMessageService,
 		saveMessageService:              saveMessageService,
+		tldrService:                     tldrService,
 	}
 
 	return handlers.NewMessage(message.All, h.handle).SetAllowEdited(true)
@@ -70,6 +73,11 @@ func (h *MessageHandler) handle(b *gotgbot.Bot, ctx *ext.Context) error {
 		return h.saveTopicService.SaveOrUpdateTopic(msg)
 	}
 
+	// Reply with TL;DR of the reply chain in monitored topics, than finish processing
+	if h.tldrService.IsTldrRequested(msg) {
+		return h.tldrService.ReplyWithTldr(msg)
+	}
+
 	// Save or delete message in Content and Tools topics
 	// by admin command, than finish processing
 	if h.adminSaveMessageService.IsMessageShouldBeSavedOrUpdated(msg) {
@@ -0,0 +1,208 @@
+package privatehandlers
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/formatters"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+)
+
+// archiveTypesText is the text of the event types choice in /archive
+const archiveTypesText = "🗂 <b>Архив мероприятий</b>\n\nЗаписи, слайды и заметки прошедших мероприятий. Выбери тип мероприятий:"
+
+type archiveHandler struct {
+	config               *config.Config
+	eventArchiveService  *services.EventArchiveService
+	userRepository       *repositories.UserRepository
+	messageSenderService *services.MessageSenderService
+	permissionsService   *services.PermissionsService
+}
+
+// NewArchiveHandler shows the archive of finished events with their materials, by event type
+func NewArchiveHandler(
+	config *config.Config,
+	eventArchiveService *services.EventArchiveService,
+	messageSenderService *services.MessageSenderService,
+	permissionsService *services.PermissionsService,
+) ext.Handler {
+	h := &archiveHandler{
+		config:               config,
+		eventArchiveService:  eventArchiveService,
+		messageSenderService: messageSenderService,
+		permissionsService:   permissionsService,
+	}
+
+	return handlers.NewCommand(constants.ArchiveCommand, h.handleCommand)
+}
+
+// NewArchiveButtonsHandler handles the buttons of /archive and of archive results in /content:
+// event types, pages and documents
+func NewArchiveButtonsHandler(
+	config *config.Config,
+	eventArchiveService *services.EventArchiveService,
+	userRepository *repositories.UserRepository,
+	messageSenderService *services.MessageSenderService,
+) ext.Handler {
+	h := &archiveHandler{
+		config:               config,
+		eventArchiveService:  eventArchiveService,
+		userRepository:       userRepository,
+		messageSenderService: messageSenderService,
+	}
+
+	return handlers.NewCallback(callbackquery.Prefix(constants.ArchivePrefix), h.handleCallback)
+}
+
+func (h *archiveHandler) handleCommand(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	// Only proceed if this is a private chat
+	if !h.permissionsService.CheckPrivateChatType(msg) {
+		return nil
+	}
+
+	// Check if user is a club member
+	if !h.permissionsService.CheckClubMemberPermissions(msg, constants.ArchiveCommand) {
+		return nil
+	}
+
+	return h.messageSenderService.SendHtml(msg.Chat.Id, archiveTypesText, &gotgbot.SendMessageOpts{
+		ReplyMarkup: archiveTypesButtons(),
+	})
+}
+
+func (h *archiveHandler) handleCallback(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+
+	user, err := h.userRepository.GetOrCreate(ctx.EffectiveUser)
+	if err != nil {
+		_, _ = cb.Answer(b, nil)
+		return fmt.Errorf("%s: failed to get user: %w", utils.GetCurrentTypeName(), err)
+	}
+	if !user.IsClubMember {
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "Архив доступен только участникам клуба"})
+		return nil
+	}
+
+	switch {
+	case cb.Data == constants.ArchiveTypesCallback:
+		_, _ = cb.Answer(b, nil)
+		return h.editText(b, ctx, archiveTypesText, archiveTypesButtons())
+	case strings.HasPrefix(cb.Data, constants.ArchiveTypeCallbackPrefix):
+		return h.handlePage(b, ctx)
+	case strings.HasPrefix(cb.Data, constants.ArchiveDocumentCallbackPrefix):
+		return h.handleDocument(b, ctx)
+	}
+
+	_, _ = cb.Answer(b, nil)
+	return nil
+}
+
+// handlePage shows a page of finished events of the type
+func (h *archiveHandler) handlePage(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+
+	// Event types contain no "_", so the page follows the first one
+	data := strings.TrimPrefix(cb.Data, constants.ArchiveTypeCallbackPrefix)
+	typeStr, pageStr, _ := strings.Cut(data, "_")
+	eventType := constants.EventType(typeStr)
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 0 || !slices.Contains(constants.AllEventTypes, eventType) {
+		_, _ = cb.Answer(b, nil)
+		return fmt.Errorf("%s: invalid callback data %q", utils.GetCurrentTypeName(), cb.Data)
+	}
+
+	events, materials, hasMore, err := h.eventArchiveService.GetPage(eventType, page)
+	if err != nil {
+		log.Printf("%s: Failed to get archive of %s: %v", utils.GetCurrentTypeName(), eventType, err)
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "❌ Не удалось загрузить архив. Попробуй позже."})
+		return nil
+	}
+	_, _ = cb.Answer(b, nil)
+
+	title := fmt.Sprintf("%s Архив: %s", formatters.GetTypeEmoji(eventType), formatters.GetTypeInRussian(eventType))
+	if len(events) == 0 {
+		text := fmt.Sprintf("<b>%s</b>\n\nПрошедших мероприятий этого типа пока нет.", title)
+		return h.editText(b, ctx, text, buttons.ArchivePageButtons(nil, string(eventType), page, false))
+	}
+
+	text := formatters.FormatHtmlEventArchive(events, materials, title, h.config.ClubTimezone)
+	markup := buttons.ArchivePageButtons(archiveDocumentOptions(events, materials), string(eventType), page, hasMore)
+	return h.editText(b, ctx, text, markup)
+}
+
+// handleDocument sends the document material
+func (h *archiveHandler) handleDocument(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+
+	materialID, err := strconv.Atoi(strings.TrimPrefix(cb.Data, constants.ArchiveDocumentCallbackPrefix))
+	if err != nil {
+		_, _ = cb.Answer(b, nil)
+		return fmt.Errorf("%s: invalid callback data %q: %w", utils.GetCurrentTypeName(), cb.Data, err)
+	}
+
+	if err := h.eventArchiveService.SendDocument(ctx.EffectiveChat.Id, materialID); err != nil {
+		log.Printf("%s: Failed to send material %d: %v", utils.GetCurrentTypeName(), materialID, err)
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "❌ Не удалось отправить документ. Возможно, его удалили из архива."})
+		return nil
+	}
+
+	_, _ = cb.Answer(b, nil)
+	return nil
+}
+
+func (h *archiveHandler) editText(b *gotgbot.Bot, ctx *ext.Context, text string, markup gotgbot.InlineKeyboardMarkup) error {
+	opts := &gotgbot.EditMessageTextOpts{
+		ParseMode:          "HTML",
+		ReplyMarkup:        markup,
+		LinkPreviewOptions: &gotgbot.LinkPreviewOptions{IsDisabled: true},
+	}
+
+	if _, _, err := ctx.EffectiveMessage.EditText(b, text, opts); err != nil {
+		return fmt.Errorf("%s: failed to edit archive message: %w", utils.GetCurrentTypeName(), err)
+	}
+	return nil
+}
+
+// archiveTypesButtons builds the event types choice of /archive
+func archiveTypesButtons() gotgbot.InlineKeyboardMarkup {
+	options := make([]buttons.ArchiveOption, 0, len(constants.AllEventTypes))
+	for _, eventType := range constants.AllEventTypes {
+		options = append(options, buttons.ArchiveOption{
+			Key:   string(eventType),
+			Title: fmt.Sprintf("%s %s", formatters.GetTypeEmoji(eventType), formatters.GetTypeInRussian(eventType)),
+		})
+	}
+	return buttons.ArchiveTypesButtons(options)
+}
+
+// archiveDocumentOptions lists the document materials of the events for the buttons sending them
+func archiveDocumentOptions(events []repositories.Event, materials map[int][]repositories.EventMaterial) []buttons.ArchiveOption {
+	var options []buttons.ArchiveOption
+	for _, event := range events {
+		for _, material := range materials[event.ID] {
+			if material.Type != constants.EventMaterialTypeDocument {
+				continue
+			}
+			options = append(options, buttons.ArchiveOption{
+				Key:   strconv.Itoa(material.ID),
+				Title: formatters.FormatEventMaterialTitle(material),
+			})
+		}
+	}
+	return options
+}
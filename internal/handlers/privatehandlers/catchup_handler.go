@@ -0,0 +1,478 @@
+package privatehandlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/message"
+)
+
+const (
+	// Conversation states names
+	catchupStateSelectTopics = "catchup_state_select_topics"
+	catchupStateSelectPeriod = "catchup_state_select_period"
+	catchupStateEnterCustom  = "catchup_state_enter_custom"
+
+	// Period limits
+	catchupMaxPeriod          = 31 * 24 * time.Hour
+	catchupLastThreeDaysShift = 3 * 24 * time.Hour
+
+	// UserStore keys
+	catchupCtxDataKeySelectedTopics    = "catchup_ctx_data_selected_topics"
+	catchupCtxDataKeyProcessing        = "catchup_ctx_data_processing"
+	catchupCtxDataKeyCancelFunc        = "catchup_ctx_data_cancel_func"
+	catchupCtxDataKeyPreviousMessageID = "catchup_ctx_data_previous_message_id"
+	catchupCtxDataKeyPreviousChatID    = "catchup_ctx_data_previous_chat_id"
+)
+
+type catchupHandler struct {
+	config                 *config.Config
+	summarizationService   *services.SummarizationService
+	messageSenderService   *services.MessageSenderService
+	permissionsService     *services.PermissionsService
+	groupMessageRepository *repositories.GroupMessageRepository
+	userStore              *utils.UserDataStore
+}
+
+func NewCatchupHandler(
+	config *config.Config,
+	summarizationService *services.SummarizationService,
+	messageSenderService *services.MessageSenderService,
+	permissionsService *services.PermissionsService,
+	groupMessageRepository *repositories.GroupMessageRepository,
+) ext.Handler {
+	h := &catchupHandler{
+		config:                 config,
+		summarizationService:   summarizationService,
+		messageSenderService:   messageSenderService,
+		permissionsService:     permissionsService,
+		groupMessageRepository: groupMessageRepository,
+		userStore:              utils.NewUserDataStore(),
+	}
+
+	return handlers.NewConversation(
+		[]ext.Handler{
+			handlers.NewCommand(constants.CatchupCommand, h.startCatchup),
+		},
+		map[string][]ext.Handler{
+			catchupStateSelectTopics: {
+				handlers.NewCallback(callbackquery.Prefix(constants.CatchupTopicCallbackPrefix), h.handleTopicToggle),
+				handlers.NewCallback(callbackquery.Equal(constants.CatchupAllTopicsCallback), h.handleAllTopics),
+				handlers.NewCallback(callbackquery.Equal(constants.CatchupTopicsDoneCallback), h.handleTopicsDone),
+				handlers.NewCallback(callbackquery.Equal(constants.CatchupCancelCallback), h.handleCallbackCancel),
+				handlers.NewMessage(message.All, h.handleTextDuringSelection),
+			},
+			catchupStateSelectPeriod: {
+				handlers.NewCallback(callbackquery.Equal(constants.CatchupPeriodSinceLastMsg), h.handlePeriodSinceLastMessage),
+				handlers.NewCallback(callbackquery.Equal(constants.CatchupPeriodLastThreeDays), h.handlePeriodLastThreeDays),
+				handlers.NewCallback(callbackquery.Equal(constants.CatchupPeriodCustomCallback), h.handlePeriodCustom),
+				handlers.NewCallback(callbackquery.Equal(constants.CatchupCancelCallback), h.handleCallbackCancel),
+				handlers.NewMessage(message.All, h.handleTextDuringSelection),
+			},
+			catchupStateEnterCustom: {
+				handlers.NewMessage(message.All, h.handleCustomPeriodInput),
+				handlers.NewCallback(callbackquery.Equal(constants.CatchupCancelCallback), h.handleCallbackCancel),
+			},
+		},
+		&handlers.ConversationOpts{
+			Exits: []ext.Handler{
+				handlers.NewCommand(constants.CancelCommand, h.handleCancel),
+				handlers.NewCallback(callbackquery.Equal(constants.CatchupCancelCallback), h.handleCallbackCancel),
+			},
+		},
+	)
+}
+
+// 1. startCatchup is the entry point handler for the catchup conversation
+func (h *catchupHandler) startCatchup(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	if !h.permissionsService.CheckPrivateChatType(msg) {
+		return handlers.EndConversation()
+	}
+
+	if !h.permissionsService.CheckClubMemberPermissions(msg, constants.CatchupCommand) {
+		return handlers.EndConversation()
+	}
+
+	if len(h.config.MonitoredTopicsIDs) == 0 {
+		h.messageSenderService.Reply(msg, "Нет тем, по которым можно составить сводку.", nil)
+		return handlers.EndConversation()
+	}
+
+	h.userStore.Set(ctx.EffectiveUser.Id, catchupCtxDataKeySelectedTopics, map[int]bool{})
+
+	sentMsg, _ := h.messageSenderService.SendWithReturnMessage(
+		msg.Chat.Id,
+		"Выбери темы, по которым хочешь получить сводку, и нажми «Далее»:",
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.CatchupTopicsButtons(h.buildTopicOptions(map[int]bool{})),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(catchupStateSelectTopics)
+}
+
+// handleTopicToggle selects or deselects a topic
+func (h *catchupHandler) handleTopicToggle(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	topicID, err := strconv.Atoi(strings.TrimPrefix(cb.Data, constants.CatchupTopicCallbackPrefix))
+	if err != nil {
+		log.Printf("%s: Invalid topic callback data %q: %v", utils.GetCurrentTypeName(), cb.Data, err)
+		return nil
+	}
+
+	selected := h.getSelectedTopics(ctx.EffectiveUser.Id)
+	selected[topicID] = !selected[topicID]
+	h.userStore.Set(ctx.EffectiveUser.Id, catchupCtxDataKeySelectedTopics, selected)
+
+	h.updateTopicsKeyboard(b, ctx, selected)
+	return nil
+}
+
+// handleAllTopics selects all monitored topics
+func (h *catchupHandler) handleAllTopics(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	selected := make(map[int]bool, len(h.config.MonitoredTopicsIDs))
+	for _, topicID := range h.config.MonitoredTopicsIDs {
+		selected[topicID] = true
+	}
+	h.userStore.Set(ctx.EffectiveUser.Id, catchupCtxDataKeySelectedTopics, selected)
+
+	h.updateTopicsKeyboard(b, ctx, selected)
+	return nil
+}
+
+// 2. handleTopicsDone moves to the period selection
+func (h *catchupHandler) handleTopicsDone(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+
+	if len(h.getSelectedTopicIDs(ctx.EffectiveUser.Id)) == 0 {
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "Выбери хотя бы одну тему"})
+		return nil
+	}
+	_, _ = cb.Answer(b, nil)
+
+	h.RemovePreviousMessage(b, &ctx.EffectiveUser.Id)
+
+	sentMsg, _ := h.messageSenderService.SendWithReturnMessage(
+		ctx.EffectiveChat.Id,
+		"За какой период составить сводку?",
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.CatchupPeriodButtons(),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(catchupStateSelectPeriod)
+}
+
+// handlePeriodSinceLastMessage summarizes messages since the user's last message in the group
+func (h *catchupHandler) handlePeriodSinceLastMessage(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	lastMessage, err := h.groupMessageRepository.GetLastByUserTgID(ctx.EffectiveUser.Id)
+	if err == sql.ErrNoRows {
+		h.messageSenderService.Send(
+			ctx.EffectiveChat.Id,
+			"Не нашёл твоих сообщений в клубном чате. Выбери другой период.",
+			nil,
+		)
+		return nil
+	}
+	if err != nil {
+		log.Printf("%s: Error getting last user message: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Send(ctx.EffectiveChat.Id, "Произошла ошибка при поиске твоего последнего сообщения.", nil)
+		return nil
+	}
+
+	return h.processCatchup(b, ctx, lastMessage.CreatedAt, time.Now())
+}
+
+// handlePeriodLastThreeDays summarizes messages for the last 3 days
+func (h *catchupHandler) handlePeriodLastThreeDays(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	now := time.Now()
+	return h.processCatchup(b, ctx, now.Add(-catchupLastThreeDaysShift), now)
+}
+
+// handlePeriodCustom asks the user to enter a custom date range
+func (h *catchupHandler) handlePeriodCustom(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	h.RemovePreviousMessage(b, &ctx.EffectiveUser.Id)
+
+	sentMsg, _ := h.messageSenderService.SendWithReturnMessage(
+		ctx.EffectiveChat.Id,
+		fmt.Sprintf("Введи период в формате <code>ДД.ММ.ГГГГ - ДД.ММ.ГГГГ</code> (%s), например <code>01.10.2025 - 05.10.2025</code>. "+
+			"Можно указать одну дату.", h.config.ClubTimezone),
+		&gotgbot.SendMessageOpts{
+			ParseMode:   "HTML",
+			ReplyMarkup: buttons.CancelButton(constants.CatchupCancelCallback),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(catchupStateEnterCustom)
+}
+
+// handleCustomPeriodInput parses the custom date range entered by the user
+func (h *catchupHandler) handleCustomPeriodInput(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	from, to, err := utils.ParseDateRange(msg.Text, h.config.ClubTimezone)
+	if err != nil {
+		h.messageSenderService.Reply(
+			msg,
+			fmt.Sprintf("Не удалось разобрать период. Используй формат ДД.ММ.ГГГГ - ДД.ММ.ГГГГ или /%s для отмены.",
+				constants.CancelCommand),
+			nil,
+		)
+		return nil
+	}
+
+	if to.Sub(from) > catchupMaxPeriod {
+		h.messageSenderService.Reply(
+			msg,
+			fmt.Sprintf("Период не может быть длиннее %d дней. Попробуй ещё раз.", int(catchupMaxPeriod.Hours()/24)),
+			nil,
+		)
+		return nil
+	}
+
+	now := time.Now()
+	if to.After(now) {
+		to = now
+	}
+	if !from.Before(to) {
+		h.messageSenderService.Reply(msg, "Этот период ещё не наступил. Попробуй ещё раз.", nil)
+		return nil
+	}
+
+	return h.processCatchup(b, ctx, from, to)
+}
+
+// 3. processCatchup summarizes the selected topics for the period and sends the results
+func (h *catchupHandler) processCatchup(b *gotgbot.Bot, ctx *ext.Context, from time.Time, to time.Time) error {
+	userId := ctx.EffectiveUser.Id
+	chatId := ctx.EffectiveChat.Id
+
+	if isProcessing, ok := h.userStore.Get(userId, catchupCtxDataKeyProcessing); ok && isProcessing.(bool) {
+		h.messageSenderService.Send(
+			chatId,
+			fmt.Sprintf("Пожалуйста, дождись окончания подготовки сводки, или используй /%s для отмены.",
+				constants.CancelCommand),
+			nil,
+		)
+		return nil
+	}
+
+	if to.Sub(from) > catchupMaxPeriod {
+		from = to.Add(-catchupMaxPeriod)
+	}
+
+	topicIDs := h.getSelectedTopicIDs(userId)
+
+	h.userStore.Set(userId, catchupCtxDataKeyProcessing, true)
+	typingCtx, cancelTyping := context.WithCancel(context.Background())
+	h.userStore.Set(userId, catchupCtxDataKeyCancelFunc, cancelTyping)
+	defer func() {
+		h.userStore.Set(userId, catchupCtxDataKeyProcessing, false)
+		h.userStore.Set(userId, catchupCtxDataKeyCancelFunc, nil)
+	}()
+
+	h.RemovePreviousMessage(b, &userId)
+
+	periodText := fmt.Sprintf("%s – %s (%s)",
+		from.In(h.config.ClubTimezone).Format("02.01.2006 15:04"),
+		to.In(h.config.ClubTimezone).Format("02.01.2006 15:04"),
+		h.config.ClubTimezone,
+	)
+	sentMsg, _ := h.messageSenderService.SendWithReturnMessage(
+		chatId,
+		fmt.Sprintf("Готовлю сводку за период %s...", periodText),
+		&gotgbot.SendMessageOpts{ReplyMarkup: buttons.CancelButton(constants.CatchupCancelCallback)},
+	)
+	h.SavePreviousMessageInfo(userId, sentMsg)
+
+	h.messageSenderService.SendTypingAction(chatId)
+
+	defer cancelTyping()
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				h.messageSenderService.SendTypingAction(chatId)
+			case <-typingCtx.Done():
+				return
+			}
+		}
+	}()
+
+	sentSummaries := 0
+	for _, topicID := range topicIDs {
+		summary, err := h.summarizationService.SummarizeTopicForPeriod(typingCtx, topicID, from, to)
+		if typingCtx.Err() != nil {
+			log.Printf("%s: Request was cancelled", utils.GetCurrentTypeName())
+			return handlers.EndConversation()
+		}
+
+		topicName := h.summarizationService.GetTopicName(topicID)
+		if err != nil {
+			log.Printf("%s: Error summarizing topic %d: %v", utils.GetCurrentTypeName(), topicID, err)
+			h.messageSenderService.SendHtml(
+				chatId,
+				fmt.Sprintf("Не удалось составить сводку по теме <b>\"%s\"</b>.", topicName),
+				nil,
+			)
+			continue
+		}
+		if summary == "" {
+			continue
+		}
+
+		title := fmt.Sprintf("📋 Сводка чата <b>\"%s\"</b> за %s", topicName, periodText)
+		if err := h.messageSenderService.SendHtml(chatId, fmt.Sprintf("%s\n\n%s", title, summary), nil); err != nil {
+			log.Printf("%s: Error during message sending: %v", utils.GetCurrentTypeName(), err)
+			continue
+		}
+		sentSummaries++
+	}
+
+	if sentSummaries == 0 {
+		h.messageSenderService.Send(chatId, "За выбранный период в этих темах не было сообщений.", nil)
+	}
+
+	h.RemovePreviousMessage(b, &userId)
+	h.userStore.Clear(userId)
+
+	return handlers.EndConversation()
+}
+
+// handleTextDuringSelection reminds the user to use the buttons
+func (h *catchupHandler) handleTextDuringSelection(b *gotgbot.Bot, ctx *ext.Context) error {
+	h.messageSenderService.Reply(
+		ctx.EffectiveMessage,
+		fmt.Sprintf("Пожалуйста, воспользуйся кнопками выше или используй /%s для отмены.", constants.CancelCommand),
+		nil,
+	)
+	return nil
+}
+
+// handleCallbackCancel processes the cancel button click
+func (h *catchupHandler) handleCallbackCancel(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	return h.handleCancel(b, ctx)
+}
+
+// handleCancel handles the /cancel command
+func (h *catchupHandler) handleCancel(b *gotgbot.Bot, ctx *ext.Context) error {
+	if cancelFunc, ok := h.userStore.Get(ctx.EffectiveUser.Id, catchupCtxDataKeyCancelFunc); ok {
+		if cf, ok := cancelFunc.(context.CancelFunc); ok {
+			cf()
+		}
+	}
+
+	h.messageSenderService.Send(ctx.EffectiveChat.Id, "Подготовка сводки отменена.", nil)
+	h.RemovePreviousMessage(b, &ctx.EffectiveUser.Id)
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+func (h *catchupHandler) updateTopicsKeyboard(b *gotgbot.Bot, ctx *ext.Context, selected map[int]bool) {
+	_, _, err := ctx.EffectiveMessage.EditReplyMarkup(b, &gotgbot.EditMessageReplyMarkupOpts{
+		ReplyMarkup: buttons.CatchupTopicsButtons(h.buildTopicOptions(selected)),
+	})
+	if err != nil {
+		log.Printf("%s: Error updating topics keyboard: %v", utils.GetCurrentTypeName(), err)
+	}
+}
+
+func (h *catchupHandler) buildTopicOptions(selected map[int]bool) []buttons.CatchupTopic {
+	topics := make([]buttons.CatchupTopic, 0, len(h.config.MonitoredTopicsIDs))
+	for _, topicID := range h.config.MonitoredTopicsIDs {
+		topics = append(topics, buttons.CatchupTopic{
+			ID:       topicID,
+			Name:     h.summarizationService.GetTopicName(topicID),
+			Selected: selected[topicID],
+		})
+	}
+	return topics
+}
+
+func (h *catchupHandler) getSelectedTopics(userID int64) map[int]bool {
+	if val, ok := h.userStore.Get(userID, catchupCtxDataKeySelectedTopics); ok {
+		if selected, ok := val.(map[int]bool); ok {
+			return selected
+		}
+	}
+	return map[int]bool{}
+}
+
+// getSelectedTopicIDs returns the selected topics in the configured order
+func (h *catchupHandler) getSelectedTopicIDs(userID int64) []int {
+	selected := h.getSelectedTopics(userID)
+	var topicIDs []int
+	for _, topicID := range h.config.MonitoredTopicsIDs {
+		if selected[topicID] {
+			topicIDs = append(topicIDs, topicID)
+		}
+	}
+	return topicIDs
+}
+
+func (h *catchupHandler) RemovePreviousMessage(b *gotgbot.Bot, userID *int64) {
+	var chatID, messageID int64
+
+	if userID != nil {
+		messageID, chatID = h.userStore.GetPreviousMessageInfo(
+			*userID,
+			catchupCtxDataKeyPreviousMessageID,
+			catchupCtxDataKeyPreviousChatID,
+		)
+	}
+
+	if chatID == 0 || messageID == 0 {
+		return
+	}
+
+	b.DeleteMessage(chatID, messageID, nil)
+}
+
+func (h *catchupHandler) SavePreviousMessageInfo(userID int64, sentMsg *gotgbot.Message) {
+	if sentMsg == nil {
+		return
+	}
+	h.userStore.SetPreviousMessageInfo(userID, sentMsg.MessageId, sentMsg.Chat.Id,
+		catchupCtxDataKeyPreviousMessageID, catchupCtxDataKeyPreviousChatID)
+}
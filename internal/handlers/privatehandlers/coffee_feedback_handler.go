@@ -0,0 +1,106 @@
+package privatehandlers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+)
+
+type coffeeFeedbackHandler struct {
+	feedbackService *services.RandomCoffeeFeedbackService
+}
+
+// NewCoffeeFeedbackHandler handles answers to random coffee feedback requests
+func NewCoffeeFeedbackHandler(feedbackService *services.RandomCoffeeFeedbackService) ext.Handler {
+	h := &coffeeFeedbackHandler{
+		feedbackService: feedbackService,
+	}
+
+	return handlers.NewCallback(callbackquery.Prefix(constants.CoffeeFeedbackPrefix), h.handleCallback)
+}
+
+func (h *coffeeFeedbackHandler) handleCallback(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	if data, ok := strings.CutPrefix(cb.Data, constants.CoffeeFeedbackStatusCallbackPrefix); ok {
+		return h.handleStatus(b, ctx, data)
+	}
+	if data, ok := strings.CutPrefix(cb.Data, constants.CoffeeFeedbackRateCallbackPrefix); ok {
+		return h.handleRating(b, ctx, data)
+	}
+	return nil
+}
+
+func (h *coffeeFeedbackHandler) handleStatus(b *gotgbot.Bot, ctx *ext.Context, data string) error {
+	idStr, statusStr, _ := strings.Cut(data, "_")
+	feedbackID, err := strconv.Atoi(idStr)
+	if err != nil {
+		return fmt.Errorf("%s: invalid feedback ID in callback data %q: %w", utils.GetCurrentTypeName(), data, err)
+	}
+
+	status := constants.CoffeeFeedbackStatus(statusStr)
+	if err := h.feedbackService.SaveStatus(feedbackID, ctx.EffectiveUser.Id, status); err != nil {
+		log.Printf("%s: Failed to save feedback status: %v", utils.GetCurrentTypeName(), err)
+		return h.editFeedbackMessage(b, ctx, "❌ Не удалось сохранить ответ. Попробуй позже.", nil)
+	}
+
+	switch status {
+	case constants.CoffeeFeedbackStatusMet:
+		markup := buttons.CoffeeFeedbackRatingButtons(feedbackID)
+		return h.editFeedbackMessage(b, ctx, "☕️ Здорово, что встреча состоялась! Оцени её, пожалуйста:", &markup)
+	case constants.CoffeeFeedbackStatusPlanned:
+		// The meeting may still happen, so the user can report it later
+		markup := buttons.CoffeeFeedbackStatusButtons(feedbackID, false)
+		return h.editFeedbackMessage(b, ctx,
+			"🗓 Отлично, спасибо! Когда встретитесь, нажми «Встретились» — или сообщи, если пара пропадёт.", &markup)
+	default:
+		return h.editFeedbackMessage(b, ctx,
+			"🙏 Спасибо за ответ! Администратор в курсе, а бот учтёт это при подборе следующих пар.", nil)
+	}
+}
+
+func (h *coffeeFeedbackHandler) handleRating(b *gotgbot.Bot, ctx *ext.Context, data string) error {
+	idStr, ratingStr, _ := strings.Cut(data, "_")
+	feedbackID, err := strconv.Atoi(idStr)
+	if err != nil {
+		return fmt.Errorf("%s: invalid feedback ID in callback data %q: %w", utils.GetCurrentTypeName(), data, err)
+	}
+	rating, err := strconv.Atoi(ratingStr)
+	if err != nil {
+		return fmt.Errorf("%s: invalid rating in callback data %q: %w", utils.GetCurrentTypeName(), data, err)
+	}
+
+	// Zero rating means the user skipped it
+	if rating > 0 {
+		if err := h.feedbackService.SaveRating(feedbackID, ctx.EffectiveUser.Id, rating); err != nil {
+			log.Printf("%s: Failed to save feedback rating: %v", utils.GetCurrentTypeName(), err)
+			return h.editFeedbackMessage(b, ctx, "❌ Не удалось сохранить оценку. Попробуй позже.", nil)
+		}
+	}
+
+	return h.editFeedbackMessage(b, ctx, "🙏 Спасибо за обратную связь! До встречи в следующем Random Coffee ☕️", nil)
+}
+
+func (h *coffeeFeedbackHandler) editFeedbackMessage(b *gotgbot.Bot, ctx *ext.Context, text string, markup *gotgbot.InlineKeyboardMarkup) error {
+	opts := &gotgbot.EditMessageTextOpts{ParseMode: "HTML"}
+	if markup != nil {
+		opts.ReplyMarkup = *markup
+	}
+
+	if _, _, err := ctx.EffectiveMessage.EditText(b, text, opts); err != nil {
+		return fmt.Errorf("%s: failed to edit feedback message: %w", utils.GetCurrentTypeName(), err)
+	}
+	return nil
+}
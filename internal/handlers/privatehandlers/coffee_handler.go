@@ -0,0 +1,695 @@
+package privatehandlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/formatters"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/message"
+)
+
+const (
+	// Conversation states names
+	coffeeStatePreferences = "coffee_state_preferences"
+	coffeeStateEnterValue  = "coffee_state_enter_value"
+
+	// Value that clears a text preference
+	coffeeClearValue = "-"
+
+	// UserStore keys
+	coffeeCtxDataKeyField             = "coffee_ctx_data_field"
+	coffeeCtxDataKeyPreviousMessageID = "coffee_ctx_data_previous_message_id"
+	coffeeCtxDataKeyPreviousChatID    = "coffee_ctx_data_previous_chat_id"
+)
+
+type coffeeHandler struct {
+	config                 *config.Config
+	messageSenderService   *services.MessageSenderService
+	permissionsService     *services.PermissionsService
+	userRepository         *repositories.UserRepository
+	preferenceRepository   *repositories.RandomCoffeePreferenceRepository
+	statsRepository        *repositories.RandomCoffeeStatsRepository
+	subscriptionRepository *repositories.RandomCoffeeSubscriptionRepository
+	exclusionRepository    *repositories.RandomCoffeeExclusionRepository
+	userStore              *utils.UserDataStore
+}
+
+func NewCoffeeHandler(
+	config *config.Config,
+	messageSenderService *services.MessageSenderService,
+	permissionsService *services.PermissionsService,
+	userRepository *repositories.UserRepository,
+	preferenceRepository *repositories.RandomCoffeePreferenceRepository,
+	statsRepository *repositories.RandomCoffeeStatsRepository,
+	subscriptionRepository *repositories.RandomCoffeeSubscriptionRepository,
+	exclusionRepository *repositories.RandomCoffeeExclusionRepository,
+) ext.Handler {
+	h := &coffeeHandler{
+		config:                 config,
+		messageSenderService:   messageSenderService,
+		permissionsService:     permissionsService,
+		userRepository:         userRepository,
+		preferenceRepository:   preferenceRepository,
+		statsRepository:        statsRepository,
+		subscriptionRepository: subscriptionRepository,
+		exclusionRepository:    exclusionRepository,
+		userStore:              utils.NewUserDataStore(),
+	}
+
+	return handlers.NewConversation(
+		[]ext.Handler{
+			handlers.NewCommand(constants.CoffeeCommand, h.startCoffee),
+		},
+		map[string][]ext.Handler{
+			coffeeStatePreferences: {
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeePreferencesFormatCallback), h.handleFormatToggle),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeePreferencesStrictCallback), h.handleStrictToggle),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeePreferencesCityCallback), h.handleEditValue),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeePreferencesLanguagesCallback), h.handleEditValue),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeePreferencesInterestsCallback), h.handleEditValue),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeHistoryCallback), h.handleHistory),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeSubscriptionCallback), h.handleSubscription),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeSubscribeCallback), h.handleSubscriptionChange),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeUnsubscribeCallback), h.handleSubscriptionChange),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeFrequencyCallback), h.handleSubscriptionChange),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeResumeCallback), h.handleSubscriptionChange),
+				handlers.NewCallback(callbackquery.Prefix(constants.CoffeePauseCallbackPrefix), h.handleSubscriptionChange),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeExclusionsCallback), h.handleExclusions),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeExclusionsAddCallback), h.handleExclusionAdd),
+				handlers.NewCallback(callbackquery.Prefix(constants.CoffeeExclusionsRemovePrefix), h.handleExclusionRemove),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeBackCallback), h.handleBack),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeCloseCallback), h.handleClose),
+				handlers.NewMessage(message.All, h.handleTextDuringSelection),
+			},
+			coffeeStateEnterValue: {
+				handlers.NewMessage(message.Text, h.handleValueInput),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeExclusionsCallback), h.handleExclusions),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeBackCallback), h.handleBack),
+				handlers.NewCallback(callbackquery.Equal(constants.CoffeeCloseCallback), h.handleClose),
+			},
+		},
+		&handlers.ConversationOpts{
+			Exits: []ext.Handler{
+				handlers.NewCommand(constants.CancelCommand, h.handleCancel),
+			},
+		},
+	)
+}
+
+// 1. startCoffee is the entry point handler for the random coffee menu
+func (h *coffeeHandler) startCoffee(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+
+	if !h.permissionsService.CheckPrivateChatType(msg) {
+		return handlers.EndConversation()
+	}
+
+	if !h.permissionsService.CheckClubMemberPermissions(msg, constants.CoffeeCommand) {
+		return handlers.EndConversation()
+	}
+
+	preference, err := h.getPreference(ctx)
+	if err != nil {
+		log.Printf("%s: Error getting random coffee preferences: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Reply(msg, "Произошла ошибка при получении твоих пожеланий для Random Coffee.", nil)
+		return handlers.EndConversation()
+	}
+
+	sentMsg, _ := h.messageSenderService.SendHtmlWithReturnMessage(
+		msg.Chat.Id,
+		formatters.FormatCoffeePreferencesView(preference),
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.CoffeePreferencesButtons(preference.Strict),
+		},
+	)
+
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+	return handlers.NextConversationState(coffeeStatePreferences)
+}
+
+// handleFormatToggle switches the meeting format to the next one
+func (h *coffeeHandler) handleFormatToggle(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	return h.updatePreference(b, ctx, func(preference *repositories.RandomCoffeePreference) {
+		formats := constants.AllCoffeeMeetingFormats
+		next := formats[0]
+		for i, format := range formats {
+			if format == preference.MeetingFormat {
+				next = formats[(i+1)%len(formats)]
+				break
+			}
+		}
+		preference.MeetingFormat = next
+	})
+}
+
+// handleStrictToggle switches between soft and hard constraints
+func (h *coffeeHandler) handleStrictToggle(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	return h.updatePreference(b, ctx, func(preference *repositories.RandomCoffeePreference) {
+		preference.Strict = !preference.Strict
+	})
+}
+
+// 2. handleEditValue asks the user to enter a text preference
+func (h *coffeeHandler) handleEditValue(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	var prompt string
+	switch cb.Data {
+	case constants.CoffeePreferencesCityCallback:
+		prompt = "Напиши город, в котором можешь встретиться офлайн, например <code>Берлин</code>."
+	case constants.CoffeePreferencesLanguagesCallback:
+		prompt = "Перечисли через запятую языки, на которых тебе удобно общаться, например <code>русский, English</code>."
+	case constants.CoffeePreferencesInterestsCallback:
+		prompt = fmt.Sprintf(
+			"Перечисли через запятую темы, о которых хочешь поговорить (до %d), например <code>LLM-агенты, Go, карьера</code>. "+
+				"Можно взять их из своего профиля.",
+			utils.MaxCoffeePreferenceItems,
+		)
+	default:
+		return nil
+	}
+	prompt += fmt.Sprintf("\n\nОтправь <code>%s</code>, чтобы очистить значение.", coffeeClearValue)
+
+	h.userStore.Set(ctx.EffectiveUser.Id, coffeeCtxDataKeyField, cb.Data)
+
+	_, _, err := ctx.EffectiveMessage.EditText(b, prompt, &gotgbot.EditMessageTextOpts{
+		ParseMode:   "HTML",
+		ReplyMarkup: buttons.BackAndCancelButton(constants.CoffeeBackCallback, constants.CoffeeCloseCallback),
+	})
+	if err != nil {
+		log.Printf("%s: Error editing message: %v", utils.GetCurrentTypeName(), err)
+	}
+
+	return handlers.NextConversationState(coffeeStateEnterValue)
+}
+
+// handleValueInput saves the entered text preference and returns to the preferences view
+func (h *coffeeHandler) handleValueInput(b *gotgbot.Bot, ctx *ext.Context) error {
+	msg := ctx.EffectiveMessage
+	value := strings.TrimSpace(msg.Text)
+
+	field, _ := h.userStore.Get(ctx.EffectiveUser.Id, coffeeCtxDataKeyField)
+	if field == constants.CoffeeExclusionsAddCallback {
+		return h.handleExclusionInput(b, ctx, value)
+	}
+	if field == constants.CoffeePreferencesCityCallback && utf8.RuneCountInString(value) > utils.MaxCoffeePreferenceItemLength {
+		h.messageSenderService.Reply(
+			msg,
+			fmt.Sprintf("Название города не может быть длиннее %d символов. Попробуй ещё раз.", utils.MaxCoffeePreferenceItemLength),
+			nil,
+		)
+		return nil
+	}
+	if value == coffeeClearValue {
+		value = ""
+	}
+
+	preference, err := h.getPreference(ctx)
+	if err != nil {
+		log.Printf("%s: Error getting random coffee preferences: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Reply(msg, "Произошла ошибка при сохранении пожеланий.", nil)
+		return nil
+	}
+
+	switch field {
+	case constants.CoffeePreferencesCityCallback:
+		preference.City = strings.Join(strings.Fields(value), " ")
+	case constants.CoffeePreferencesLanguagesCallback:
+		preference.Languages = utils.ParseCoffeePreferenceList(value)
+	case constants.CoffeePreferencesInterestsCallback:
+		preference.Interests = utils.ParseCoffeePreferenceList(value)
+	}
+
+	if err := h.preferenceRepository.Upsert(*preference); err != nil {
+		log.Printf("%s: Error saving random coffee preferences: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Reply(msg, "Произошла ошибка при сохранении пожеланий.", nil)
+		return nil
+	}
+
+	b.DeleteMessage(msg.Chat.Id, msg.MessageId, nil)
+	h.RemovePreviousMessage(b, &ctx.EffectiveUser.Id)
+
+	sentMsg, _ := h.messageSenderService.SendHtmlWithReturnMessage(
+		msg.Chat.Id,
+		formatters.FormatCoffeePreferencesView(preference),
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: buttons.CoffeePreferencesButtons(preference.Strict),
+		},
+	)
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+
+	return handlers.NextConversationState(coffeeStatePreferences)
+}
+
+// handleHistory shows past partners, the streak and members the user has never met
+func (h *coffeeHandler) handleHistory(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	text, err := h.getHistoryView(ctx)
+	if err != nil {
+		log.Printf("%s: Error getting random coffee history: %v", utils.GetCurrentTypeName(), err)
+		text = "Произошла ошибка при получении истории Random Coffee."
+	}
+
+	_, _, err = ctx.EffectiveMessage.EditText(b, text, &gotgbot.EditMessageTextOpts{
+		ParseMode:   "HTML",
+		ReplyMarkup: buttons.BackAndCancelButton(constants.CoffeeBackCallback, constants.CoffeeCloseCallback),
+	})
+	if err != nil {
+		log.Printf("%s: Error editing message: %v", utils.GetCurrentTypeName(), err)
+	}
+	return nil
+}
+
+// handleSubscription shows the standing subscription menu
+func (h *coffeeHandler) handleSubscription(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	user, err := h.userRepository.GetOrCreate(ctx.EffectiveUser)
+	if err != nil {
+		log.Printf("%s: Error getting user: %v", utils.GetCurrentTypeName(), err)
+		return nil
+	}
+
+	subscription, err := h.subscriptionRepository.GetByUserID(user.ID)
+	if err != nil {
+		log.Printf("%s: Error getting random coffee subscription: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Send(ctx.EffectiveChat.Id, "Произошла ошибка при получении подписки на Random Coffee.", nil)
+		return nil
+	}
+
+	h.showSubscription(b, ctx, subscription)
+	return nil
+}
+
+// handleSubscriptionChange subscribes, unsubscribes, pauses the subscription or changes its frequency
+func (h *coffeeHandler) handleSubscriptionChange(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	user, err := h.userRepository.GetOrCreate(ctx.EffectiveUser)
+	if err != nil {
+		log.Printf("%s: Error getting user: %v", utils.GetCurrentTypeName(), err)
+		return nil
+	}
+
+	subscription, err := h.subscriptionRepository.GetByUserID(user.ID)
+	if err != nil {
+		log.Printf("%s: Error getting random coffee subscription: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Send(ctx.EffectiveChat.Id, "Произошла ошибка при получении подписки на Random Coffee.", nil)
+		return nil
+	}
+
+	// Biweekly rounds are counted from the first round in which the subscriber takes part
+	nextWeek := utils.NextCoffeeWeekStart(time.Now().In(h.config.ClubTimezone))
+	firstActiveWeek := func() time.Time {
+		if subscription.IsPaused(nextWeek) {
+			return subscription.PausedUntil
+		}
+		return nextWeek
+	}
+
+	switch {
+	case cb.Data == constants.CoffeeSubscribeCallback:
+		if subscription == nil {
+			subscription = &repositories.RandomCoffeeSubscription{
+				UserID:    user.ID,
+				Frequency: constants.CoffeeSubscriptionFrequencyWeekly,
+				StartWeek: nextWeek,
+			}
+		}
+		err = h.subscriptionRepository.Upsert(*subscription)
+	case cb.Data == constants.CoffeeUnsubscribeCallback:
+		subscription = nil
+		err = h.subscriptionRepository.Delete(user.ID)
+	case subscription == nil:
+		// The subscription was removed in another menu, just show the actual state
+	case cb.Data == constants.CoffeeFrequencyCallback:
+		if subscription.Frequency == constants.CoffeeSubscriptionFrequencyBiweekly {
+			subscription.Frequency = constants.CoffeeSubscriptionFrequencyWeekly
+		} else {
+			subscription.Frequency = constants.CoffeeSubscriptionFrequencyBiweekly
+		}
+		subscription.StartWeek = firstActiveWeek()
+		err = h.subscriptionRepository.Upsert(*subscription)
+	case cb.Data == constants.CoffeeResumeCallback:
+		subscription.PausedUntil = time.Time{}
+		subscription.StartWeek = nextWeek
+		err = h.subscriptionRepository.Upsert(*subscription)
+	default:
+		weeks, convErr := strconv.Atoi(strings.TrimPrefix(cb.Data, constants.CoffeePauseCallbackPrefix))
+		if convErr != nil || weeks < 1 {
+			log.Printf("%s: Invalid pause callback data: %s", utils.GetCurrentTypeName(), cb.Data)
+			return nil
+		}
+		subscription.PausedUntil = nextWeek.AddDate(0, 0, 7*weeks)
+		subscription.StartWeek = subscription.PausedUntil
+		err = h.subscriptionRepository.Upsert(*subscription)
+	}
+
+	if err != nil {
+		log.Printf("%s: Error saving random coffee subscription: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Send(ctx.EffectiveChat.Id, "Произошла ошибка при сохранении подписки на Random Coffee.", nil)
+		return nil
+	}
+
+	h.showSubscription(b, ctx, subscription)
+	return nil
+}
+
+// showSubscription refreshes the subscription menu in place
+func (h *coffeeHandler) showSubscription(b *gotgbot.Bot, ctx *ext.Context, subscription *repositories.RandomCoffeeSubscription) {
+	nextWeek := utils.NextCoffeeWeekStart(time.Now().In(h.config.ClubTimezone))
+
+	var markup gotgbot.InlineKeyboardMarkup
+	if subscription == nil {
+		markup = buttons.CoffeeSubscriptionButtons(false, false, "")
+	} else {
+		markup = buttons.CoffeeSubscriptionButtons(true, subscription.IsPaused(nextWeek), subscription.Frequency)
+	}
+
+	_, _, err := ctx.EffectiveMessage.EditText(b, formatters.FormatCoffeeSubscriptionView(subscription, nextWeek), &gotgbot.EditMessageTextOpts{
+		ParseMode:   "HTML",
+		ReplyMarkup: markup,
+	})
+	if err != nil {
+		log.Printf("%s: Error updating subscription view: %v", utils.GetCurrentTypeName(), err)
+	}
+}
+
+// handleExclusions shows the personal list of users the user doesn't want to be paired with
+func (h *coffeeHandler) handleExclusions(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	text, markup, err := h.getExclusionsView(ctx)
+	if err != nil {
+		log.Printf("%s: Error getting random coffee exclusions: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Send(ctx.EffectiveChat.Id, "Произошла ошибка при получении списка.", nil)
+		return nil
+	}
+
+	_, _, err = ctx.EffectiveMessage.EditText(b, text, &gotgbot.EditMessageTextOpts{
+		ParseMode:   "HTML",
+		ReplyMarkup: markup,
+	})
+	if err != nil {
+		log.Printf("%s: Error editing message: %v", utils.GetCurrentTypeName(), err)
+	}
+
+	// The list is also shown by the back button of the username prompt
+	return handlers.NextConversationState(coffeeStatePreferences)
+}
+
+// handleExclusionAdd asks the user to enter the username of the user to exclude
+func (h *coffeeHandler) handleExclusionAdd(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	h.userStore.Set(ctx.EffectiveUser.Id, coffeeCtxDataKeyField, cb.Data)
+
+	_, _, err := ctx.EffectiveMessage.EditText(
+		b,
+		"Напиши username участника клуба, с которым тебя не нужно объединять в пару, например <code>@ivan</code>.",
+		&gotgbot.EditMessageTextOpts{
+			ParseMode:   "HTML",
+			ReplyMarkup: buttons.BackAndCancelButton(constants.CoffeeExclusionsCallback, constants.CoffeeCloseCallback),
+		},
+	)
+	if err != nil {
+		log.Printf("%s: Error editing message: %v", utils.GetCurrentTypeName(), err)
+	}
+
+	return handlers.NextConversationState(coffeeStateEnterValue)
+}
+
+// handleExclusionInput adds the user with the entered username to the exclusion list
+func (h *coffeeHandler) handleExclusionInput(b *gotgbot.Bot, ctx *ext.Context, value string) error {
+	msg := ctx.EffectiveMessage
+
+	user, err := h.userRepository.GetOrCreate(ctx.EffectiveUser)
+	if err != nil {
+		log.Printf("%s: Error getting user: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Reply(msg, "Произошла ошибка при сохранении списка.", nil)
+		return nil
+	}
+
+	excluded, err := h.exclusionRepository.GetExcludedUsers(user.ID)
+	if err != nil {
+		log.Printf("%s: Error getting random coffee exclusions: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Reply(msg, "Произошла ошибка при сохранении списка.", nil)
+		return nil
+	}
+	if len(excluded) >= utils.MaxCoffeeExclusions {
+		h.messageSenderService.Reply(msg, fmt.Sprintf("В списке может быть не больше %d участников.", utils.MaxCoffeeExclusions), nil)
+		return nil
+	}
+
+	username := strings.TrimPrefix(value, "@")
+	excludedUser, err := h.userRepository.GetByTelegramUsername(username)
+	if err != nil || username == "" {
+		if err != nil && err != sql.ErrNoRows {
+			log.Printf("%s: Error getting user by username %s: %v", utils.GetCurrentTypeName(), username, err)
+		}
+		h.messageSenderService.Reply(msg, "Не нашёл участника с таким username. Проверь написание и попробуй ещё раз.", nil)
+		return nil
+	}
+	if excludedUser.ID == user.ID {
+		h.messageSenderService.Reply(msg, "Себя добавить в список нельзя 🙂 Попробуй ещё раз.", nil)
+		return nil
+	}
+
+	if err := h.exclusionRepository.Add(user.ID, excludedUser.ID); err != nil {
+		log.Printf("%s: Error adding random coffee exclusion: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Reply(msg, "Произошла ошибка при сохранении списка.", nil)
+		return nil
+	}
+
+	b.DeleteMessage(msg.Chat.Id, msg.MessageId, nil)
+	h.RemovePreviousMessage(b, &ctx.EffectiveUser.Id)
+
+	text, markup, err := h.getExclusionsView(ctx)
+	if err != nil {
+		log.Printf("%s: Error getting random coffee exclusions: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Send(msg.Chat.Id, "Произошла ошибка при получении списка.", nil)
+		return handlers.NextConversationState(coffeeStatePreferences)
+	}
+
+	sentMsg, _ := h.messageSenderService.SendHtmlWithReturnMessage(msg.Chat.Id, text, &gotgbot.SendMessageOpts{
+		ReplyMarkup: markup,
+	})
+	h.SavePreviousMessageInfo(ctx.EffectiveUser.Id, sentMsg)
+
+	return handlers.NextConversationState(coffeeStatePreferences)
+}
+
+// handleExclusionRemove removes the user from the exclusion list
+func (h *coffeeHandler) handleExclusionRemove(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	excludedUserID, err := strconv.Atoi(strings.TrimPrefix(cb.Data, constants.CoffeeExclusionsRemovePrefix))
+	if err != nil {
+		log.Printf("%s: Invalid exclusion callback data: %s", utils.GetCurrentTypeName(), cb.Data)
+		return nil
+	}
+
+	user, err := h.userRepository.GetOrCreate(ctx.EffectiveUser)
+	if err == nil {
+		err = h.exclusionRepository.Remove(user.ID, excludedUserID)
+	}
+	if err != nil {
+		log.Printf("%s: Error removing random coffee exclusion: %v", utils.GetCurrentTypeName(), err)
+		h.messageSenderService.Send(ctx.EffectiveChat.Id, "Произошла ошибка при сохранении списка.", nil)
+		return nil
+	}
+
+	return h.handleExclusions(b, ctx)
+}
+
+// handleBack returns to the preferences view without changes
+func (h *coffeeHandler) handleBack(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	if err := h.updatePreference(b, ctx, nil); err != nil {
+		return err
+	}
+	return handlers.NextConversationState(coffeeStatePreferences)
+}
+
+// 3. handleClose finishes the conversation keeping the preferences view
+func (h *coffeeHandler) handleClose(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.Update.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	preference, err := h.getPreference(ctx)
+	if err == nil {
+		_, _, err = ctx.EffectiveMessage.EditText(b, formatters.FormatCoffeePreferencesView(preference), &gotgbot.EditMessageTextOpts{
+			ParseMode: "HTML",
+		})
+	}
+	if err != nil {
+		log.Printf("%s: Error closing preferences view: %v", utils.GetCurrentTypeName(), err)
+	}
+
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+	return handlers.EndConversation()
+}
+
+// handleTextDuringSelection reminds the user to use the buttons
+func (h *coffeeHandler) handleTextDuringSelection(b *gotgbot.Bot, ctx *ext.Context) error {
+	h.messageSenderService.Reply(
+		ctx.EffectiveMessage,
+		fmt.Sprintf("Пожалуйста, воспользуйся кнопками выше или используй /%s для выхода.", constants.CancelCommand),
+		nil,
+	)
+	return nil
+}
+
+// handleCancel handles the /cancel command
+func (h *coffeeHandler) handleCancel(b *gotgbot.Bot, ctx *ext.Context) error {
+	h.messageSenderService.Send(ctx.EffectiveChat.Id, "Настройка Random Coffee завершена.", nil)
+	h.RemovePreviousMessage(b, &ctx.EffectiveUser.Id)
+	h.userStore.Clear(ctx.EffectiveUser.Id)
+
+	return handlers.EndConversation()
+}
+
+// updatePreference applies the change, saves preferences and refreshes the preferences view in place
+func (h *coffeeHandler) updatePreference(b *gotgbot.Bot, ctx *ext.Context, change func(preference *repositories.RandomCoffeePreference)) error {
+	preference, err := h.getPreference(ctx)
+	if err != nil {
+		log.Printf("%s: Error getting random coffee preferences: %v", utils.GetCurrentTypeName(), err)
+		return nil
+	}
+
+	if change != nil {
+		change(preference)
+		if err := h.preferenceRepository.Upsert(*preference); err != nil {
+			log.Printf("%s: Error saving random coffee preferences: %v", utils.GetCurrentTypeName(), err)
+			h.messageSenderService.Send(ctx.EffectiveChat.Id, "Произошла ошибка при сохранении пожеланий.", nil)
+			return nil
+		}
+	}
+
+	_, _, err = ctx.EffectiveMessage.EditText(b, formatters.FormatCoffeePreferencesView(preference), &gotgbot.EditMessageTextOpts{
+		ParseMode:   "HTML",
+		ReplyMarkup: buttons.CoffeePreferencesButtons(preference.Strict),
+	})
+	if err != nil {
+		log.Printf("%s: Error updating preferences view: %v", utils.GetCurrentTypeName(), err)
+	}
+	return nil
+}
+
+func (h *coffeeHandler) getPreference(ctx *ext.Context) (*repositories.RandomCoffeePreference, error) {
+	user, err := h.userRepository.GetOrCreate(ctx.EffectiveUser)
+	if err != nil {
+		return nil, err
+	}
+
+	preference, _, err := h.preferenceRepository.GetOrCreate(user.ID)
+	return preference, err
+}
+
+func (h *coffeeHandler) getExclusionsView(ctx *ext.Context) (string, gotgbot.InlineKeyboardMarkup, error) {
+	user, err := h.userRepository.GetOrCreate(ctx.EffectiveUser)
+	if err != nil {
+		return "", gotgbot.InlineKeyboardMarkup{}, err
+	}
+
+	excluded, err := h.exclusionRepository.GetExcludedUsers(user.ID)
+	if err != nil {
+		return "", gotgbot.InlineKeyboardMarkup{}, err
+	}
+
+	excludedButtons := make([]buttons.CoffeeExcludedUser, 0, len(excluded))
+	for _, excludedUser := range excluded {
+		excludedButtons = append(excludedButtons, buttons.CoffeeExcludedUser{
+			UserID: excludedUser.ID,
+			Name:   utils.FormatUserDisplayName(excludedUser.Firstname, excludedUser.Lastname, ""),
+		})
+	}
+
+	markup := buttons.CoffeeExclusionsButtons(excludedButtons, len(excluded) < utils.MaxCoffeeExclusions)
+	return formatters.FormatCoffeeExclusionsView(excluded), markup, nil
+}
+
+func (h *coffeeHandler) getHistoryView(ctx *ext.Context) (string, error) {
+	user, err := h.userRepository.GetOrCreate(ctx.EffectiveUser)
+	if err != nil {
+		return "", err
+	}
+
+	meetings, err := h.statsRepository.GetUserMeetings(user.ID)
+	if err != nil {
+		return "", err
+	}
+	rounds, err := h.statsRepository.GetRoundWeeks()
+	if err != nil {
+		return "", err
+	}
+	neverMet, err := h.statsRepository.GetNeverMetUsers(user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	userRounds := make([]time.Time, 0, len(meetings))
+	for _, meeting := range meetings {
+		userRounds = append(userRounds, meeting.WeekStartDate)
+	}
+
+	return formatters.FormatCoffeeHistoryView(meetings, utils.CoffeeStreak(rounds, userRounds), neverMet), nil
+}
+
+func (h *coffeeHandler) RemovePreviousMessage(b *gotgbot.Bot, userID *int64) {
+	var chatID, messageID int64
+
+	if userID != nil {
+		messageID, chatID = h.userStore.GetPreviousMessageInfo(
+			*userID,
+			coffeeCtxDataKeyPreviousMessageID,
+			coffeeCtxDataKeyPreviousChatID,
+		)
+	}
+
+	if chatID == 0 || messageID == 0 {
+		return
+	}
+
+	b.DeleteMessage(chatID, messageID, nil)
+}
+
+func (h *coffeeHandler) SavePreviousMessageInfo(userID int64, sentMsg *gotgbot.Message) {
+	if sentMsg == nil {
+		return
+	}
+	h.userStore.SetPreviousMessageInfo(userID, sentMsg.MessageId, sentMsg.Chat.Id,
+		coffeeCtxDataKeyPreviousMessageID, coffeeCtxDataKeyPreviousChatID)
+}
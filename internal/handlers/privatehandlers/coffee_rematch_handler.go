@@ -0,0 +1,107 @@
+package privatehandlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+)
+
+type coffeeRematchHandler struct {
+	randomCoffeeService *services.RandomCoffeeService
+}
+
+// NewCoffeeRematchHandler handles the "my partner is silent" button of random coffee pair introductions
+func NewCoffeeRematchHandler(randomCoffeeService *services.RandomCoffeeService) ext.Handler {
+	h := &coffeeRematchHandler{
+		randomCoffeeService: randomCoffeeService,
+	}
+
+	return handlers.NewCallback(callbackquery.Prefix(constants.CoffeeRematchPrefix), h.handleCallback)
+}
+
+func (h *coffeeRematchHandler) handleCallback(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+	_, _ = cb.Answer(b, nil)
+
+	if cb.Data == constants.CoffeeRematchCancelCallback {
+		if _, err := ctx.EffectiveMessage.Delete(b, nil); err != nil {
+			log.Printf("%s: Failed to delete rematch confirmation: %v", utils.GetCurrentTypeName(), err)
+		}
+		return nil
+	}
+	if data, ok := strings.CutPrefix(cb.Data, constants.CoffeeRematchAskCallbackPrefix); ok {
+		return h.handleAsk(b, ctx, data)
+	}
+	if data, ok := strings.CutPrefix(cb.Data, constants.CoffeeRematchConfirmCallbackPrefix); ok {
+		return h.handleConfirm(b, ctx, data)
+	}
+	return nil
+}
+
+// handleAsk sends a confirmation as a separate message, so the introduction with the partner's profile stays intact
+func (h *coffeeRematchHandler) handleAsk(b *gotgbot.Bot, ctx *ext.Context, data string) error {
+	pairID, err := strconv.Atoi(data)
+	if err != nil {
+		return fmt.Errorf("%s: invalid pair ID in callback data %q: %w", utils.GetCurrentTypeName(), data, err)
+	}
+
+	_, err = b.SendMessage(
+		ctx.EffectiveChat.Id,
+		"🙊 <b>Партнёр не отвечает?</b>\n\n"+
+			"Бот отметит встречу как несостоявшуюся и подберёт тебе нового партнёра из участников этой недели: "+
+			"того, у кого тоже молчит пара, или того, кто остался без пары.",
+		&gotgbot.SendMessageOpts{
+			ParseMode:   "HTML",
+			ReplyMarkup: buttons.CoffeeRematchConfirmButtons(pairID),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%s: failed to send rematch confirmation: %w", utils.GetCurrentTypeName(), err)
+	}
+	return nil
+}
+
+func (h *coffeeRematchHandler) handleConfirm(b *gotgbot.Bot, ctx *ext.Context, data string) error {
+	pairID, err := strconv.Atoi(data)
+	if err != nil {
+		return fmt.Errorf("%s: invalid pair ID in callback data %q: %w", utils.GetCurrentTypeName(), data, err)
+	}
+
+	partner, err := h.randomCoffeeService.RequestRematch(context.Background(), pairID, ctx.EffectiveUser.Id)
+	switch {
+	case errors.Is(err, services.ErrCoffeeRematchExpired), errors.Is(err, services.ErrCoffeeRematchDone):
+		return h.editText(b, ctx, fmt.Sprintf("ℹ️ Новая пара не нужна: %s.", err.Error()))
+	case err != nil:
+		log.Printf("%s: Failed to rematch pair %d: %v", utils.GetCurrentTypeName(), pairID, err)
+		return h.editText(b, ctx, "❌ Не удалось подобрать нового партнёра. Попробуй позже.")
+	case partner == nil:
+		return h.editText(b, ctx,
+			"⏳ Свободных участников пока нет. Как только кто-то ещё останется без пары, бот вас познакомит и пришлёт сообщение.")
+	}
+
+	return h.editText(b, ctx, fmt.Sprintf(
+		"✅ Новый партнёр на эту неделю — %s. Знакомство с профилем уже в сообщении выше 👆",
+		html.EscapeString(strings.TrimSpace(partner.Firstname+" "+partner.Lastname)),
+	))
+}
+
+func (h *coffeeRematchHandler) editText(b *gotgbot.Bot, ctx *ext.Context, text string) error {
+	if _, _, err := ctx.EffectiveMessage.EditText(b, text, &gotgbot.EditMessageTextOpts{ParseMode: "HTML"}); err != nil {
+		return fmt.Errorf("%s: failed to edit rematch message: %w", utils.GetCurrentTypeName(), err)
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"evo-bot-go/internal/constants"
 	"evo-bot-go/internal/database/prompts"
 	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/formatters"
 	"evo-bot-go/internal/services"
 	"evo-bot-go/internal/utils"
 
@@ -51,6 +52,7 @@ type contentHandler struct {
 	openaiClient                *clients.OpenAiClient
 	promptingTemplateRepository *repositories.PromptingTemplateRepository
 	groupMessageRepository      *repositories.GroupMessageRepository
+	eventArchiveService         *services.EventArchiveService
 	messageSenderService        *services.MessageSenderService
 	userStore                   *utils.UserDataStore
 	permissionsService          *services.PermissionsService
@@ -62,6 +64,7 @@ func NewContentHandler(
 	messageSenderService *services.MessageSenderService,
 	promptingTemplateRepository *repositories.PromptingTemplateRepository,
 	groupMessageRepository *repositories.GroupMessageRepository,
+	eventArchiveService *services.EventArchiveService,
 	permissionsService *services.PermissionsService,
 ) ext.Handler {
 	h := &contentHandler{
@@ -69,6 +72,7 @@ func NewContentHandler(
 		openaiClient:                openaiClient,
 		promptingTemplateRepository: promptingTemplateRepository,
 		groupMessageRepository:      groupMessageRepository,
+		eventArchiveService:         eventArchiveService,
 		messageSenderService:        messageSenderService,
 		userStore:                   utils.NewUserDataStore(),
 		permissionsService:          permissionsService,
@@ -300,6 +304,8 @@ func (h *contentHandler) processContentSearchWithType(b *gotgbot.Bot, ctx *ext.C
 		return handlers.EndConversation()
 	}
 
+	h.sendArchiveResults(msg.Chat.Id, query)
+
 	h.RemovePreviousMessage(b, &userId)
 	h.userStore.Clear(userId)
 
@@ -338,6 +344,30 @@ func (h *contentHandler) handleCancel(b *gotgbot.Bot, ctx *ext.Context) error {
 	return handlers.EndConversation()
 }
 
+// sendArchiveResults sends materials of finished events matching the query, nothing is sent if none match
+func (h *contentHandler) sendArchiveResults(chatID int64, query string) {
+	events, materials, err := h.eventArchiveService.Search(query)
+	if err != nil {
+		// The content topic answer is already sent, the archive is an addition to it
+		log.Printf("%s: Error during archive search: %v", utils.GetCurrentTypeName(), err)
+		return
+	}
+	if len(events) == 0 {
+		return
+	}
+
+	text := formatters.FormatHtmlEventArchive(events, materials, "🗂 Из архива мероприятий", h.config.ClubTimezone)
+	text += fmt.Sprintf("\nВесь архив записей, слайдов и заметок — в /%s.", constants.ArchiveCommand)
+
+	var opts *gotgbot.SendMessageOpts
+	if documents := archiveDocumentOptions(events, materials); len(documents) > 0 {
+		opts = &gotgbot.SendMessageOpts{ReplyMarkup: buttons.ArchiveDocumentsButtons(documents)}
+	}
+	if err := h.messageSenderService.SendHtml(chatID, text, opts); err != nil {
+		log.Printf("%s: Error during archive results sending: %v", utils.GetCurrentTypeName(), err)
+	}
+}
+
 func (h *contentHandler) preprocessingMessages(messages []*repositories.GroupMessage) ([]byte, error) {
 	// MessageObject represents the structured format for AI processing
 	type MessageObject struct {
@@ -0,0 +1,172 @@
+package privatehandlers
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+)
+
+type eventCalendarHandler struct {
+	eventCalendarService *services.EventCalendarService
+	userRepository       *repositories.UserRepository
+	messageSenderService *services.MessageSenderService
+}
+
+// NewEventCalendarHandler handles the calendar buttons of /events: .ics files and the personal calendar feed
+func NewEventCalendarHandler(
+	eventCalendarService *services.EventCalendarService,
+	userRepository *repositories.UserRepository,
+	messageSenderService *services.MessageSenderService,
+) ext.Handler {
+	h := &eventCalendarHandler{
+		eventCalendarService: eventCalendarService,
+		userRepository:       userRepository,
+		messageSenderService: messageSenderService,
+	}
+
+	return handlers.NewCallback(callbackquery.Prefix(constants.EventsCalendarPrefix), h.handleCallback)
+}
+
+func (h *eventCalendarHandler) handleCallback(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+
+	switch {
+	case cb.Data == constants.EventsCalendarAllCallback:
+		return h.handleAll(b, ctx)
+	case cb.Data == constants.EventsCalendarFeedCallback:
+		return h.handleFeed(b, ctx, false)
+	case cb.Data == constants.EventsCalendarFeedResetCallback:
+		return h.handleFeed(b, ctx, true)
+	case strings.HasPrefix(cb.Data, constants.EventsCalendarEventCallbackPrefix):
+		return h.handleEvent(b, ctx)
+	}
+
+	_, _ = cb.Answer(b, nil)
+	return nil
+}
+
+// handleEvent sends the .ics file with a single event
+func (h *eventCalendarHandler) handleEvent(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+
+	eventID, err := strconv.Atoi(strings.TrimPrefix(cb.Data, constants.EventsCalendarEventCallbackPrefix))
+	if err != nil {
+		_, _ = cb.Answer(b, nil)
+		return fmt.Errorf("%s: invalid callback data %q: %w", utils.GetCurrentTypeName(), cb.Data, err)
+	}
+
+	calendar, ok, err := h.eventCalendarService.EventCalendar(eventID)
+	if err != nil {
+		log.Printf("%s: Failed to export event %d: %v", utils.GetCurrentTypeName(), eventID, err)
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "❌ Не удалось выгрузить мероприятие. Попробуй позже."})
+		return nil
+	}
+	if !ok {
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "📅 У этого мероприятия пока нет даты"})
+		return nil
+	}
+
+	_, _ = cb.Answer(b, nil)
+	return h.messageSenderService.SendDocumentWithCaption(
+		ctx.EffectiveChat.Id,
+		fmt.Sprintf("event_%d.ics", eventID),
+		calendar,
+		"📅 Открой файл, чтобы добавить мероприятие в календарь.",
+	)
+}
+
+// handleAll sends the .ics file with all upcoming events
+func (h *eventCalendarHandler) handleAll(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+
+	calendar, ok, err := h.eventCalendarService.UpcomingCalendar()
+	if err != nil {
+		log.Printf("%s: Failed to export upcoming events: %v", utils.GetCurrentTypeName(), err)
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "❌ Не удалось выгрузить мероприятия. Попробуй позже."})
+		return nil
+	}
+	if !ok {
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "📅 Нет предстоящих мероприятий с датой"})
+		return nil
+	}
+
+	_, _ = cb.Answer(b, nil)
+
+	caption := "📅 Открой файл, чтобы добавить все предстоящие мероприятия в календарь."
+	if h.eventCalendarService.IsFeedEnabled() {
+		caption += "\n\nЧтобы новые мероприятия и изменения появлялись в календаре сами, подпишись на календарь кнопкой «🔗 Подписка на календарь» в /" + constants.EventsCommand + "."
+	}
+	return h.messageSenderService.SendDocumentWithCaption(ctx.EffectiveChat.Id, "evocoders_events.ics", calendar, caption)
+}
+
+// handleFeed shows the personal feed link, reset replaces it with a new one
+func (h *eventCalendarHandler) handleFeed(b *gotgbot.Bot, ctx *ext.Context, reset bool) error {
+	cb := ctx.CallbackQuery
+
+	if !h.eventCalendarService.IsFeedEnabled() {
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "Подписка на календарь сейчас недоступна"})
+		return nil
+	}
+
+	user, err := h.userRepository.GetOrCreate(ctx.EffectiveUser)
+	if err != nil {
+		_, _ = cb.Answer(b, nil)
+		return fmt.Errorf("%s: failed to get user: %w", utils.GetCurrentTypeName(), err)
+	}
+	if !user.IsClubMember {
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "Подписка на календарь доступна только участникам клуба"})
+		return nil
+	}
+
+	var feedURL string
+	if reset {
+		feedURL, err = h.eventCalendarService.ResetFeedURL(user.ID)
+	} else {
+		feedURL, err = h.eventCalendarService.FeedURL(user.ID)
+	}
+	if err != nil {
+		log.Printf("%s: Failed to get feed URL of user %d: %v", utils.GetCurrentTypeName(), user.ID, err)
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "❌ Не удалось получить ссылку. Попробуй позже."})
+		return nil
+	}
+
+	answer := ""
+	if reset {
+		answer = "🔄 Старая ссылка больше не работает"
+	}
+	_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: answer})
+
+	text := fmt.Sprintf(
+		"🔗 <b>Твоя ссылка для подписки на календарь клуба:</b>\n\n<code>%s</code>\n\n"+
+			"Добавь её в календарь как подписку по URL (например, в Google Calendar — «Добавить календарь» → «По URL»). "+
+			"Новые мероприятия, изменения и отмены будут появляться в календаре сами.\n\n"+
+			"⚠️ Ссылка личная — не делись ей. Если она попала в чужие руки, нажми «Новая ссылка», и старая перестанет работать.",
+		html.EscapeString(feedURL),
+	)
+	if reset {
+		_, _, err = ctx.EffectiveMessage.EditText(b, text, &gotgbot.EditMessageTextOpts{
+			ParseMode:   "HTML",
+			ReplyMarkup: buttons.EventCalendarFeedButtons(),
+		})
+		if err != nil {
+			return fmt.Errorf("%s: failed to update feed link: %w", utils.GetCurrentTypeName(), err)
+		}
+		return nil
+	}
+	return h.messageSenderService.SendHtml(ctx.EffectiveChat.Id, text, &gotgbot.SendMessageOpts{
+		ReplyMarkup: buttons.EventCalendarFeedButtons(),
+	})
+}
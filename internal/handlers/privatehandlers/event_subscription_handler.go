@@ -0,0 +1,153 @@
+package privatehandlers
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/formatters"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/callbackquery"
+)
+
+type eventSubscriptionHandler struct {
+	config                 *config.Config
+	eventRepository        *repositories.EventRepository
+	subscriptionRepository *repositories.EventSubscriptionRepository
+	userRepository         *repositories.UserRepository
+}
+
+// NewEventSubscriptionHandler handles the buttons of /events that toggle reminders about events and event types
+func NewEventSubscriptionHandler(
+	config *config.Config,
+	eventRepository *repositories.EventRepository,
+	subscriptionRepository *repositories.EventSubscriptionRepository,
+	userRepository *repositories.UserRepository,
+) ext.Handler {
+	h := &eventSubscriptionHandler{
+		config:                 config,
+		eventRepository:        eventRepository,
+		subscriptionRepository: subscriptionRepository,
+		userRepository:         userRepository,
+	}
+
+	return handlers.NewCallback(callbackquery.Prefix(constants.EventsSubscribePrefix), h.handleCallback)
+}
+
+func (h *eventSubscriptionHandler) handleCallback(b *gotgbot.Bot, ctx *ext.Context) error {
+	cb := ctx.CallbackQuery
+
+	user, err := h.userRepository.GetOrCreate(ctx.EffectiveUser)
+	if err != nil {
+		_, _ = cb.Answer(b, nil)
+		return fmt.Errorf("%s: failed to get user: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	subscriptions, err := h.subscriptionRepository.GetByUserID(user.ID)
+	if err != nil {
+		_, _ = cb.Answer(b, nil)
+		return err
+	}
+
+	var subscribed bool
+	if data, ok := strings.CutPrefix(cb.Data, constants.EventsSubscribeEventCallbackPrefix); ok {
+		subscribed, err = h.toggleEvent(user.ID, data, subscriptions)
+	} else if data, ok := strings.CutPrefix(cb.Data, constants.EventsSubscribeEventTypeCallbackPrefix); ok {
+		subscribed, err = h.toggleEventType(user.ID, data, subscriptions)
+	} else {
+		_, _ = cb.Answer(b, nil)
+		return nil
+	}
+	if err != nil {
+		log.Printf("%s: Failed to toggle event subscription %q: %v", utils.GetCurrentTypeName(), cb.Data, err)
+		_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: "❌ Не удалось изменить подписку. Попробуй позже."})
+		return nil
+	}
+
+	answer := "🔕 Напоминания выключены"
+	if subscribed {
+		answer = "🔔 Напоминания включены"
+	}
+	_, _ = cb.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: answer})
+
+	events, err := h.eventRepository.GetLastActualEvents(eventsListLimit)
+	if err != nil {
+		return fmt.Errorf("%s: failed to get events: %w", utils.GetCurrentTypeName(), err)
+	}
+	subscriptions, err = h.subscriptionRepository.GetByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = ctx.EffectiveMessage.EditReplyMarkup(b, &gotgbot.EditMessageReplyMarkupOpts{
+		ReplyMarkup: eventsListButtons(events, subscriptions, h.config.CalendarFeedListenAddr != ""),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: failed to update subscription buttons: %w", utils.GetCurrentTypeName(), err)
+	}
+	return nil
+}
+
+// toggleEvent switches reminders about the event, returns whether the user is subscribed now
+func (h *eventSubscriptionHandler) toggleEvent(userID int, data string, subscriptions *repositories.EventSubscriptions) (bool, error) {
+	eventID, err := strconv.Atoi(data)
+	if err != nil {
+		return false, fmt.Errorf("%s: invalid event ID %q: %w", utils.GetCurrentTypeName(), data, err)
+	}
+
+	if subscriptions.EventIDs[eventID] {
+		return false, h.subscriptionRepository.UnsubscribeFromEvent(userID, eventID)
+	}
+
+	if _, err := h.eventRepository.GetEventByID(eventID); err != nil {
+		return false, err
+	}
+	return true, h.subscriptionRepository.SubscribeToEvent(userID, eventID)
+}
+
+// toggleEventType switches reminders about all events of the type, returns whether the user is subscribed now
+func (h *eventSubscriptionHandler) toggleEventType(userID int, data string, subscriptions *repositories.EventSubscriptions) (bool, error) {
+	eventType := constants.EventType(data)
+	if !slices.Contains(constants.AllEventTypes, eventType) {
+		return false, fmt.Errorf("%s: invalid event type %q", utils.GetCurrentTypeName(), data)
+	}
+
+	if subscriptions.EventTypes[eventType] {
+		return false, h.subscriptionRepository.UnsubscribeFromType(userID, eventType)
+	}
+	return true, h.subscriptionRepository.SubscribeToType(userID, eventType)
+}
+
+// eventsListButtons builds the buttons of /events: reminder toggles and .ics exports for the listed events,
+// reminder toggles for all event types and the calendar subscription
+func eventsListButtons(events []repositories.Event, subscriptions *repositories.EventSubscriptions, withFeed bool) gotgbot.InlineKeyboardMarkup {
+	eventOptions := make([]buttons.EventSubscriptionOption, 0, len(events))
+	for _, event := range events {
+		eventOptions = append(eventOptions, buttons.EventSubscriptionOption{
+			Key:        strconv.Itoa(event.ID),
+			Title:      event.Name,
+			Subscribed: subscriptions.EventIDs[event.ID],
+		})
+	}
+
+	typeOptions := make([]buttons.EventSubscriptionOption, 0, len(constants.AllEventTypes))
+	for _, eventType := range constants.AllEventTypes {
+		typeOptions = append(typeOptions, buttons.EventSubscriptionOption{
+			Key:        string(eventType),
+			Title:      fmt.Sprintf("все: %s", formatters.GetTypeInRussian(eventType)),
+			Subscribed: subscriptions.EventTypes[eventType],
+		})
+	}
+
+	return buttons.EventsListButtons(eventOptions, typeOptions, withFeed)
+}
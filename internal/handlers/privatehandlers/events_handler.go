@@ -15,24 +15,33 @@ import (
 	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers"
 )
 
+// eventsListLimit is how many upcoming events /events shows
+const eventsListLimit = 10
+
 type eventsHandler struct {
-	config               *config.Config
-	eventRepository      *repositories.EventRepository
-	messageSenderService *services.MessageSenderService
-	permissionsService   *services.PermissionsService
+	config                 *config.Config
+	eventRepository        *repositories.EventRepository
+	subscriptionRepository *repositories.EventSubscriptionRepository
+	userRepository         *repositories.UserRepository
+	messageSenderService   *services.MessageSenderService
+	permissionsService     *services.PermissionsService
 }
 
 func NewEventsHandler(
 	config *config.Config,
 	eventRepository *repositories.EventRepository,
+	subscriptionRepository *repositories.EventSubscriptionRepository,
+	userRepository *repositories.UserRepository,
 	messageSenderService *services.MessageSenderService,
 	permissionsService *services.PermissionsService,
 ) ext.Handler {
 	h := &eventsHandler{
-		config:               config,
-		eventRepository:      eventRepository,
-		messageSenderService: messageSenderService,
-		permissionsService:   permissionsService,
+		config:                 config,
+		eventRepository:        eventRepository,
+		subscriptionRepository: subscriptionRepository,
+		userRepository:         userRepository,
+		messageSenderService:   messageSenderService,
+		permissionsService:     permissionsService,
 	}
 
 	return handlers.NewCommand(constants.EventsCommand, h.handleCommand)
@@ -52,7 +61,7 @@ func (h *eventsHandler) handleCommand(b *gotgbot.Bot, ctx *ext.Context) error {
 	}
 
 	// Get actual events to show
-	events, err := h.eventRepository.GetLastActualEvents(10) // Fetch last 10 actual events
+	events, err := h.eventRepository.GetLastActualEvents(eventsListLimit)
 	if err != nil {
 		h.messageSenderService.Reply(msg, "Ошибка при получении списка мероприятий.", nil)
 		log.Printf("%s: Error during events retrieval: %v", utils.GetCurrentTypeName(), err)
@@ -64,15 +73,43 @@ func (h *eventsHandler) handleCommand(b *gotgbot.Bot, ctx *ext.Context) error {
 		return nil
 	}
 
+	// Speakers are shown by name, the list is still useful without them
+	var speakerIDs []int64
+	for _, event := range events {
+		speakerIDs = append(speakerIDs, event.SpeakerUserIDs...)
+	}
+	speakerNames, err := h.userRepository.GetDisplayNamesByIDs(speakerIDs)
+	if err != nil {
+		log.Printf("%s: Error getting speaker names: %v", utils.GetCurrentTypeName(), err)
+	}
+
 	// Format and display event list
 	formattedEvents := formatters.FormatEventListForEventsView(
 		events,
 		"📋 Список ближайших мероприятий",
+		h.config.ClubTimezone,
+		speakerNames,
 	)
 	formattedEvents += fmt.Sprintf("\nДобавить темы и вопросы /%s. ", constants.TopicAddCommand)
 	formattedEvents += fmt.Sprintf("Просмотреть темы и вопросы /%s. ", constants.TopicsCommand)
 	formattedEvents += "Больше информации о мероприятиях смотри в [клубном календаре](https://itbeard.com/s/evo-calendar)."
-	h.messageSenderService.ReplyMarkdown(msg, formattedEvents, nil)
+	formattedEvents += "\n\n🔔 Нажми на мероприятие или тип мероприятий ниже, чтобы получать напоминания в личку перед началом. ✅ — напоминания включены."
+	formattedEvents += "\n📅 Кнопка .ics добавит мероприятие в твой календарь."
+
+	var opts *gotgbot.SendMessageOpts
+	user, err := h.userRepository.GetOrCreate(ctx.EffectiveUser)
+	if err == nil {
+		var subscriptions *repositories.EventSubscriptions
+		subscriptions, err = h.subscriptionRepository.GetByUserID(user.ID)
+		if err == nil {
+			opts = &gotgbot.SendMessageOpts{ReplyMarkup: eventsListButtons(events, subscriptions, h.config.CalendarFeedListenAddr != "")}
+		}
+	}
+	if err != nil {
+		// The list is still useful without the reminder buttons
+		log.Printf("%s: Error getting event subscriptions: %v", utils.GetCurrentTypeName(), err)
+	}
+	h.messageSenderService.ReplyMarkdown(msg, formattedEvents, opts)
 
 	return nil
 }
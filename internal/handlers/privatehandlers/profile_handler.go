@@ -51,6 +51,7 @@ type profileHandler struct {
 	messageSenderService        *services.MessageSenderService
 	permissionsService          *services.PermissionsService
 	profileService              *services.ProfileService
+	summarizationService        *services.SummarizationService
 	userRepository              *repositories.UserRepository
 	profileRepository           *repositories.ProfileRepository
 	promptingTemplateRepository *repositories.PromptingTemplateRepository
@@ -63,6 +64,7 @@ func NewProfileHandler(
 	messageSenderService *services.MessageSenderService,
 	permissionsService *services.PermissionsService,
 	profileService *services.ProfileService,
+	summarizationService *services.SummarizationService,
 	userRepository *repositories.UserRepository,
 	profileRepository *repositories.ProfileRepository,
 	promptingTemplateRepository *repositories.PromptingTemplateRepository,
@@ -73,6 +75,7 @@ func NewProfileHandler(
 		messageSenderService:        messageSenderService,
 		permissionsService:          permissionsService,
 		profileService:              profileService,
+		summarizationService:        summarizationService,
 		userRepository:              userRepository,
 		profileRepository:           profileRepository,
 		promptingTemplateRepository: promptingTemplateRepository,
@@ -141,6 +144,8 @@ func (h *profileHandler) handleCallback(b *gotgbot.Bot, ctx *ext.Context) error
 		return h.handleEditField(b, ctx, effectiveMsg, "новое имя", profileStateAwaitFirstname)
 	case constants.ProfileEditLastnameCallback:
 		return h.handleEditField(b, ctx, effectiveMsg, "новую фамилию", profileStateAwaitLastname)
+	case constants.ProfileToggleHideInSummariesCallback:
+		return h.handleToggleHideInSummaries(b, ctx, effectiveMsg)
 	case constants.ProfileStartCallback:
 		return h.handleStart(b, ctx)
 	}
@@ -166,6 +171,7 @@ func (h *profileHandler) handleStart(b *gotgbot.Bot, ctx *ext.Context) error {
 	firstNameString := "└ ❌ Имя"
 	lastNameString := "└ ❌ Фамилия"
 	bioString := "└ ❌ Биография"
+	summariesString := ""
 	profileLinkString := ""
 	dbUser, err := h.userRepository.GetOrCreate(user)
 	if err == nil {
@@ -175,6 +181,7 @@ func (h *profileHandler) handleStart(b *gotgbot.Bot, ctx *ext.Context) error {
 		if dbUser.Lastname != "" {
 			lastNameString = "└ ✅ Фамилия" + " <i>(" + dbUser.Lastname + ")</i>"
 		}
+		summariesString = "\n\n" + h.formatHideInSummariesStatus(dbUser.HideInSummaries)
 
 		profile, err := h.profileRepository.GetOrCreate(dbUser.ID)
 		if err == nil {
@@ -202,6 +209,7 @@ func (h *profileHandler) handleStart(b *gotgbot.Bot, ctx *ext.Context) error {
 		lastNameString +
 		"\n" +
 		bioString +
+		summariesString +
 		"\n\n" +
 		profileLinkString
 
@@ -223,13 +231,19 @@ func (h *profileHandler) handleStart(b *gotgbot.Bot, ctx *ext.Context) error {
 func (h *profileHandler) handleEditMyProfile(b *gotgbot.Bot, ctx *ext.Context, msg *gotgbot.Message) error {
 	currentUser := ctx.Update.CallbackQuery.From
 
+	dbUser, err := h.userRepository.GetOrCreate(&currentUser)
+	if err != nil {
+		return fmt.Errorf("%s: failed to get user in handleEditMyProfile: %w", utils.GetCurrentTypeName(), err)
+	}
+
 	h.RemovePreviousMessage(b, &currentUser.Id)
 	editedMsg, err := h.messageSenderService.SendHtmlWithReturnMessage(
 		msg.Chat.Id,
 		fmt.Sprintf("<b>%s</b>", profileMenuEditHeader)+
+			"\n\n"+h.formatHideInSummariesStatus(dbUser.HideInSummaries)+
 			"\n\nВыбери, что бы ты хотел/а изменить:",
 		&gotgbot.SendMessageOpts{
-			ReplyMarkup: buttons.ProfileEditButtons(constants.ProfileStartCallback),
+			ReplyMarkup: buttons.ProfileEditButtons(constants.ProfileStartCallback, dbUser.HideInSummaries),
 		})
 
 	if err != nil {
@@ -240,6 +254,30 @@ func (h *profileHandler) handleEditMyProfile(b *gotgbot.Bot, ctx *ext.Context, m
 	return handlers.NextConversationState(profileStateViewOptions)
 }
 
+// Toggles whether the user may be named in chat summaries and shows the edit menu again
+func (h *profileHandler) handleToggleHideInSummaries(b *gotgbot.Bot, ctx *ext.Context, msg *gotgbot.Message) error {
+	callback := ctx.Update.CallbackQuery
+	currentUser := callback.From
+
+	dbUser, err := h.userRepository.GetOrCreate(&currentUser)
+	if err != nil {
+		return fmt.Errorf("%s: failed to get user in handleToggleHideInSummaries: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	hideInSummaries := !dbUser.HideInSummaries
+	if err := h.summarizationService.SetHideInSummaries(dbUser.ID, hideInSummaries); err != nil {
+		return fmt.Errorf("%s: failed to update summaries visibility in handleToggleHideInSummaries: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	answerText := "Теперь я буду упоминать тебя в сводках чата"
+	if hideInSummaries {
+		answerText = "Больше не буду упоминать тебя в сводках чата"
+	}
+	_, _ = callback.Answer(b, &gotgbot.AnswerCallbackQueryOpts{Text: answerText})
+
+	return h.handleEditMyProfile(b, ctx, msg)
+}
+
 func (h *profileHandler) handleSearchProfile(b *gotgbot.Bot, ctx *ext.Context, msg *gotgbot.Message) error {
 	user := ctx.Update.CallbackQuery.From
 
@@ -687,6 +725,14 @@ func (h *profileHandler) saveUserField(tgUser *gotgbot.User, fieldName string, v
 	return nil
 }
 
+// formatHideInSummariesStatus describes whether the user is named in chat summaries
+func (h *profileHandler) formatHideInSummariesStatus(hideInSummaries bool) string {
+	if hideInSummaries {
+		return "🙈 В сводках чата твоё имя не упоминается."
+	}
+	return "👁 В сводках чата тебя могут упоминать по имени (например, «Иван предложил...»)."
+}
+
 func (h *profileHandler) RemovePreviousMessage(b *gotgbot.Bot, userID *int64) {
 	var chatID, messageID int64
 
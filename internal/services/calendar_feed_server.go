@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"evo-bot-go/internal/utils"
+)
+
+// CalendarFeedServer serves personal calendar feeds at /calendar/<token>.ics
+type CalendarFeedServer struct {
+	server          *http.Server
+	calendarService *EventCalendarService
+}
+
+// NewCalendarFeedServer creates a new calendar feed server listening on the address
+func NewCalendarFeedServer(listenAddr string, calendarService *EventCalendarService) *CalendarFeedServer {
+	s := &CalendarFeedServer{calendarService: calendarService}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /calendar/{file}", s.handleFeed)
+
+	s.server = &http.Server{
+		Addr:              listenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+	return s
+}
+
+// Start starts serving in the background
+func (s *CalendarFeedServer) Start() {
+	go func() {
+		log.Printf("%s: Listening on %s", utils.GetCurrentTypeName(), s.server.Addr)
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("%s: Server stopped with error: %v", utils.GetCurrentTypeName(), err)
+		}
+	}()
+}
+
+// Stop gracefully shuts the server down
+func (s *CalendarFeedServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		log.Printf("%s: Failed to shut down: %v", utils.GetCurrentTypeName(), err)
+	}
+}
+
+func (s *CalendarFeedServer) handleFeed(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutSuffix(r.PathValue("file"), ".ics")
+	if !ok || token == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	calendar, err := s.calendarService.Feed(token)
+	if errors.Is(err, ErrCalendarFeedNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("%s: Failed to build calendar feed: %v", utils.GetCurrentTypeName(), err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	if _, err := w.Write(calendar); err != nil {
+		log.Printf("%s: Failed to write calendar feed: %v", utils.GetCurrentTypeName(), err)
+	}
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// EventArchivePageSize is how many finished events a page of /archive shows
+const EventArchivePageSize = 5
+
+// EventArchiveSearchLimit is how many archived events a content search shows
+const EventArchiveSearchLimit = 5
+
+// ErrEventNotFinished is returned when materials are attached to an event that hasn't finished yet
+var ErrEventNotFinished = errors.New("материалы можно прикрепить только к завершённому мероприятию")
+
+// EventArchiveService keeps materials of finished events: recordings, slides and notes,
+// and lets members browse and search them
+type EventArchiveService struct {
+	messageSenderService *MessageSenderService
+	eventRepo            *repositories.EventRepository
+	materialRepo         *repositories.EventMaterialRepository
+}
+
+// NewEventArchiveService creates a new event archive service
+func NewEventArchiveService(
+	messageSenderService *MessageSenderService,
+	eventRepo *repositories.EventRepository,
+	materialRepo *repositories.EventMaterialRepository,
+) *EventArchiveService {
+	return &EventArchiveService{
+		messageSenderService: messageSenderService,
+		eventRepo:            eventRepo,
+		materialRepo:         materialRepo,
+	}
+}
+
+// GetPage returns a page of finished events of the type, the latest first, with their materials,
+// and whether there are older events
+func (s *EventArchiveService) GetPage(
+	eventType constants.EventType,
+	page int,
+) ([]repositories.Event, map[int][]repositories.EventMaterial, bool, error) {
+	// One more event tells whether the next page exists
+	events, err := s.eventRepo.GetFinishedEvents(eventType, EventArchivePageSize+1, page*EventArchivePageSize)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	hasMore := len(events) > EventArchivePageSize
+	if hasMore {
+		events = events[:EventArchivePageSize]
+	}
+
+	materials, err := s.getMaterials(events)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return events, materials, hasMore, nil
+}
+
+// Search finds finished events with materials matching the query, the most relevant first
+func (s *EventArchiveService) Search(query string) ([]repositories.Event, map[int][]repositories.EventMaterial, error) {
+	events, err := s.eventRepo.SearchFinishedEventsWithMaterials(query, EventArchiveSearchLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	materials, err := s.getMaterials(events)
+	if err != nil {
+		return nil, nil, err
+	}
+	return events, materials, nil
+}
+
+// GetMaterials returns materials of the event in the order they were added
+func (s *EventArchiveService) GetMaterials(eventID int) ([]repositories.EventMaterial, error) {
+	materials, err := s.materialRepo.GetByEventIDs([]int{eventID})
+	if err != nil {
+		return nil, err
+	}
+	return materials[eventID], nil
+}
+
+// AddMaterial attaches a material to the finished event, only the field matching the material type has to be set
+func (s *EventArchiveService) AddMaterial(
+	eventID int,
+	materialType constants.EventMaterialType,
+	title string,
+	url string,
+	fileID string,
+	note string,
+) error {
+	event, err := s.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return err
+	}
+	if event.Status != string(constants.EventStatusFinished) {
+		return ErrEventNotFinished
+	}
+
+	_, err = s.materialRepo.Create(eventID, materialType, title, url, fileID, note)
+	return err
+}
+
+// DeleteMaterial removes the material from the archive
+func (s *EventArchiveService) DeleteMaterial(materialID int) error {
+	return s.materialRepo.Delete(materialID)
+}
+
+// SendDocument sends the document material to the chat with its title as the caption
+func (s *EventArchiveService) SendDocument(chatID int64, materialID int) error {
+	material, err := s.materialRepo.GetByID(materialID)
+	if err != nil {
+		return err
+	}
+	if material.Type != constants.EventMaterialTypeDocument {
+		return fmt.Errorf("%s: material %d is not a document", utils.GetCurrentTypeName(), materialID)
+	}
+
+	return s.messageSenderService.SendDocumentByFileID(chatID, material.FileID, &gotgbot.SendDocumentOpts{
+		Caption:   html.EscapeString(material.Title),
+		ParseMode: "HTML",
+	})
+}
+
+// getMaterials returns materials of the events grouped by event ID
+func (s *EventArchiveService) getMaterials(events []repositories.Event) (map[int][]repositories.EventMaterial, error) {
+	eventIDs := make([]int, 0, len(events))
+	for _, event := range events {
+		eventIDs = append(eventIDs, event.ID)
+	}
+	return s.materialRepo.GetByEventIDs(eventIDs)
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/formatters"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// ErrEventRsvpClosed is returned when users answer for an event that has already finished or was cancelled
+var ErrEventRsvpClosed = errors.New("мероприятие уже прошло или отменено, ответы больше не принимаются")
+
+// EventAttendanceService handles RSVP answers for events, keeps the number of attendees within the capacity
+// and promotes users from the waitlist when a spot frees up
+type EventAttendanceService struct {
+	config        *config.Config
+	messageSender *MessageSenderService
+	eventRepo     *repositories.EventRepository
+	attendeeRepo  *repositories.EventAttendeeRepository
+	userRepo      *repositories.UserRepository
+
+	// attendanceMu serializes answers, so two users never take the last spot at once
+	attendanceMu sync.Mutex
+}
+
+// NewEventAttendanceService creates a new event attendance service
+func NewEventAttendanceService(
+	config *config.Config,
+	messageSender *MessageSenderService,
+	eventRepo *repositories.EventRepository,
+	attendeeRepo *repositories.EventAttendeeRepository,
+	userRepo *repositories.UserRepository,
+) *EventAttendanceService {
+	return &EventAttendanceService{
+		config:        config,
+		messageSender: messageSender,
+		eventRepo:     eventRepo,
+		attendeeRepo:  attendeeRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// Respond saves the RSVP answer of the user and returns the resulting status.
+// A user going to a full event gets to the waitlist, a user leaving the attendees frees the spot for the waitlist.
+// Answers are accepted only while the event is active.
+func (s *EventAttendanceService) Respond(tgUser *gotgbot.User, eventID int, status constants.EventAttendeeStatus) (constants.EventAttendeeStatus, error) {
+	if !slices.Contains(constants.RsvpEventAttendeeStatuses, status) {
+		return "", fmt.Errorf("%s: status %q can't be chosen with RSVP", utils.GetCurrentTypeName(), status)
+	}
+
+	user, err := s.userRepo.GetOrCreate(tgUser)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to get user: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	s.attendanceMu.Lock()
+	defer s.attendanceMu.Unlock()
+
+	event, err := s.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return "", err
+	}
+	if !isEventActive(*event) {
+		return "", ErrEventRsvpClosed
+	}
+
+	current, answered, err := s.attendeeRepo.GetStatus(eventID, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if status == constants.EventAttendeeStatusGoing {
+		if answered && (current == constants.EventAttendeeStatusGoing || current == constants.EventAttendeeStatusWaitlisted) {
+			return current, nil
+		}
+
+		counts, err := s.attendeeRepo.GetCounts(eventID)
+		if err != nil {
+			return "", err
+		}
+		if event.Capacity != nil && counts.Going >= *event.Capacity {
+			status = constants.EventAttendeeStatusWaitlisted
+		}
+	}
+
+	if err := s.attendeeRepo.SetStatus(eventID, user.ID, status); err != nil {
+		return "", err
+	}
+
+	if answered && current == constants.EventAttendeeStatusGoing && status != constants.EventAttendeeStatusGoing {
+		if err := s.promoteWaitlisted(*event); err != nil {
+			log.Printf("%s: Failed to promote waitlisted users of event %d: %v", utils.GetCurrentTypeName(), eventID, err)
+		}
+	}
+
+	return status, nil
+}
+
+// SetCapacity changes the capacity of the event, nil removes the limit.
+// Users from the waitlist take the added spots, nobody loses a spot when the capacity is reduced.
+func (s *EventAttendanceService) SetCapacity(eventID int, capacity *int) error {
+	s.attendanceMu.Lock()
+	defer s.attendanceMu.Unlock()
+
+	if err := s.eventRepo.UpdateEventCapacity(eventID, capacity); err != nil {
+		return err
+	}
+
+	event, err := s.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return err
+	}
+	return s.promoteWaitlisted(*event)
+}
+
+// promoteWaitlisted moves users from the waitlist to the attendees while there are free spots and notifies them.
+// Nobody is promoted for an event that has already ended.
+func (s *EventAttendanceService) promoteWaitlisted(event repositories.Event) error {
+	if !isEventActive(event) {
+		return nil
+	}
+
+	counts, err := s.attendeeRepo.GetCounts(event.ID)
+	if err != nil {
+		return err
+	}
+
+	free := counts.Waitlisted
+	if event.Capacity != nil {
+		free = min(free, *event.Capacity-counts.Going)
+	}
+	if free <= 0 {
+		return nil
+	}
+
+	userIDs, err := s.attendeeRepo.GetFirstWaitlisted(event.ID, free)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		if err := s.attendeeRepo.SetStatus(event.ID, userID, constants.EventAttendeeStatusGoing); err != nil {
+			return err
+		}
+		s.notifyPromoted(event, userID)
+	}
+
+	log.Printf("%s: Promoted %d users from the waitlist of event %d", utils.GetCurrentTypeName(), len(userIDs), event.ID)
+	return nil
+}
+
+// isEventActive reports whether the event hasn't finished and wasn't cancelled
+func isEventActive(event repositories.Event) bool {
+	return slices.Contains(constants.ActiveEventStatuses, constants.EventStatus(event.Status))
+}
+
+func (s *EventAttendanceService) notifyPromoted(event repositories.Event, userID int) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		log.Printf("%s: Failed to get promoted user %d: %v", utils.GetCurrentTypeName(), userID, err)
+		return
+	}
+
+	text := fmt.Sprintf("🎉 Освободилось место на мероприятии <b>%s</b> — ты больше не в листе ожидания, а в списке участников!", html.EscapeString(event.Name))
+	if event.StartedAt != nil {
+		text += fmt.Sprintf("\n\n🗓 %s", formatters.FormatEventStartedAt(*event.StartedAt, s.config.ClubTimezone))
+	}
+	text += "\n\nЕсли планы изменились, нажми «Не иду» под анонсом, чтобы место досталось следующему."
+
+	if err := s.messageSender.SendHtml(user.TgID, text, nil); err != nil {
+		log.Printf("%s: Failed to notify user %d about the promotion for event %d: %v", utils.GetCurrentTypeName(), userID, event.ID, err)
+	}
+}
+
+// RsvpButtons returns the RSVP buttons of the event with the current numbers of answers
+func (s *EventAttendanceService) RsvpButtons(event repositories.Event) (gotgbot.InlineKeyboardMarkup, error) {
+	counts, err := s.attendeeRepo.GetCounts(event.ID)
+	if err != nil {
+		return gotgbot.InlineKeyboardMarkup{}, err
+	}
+
+	rsvpCounts := buttons.EventRsvpCounts{
+		Going:      counts.Going,
+		Waitlisted: counts.Waitlisted,
+		Maybe:      counts.Maybe,
+		NotGoing:   counts.NotGoing,
+	}
+	if event.Capacity != nil {
+		rsvpCounts.Capacity = *event.Capacity
+	}
+	return buttons.EventRsvpButtons(event.ID, rsvpCounts), nil
+}
+
+// SendAttendeesExport sends the RSVP summary and the CSV list of attendees of the event to the chat
+func (s *EventAttendanceService) SendAttendeesExport(chatID int64, eventID int) error {
+	event, err := s.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return err
+	}
+	counts, err := s.attendeeRepo.GetCounts(eventID)
+	if err != nil {
+		return err
+	}
+	attendees, err := s.attendeeRepo.GetAttendees(eventID)
+	if err != nil {
+		return err
+	}
+
+	summary := formatters.FormatHtmlEventAttendanceSummary(*event, counts)
+	if len(attendees) == 0 {
+		return s.messageSender.SendHtml(chatID, summary+"\n\nПока никто не ответил.", nil)
+	}
+
+	data, err := s.attendeesCsv(attendees)
+	if err != nil {
+		return err
+	}
+	return s.messageSender.SendDocumentWithCaption(chatID, fmt.Sprintf("event_%d_attendees.csv", eventID), data, summary)
+}
+
+// attendeesCsv builds the list of attendees in the order of the answers, the waitlist is numbered by position
+func (s *EventAttendanceService) attendeesCsv(attendees []repositories.EventAttendee) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	records := [][]string{{"Статус", "Очередь", "Имя", "Фамилия", "Username", "Telegram ID", "Дата ответа"}}
+	waitlistPosition := 0
+	for _, attendee := range attendees {
+		position := ""
+		if attendee.Status == constants.EventAttendeeStatusWaitlisted {
+			waitlistPosition++
+			position = strconv.Itoa(waitlistPosition)
+		}
+
+		username := ""
+		if attendee.TgUsername != "" {
+			username = "@" + attendee.TgUsername
+		}
+
+		records = append(records, []string{
+			formatters.GetAttendeeStatusInRussian(attendee.Status),
+			position,
+			attendee.Firstname,
+			attendee.Lastname,
+			username,
+			strconv.FormatInt(attendee.TgID, 10),
+			attendee.RespondedAt.In(s.config.ClubTimezone).Format("02.01.2006 15:04"),
+		})
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		return nil, fmt.Errorf("%s: failed to write attendees CSV: %w", utils.GetCurrentTypeName(), err)
+	}
+	return buf.Bytes(), nil
+}
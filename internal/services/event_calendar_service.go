@@ -0,0 +1,240 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/formatters"
+	"evo-bot-go/internal/utils"
+)
+
+// EventCalendarFeedPastWindow keeps recent events in the feed, so they don't vanish from calendars right after the start
+const EventCalendarFeedPastWindow = 30 * 24 * time.Hour
+
+// ErrCalendarFeedNotFound is returned for an unknown feed token or a token of a user who is no longer a club member
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// EventCalendarService exports club events in the iCalendar format, as files and as personal subscribable feeds.
+// Every event has a stable UID and a sequence growing with its updates, so calendar apps pick up changes.
+type EventCalendarService struct {
+	config    *config.Config
+	eventRepo *repositories.EventRepository
+	tokenRepo *repositories.CalendarFeedTokenRepository
+	userRepo  *repositories.UserRepository
+}
+
+// NewEventCalendarService creates a new event calendar service
+func NewEventCalendarService(
+	config *config.Config,
+	eventRepo *repositories.EventRepository,
+	tokenRepo *repositories.CalendarFeedTokenRepository,
+	userRepo *repositories.UserRepository,
+) *EventCalendarService {
+	return &EventCalendarService{
+		config:    config,
+		eventRepo: eventRepo,
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// IsFeedEnabled reports whether the feed server is configured
+func (s *EventCalendarService) IsFeedEnabled() bool {
+	return s.config.CalendarFeedListenAddr != ""
+}
+
+// EventCalendar returns the .ics file with a single event, false if the event has no start date
+func (s *EventCalendarService) EventCalendar(eventID int) ([]byte, bool, error) {
+	event, err := s.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, false, err
+	}
+	if event.StartedAt == nil {
+		return nil, false, nil
+	}
+	calendar, err := s.buildCalendar([]repositories.Event{*event}, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	return calendar, true, nil
+}
+
+// UpcomingCalendar returns the .ics file with all upcoming planned and live events, false if there are none
+func (s *EventCalendarService) UpcomingCalendar() ([]byte, bool, error) {
+	events, err := s.eventRepo.GetEventsStartingSince(time.Now())
+	if err != nil {
+		return nil, false, err
+	}
+
+	var upcoming []repositories.Event
+	for _, event := range events {
+		if event.Status == string(constants.EventStatusPlanned) || event.Status == string(constants.EventStatusLive) {
+			upcoming = append(upcoming, event)
+		}
+	}
+	if len(upcoming) == 0 {
+		return nil, false, nil
+	}
+	calendar, err := s.buildCalendar(upcoming, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	return calendar, true, nil
+}
+
+// Feed returns the personal feed of the token owner: recent and upcoming events and cancellations of deleted ones
+func (s *EventCalendarService) Feed(token string) ([]byte, error) {
+	userID, ok, err := s.tokenRepo.GetUserIDByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get feed owner %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+	if !user.IsClubMember {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	since := time.Now().Add(-EventCalendarFeedPastWindow)
+	events, err := s.eventRepo.GetEventsStartingSince(since)
+	if err != nil {
+		return nil, err
+	}
+	cancellations, err := s.eventRepo.GetCancellationsSince(since)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildCalendar(events, cancellations)
+}
+
+// FeedURL returns the personal feed URL of the user, the token is created on the first request
+func (s *EventCalendarService) FeedURL(userID int) (string, error) {
+	token, ok, err := s.tokenRepo.GetByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return s.ResetFeedURL(userID)
+	}
+	return s.feedURL(token), nil
+}
+
+// ResetFeedURL replaces the feed token of the user, e.g. when the link leaked, and returns the new URL
+func (s *EventCalendarService) ResetFeedURL(userID int) (string, error) {
+	tokenBytes := make([]byte, 24)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("%s: failed to generate feed token: %w", utils.GetCurrentTypeName(), err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	if err := s.tokenRepo.Set(userID, token); err != nil {
+		return "", err
+	}
+	return s.feedURL(token), nil
+}
+
+func (s *EventCalendarService) feedURL(token string) string {
+	return fmt.Sprintf("%s/calendar/%s.ics", s.config.CalendarFeedBaseURL, token)
+}
+
+// buildCalendar builds the iCalendar document, events without a start date are skipped.
+// Cancelled and deleted events are published as cancelled.
+func (s *EventCalendarService) buildCalendar(events []repositories.Event, cancellations []repositories.EventCancellation) ([]byte, error) {
+	now := utils.FormatICalTime(time.Now())
+
+	var speakerIDs []int64
+	for _, event := range events {
+		speakerIDs = append(speakerIDs, event.SpeakerUserIDs...)
+	}
+	speakerNames, err := s.userRepo.GetDisplayNamesByIDs(speakerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var c utils.ICalBuilder
+	c.WriteProperty("BEGIN", "VCALENDAR")
+	c.WriteProperty("VERSION", "2.0")
+	c.WriteProperty("PRODID", "-//Evocoders//evo-bot-go//RU")
+	c.WriteProperty("CALSCALE", "GREGORIAN")
+	c.WriteProperty("METHOD", "PUBLISH")
+	c.WriteProperty("X-WR-CALNAME", utils.EscapeICalText("Эволюция Кода"))
+	c.WriteProperty("X-WR-TIMEZONE", s.config.ClubTimezone.String())
+
+	for _, event := range events {
+		if event.StartedAt == nil {
+			continue
+		}
+
+		eventType := constants.EventType(event.Type)
+
+		description := ""
+		if event.Description != "" {
+			description = event.Description + "\n\n"
+		}
+		if speakers := formatters.FormatEventSpeakers(event.SpeakerUserIDs, speakerNames); speakers != "" {
+			description += fmt.Sprintf("Спикеры: %s\n\n", speakers)
+		}
+		description += fmt.Sprintf(
+			"Мероприятие клуба «Эволюция Кода» (%s).\nТемы и вопросы к нему можно добавить в боте через /%s.",
+			formatters.GetTypeInRussian(eventType), constants.TopicAddCommand,
+		)
+
+		status := "CONFIRMED"
+		if event.Status == string(constants.EventStatusCancelled) {
+			status = "CANCELLED"
+		}
+
+		c.WriteProperty("BEGIN", "VEVENT")
+		c.WriteProperty("UID", eventCalendarUID(event.ID))
+		c.WriteProperty("DTSTAMP", now)
+		c.WriteProperty("DTSTART", utils.FormatICalTime(*event.StartedAt))
+		c.WriteProperty("DTEND", utils.FormatICalTime(event.StartedAt.Add(EventDuration(event))))
+		c.WriteProperty("SEQUENCE", eventCalendarSequence(event.CreatedAt, event.UpdatedAt))
+		c.WriteProperty("LAST-MODIFIED", utils.FormatICalTime(event.UpdatedAt))
+		c.WriteProperty("SUMMARY", utils.EscapeICalText(fmt.Sprintf("%s %s", formatters.GetTypeEmoji(eventType), event.Name)))
+		c.WriteProperty("CATEGORIES", utils.EscapeICalText(formatters.GetTypeInRussian(eventType)))
+		c.WriteProperty("DESCRIPTION", utils.EscapeICalText(description))
+		if event.Location != "" {
+			c.WriteProperty("LOCATION", utils.EscapeICalText(event.Location))
+		}
+		c.WriteProperty("STATUS", status)
+		c.WriteProperty("END", "VEVENT")
+	}
+
+	for _, cancellation := range cancellations {
+		c.WriteProperty("BEGIN", "VEVENT")
+		c.WriteProperty("UID", eventCalendarUID(cancellation.EventID))
+		c.WriteProperty("DTSTAMP", now)
+		c.WriteProperty("DTSTART", utils.FormatICalTime(cancellation.StartedAt))
+		c.WriteProperty("DTEND", utils.FormatICalTime(cancellation.StartedAt.Add(EventDefaultDuration)))
+		c.WriteProperty("SEQUENCE", eventCalendarSequence(cancellation.CreatedAt, cancellation.CancelledAt))
+		c.WriteProperty("LAST-MODIFIED", utils.FormatICalTime(cancellation.CancelledAt))
+		c.WriteProperty("SUMMARY", utils.EscapeICalText(fmt.Sprintf("Отменено: %s", cancellation.Name)))
+		c.WriteProperty("STATUS", "CANCELLED")
+		c.WriteProperty("END", "VEVENT")
+	}
+
+	c.WriteProperty("END", "VCALENDAR")
+	return []byte(c.String()), nil
+}
+
+// eventCalendarUID is stable for the whole life of the event, so an update replaces it in calendars
+func eventCalendarUID(eventID int) string {
+	return fmt.Sprintf("event-%d@evo-bot-go", eventID)
+}
+
+// eventCalendarSequence grows with every update of the event, calendar apps apply only a newer sequence
+func eventCalendarSequence(createdAt time.Time, modifiedAt time.Time) string {
+	return fmt.Sprintf("%d", max(int64(modifiedAt.Sub(createdAt).Seconds()), 0))
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/utils"
+)
+
+// EventDefaultDuration is used for events without a duration
+const EventDefaultDuration = 2 * time.Hour
+
+// EventLifecycleService moves events through the statuses by time: planned events become live at the start
+// and finished at the end. Cancelled events are never touched.
+type EventLifecycleService struct {
+	eventRepo *repositories.EventRepository
+}
+
+// NewEventLifecycleService creates a new event lifecycle service
+func NewEventLifecycleService(eventRepo *repositories.EventRepository) *EventLifecycleService {
+	return &EventLifecycleService{eventRepo: eventRepo}
+}
+
+// UpdateStatuses applies the due status transitions, ended events are finished first,
+// so an event missed entirely (e.g. while the bot was down) goes straight to finished
+func (s *EventLifecycleService) UpdateStatuses(ctx context.Context) error {
+	now := time.Now()
+
+	finished, err := s.eventRepo.FinishEndedEvents(now, EventDefaultDuration)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	started, err := s.eventRepo.StartDueEvents(now)
+	if err != nil {
+		return err
+	}
+
+	if finished > 0 || started > 0 {
+		log.Printf("%s: %d events went live, %d events finished", utils.GetCurrentTypeName(), started, finished)
+	}
+	return nil
+}
+
+// EventDuration returns the duration of the event, the default one if it isn't set
+func EventDuration(event repositories.Event) time.Duration {
+	if event.DurationMinutes == nil {
+		return EventDefaultDuration
+	}
+	return time.Duration(*event.DurationMinutes) * time.Minute
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/formatters"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// EventReminderCheckInterval is how often due reminders are checked, a reminder is sent at most that late
+const EventReminderCheckInterval = 5 * time.Minute
+
+// EventReminderService reminds about upcoming events in the announcement topic and in DMs of subscribers
+type EventReminderService struct {
+	config            *config.Config
+	messageSender     *MessageSenderService
+	attendanceService *EventAttendanceService
+	eventRepo         *repositories.EventRepository
+	subscriptionRepo  *repositories.EventSubscriptionRepository
+	reminderRepo      *repositories.EventReminderRepository
+}
+
+// NewEventReminderService creates a new event reminder service
+func NewEventReminderService(
+	config *config.Config,
+	messageSender *MessageSenderService,
+	attendanceService *EventAttendanceService,
+	eventRepo *repositories.EventRepository,
+	subscriptionRepo *repositories.EventSubscriptionRepository,
+	reminderRepo *repositories.EventReminderRepository,
+) *EventReminderService {
+	return &EventReminderService{
+		config:            config,
+		messageSender:     messageSender,
+		attendanceService: attendanceService,
+		eventRepo:         eventRepo,
+		subscriptionRepo:  subscriptionRepo,
+		reminderRepo:      reminderRepo,
+	}
+}
+
+// SendDueReminders sends reminders about events whose start is within one of the configured offsets.
+// Every reminder is claimed in the database before sending, so it's never sent twice.
+func (s *EventReminderService) SendDueReminders(ctx context.Context) error {
+	if len(s.config.EventReminderOffsets) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	events, err := s.eventRepo.GetActualEventsStartingBetween(now, now.Add(s.config.EventReminderOffsets[0]))
+	if err != nil {
+		return fmt.Errorf("%s: error getting upcoming events: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	sent := 0
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		offset, ok, err := s.claimDueReminder(event, now)
+		if err != nil {
+			log.Printf("%s: Failed to claim reminder of event %d: %v", utils.GetCurrentTypeName(), event.ID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		// A reminder sent right at its offset shows the offset itself instead of a few minutes less
+		timeUntil := event.StartedAt.Sub(now)
+		if offset-timeUntil < EventReminderCheckInterval {
+			timeUntil = offset
+		}
+		s.sendReminder(event, formatters.FormatHtmlEventReminder(event, timeUntil, s.config.ClubTimezone))
+		sent++
+	}
+
+	log.Printf("%s: Sent reminders about %d of %d upcoming events", utils.GetCurrentTypeName(), sent, len(events))
+	return nil
+}
+
+// claimDueReminder claims all reminders of the event that are due and returns the smallest of their offsets.
+// Only the smallest one is sent, e.g. for an event created an hour before the start the day-before reminder is skipped.
+func (s *EventReminderService) claimDueReminder(event repositories.Event, now time.Time) (time.Duration, bool, error) {
+	timeUntil := event.StartedAt.Sub(now)
+
+	var due []time.Duration
+	for _, offset := range s.config.EventReminderOffsets {
+		if offset >= timeUntil {
+			due = append(due, offset)
+		}
+	}
+	if len(due) == 0 {
+		return 0, false, nil
+	}
+
+	smallest := due[len(due)-1]
+	claimed := false
+	for _, offset := range due {
+		ok, err := s.reminderRepo.Claim(event.ID, offset, *event.StartedAt)
+		if err != nil {
+			return 0, false, err
+		}
+		if offset == smallest {
+			claimed = ok
+		}
+	}
+	return smallest, claimed, nil
+}
+
+// sendReminder posts the reminder to the announcement topic and sends it to subscribers of the event and its type,
+// both with the RSVP buttons
+func (s *EventReminderService) sendReminder(event repositories.Event, text string) {
+	var replyMarkup gotgbot.ReplyMarkup
+	if rsvpMarkup, err := s.attendanceService.RsvpButtons(event); err != nil {
+		log.Printf("%s: Failed to get RSVP buttons of event %d: %v", utils.GetCurrentTypeName(), event.ID, err)
+	} else {
+		replyMarkup = rsvpMarkup
+	}
+
+	if s.config.AnnouncementTopicID != 0 {
+		opts := &gotgbot.SendMessageOpts{
+			MessageThreadId: int64(s.config.AnnouncementTopicID),
+			ReplyMarkup:     replyMarkup,
+		}
+		if err := s.messageSender.SendHtml(utils.ChatIdToFullChatId(s.config.SuperGroupChatID), text, opts); err != nil {
+			log.Printf("%s: Failed to post reminder of event %d: %v", utils.GetCurrentTypeName(), event.ID, err)
+		}
+	}
+
+	subscribers, err := s.subscriptionRepo.GetSubscribers(event.ID, constants.EventType(event.Type))
+	if err != nil {
+		log.Printf("%s: Failed to get subscribers of event %d: %v", utils.GetCurrentTypeName(), event.ID, err)
+		return
+	}
+
+	delivered := 0
+	for _, subscriber := range subscribers {
+		if err := s.messageSender.SendHtml(subscriber.TgID, text, &gotgbot.SendMessageOpts{ReplyMarkup: replyMarkup}); err != nil {
+			log.Printf("%s: Failed to send reminder of event %d to user %d: %v", utils.GetCurrentTypeName(), event.ID, subscriber.ID, err)
+			continue
+		}
+		delivered++
+	}
+
+	log.Printf("%s: Reminder of event %d delivered to %d of %d subscribers", utils.GetCurrentTypeName(), event.ID, delivered, len(subscribers))
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/utils"
+)
+
+// EventSeriesGenerationHorizon is how far ahead occurrences of recurring events are generated
+const EventSeriesGenerationHorizon = 14 * 24 * time.Hour
+
+// EventSeriesService manages weekly recurring events. Occurrences are regular events generated ahead of time,
+// so topics, reminders, RSVP and the calendar work for every occurrence separately.
+type EventSeriesService struct {
+	config            *config.Config
+	attendanceService *EventAttendanceService
+	eventRepo         *repositories.EventRepository
+	seriesRepo        *repositories.EventSeriesRepository
+
+	// seriesMu serializes generation and edits of all future occurrences, so an occurrence is never generated from a stale series
+	seriesMu sync.Mutex
+}
+
+// NewEventSeriesService creates a new event series service
+func NewEventSeriesService(
+	config *config.Config,
+	attendanceService *EventAttendanceService,
+	eventRepo *repositories.EventRepository,
+	seriesRepo *repositories.EventSeriesRepository,
+) *EventSeriesService {
+	return &EventSeriesService{
+		config:            config,
+		attendanceService: attendanceService,
+		eventRepo:         eventRepo,
+		seriesRepo:        seriesRepo,
+	}
+}
+
+// CreateSeries makes the event the first occurrence of a weekly series and generates the upcoming occurrences.
+// Exactly one of untilDate (a date in the club timezone) and occurrencesCount must be set.
+func (s *EventSeriesService) CreateSeries(eventID int, untilDate *time.Time, occurrencesCount *int) (*repositories.EventSeries, error) {
+	if (untilDate == nil) == (occurrencesCount == nil) {
+		return nil, fmt.Errorf("%s: either the end date or the number of occurrences must be set", utils.GetCurrentTypeName())
+	}
+
+	s.seriesMu.Lock()
+	defer s.seriesMu.Unlock()
+
+	event, err := s.eventRepo.GetEventByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.StartedAt == nil {
+		return nil, fmt.Errorf("%s: event %d has no start date", utils.GetCurrentTypeName(), eventID)
+	}
+	if event.SeriesID != nil {
+		return nil, fmt.Errorf("%s: event %d already belongs to series %d", utils.GetCurrentTypeName(), eventID, *event.SeriesID)
+	}
+
+	seriesID, err := s.seriesRepo.Create(
+		event.Name, constants.EventType(event.Type), *event.StartedAt, untilDate, occurrencesCount, event.Capacity, event.EventDetails,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.eventRepo.SetEventSeries(eventID, seriesID, 0); err != nil {
+		return nil, err
+	}
+	if err := s.seriesRepo.SetGeneratedCount(seriesID, 1); err != nil {
+		return nil, err
+	}
+
+	series, err := s.seriesRepo.GetByID(seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.generate(series, time.Now()); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// GenerateOccurrences creates occurrences of all series starting within the generation horizon
+func (s *EventSeriesService) GenerateOccurrences(ctx context.Context) error {
+	s.seriesMu.Lock()
+	defer s.seriesMu.Unlock()
+
+	seriesList, err := s.seriesRepo.GetUnfinished()
+	if err != nil {
+		return fmt.Errorf("%s: error getting event series: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	now := time.Now()
+	for i := range seriesList {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.generate(&seriesList[i], now); err != nil {
+			log.Printf("%s: Failed to generate occurrences of series %d: %v", utils.GetCurrentTypeName(), seriesList[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// generate creates the occurrences of the series up to the horizon, the caller must hold seriesMu.
+// The generated count only grows, so an occurrence deleted by an admin isn't created again,
+// and occurrences whose time has already passed are skipped.
+func (s *EventSeriesService) generate(series *repositories.EventSeries, now time.Time) error {
+	horizon := now.Add(EventSeriesGenerationHorizon)
+
+	index := series.GeneratedCount
+	created := 0
+	var err error
+	for ; s.withinRule(series, index); index++ {
+		startedAt := s.occurrenceStart(series, index)
+		if startedAt.After(horizon) {
+			break
+		}
+		if startedAt.Before(now) {
+			continue
+		}
+
+		var ok bool
+		_, ok, err = s.eventRepo.CreateSeriesOccurrence(
+			series.Name, constants.EventType(series.Type), startedAt, series.Capacity, series.EventDetails, series.ID, index,
+		)
+		if err != nil {
+			break
+		}
+		if ok {
+			created++
+		}
+	}
+
+	if index != series.GeneratedCount {
+		if saveErr := s.seriesRepo.SetGeneratedCount(series.ID, index); saveErr != nil {
+			return saveErr
+		}
+		series.GeneratedCount = index
+	}
+	if err != nil {
+		return err
+	}
+
+	if created > 0 {
+		log.Printf("%s: Generated %d occurrences of series %d", utils.GetCurrentTypeName(), created, series.ID)
+	}
+	return nil
+}
+
+// occurrenceStart returns the start of the occurrence with the index, the same weekday and local time as the first one
+func (s *EventSeriesService) occurrenceStart(series *repositories.EventSeries, index int) time.Time {
+	return series.StartsAt.In(s.config.ClubTimezone).AddDate(0, 0, 7*index)
+}
+
+// withinRule reports whether the occurrence with the index is within the end date or the number of occurrences
+func (s *EventSeriesService) withinRule(series *repositories.EventSeries, index int) bool {
+	if series.OccurrencesCount != nil {
+		return index < *series.OccurrencesCount
+	}
+	if series.UntilDate != nil {
+		date := s.occurrenceStart(series, index).Format("2006-01-02")
+		return date <= series.UntilDate.Format("2006-01-02")
+	}
+	return false
+}
+
+// RenameFuture renames the occurrence, all following occurrences and the ones to be generated
+func (s *EventSeriesService) RenameFuture(event repositories.Event, name string) error {
+	return s.updateFuture(event, func(series *repositories.EventSeries, occurrence repositories.Event, _ int) error {
+		series.Name = name
+		return s.eventRepo.UpdateEventName(occurrence.ID, name)
+	})
+}
+
+// ChangeTypeFuture changes the type of the occurrence, all following occurrences and the ones to be generated
+func (s *EventSeriesService) ChangeTypeFuture(event repositories.Event, eventType constants.EventType) error {
+	return s.updateFuture(event, func(series *repositories.EventSeries, occurrence repositories.Event, _ int) error {
+		series.Type = string(eventType)
+		return s.eventRepo.UpdateEventType(occurrence.ID, eventType)
+	})
+}
+
+// RescheduleFuture moves the occurrence to the new start, following occurrences keep the weekly step from it
+func (s *EventSeriesService) RescheduleFuture(event repositories.Event, startedAt time.Time) error {
+	anchor := startedAt.In(s.config.ClubTimezone)
+	return s.updateFuture(event, func(series *repositories.EventSeries, occurrence repositories.Event, fromIndex int) error {
+		series.StartsAt = anchor.AddDate(0, 0, -7*fromIndex)
+		return s.eventRepo.UpdateEventStartedAt(occurrence.ID, s.occurrenceStart(series, *occurrence.SeriesIndex))
+	})
+}
+
+// SetCapacityFuture changes the capacity of the occurrence, all following occurrences and the ones to be generated
+func (s *EventSeriesService) SetCapacityFuture(event repositories.Event, capacity *int) error {
+	return s.updateFuture(event, func(series *repositories.EventSeries, occurrence repositories.Event, _ int) error {
+		series.Capacity = capacity
+		return s.attendanceService.SetCapacity(occurrence.ID, capacity)
+	})
+}
+
+// UpdateDetailsFuture changes the details of the occurrence, all following occurrences and the ones to be generated.
+// Only the fields changed by update are replaced, so other details of each occurrence are kept.
+func (s *EventSeriesService) UpdateDetailsFuture(event repositories.Event, update func(details *repositories.EventDetails)) error {
+	return s.updateFuture(event, func(series *repositories.EventSeries, occurrence repositories.Event, _ int) error {
+		update(&series.EventDetails)
+		update(&occurrence.EventDetails)
+		return s.eventRepo.UpdateEventDetails(occurrence.ID, occurrence.EventDetails)
+	})
+}
+
+// updateFuture applies the change to the series and to its actual occurrences starting from the event
+func (s *EventSeriesService) updateFuture(
+	event repositories.Event,
+	apply func(series *repositories.EventSeries, occurrence repositories.Event, fromIndex int) error,
+) error {
+	if event.SeriesID == nil || event.SeriesIndex == nil {
+		return fmt.Errorf("%s: event %d is not an occurrence of a series", utils.GetCurrentTypeName(), event.ID)
+	}
+
+	s.seriesMu.Lock()
+	defer s.seriesMu.Unlock()
+
+	series, err := s.seriesRepo.GetByID(*event.SeriesID)
+	if err != nil {
+		return err
+	}
+	occurrences, err := s.eventRepo.GetSeriesOccurrencesFrom(series.ID, *event.SeriesIndex)
+	if err != nil {
+		return err
+	}
+
+	// The edited occurrence is included even if it's no longer actual
+	if len(occurrences) == 0 || occurrences[0].ID != event.ID {
+		occurrences = append([]repositories.Event{event}, occurrences...)
+	}
+
+	for _, occurrence := range occurrences {
+		if err := apply(series, occurrence, *event.SeriesIndex); err != nil {
+			return err
+		}
+	}
+	return s.seriesRepo.Update(*series)
+}
@@ -2,6 +2,7 @@ package grouphandlersservices
 
 import (
 	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/services"
 	"evo-bot-go/internal/utils"
 	"fmt"
 	"log"
@@ -11,11 +12,17 @@ import (
 )
 
 type JoinLeftService struct {
-	userRepo *repositories.UserRepository
+	userRepo            *repositories.UserRepository
+	participantRepo     *repositories.RandomCoffeeParticipantRepository
+	randomCoffeeService *services.RandomCoffeeService
 }
 
-func NewJoinLeftService(userRepo *repositories.UserRepository) *JoinLeftService {
-	return &JoinLeftService{userRepo: userRepo}
+func NewJoinLeftService(
+	userRepo *repositories.UserRepository,
+	participantRepo *repositories.RandomCoffeeParticipantRepository,
+	randomCoffeeService *services.RandomCoffeeService,
+) *JoinLeftService {
+	return &JoinLeftService{userRepo: userRepo, participantRepo: participantRepo, randomCoffeeService: randomCoffeeService}
 }
 
 func (h *JoinLeftService) HandleJoinLeftMember(b *gotgbot.Bot, ctx *ext.Context) error {
@@ -52,6 +59,20 @@ func (h *JoinLeftService) HandleJoinLeftMember(b *gotgbot.Bot, ctx *ext.Context)
 				return fmt.Errorf("%s: failed to set club member status to false for user %d: %w", utils.GetCurrentTypeName(), dbUser.ID, err)
 			}
 		}
+
+		// A vote for random coffee is retracted if pairs were not published yet
+		retracted, err := h.participantRepo.RemoveFromOpenPoll(int64(dbUser.ID))
+		if err != nil {
+			return fmt.Errorf("%s: failed to retract random coffee vote of user %d: %w", utils.GetCurrentTypeName(), dbUser.ID, err)
+		}
+		if retracted {
+			log.Printf("%s: Retracted random coffee vote of user %s (%d) who left", utils.GetCurrentTypeName(), user.Username, user.Id)
+		}
+
+		// Pairs waiting for approval must not be published with the user
+		if err := h.randomCoffeeService.ExcludeFromLatestDraft(dbUser.ID); err != nil {
+			return fmt.Errorf("%s: failed to exclude user %d from random coffee draft: %w", utils.GetCurrentTypeName(), dbUser.ID, err)
+		}
 	}
 
 	return nil
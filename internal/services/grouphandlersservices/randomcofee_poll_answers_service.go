@@ -2,9 +2,11 @@ package grouphandlersservices
 
 import (
 	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
 	"evo-bot-go/internal/database/repositories"
 	"evo-bot-go/internal/services"
 	"evo-bot-go/internal/utils"
+	"fmt"
 	"log"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
@@ -16,6 +18,7 @@ type RandomCoffeePollAnswersService struct {
 	pollRepo             *repositories.RandomCoffeePollRepository
 	participantRepo      *repositories.RandomCoffeeParticipantRepository
 	userRepo             *repositories.UserRepository
+	preferenceRepo       *repositories.RandomCoffeePreferenceRepository
 }
 
 func NewRandomCoffeePollAnswersService(
@@ -24,6 +27,7 @@ func NewRandomCoffeePollAnswersService(
 	pollRepo *repositories.RandomCoffeePollRepository,
 	participantRepo *repositories.RandomCoffeeParticipantRepository,
 	userRepo *repositories.UserRepository,
+	preferenceRepo *repositories.RandomCoffeePreferenceRepository,
 ) *RandomCoffeePollAnswersService {
 	return &RandomCoffeePollAnswersService{
 		messageSenderService: messageSenderService,
@@ -31,6 +35,7 @@ func NewRandomCoffeePollAnswersService(
 		pollRepo:             pollRepo,
 		participantRepo:      participantRepo,
 		userRepo:             userRepo,
+		preferenceRepo:       preferenceRepo,
 	}
 }
 
@@ -71,10 +76,42 @@ func (s *RandomCoffeePollAnswersService) ProcessAnswer(pollAnswer *gotgbot.PollA
 		} else {
 			log.Printf("%s: Participant (PollID: %d, UserID: %d, Participating: %t) upserted.", utils.GetCurrentTypeName(), retrievedPoll.ID, internalUser.ID, isParticipating)
 		}
+
+		if isParticipating {
+			s.suggestPreferences(internalUser)
+		}
 	}
 	return nil
 }
 
+// suggestPreferences sends a follow-up DM about matching preferences to participants who have never seen them
+func (s *RandomCoffeePollAnswersService) suggestPreferences(internalUser *repositories.User) {
+	if s.preferenceRepo == nil {
+		return
+	}
+
+	// Default preferences are created on the first vote, so the suggestion is sent only once
+	_, created, err := s.preferenceRepo.GetOrCreate(internalUser.ID)
+	if err != nil {
+		log.Printf("%s: Error getting random coffee preferences of user %d: %v", utils.GetCurrentTypeName(), internalUser.ID, err)
+		return
+	}
+	if !created {
+		return
+	}
+
+	err = s.messageSenderService.SendHtml(
+		internalUser.TgID,
+		fmt.Sprintf("☕️ Спасибо за участие в Random Coffee!\n\n"+
+			"Хочешь, чтобы пара подбиралась с учётом формата встречи, города, языка и интересов? "+
+			"Укажи свои пожелания через команду /%s — это займёт минуту.", constants.CoffeeCommand),
+		nil,
+	)
+	if err != nil {
+		log.Printf("%s: Failed to send preferences suggestion to user %d: %v", utils.GetCurrentTypeName(), internalUser.ID, err)
+	}
+}
+
 func (s *RandomCoffeePollAnswersService) IsAnswerShouldBeProcessed(pollAnswer *gotgbot.PollAnswer, internalUser *repositories.User) bool {
 	// Do nothing if pollAnswer is nil
 	if pollAnswer == nil {
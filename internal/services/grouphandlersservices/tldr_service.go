@@ -0,0 +1,204 @@
+package grouphandlersservices
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"evo-bot-go/internal/clients"
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/prompts"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/services"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+const (
+	// tldrMaxChainMessages limits how many messages of the reply chain go into the prompt
+	tldrMaxChainMessages = 300
+	// tldrMinChainMessages is the minimum chain length worth summarizing
+	tldrMinChainMessages = 2
+	// tldrCommandDeleteDelay is how long the /tldr command message stays in the chat
+	tldrCommandDeleteDelay = 10 * time.Second
+	// tldrRequestTimeout limits the time spent waiting for the LLM
+	tldrRequestTimeout = 5 * time.Minute
+)
+
+// TldrService summarizes reply chains in monitored topics on the /tldr command
+type TldrService struct {
+	config                      *config.Config
+	bot                         *gotgbot.Bot
+	openaiClient                *clients.OpenAiClient
+	messageSenderService        *services.MessageSenderService
+	groupMessageRepository      *repositories.GroupMessageRepository
+	promptingTemplateRepository *repositories.PromptingTemplateRepository
+	userRepository              *repositories.UserRepository
+}
+
+// NewTldrService creates a new tldr service
+func NewTldrService(
+	config *config.Config,
+	bot *gotgbot.Bot,
+	openaiClient *clients.OpenAiClient,
+	messageSenderService *services.MessageSenderService,
+	groupMessageRepository *repositories.GroupMessageRepository,
+	promptingTemplateRepository *repositories.PromptingTemplateRepository,
+	userRepository *repositories.UserRepository,
+) *TldrService {
+	return &TldrService{
+		config:                      config,
+		bot:                         bot,
+		openaiClient:                openaiClient,
+		messageSenderService:        messageSenderService,
+		groupMessageRepository:      groupMessageRepository,
+		promptingTemplateRepository: promptingTemplateRepository,
+		userRepository:              userRepository,
+	}
+}
+
+func (s *TldrService) IsTldrRequested(msg *gotgbot.Message) bool {
+	// Only new messages, edits of the command are ignored
+	if msg.EditDate != 0 {
+		return false
+	}
+
+	// Must be "/tldr" or "/tldr@botname" command
+	command := strings.ToLower(strings.TrimSpace(msg.Text))
+	if command != constants.ServiceTldr_Command &&
+		!strings.HasPrefix(command, constants.ServiceTldr_Command+"@") {
+		return false
+	}
+
+	// Must be in monitored topic
+	return slices.Contains(s.config.MonitoredTopicsIDs, int(s.extractGroupTopicID(msg)))
+}
+
+func (s *TldrService) ReplyWithTldr(msg *gotgbot.Message) error {
+	defer s.deleteCommandMessage(msg)
+
+	groupTopicID := s.extractGroupTopicID(msg)
+
+	// By default all messages is reply to Topic itself, so check it
+	repliedMessage := msg.ReplyToMessage
+	if repliedMessage == nil || (msg.IsTopicMessage && repliedMessage.MessageId == msg.MessageThreadId) {
+		s.replyAndDelete(msg, fmt.Sprintf("Ответь командой %s на любое сообщение из обсуждения, чтобы получить его краткое содержание.",
+			constants.ServiceTldr_Command))
+		return nil
+	}
+
+	chain, err := s.groupMessageRepository.GetReplyChain(repliedMessage.MessageId, groupTopicID, tldrMaxChainMessages)
+	if err != nil {
+		s.replyAndDelete(msg, "Не удалось получить ветку обсуждения.")
+		return fmt.Errorf("%s: failed to get reply chain: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	if len(chain) < tldrMinChainMessages {
+		s.replyAndDelete(msg, "В этой ветке слишком мало сообщений для краткого содержания.")
+		return nil
+	}
+
+	s.messageSenderService.SendTypingAction(msg.Chat.Id)
+
+	templateText, err := s.promptingTemplateRepository.Get(prompts.TldrPromptKey, prompts.TldrPromptDefaultValue)
+	if err != nil {
+		s.replyAndDelete(msg, "Не удалось подготовить краткое содержание.")
+		return fmt.Errorf("%s: failed to get prompt template: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	prompt := fmt.Sprintf(templateText, s.buildMessageLinkPrefix(groupTopicID), s.buildContext(chain))
+
+	ctx, cancel := context.WithTimeout(context.Background(), tldrRequestTimeout)
+	defer cancel()
+
+	summary, err := s.openaiClient.GetCompletion(ctx, prompt)
+	if err != nil {
+		s.replyAndDelete(msg, "Не удалось подготовить краткое содержание.")
+		return fmt.Errorf("%s: failed to generate tldr: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	text := fmt.Sprintf("📝 <b>TL;DR ветки</b> (%d сообщ.)\n\n%s", len(chain), summary)
+	if err := s.messageSenderService.ReplyHtml(repliedMessage, text, nil); err != nil {
+		return fmt.Errorf("%s: failed to send tldr: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	log.Printf("%s: TL;DR for message %d sent, chain length %d",
+		utils.GetCurrentTypeName(), repliedMessage.MessageId, len(chain))
+	return nil
+}
+
+// buildContext formats the chain messages for the prompt, authors are named only if they allow it
+func (s *TldrService) buildContext(chain []*repositories.GroupMessage) string {
+	tgIDs := make([]int64, 0, len(chain))
+	for _, msg := range chain {
+		tgIDs = append(tgIDs, msg.UserTgID)
+	}
+	authorNames, err := s.userRepository.GetSummaryDisplayNames(tgIDs)
+	if err != nil {
+		log.Printf("%s: Error getting author names, authors will stay anonymous: %v", utils.GetCurrentTypeName(), err)
+		authorNames = map[int64]string{}
+	}
+
+	var builder strings.Builder
+	for _, msg := range chain {
+		replyTo := ""
+		if msg.ReplyToMessageID != nil {
+			replyTo = fmt.Sprintf("ReplyID: %d\n", *msg.ReplyToMessageID)
+		}
+		author := ""
+		if name, ok := authorNames[msg.UserTgID]; ok {
+			author = fmt.Sprintf("Author: %s\n", name)
+		}
+		builder.WriteString(fmt.Sprintf("\n---\nMessageID: %d\n%sUserID: user_%d\n%sTimestamp: %s\nText: %s",
+			msg.MessageID,
+			replyTo,
+			msg.UserTgID,
+			author,
+			msg.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+			msg.MessageText))
+	}
+	return builder.String()
+}
+
+// buildMessageLinkPrefix returns the link to the topic, message ID should be appended
+func (s *TldrService) buildMessageLinkPrefix(groupTopicID int64) string {
+	if groupTopicID == 0 {
+		groupTopicID = 1 // Hack for non main topic (id = 0)
+	}
+	return fmt.Sprintf("https://t.me/c/%d/%d/", s.config.SuperGroupChatID, groupTopicID)
+}
+
+// replyAndDelete replies to the command and deletes the reply after a delay
+func (s *TldrService) replyAndDelete(msg *gotgbot.Message, text string) {
+	sentMsg, err := s.messageSenderService.ReplyWithReturnMessage(msg, text, nil)
+	if err != nil {
+		log.Printf("%s: Failed to reply to tldr command: %v", utils.GetCurrentTypeName(), err)
+		return
+	}
+	s.deleteCommandMessage(sentMsg)
+}
+
+// deleteCommandMessage deletes the message after a delay in a goroutine to avoid blocking
+func (s *TldrService) deleteCommandMessage(msg *gotgbot.Message) {
+	go func() {
+		time.Sleep(tldrCommandDeleteDelay)
+		_, err := msg.Delete(s.bot, nil)
+		if err != nil {
+			log.Printf("%s: Failed to delete message %d: %v",
+				utils.GetCurrentTypeName(), msg.MessageId, err)
+		}
+	}()
+}
+
+// extractGroupTopicID extracts group topic ID from a message
+func (s *TldrService) extractGroupTopicID(msg *gotgbot.Message) int64 {
+	if msg.MessageThreadId != 0 && msg.IsTopicMessage {
+		return msg.MessageThreadId
+	}
+	return 0
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"bytes"
 	"evo-bot-go/internal/utils"
 	"fmt"
 	"log"
@@ -397,6 +398,48 @@ func (s *MessageSenderService) SendCopy(
 	return sentMessage, nil
 }
 
+// SendPngWithCaption sends a PNG image with an HTML caption to the chat
+func (s *MessageSenderService) SendPngWithCaption(chatId int64, fileName string, data []byte, caption string) error {
+	_, err := s.bot.SendPhoto(chatId, gotgbot.InputFileByReader(fileName, bytes.NewReader(data)), &gotgbot.SendPhotoOpts{
+		Caption:   caption,
+		ParseMode: "HTML",
+	})
+	if err != nil {
+		log.Printf("%s: SendPngWithCaption: Failed to send image: %v", utils.GetCurrentTypeName(), err)
+	}
+	return err
+}
+
+// SendPhotoByFileID sends a photo already uploaded to Telegram by its file ID
+func (s *MessageSenderService) SendPhotoByFileID(chatId int64, fileID string, opts *gotgbot.SendPhotoOpts) error {
+	_, err := s.bot.SendPhoto(chatId, gotgbot.InputFileByID(fileID), opts)
+	if err != nil {
+		log.Printf("%s: SendPhotoByFileID: Failed to send photo: %v", utils.GetCurrentTypeName(), err)
+	}
+	return err
+}
+
+// SendDocumentWithCaption sends a file with an HTML caption to the chat
+func (s *MessageSenderService) SendDocumentWithCaption(chatId int64, fileName string, data []byte, caption string) error {
+	_, err := s.bot.SendDocument(chatId, gotgbot.InputFileByReader(fileName, bytes.NewReader(data)), &gotgbot.SendDocumentOpts{
+		Caption:   caption,
+		ParseMode: "HTML",
+	})
+	if err != nil {
+		log.Printf("%s: SendDocumentWithCaption: Failed to send document: %v", utils.GetCurrentTypeName(), err)
+	}
+	return err
+}
+
+// SendDocumentByFileID sends a document already uploaded to Telegram by its file ID
+func (s *MessageSenderService) SendDocumentByFileID(chatId int64, fileID string, opts *gotgbot.SendDocumentOpts) error {
+	_, err := s.bot.SendDocument(chatId, gotgbot.InputFileByID(fileID), opts)
+	if err != nil {
+		log.Printf("%s: SendDocumentByFileID: Failed to send document: %v", utils.GetCurrentTypeName(), err)
+	}
+	return err
+}
+
 // SendTypingAction sends a typing action to the specified chat.
 func (s *MessageSenderService) SendTypingAction(chatId int64) error {
 	_, err := s.bot.Request("sendChatAction", map[string]string{
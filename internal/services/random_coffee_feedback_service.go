@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/utils"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// coffeeFeedbackLookback is how old pairs can be to get a feedback request
+const coffeeFeedbackLookback = 7 * 24 * time.Hour
+
+// RandomCoffeeFeedbackService asks pairs whether they met and stores the answers
+type RandomCoffeeFeedbackService struct {
+	config        *config.Config
+	messageSender *MessageSenderService
+	feedbackRepo  *repositories.RandomCoffeeFeedbackRepository
+	pairRepo      *repositories.RandomCoffeePairRepository
+	userRepo      *repositories.UserRepository
+}
+
+// NewRandomCoffeeFeedbackService creates a new random coffee feedback service
+func NewRandomCoffeeFeedbackService(
+	config *config.Config,
+	messageSender *MessageSenderService,
+	feedbackRepo *repositories.RandomCoffeeFeedbackRepository,
+	pairRepo *repositories.RandomCoffeePairRepository,
+	userRepo *repositories.UserRepository,
+) *RandomCoffeeFeedbackService {
+	return &RandomCoffeeFeedbackService{
+		config:        config,
+		messageSender: messageSender,
+		feedbackRepo:  feedbackRepo,
+		pairRepo:      pairRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// SendFeedbackRequests asks every member of the recent pairs whether the meeting took place
+func (s *RandomCoffeeFeedbackService) SendFeedbackRequests(ctx context.Context) error {
+	now := time.Now().In(s.config.ClubTimezone)
+	pairs, err := s.feedbackRepo.GetPairsAwaitingFeedback(now.Add(-coffeeFeedbackLookback), now)
+	if err != nil {
+		return fmt.Errorf("%s: failed to get pairs awaiting feedback: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	sent := 0
+	for _, pair := range pairs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		members, err := s.getMembers(pair.RandomCoffeePair)
+		if err != nil {
+			log.Printf("%s: Failed to get members of pair %d: %v", utils.GetCurrentTypeName(), pair.ID, err)
+			continue
+		}
+
+		for i, member := range members {
+			feedbackID, err := s.feedbackRepo.CreateRequest(pair.ID, member.ID)
+			if err != nil {
+				log.Printf("%s: Failed to create feedback request: %v", utils.GetCurrentTypeName(), err)
+				continue
+			}
+			if feedbackID == 0 {
+				continue
+			}
+
+			partners := make([]repositories.User, 0, len(members)-1)
+			partners = append(partners, members[:i]...)
+			partners = append(partners, members[i+1:]...)
+
+			text := fmt.Sprintf(
+				"☕️ <b>Random Coffee</b> ➪ <b><i>неделя %s</i></b>\n\n"+
+					"Как дела со встречей с %s? Расскажи, получилось ли встретиться — это поможет подбирать пары лучше.",
+				pair.WeekStartDate.Format("Mon, Jan 2"),
+				formatCoffeePartnerNames(partners),
+			)
+			err = s.messageSender.SendHtml(member.TgID, text, &gotgbot.SendMessageOpts{
+				ReplyMarkup: buttons.CoffeeFeedbackStatusButtons(feedbackID, true),
+			})
+			if err != nil {
+				log.Printf("%s: Failed to send feedback request to user %d: %v", utils.GetCurrentTypeName(), member.ID, err)
+				// The user wasn't asked, drop the request so the next run retries it
+				if err := s.feedbackRepo.DeleteRequest(feedbackID); err != nil {
+					log.Printf("%s: Failed to delete unsent feedback request %d: %v", utils.GetCurrentTypeName(), feedbackID, err)
+				}
+				continue
+			}
+			sent++
+		}
+	}
+
+	log.Printf("%s: Sent %d feedback requests for %d pairs", utils.GetCurrentTypeName(), sent, len(pairs))
+	return nil
+}
+
+// SaveStatus saves the answer of the user, a report about a silent partner is forwarded to the admin
+func (s *RandomCoffeeFeedbackService) SaveStatus(feedbackID int, tgUserID int64, status constants.CoffeeFeedbackStatus) error {
+	feedback, user, err := s.getOwnFeedback(feedbackID, tgUserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.feedbackRepo.SetStatus(feedback.ID, status); err != nil {
+		return err
+	}
+
+	if status == constants.CoffeeFeedbackStatusNoResponse {
+		s.flagNonResponders(feedback, user)
+	}
+	return nil
+}
+
+// SaveRating saves the rating of the meeting from 1 to 5
+func (s *RandomCoffeeFeedbackService) SaveRating(feedbackID int, tgUserID int64, rating int) error {
+	if rating < 1 || rating > 5 {
+		return fmt.Errorf("%s: invalid rating %d", utils.GetCurrentTypeName(), rating)
+	}
+
+	feedback, _, err := s.getOwnFeedback(feedbackID, tgUserID)
+	if err != nil {
+		return err
+	}
+
+	return s.feedbackRepo.SetRating(feedback.ID, rating)
+}
+
+// flagNonResponders tells the admin which partners didn't respond to the user
+func (s *RandomCoffeeFeedbackService) flagNonResponders(feedback *repositories.RandomCoffeeFeedback, reporter *repositories.User) {
+	pair, err := s.pairRepo.GetByID(feedback.PairID)
+	if err != nil {
+		log.Printf("%s: Failed to get pair %d: %v", utils.GetCurrentTypeName(), feedback.PairID, err)
+		return
+	}
+
+	members, err := s.getMembers(*pair)
+	if err != nil {
+		log.Printf("%s: Failed to get members of pair %d: %v", utils.GetCurrentTypeName(), pair.ID, err)
+		return
+	}
+
+	var partners []repositories.User
+	for _, member := range members {
+		if member.ID != reporter.ID {
+			partners = append(partners, member)
+		}
+	}
+
+	text := fmt.Sprintf(
+		"⚠️ <b>Random Coffee</b>: %s сообщает, что %s не отвечает на предложение встретиться.",
+		formatCoffeePartnerNames([]repositories.User{*reporter}),
+		formatCoffeePartnerNames(partners),
+	)
+	if err := s.messageSender.SendHtml(s.config.AdminUserID, text, nil); err != nil {
+		log.Printf("%s: Failed to flag non-responsive partners to admin: %v", utils.GetCurrentTypeName(), err)
+	}
+}
+
+// getOwnFeedback returns the feedback if it belongs to the Telegram user
+func (s *RandomCoffeeFeedbackService) getOwnFeedback(feedbackID int, tgUserID int64) (*repositories.RandomCoffeeFeedback, *repositories.User, error) {
+	feedback, err := s.feedbackRepo.GetByID(feedbackID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByTelegramID(tgUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if feedback.UserID != user.ID {
+		return nil, nil, fmt.Errorf("%s: feedback %d doesn't belong to user %d", utils.GetCurrentTypeName(), feedbackID, user.ID)
+	}
+	return feedback, user, nil
+}
+
+func (s *RandomCoffeeFeedbackService) getMembers(pair repositories.RandomCoffeePair) ([]repositories.User, error) {
+	var members []repositories.User
+	for _, userID := range pair.MemberIDs() {
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, *user)
+	}
+	return members, nil
+}
+
+// formatCoffeePartnerNames joins names of the users with their usernames
+func formatCoffeePartnerNames(users []repositories.User) string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		name := html.EscapeString(user.Firstname)
+		if user.TgUsername != "" {
+			name += fmt.Sprintf(" (@%s)", user.TgUsername)
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, " и ")
+}
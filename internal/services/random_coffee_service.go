@@ -2,29 +2,55 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html"
 	"log"
+	"math"
 	"math/rand"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"evo-bot-go/internal/buttons"
+	"evo-bot-go/internal/clients"
 	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/constants"
+	"evo-bot-go/internal/database/prompts"
 	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/formatters"
 	"evo-bot-go/internal/utils"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
 )
 
+// coffeeStartersTimeout limits the time of generating conversation starters for one participant
+const coffeeStartersTimeout = 2 * time.Minute
+
+// coffeeStartersConcurrency limits the number of conversation starters generated at the same time
+const coffeeStartersConcurrency = 5
+
 type RandomCoffeeService struct {
-	bot             *gotgbot.Bot
-	config          *config.Config
-	pollSender      *PollSenderService
-	messageSender   *MessageSenderService
-	pollRepo        *repositories.RandomCoffeePollRepository
-	participantRepo *repositories.RandomCoffeeParticipantRepository
-	profileRepo     *repositories.ProfileRepository
-	pairRepo        *repositories.RandomCoffeePairRepository
-	userRepo        *repositories.UserRepository
+	bot              *gotgbot.Bot
+	config           *config.Config
+	pollSender       *PollSenderService
+	messageSender    *MessageSenderService
+	pollRepo         *repositories.RandomCoffeePollRepository
+	participantRepo  *repositories.RandomCoffeeParticipantRepository
+	profileRepo      *repositories.ProfileRepository
+	pairRepo         *repositories.RandomCoffeePairRepository
+	userRepo         *repositories.UserRepository
+	preferenceRepo   *repositories.RandomCoffeePreferenceRepository
+	feedbackRepo     *repositories.RandomCoffeeFeedbackRepository
+	subscriptionRepo *repositories.RandomCoffeeSubscriptionRepository
+	exclusionRepo    *repositories.RandomCoffeeExclusionRepository
+	rematchRepo      *repositories.RandomCoffeeRematchRepository
+	openaiClient     *clients.OpenAiClient
+	promptingRepo    *repositories.PromptingTemplateRepository
+	// rematchMu serializes rematch requests, so a waiting participant never gets two new partners
+	rematchMu sync.Mutex
 }
 
 // NewRandomCoffeeService creates a new random coffee poll service
@@ -38,17 +64,31 @@ func NewRandomCoffeeService(
 	profileRepo *repositories.ProfileRepository,
 	pairRepo *repositories.RandomCoffeePairRepository,
 	userRepo *repositories.UserRepository,
+	preferenceRepo *repositories.RandomCoffeePreferenceRepository,
+	feedbackRepo *repositories.RandomCoffeeFeedbackRepository,
+	subscriptionRepo *repositories.RandomCoffeeSubscriptionRepository,
+	exclusionRepo *repositories.RandomCoffeeExclusionRepository,
+	rematchRepo *repositories.RandomCoffeeRematchRepository,
+	openaiClient *clients.OpenAiClient,
+	promptingRepo *repositories.PromptingTemplateRepository,
 ) *RandomCoffeeService {
 	return &RandomCoffeeService{
-		bot:             bot,
-		config:          config,
-		pollSender:      pollSender,
-		messageSender:   messageSender,
-		pollRepo:        pollRepo,
-		participantRepo: participantRepo,
-		profileRepo:     profileRepo,
-		pairRepo:        pairRepo,
-		userRepo:        userRepo,
+		bot:              bot,
+		config:           config,
+		pollSender:       pollSender,
+		messageSender:    messageSender,
+		pollRepo:         pollRepo,
+		participantRepo:  participantRepo,
+		profileRepo:      profileRepo,
+		pairRepo:         pairRepo,
+		userRepo:         userRepo,
+		preferenceRepo:   preferenceRepo,
+		feedbackRepo:     feedbackRepo,
+		subscriptionRepo: subscriptionRepo,
+		exclusionRepo:    exclusionRepo,
+		rematchRepo:      rematchRepo,
+		openaiClient:     openaiClient,
+		promptingRepo:    promptingRepo,
 	}
 }
 
@@ -63,13 +103,18 @@ func (s *RandomCoffeeService) SendPoll(ctx context.Context) error {
 		return fmt.Errorf("%s: RandomCoffeeTopicID is not configured", utils.GetCurrentTypeName())
 	}
 
+	if !s.config.RandomCoffeeSendPoll {
+		return s.openSubscribersRound(chatID)
+	}
+
 	// Send reqular message with link to rules and new random coffee poll
 	message :=
 		fmt.Sprintf("Привет! Открываю запись на новый <b>Random Coffee</b> <i>(<a href=\"https://t.me/c/%d/%d/%d\">правила участия</a>)</i>.",
 			s.config.SuperGroupChatID,
 			s.config.RandomCoffeeTopicID,
 			s.config.RandomCoffeeTopicID+1, // next message id (small hack)
-		) + " Голосуй в опросе ниже, если хочешь участвовать ⬇️"
+		) + " Голосуй в опросе ниже, если хочешь участвовать ⬇️" +
+			fmt.Sprintf("\n\n<i>Не хочешь голосовать каждую неделю? Подпишись на Random Coffee в личке с ботом через /%s.</i>", constants.CoffeeCommand)
 
 	opts := &gotgbot.SendMessageOpts{
 		MessageThreadId: int64(s.config.RandomCoffeeTopicID),
@@ -109,39 +154,53 @@ func (s *RandomCoffeeService) SendPoll(ctx context.Context) error {
 	return s.savePollToDB(sentPollMsg)
 }
 
-// savePollToDB saves the poll information to the database
+// openSubscribersRound opens a round without a poll, only subscribers take part in it
+func (s *RandomCoffeeService) openSubscribersRound(chatID int64) error {
+	weekStartDate := utils.NextCoffeeWeekStart(time.Now().In(s.config.ClubTimezone))
+
+	message := fmt.Sprintf(
+		"Привет! Открываю новый раунд <b>Random Coffee</b> на неделе с %s ☕️\n\n"+
+			"Участвуют все подписчики. Подписаться, поставить паузу или выбрать частоту встреч можно в личке с ботом через /%s.",
+		weekStartDate.Format("02.01"),
+		constants.CoffeeCommand,
+	)
+	sentMsg, err := s.messageSender.SendHtmlWithReturnMessage(chatID, message, &gotgbot.SendMessageOpts{
+		MessageThreadId: int64(s.config.RandomCoffeeTopicID),
+	})
+	if err != nil {
+		return fmt.Errorf("%s: Failed to send round message: %v", utils.GetCurrentTypeName(), err)
+	}
+
+	if err := s.messageSender.PinMessage(sentMsg.Chat.Id, sentMsg.MessageId, true); err != nil {
+		return fmt.Errorf("%s: Failed to pin round message: %v", utils.GetCurrentTypeName(), err)
+	}
+
+	return s.savePollToDB(sentMsg)
+}
+
+// savePollToDB saves the poll information to the database, a message without a poll opens a round only for subscribers
 func (s *RandomCoffeeService) savePollToDB(sentPollMsg *gotgbot.Message) error {
 	if s.pollRepo == nil {
 		log.Printf("%s: pollRepo is nil, skipping DB interaction.", utils.GetCurrentTypeName())
 		return nil
 	}
 
-	// Calculate next Monday (week start date)
-	now := time.Now().UTC()
-	daysUntilMonday := (8 - int(now.Weekday())) % 7
-	if daysUntilMonday == 0 {
-		daysUntilMonday = 7 // Next Monday if today is Monday
-	}
-
-	weekStartDate := now.AddDate(0, 0, daysUntilMonday)
-	weekStartDate =
-		time.Date(
-			weekStartDate.Year(),
-			weekStartDate.Month(),
-			weekStartDate.Day(),
-			0, 0, 0, 0, time.UTC,
-		)
+	// Calculate next Monday (week start date) by the club calendar
+	weekStartDate := utils.NextCoffeeWeekStart(time.Now().In(s.config.ClubTimezone))
 
 	log.Printf(
-		"%s: Calculated WeekStartDate: %s (UTC)",
+		"%s: Calculated WeekStartDate: %s (%s)",
 		utils.GetCurrentTypeName(),
 		weekStartDate.Format("2006-01-02"),
+		s.config.ClubTimezone,
 	)
 
 	newPollEntry := repositories.RandomCoffeePoll{
-		MessageID:      sentPollMsg.MessageId,
-		TelegramPollID: sentPollMsg.Poll.Id,
-		WeekStartDate:  weekStartDate,
+		MessageID:     sentPollMsg.MessageId,
+		WeekStartDate: weekStartDate,
+	}
+	if sentPollMsg.Poll != nil {
+		newPollEntry.TelegramPollID = sentPollMsg.Poll.Id
 	}
 
 	pollID, err := s.pollRepo.CreatePoll(newPollEntry)
@@ -163,40 +222,83 @@ func (s *RandomCoffeeService) savePollToDB(sentPollMsg *gotgbot.Message) error {
 	return nil
 }
 
-func (s *RandomCoffeeService) GenerateAndSendPairs() error {
+// GenerateAndSendPairs generates pairs for the latest poll and publishes them right away
+func (s *RandomCoffeeService) GenerateAndSendPairs(ctx context.Context) error {
+	poll, err := s.GenerateDraftPairs(ctx)
+	if err != nil {
+		return err
+	}
+	return s.PublishDraft(ctx, int(poll.ID))
+}
+
+// RunPairsTask generates pairs for the latest poll and, depending on the config,
+// publishes them or sends the draft to the admin for approval
+func (s *RandomCoffeeService) RunPairsTask(ctx context.Context) error {
+	if !s.config.RandomCoffeePairsRequireApproval {
+		return s.GenerateAndSendPairs(ctx)
+	}
+
+	poll, err := s.GenerateDraftPairs(ctx)
+	if err != nil {
+		return err
+	}
+	return s.SendDraftPreview(s.config.AdminUserID, int(poll.ID))
+}
+
+// GenerateDraftPairs stops the latest poll and saves draft pairs for it, previous drafts of the poll are replaced
+func (s *RandomCoffeeService) GenerateDraftPairs(ctx context.Context) (*repositories.RandomCoffeePoll, error) {
 	latestPoll, err := s.pollRepo.GetLatestPoll()
 	if err != nil {
-		return fmt.Errorf("%s: error getting latest poll: %w", utils.GetCurrentTypeName(), err)
+		return nil, fmt.Errorf("%s: error getting latest poll: %w", utils.GetCurrentTypeName(), err)
 	}
 	if latestPoll == nil {
-		return fmt.Errorf("%s: опрос для рандом кофе не найден", utils.GetCurrentTypeName())
+		return nil, fmt.Errorf("%s: опрос для рандом кофе не найден", utils.GetCurrentTypeName())
 	}
 
-	// Stop the poll first before generating pairs
+	// Stop the poll first before generating pairs, rounds only for subscribers have no poll
 	chatID := utils.ChatIdToFullChatId(s.config.SuperGroupChatID)
-	_, err = s.pollSender.StopPoll(chatID, latestPoll.MessageID, nil)
-	if err != nil {
-		log.Printf("%s: Warning - failed to stop poll (message ID %d): %v", utils.GetCurrentTypeName(), latestPoll.MessageID, err)
-		// Continue anyway - we might still be able to generate pairs
-	} else {
-		log.Printf("%s: Successfully stopped poll (message ID %d)", utils.GetCurrentTypeName(), latestPoll.MessageID)
+	if latestPoll.TelegramPollID != "" {
+		_, err = s.pollSender.StopPoll(chatID, latestPoll.MessageID, nil)
+		if err != nil {
+			log.Printf("%s: Warning - failed to stop poll (message ID %d): %v", utils.GetCurrentTypeName(), latestPoll.MessageID, err)
+			// Continue anyway - we might still be able to generate pairs
+		} else {
+			log.Printf("%s: Successfully stopped poll (message ID %d)", utils.GetCurrentTypeName(), latestPoll.MessageID)
+		}
 	}
 
-	participants, err := s.participantRepo.GetParticipatingUsers(latestPoll.ID)
-	if err != nil {
-		return fmt.Errorf("%s: error getting participants for poll ID %d: %w", utils.GetCurrentTypeName(), latestPoll.ID, err)
+	if err := s.addSubscribers(latestPoll); err != nil {
+		return nil, err
 	}
 
-	if len(participants) < 2 {
-		return fmt.Errorf("недостаточно участников для создания пар (нужно минимум 2, зарегистрировалось %d)", len(participants))
+	participants, err := s.participantRepo.GetParticipatingUsers(latestPoll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: error getting participants for poll ID %d: %w", utils.GetCurrentTypeName(), latestPoll.ID, err)
 	}
 
-	// Update participant info using Telegram Bot API if any field has changed
+	// Update participant info using Telegram Bot API if any field has changed,
+	// participants who left the club or got a coffee ban after voting are removed from the round
+	eligible := make([]repositories.User, 0, len(participants))
+	var removed []coffeeRemovedParticipant
 	for i := range participants {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		participant := &participants[i]
 		user, err := s.userRepo.GetByTelegramID(participant.TgID)
 		if err != nil {
 			log.Printf("%s: error getting user by telegram ID %d: %v", utils.GetCurrentTypeName(), participant.TgID, err)
+			eligible = append(eligible, *participant)
+			continue
+		}
+
+		if user.HasCoffeeBan {
+			removed = append(removed, coffeeRemovedParticipant{
+				user: *participant,
+				message: "☕️ Участие в Random Coffee для тебя сейчас ограничено, поэтому бот убрал тебя из этого раунда. " +
+					"Если это ошибка, напиши администратору.",
+			})
 			continue
 		}
 
@@ -204,6 +306,15 @@ func (s *RandomCoffeeService) GenerateAndSendPairs() error {
 		chatMember, err := s.bot.GetChatMember(chatID, participant.TgID, nil)
 		if err != nil {
 			log.Printf("%s: error getting chat member for user ID %d: %v", utils.GetCurrentTypeName(), participant.TgID, err)
+			eligible = append(eligible, *participant)
+			continue
+		}
+
+		if status := chatMember.GetStatus(); status == "left" || status == "kicked" {
+			removed = append(removed, coffeeRemovedParticipant{
+				user:    *participant,
+				message: "☕️ Ты больше не в клубе, поэтому бот убрал тебя из этого раунда Random Coffee. Возвращайся — будем рады!",
+			})
 			continue
 		}
 
@@ -241,10 +352,103 @@ func (s *RandomCoffeeService) GenerateAndSendPairs() error {
 				participant.Lastname = currentUser.LastName
 			}
 		}
+
+		eligible = append(eligible, *participant)
+	}
+
+	if len(removed) > 0 {
+		s.removeParticipants(latestPoll, removed)
+	}
+	participants = eligible
+
+	if len(participants) < 2 {
+		return nil, fmt.Errorf("недостаточно участников для создания пар (нужно минимум 2, зарегистрировалось %d)", len(participants))
+	}
+
+	if err := s.saveDraftPairs(latestPoll, participants); err != nil {
+		return nil, err
+	}
+	return latestPoll, nil
+}
+
+// addSubscribers adds active subscribers to participants of the poll, answers in the poll take precedence
+func (s *RandomCoffeeService) addSubscribers(poll *repositories.RandomCoffeePoll) error {
+	subscriptions, err := s.subscriptionRepo.GetAllowedSubscriptions()
+	if err != nil {
+		return fmt.Errorf("%s: error getting subscriptions: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	added := 0
+	for _, subscription := range subscriptions {
+		if !subscription.IsActive(poll.WeekStartDate) {
+			continue
+		}
+
+		ok, err := s.participantRepo.AddParticipantIfAbsent(poll.ID, int64(subscription.UserID))
+		if err != nil {
+			return fmt.Errorf("%s: error adding subscriber %d to poll %d: %w", utils.GetCurrentTypeName(), subscription.UserID, poll.ID, err)
+		}
+		if ok {
+			added++
+		}
+	}
+
+	log.Printf("%s: Added %d of %d subscribers to poll %d", utils.GetCurrentTypeName(), added, len(subscriptions), poll.ID)
+	return nil
+}
+
+// coffeeRemovedParticipant is a participant removed from the round at pairing time with a DM explaining why
+type coffeeRemovedParticipant struct {
+	user    repositories.User
+	message string
+}
+
+// removeParticipants retracts the votes of the removed participants, explains them why
+// and tells the random coffee topic how many participants were removed.
+// A Telegram poll can't be edited, so the note is a reply to the poll message.
+func (s *RandomCoffeeService) removeParticipants(poll *repositories.RandomCoffeePoll, removed []coffeeRemovedParticipant) {
+	for _, participant := range removed {
+		if err := s.participantRepo.RemoveParticipant(poll.ID, int64(participant.user.ID)); err != nil {
+			log.Printf("%s: Failed to remove participant %d from poll %d: %v", utils.GetCurrentTypeName(), participant.user.ID, poll.ID, err)
+			continue
+		}
+		log.Printf("%s: Removed participant %d from poll %d at pairing time", utils.GetCurrentTypeName(), participant.user.ID, poll.ID)
+
+		if err := s.messageSender.SendHtml(participant.user.TgID, participant.message, nil); err != nil {
+			log.Printf("%s: Failed to notify removed participant %d: %v", utils.GetCurrentTypeName(), participant.user.ID, err)
+		}
+	}
+
+	text := fmt.Sprintf(
+		"ℹ️ Перед составлением пар из раунда исключено участников: %d — они покинули клуб или не могут участвовать в Random Coffee.",
+		len(removed),
+	)
+	opts := &gotgbot.SendMessageOpts{
+		MessageThreadId: int64(s.config.RandomCoffeeTopicID),
+		ReplyParameters: &gotgbot.ReplyParameters{
+			MessageId:                poll.MessageID,
+			AllowSendingWithoutReply: true,
+		},
+	}
+	if err := s.messageSender.SendHtml(utils.ChatIdToFullChatId(s.config.SuperGroupChatID), text, opts); err != nil {
+		log.Printf("%s: Failed to update poll %d about removed participants: %v", utils.GetCurrentTypeName(), poll.ID, err)
+	}
+}
+
+// saveDraftPairs matches the participants and replaces draft pairs of the poll with the result
+func (s *RandomCoffeeService) saveDraftPairs(poll *repositories.RandomCoffeePoll, participants []repositories.User) error {
+	// Personal exclusion lists are hard constraints, pairing without them is not allowed
+	userIDs := make([]int, len(participants))
+	for i, participant := range participants {
+		userIDs[i] = participant.ID
+	}
+	excluded, err := s.exclusionRepo.GetExcludedPairs(userIDs)
+	if err != nil {
+		return fmt.Errorf("%s: error getting excluded pairs: %w", utils.GetCurrentTypeName(), err)
 	}
 
 	// Smart Pairing Logic with History Consideration
-	pairs, unpaired, err := s.generateSmartPairs(participants, int(latestPoll.ID))
+	pairs, unpaired, err := s.generateSmartPairs(participants, poll, excluded)
 	if err != nil {
 		log.Printf("%s: Smart pairing failed, falling back to random: %v", utils.GetCurrentTypeName(), err)
 		// Fallback to old random logic
@@ -252,21 +456,48 @@ func (s *RandomCoffeeService) GenerateAndSendPairs() error {
 		r.Shuffle(len(participants), func(i, j int) {
 			participants[i], participants[j] = participants[j], participants[i]
 		})
-		pairs, unpaired = s.createPairsFromShuffled(participants, int(latestPoll.ID))
+		pairs, unpaired = s.createPairsFromShuffled(participants, excluded)
 	}
 
+	groups := make([][]int, len(pairs))
+	for i, pair := range pairs {
+		groups[i] = draftMemberIDs(pair)
+	}
+	if err := s.pairRepo.ReplaceDraftPairs(int(poll.ID), groups); err != nil {
+		return fmt.Errorf("%s: error saving draft pairs: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	log.Printf("%s: Saved %d draft pairs for poll ID %d, %d participants unpaired",
+		utils.GetCurrentTypeName(), len(pairs), poll.ID, len(unpaired))
+	return nil
+}
+
+// announcePairs posts the pairs to the random coffee topic, pins the message and sends introductions to the participants
+func (s *RandomCoffeeService) announcePairs(
+	ctx context.Context,
+	latestPoll *repositories.RandomCoffeePoll,
+	pairs []CoffeePair,
+	unpaired []repositories.User,
+) error {
+	chatID := utils.ChatIdToFullChatId(s.config.SuperGroupChatID)
+
 	// Format pairs display text
 	var pairsText []string
-	var unpairedUserText string
+	var unpairedUsersText []string
 
 	for _, pair := range pairs {
 		user1Display := s.formatUserDisplay(&pair.User1)
 		user2Display := s.formatUserDisplay(&pair.User2)
-		pairsText = append(pairsText, fmt.Sprintf("%s x %s", user1Display, user2Display))
+		if pair.User3 != nil {
+			user3Display := s.formatUserDisplay(pair.User3)
+			pairsText = append(pairsText, fmt.Sprintf("%s x %s x %s", user1Display, user2Display, user3Display)+formatPairReason(pair))
+			continue
+		}
+		pairsText = append(pairsText, fmt.Sprintf("%s x %s", user1Display, user2Display)+formatPairReason(pair))
 	}
 
-	if unpaired != nil {
-		unpairedUserText = s.formatUserDisplay(unpaired)
+	for i := range unpaired {
+		unpairedUsersText = append(unpairedUsersText, s.formatUserDisplay(&unpaired[i]))
 	}
 
 	var messageBuilder strings.Builder
@@ -274,8 +505,12 @@ func (s *RandomCoffeeService) GenerateAndSendPairs() error {
 	for _, pair := range pairsText {
 		messageBuilder.WriteString(fmt.Sprintf("➪ %s\n", pair))
 	}
-	if unpairedUserText != "" {
-		messageBuilder.WriteString(fmt.Sprintf("\n😔 %s без пары и ищет компанию на эту неделю!\n", unpairedUserText))
+	switch {
+	case len(unpairedUsersText) == 1:
+		messageBuilder.WriteString(fmt.Sprintf("\n😔 %s без пары и ищет компанию на эту неделю!\n", unpairedUsersText[0]))
+	case len(unpairedUsersText) > 1:
+		messageBuilder.WriteString(fmt.Sprintf("\n😔 %s без пары (не нашлось никого под их условия) и ищут компанию на эту неделю!\n",
+			strings.Join(unpairedUsersText, ", ")))
 	}
 	messageBuilder.WriteString("\n🗓 День, время и формат встречи вы выбираете сами. Просто напиши своей паре в личку, когда и в каком формате тебе удобно встретиться.")
 
@@ -296,9 +531,237 @@ func (s *RandomCoffeeService) GenerateAndSendPairs() error {
 	}
 
 	log.Printf("%s: Successfully sent pairings for poll ID %d to chat %d.", utils.GetCurrentTypeName(), latestPoll.ID, s.config.SuperGroupChatID)
+
+	s.sendPairIntroductions(ctx, chatID, latestPoll, pairs)
 	return nil
 }
 
+// coffeeIntroduction is a DM introducing the member to the partners of the pair
+type coffeeIntroduction struct {
+	pair     CoffeePair
+	member   repositories.User
+	partners []repositories.User
+	// starters are conversation starters from the LLM, empty if they couldn't be generated
+	starters string
+}
+
+// sendPairIntroductions sends every participant a DM with profiles of the partners and conversation starters,
+// participants who can't get a DM (e.g. never started the bot) are mentioned in the random coffee topic instead.
+// The pairs are already announced, so a cancelled context only drops the conversation starters.
+func (s *RandomCoffeeService) sendPairIntroductions(
+	ctx context.Context,
+	chatID int64,
+	poll *repositories.RandomCoffeePoll,
+	pairs []CoffeePair,
+) {
+	var introductions []coffeeIntroduction
+	bios := make(map[int]string)
+	for _, pair := range pairs {
+		members := pair.Members()
+		for i, member := range members {
+			partners := make([]repositories.User, 0, len(members)-1)
+			partners = append(partners, members[:i]...)
+			partners = append(partners, members[i+1:]...)
+			introductions = append(introductions, coffeeIntroduction{pair: pair, member: member, partners: partners})
+
+			if _, ok := bios[member.ID]; !ok {
+				bios[member.ID] = s.getBio(member)
+			}
+		}
+	}
+
+	// Starters are generated before sending, so the introductions aren't held up by the LLM one by one
+	s.generateConversationStartersForAll(ctx, introductions, bios)
+
+	var notReached []string
+	sent := 0
+	for _, introduction := range introductions {
+		if err := s.sendPairIntroduction(poll, introduction, bios); err != nil {
+			log.Printf("%s: Failed to send pair introduction to user %d: %v", utils.GetCurrentTypeName(), introduction.member.ID, err)
+			notReached = append(notReached, formatUserMention(introduction.member))
+			continue
+		}
+		sent++
+	}
+
+	log.Printf("%s: Sent %d pair introductions, %d participants not reachable by DM", utils.GetCurrentTypeName(), sent, len(notReached))
+	if len(notReached) == 0 {
+		return
+	}
+
+	botLink := "мне"
+	if s.bot != nil && s.bot.Username != "" {
+		botLink = fmt.Sprintf("<a href=\"https://t.me/%s\">мне</a>", s.bot.Username)
+	}
+	message := fmt.Sprintf(
+		"📬 %s, не получилось написать вам в личку. Чтобы получать знакомство с парой и идеи для разговора, напишите %s /start.",
+		strings.Join(notReached, ", "),
+		botLink,
+	)
+
+	opts := &gotgbot.SendMessageOpts{
+		MessageThreadId: int64(s.config.RandomCoffeeTopicID),
+	}
+	if err := s.messageSender.SendHtml(chatID, message, opts); err != nil {
+		log.Printf("%s: Failed to mention participants not reachable by DM: %v", utils.GetCurrentTypeName(), err)
+	}
+}
+
+// sendPairIntroduction sends the introduction to the member. The starters come from the LLM and may break
+// the HTML markup, so if the message is rejected it is sent again without them.
+func (s *RandomCoffeeService) sendPairIntroduction(
+	poll *repositories.RandomCoffeePoll,
+	introduction coffeeIntroduction,
+	bios map[int]string,
+) error {
+	opts := &gotgbot.SendMessageOpts{}
+	if introduction.pair.ID != 0 {
+		opts.ReplyMarkup = buttons.CoffeeRematchButton(introduction.pair.ID)
+	}
+
+	text := s.formatPairIntroduction(poll, introduction, bios)
+	err := s.messageSender.SendHtml(introduction.member.TgID, text, opts)
+	if err == nil || introduction.starters == "" {
+		return err
+	}
+
+	log.Printf("%s: Failed to send pair introduction with conversation starters to user %d, sending without them: %v",
+		utils.GetCurrentTypeName(), introduction.member.ID, err)
+	introduction.starters = ""
+	return s.messageSender.SendHtml(introduction.member.TgID, s.formatPairIntroduction(poll, introduction, bios), opts)
+}
+
+// formatPairIntroduction formats a DM with profiles of the partners, a link to chat and conversation starters
+func (s *RandomCoffeeService) formatPairIntroduction(
+	poll *repositories.RandomCoffeePoll,
+	introduction coffeeIntroduction,
+	bios map[int]string,
+) string {
+	pair, partners := introduction.pair, introduction.partners
+
+	partnersTitle := "Твоя пара"
+	if len(partners) > 1 {
+		partnersTitle = "Твоя компания"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("☕️ <b>Random Coffee</b> ➪ <b><i>неделя %s</i></b>\n\n", poll.WeekStartDate.Format("Mon, Jan 2")))
+	if pair.IsSupplementary {
+		sb.WriteString("🔄 Прошлая пара не откликнулась, поэтому бот подобрал новую.\n\n")
+	}
+	sb.WriteString(fmt.Sprintf("%s на эту неделю:\n\n", partnersTitle))
+
+	for _, partner := range partners {
+		// FormatProfileView escapes the bio in place, so it gets a copy
+		sb.WriteString(formatters.FormatProfileView(&partner, &repositories.Profile{Bio: bios[partner.ID]}, false))
+		sb.WriteString(fmt.Sprintf("💬 <a href=\"%s\">Написать %s</a>\n\n", getUserChatLink(partner), html.EscapeString(partner.Firstname)))
+	}
+
+	if pair.Reason != "" {
+		sb.WriteString(fmt.Sprintf("🤝 Что вас объединяет: <i>%s</i>\n\n", pair.Reason))
+	}
+
+	if introduction.starters != "" {
+		sb.WriteString(fmt.Sprintf("💡 <b>С чего начать разговор:</b>\n%s\n\n", introduction.starters))
+	}
+
+	sb.WriteString("🗓 День, время и формат встречи вы выбираете сами — напиши первым, не жди 🙂")
+	if pair.ID != 0 {
+		sb.WriteString("\n\nЕсли пара не отвечает несколько дней, нажми «Партнёр молчит» — бот подберёт нового.")
+	}
+	return sb.String()
+}
+
+// generateConversationStartersForAll fills in conversation starters of the introductions,
+// up to coffeeStartersConcurrency requests to the LLM run at the same time
+func (s *RandomCoffeeService) generateConversationStartersForAll(
+	ctx context.Context,
+	introductions []coffeeIntroduction,
+	bios map[int]string,
+) {
+	semaphore := make(chan struct{}, coffeeStartersConcurrency)
+	var wg sync.WaitGroup
+	for i := range introductions {
+		wg.Add(1)
+		go func(introduction *coffeeIntroduction) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			introduction.starters = s.generateConversationStarters(ctx, introduction.member, introduction.partners, bios)
+		}(&introductions[i])
+	}
+	wg.Wait()
+}
+
+// generateConversationStarters asks the LLM for conversation starters based on the bios, returns an empty string on failure
+func (s *RandomCoffeeService) generateConversationStarters(
+	ctx context.Context,
+	member repositories.User,
+	partners []repositories.User,
+	bios map[int]string,
+) string {
+	if s.openaiClient == nil || s.promptingRepo == nil || ctx.Err() != nil {
+		return ""
+	}
+
+	templateText, err := s.promptingRepo.Get(prompts.CoffeeStartersPromptKey, prompts.CoffeeStartersPromptDefaultValue)
+	if err != nil {
+		log.Printf("%s: Failed to get conversation starters prompt: %v", utils.GetCurrentTypeName(), err)
+		return ""
+	}
+
+	describe := func(user repositories.User) string {
+		bio := bios[user.ID]
+		if bio == "" {
+			bio = "не указано"
+		}
+		return fmt.Sprintf("Имя: %s\nО себе: %s", user.Firstname, bio)
+	}
+	partnerDescriptions := make([]string, 0, len(partners))
+	for _, partner := range partners {
+		partnerDescriptions = append(partnerDescriptions, describe(partner))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, coffeeStartersTimeout)
+	defer cancel()
+
+	prompt := fmt.Sprintf(templateText, describe(member), strings.Join(partnerDescriptions, "\n\n"))
+	starters, err := s.openaiClient.GetCompletion(ctx, prompt)
+	if err != nil {
+		log.Printf("%s: Failed to generate conversation starters for user %d: %v", utils.GetCurrentTypeName(), member.ID, err)
+		return ""
+	}
+
+	return strings.TrimSpace(starters)
+}
+
+// getBio returns the bio from the user profile, empty if there is none
+func (s *RandomCoffeeService) getBio(user repositories.User) string {
+	profile, err := s.profileRepo.GetOrCreate(user.ID)
+	if err != nil {
+		log.Printf("%s: Error getting profile for user %d: %v", utils.GetCurrentTypeName(), user.ID, err)
+		return ""
+	}
+	return profile.Bio
+}
+
+// getUserChatLink returns a deep link to a private chat with the user
+func getUserChatLink(user repositories.User) string {
+	if user.TgUsername != "" {
+		return fmt.Sprintf("https://t.me/%s", user.TgUsername)
+	}
+	return fmt.Sprintf("tg://user?id=%d", user.TgID)
+}
+
+// formatUserMention returns a mention that notifies the user even without a username
+func formatUserMention(user repositories.User) string {
+	if user.TgUsername != "" {
+		return fmt.Sprintf("@%s", user.TgUsername)
+	}
+	return fmt.Sprintf("<a href=\"tg://user?id=%d\">%s</a>", user.TgID, html.EscapeString(user.Firstname))
+}
+
 func (s *RandomCoffeeService) formatUserDisplay(user *repositories.User) string {
 	userDisplay := user.Firstname
 
@@ -323,157 +786,724 @@ func (s *RandomCoffeeService) formatUserDisplay(user *repositories.User) string
 	return userDisplay
 }
 
-// CoffeePair represents a pair of users for coffee meetings
+// CoffeePair represents a pair of users for coffee meetings, User3 is set for a group of three
 type CoffeePair struct {
+	// ID is zero until the pair is saved
+	ID    int
 	User1 repositories.User
 	User2 repositories.User
+	User3 *repositories.User
+	// Reason explains what the members have in common by their preferences, empty if nothing
+	Reason string
+	// IsSupplementary is set for a pair created instead of a failed one
+	IsSupplementary bool
 }
 
-// generateSmartPairs creates pairs considering history to avoid recent repeats
-func (s *RandomCoffeeService) generateSmartPairs(participants []repositories.User, pollID int) ([]CoffeePair, *repositories.User, error) {
+// Members returns all users of the pair or the group of three
+func (p CoffeePair) Members() []repositories.User {
+	members := []repositories.User{p.User1, p.User2}
+	if p.User3 != nil {
+		members = append(members, *p.User3)
+	}
+	return members
+}
+
+// generateSmartPairs creates pairs with the minimum total cost of repeats, taking the whole pairing history into account.
+// Excluded pairs keyed by utils.CoffeePairKey are never matched.
+func (s *RandomCoffeeService) generateSmartPairs(
+	participants []repositories.User,
+	poll *repositories.RandomCoffeePoll,
+	excluded map[[2]int]bool,
+) ([]CoffeePair, []repositories.User, error) {
 	if len(participants) < 2 {
 		return nil, nil, fmt.Errorf("not enough participants for pairing")
 	}
 
-	// Get user IDs for history lookup
+	usersByID := make(map[int]repositories.User, len(participants))
 	userIDs := make([]int, len(participants))
 	for i, user := range participants {
 		userIDs[i] = user.ID
+		usersByID[user.ID] = user
 	}
 
-	// Get history of pairs from last 4 polls
-	pairHistory, err := s.pairRepo.GetPairsHistoryForUsers(userIDs, 4)
+	pairHistory, err := s.pairRepo.GetPairsHistoryForUsers(userIDs)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get pair history: %w", err)
 	}
 
-	log.Printf("%s: Smart pairing for %d participants, found %d historical pair combinations",
-		utils.GetCurrentTypeName(), len(participants), len(pairHistory))
+	// Pairs of the current poll are skipped, so regenerating pairs doesn't treat them as repeats
+	history := make([]utils.CoffeePairHistory, 0, len(pairHistory))
+	for _, entry := range pairHistory {
+		if entry.PollID == int(poll.ID) {
+			continue
+		}
+		history = append(history, utils.CoffeePairHistory{
+			User1ID:  entry.User1ID,
+			User2ID:  entry.User2ID,
+			WeeksAgo: poll.WeekStartDate.Sub(entry.WeekStartDate).Hours() / (24 * 7),
+		})
+	}
 
-	// Create a copy of participants for pairing
-	availableUsers := make([]repositories.User, len(participants))
-	copy(availableUsers, participants)
+	preferences := s.getMatchingPreferences(userIDs)
+	unreliability := s.getUnreliability(userIDs)
 
-	// Randomly shuffle to maintain fairness
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	r.Shuffle(len(availableUsers), func(i, j int) {
-		availableUsers[i], availableUsers[j] = availableUsers[j], availableUsers[i]
+	seed := s.config.RandomCoffeeMatchingSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	log.Printf("%s: Smart pairing for %d participants, found %d historical pairs, seed %d",
+		utils.GetCurrentTypeName(), len(participants), len(history), seed)
+
+	matchedGroups, unpairedIDs := utils.MatchCoffeeGroups(userIDs, history, utils.CoffeeMatchingOptions{
+		RepeatPenalty: s.config.RandomCoffeeRepeatPenalty,
+		HalfLifeWeeks: s.config.RandomCoffeeHistoryHalfLifeWeeks,
+		Seed:          seed,
+		AllowTriple:   s.config.RandomCoffeeAllowTriples,
+		PairCost: func(user1ID, user2ID int) float64 {
+			if excluded[utils.CoffeePairKey(user1ID, user2ID)] {
+				return math.Inf(1)
+			}
+			return utils.CoffeePreferencesCost(
+				preferences[user1ID],
+				preferences[user2ID],
+				s.config.RandomCoffeePreferencePenalty,
+				s.config.RandomCoffeeInterestBonus,
+			) + utils.CoffeeReliabilityCost(
+				unreliability[user1ID],
+				unreliability[user2ID],
+				s.config.RandomCoffeeUnreliabilityPenalty,
+			)
+		},
 	})
 
+	lastMet := make(map[[2]int]float64)
+	for _, h := range history {
+		key := utils.CoffeePairKey(h.User1ID, h.User2ID)
+		if weeksAgo, exists := lastMet[key]; !exists || h.WeeksAgo < weeksAgo {
+			lastMet[key] = h.WeeksAgo
+		}
+	}
+
 	var pairs []CoffeePair
-	used := make(map[int]bool)
+	for _, matched := range matchedGroups {
+		user1, user2 := usersByID[matched[0]], usersByID[matched[1]]
+		pair := CoffeePair{User1: user1, User2: user2}
+		if len(matched) > 2 {
+			user3 := usersByID[matched[2]]
+			pair.User3 = &user3
+		}
+		pairs = append(pairs, pair)
+
+		// Log pairing decision
+		if pair.User3 != nil {
+			log.Printf("%s: Created TRIPLE: %s x %s x %s",
+				utils.GetCurrentTypeName(), user1.Firstname, user2.Firstname, pair.User3.Firstname)
+		} else if weeksAgo, exists := lastMet[utils.CoffeePairKey(user1.ID, user2.ID)]; exists {
+			log.Printf("%s: Created REPEAT pair: %s x %s (last paired %.0f weeks ago)",
+				utils.GetCurrentTypeName(), user1.Firstname, user2.Firstname, weeksAgo)
+		} else {
+			log.Printf("%s: Created NEW pair: %s x %s (never paired before)",
+				utils.GetCurrentTypeName(), user1.Firstname, user2.Firstname)
+		}
+	}
 
-	// Try to pair users avoiding recent history
-	for i := 0; i < len(availableUsers); i++ {
-		if used[availableUsers[i].ID] {
-			continue
+	// Without a triple one participant stays unpaired with an odd number of participants,
+	// strict preferences and exclusion lists may leave more participants without a pair
+	var unpaired []repositories.User
+	for _, userID := range unpairedIDs {
+		unpaired = append(unpaired, usersByID[userID])
+	}
+
+	return pairs, unpaired, nil
+}
+
+// getMatchingPreferences loads preferences of the participants, participants without preferences get empty ones
+func (s *RandomCoffeeService) getMatchingPreferences(userIDs []int) map[int]utils.CoffeePreferences {
+	preferences := make(map[int]utils.CoffeePreferences, len(userIDs))
+	if s.preferenceRepo == nil {
+		return preferences
+	}
+
+	stored, err := s.preferenceRepo.GetByUserIDs(userIDs)
+	if err != nil {
+		log.Printf("%s: Failed to get participants preferences, pairing without them: %v", utils.GetCurrentTypeName(), err)
+		return preferences
+	}
+
+	for userID, preference := range stored {
+		preferences[userID] = utils.CoffeePreferences{
+			Format:    preference.MeetingFormat,
+			City:      preference.City,
+			Languages: preference.Languages,
+			Interests: preference.Interests,
+			Strict:    preference.Strict,
 		}
+	}
+	return preferences
+}
 
-		user1 := availableUsers[i]
-		bestPartner := -1
-		oldestPoll := 999999 // Very high number to represent "never paired"
+// getUnreliability estimates unreliability of the participants by the feedback of their past partners
+func (s *RandomCoffeeService) getUnreliability(userIDs []int) map[int]float64 {
+	unreliability := make(map[int]float64, len(userIDs))
+	if s.feedbackRepo == nil {
+		return unreliability
+	}
 
-		// Find best partner (never paired or oldest pairing)
-		for j := i + 1; j < len(availableUsers); j++ {
-			if used[availableUsers[j].ID] {
-				continue
-			}
+	stats, err := s.feedbackRepo.GetReliabilityStats(userIDs)
+	if err != nil {
+		log.Printf("%s: Failed to get participants reliability, pairing without it: %v", utils.GetCurrentTypeName(), err)
+		return unreliability
+	}
 
-			user2 := availableUsers[j]
-			pairKey := fmt.Sprintf("%d-%d", user1.ID, user2.ID)
-
-			// Check if they were paired in recent history
-			if pollIDs, exists := pairHistory[pairKey]; exists && len(pollIDs) > 0 {
-				// Find the oldest poll ID (minimum value) where they were paired
-				oldestPairPoll := pollIDs[0]
-				for _, pollID := range pollIDs {
-					if pollID < oldestPairPoll {
-						oldestPairPoll = pollID
-					}
-				}
+	for userID, reliability := range stats {
+		unreliability[userID] = utils.CoffeeUnreliability(reliability.Confirmed, reliability.NoResponse)
+	}
+	return unreliability
+}
 
-				// If this is older than our current best, update
-				if oldestPairPoll < oldestPoll {
-					oldestPoll = oldestPairPoll
-					bestPartner = j
-				}
-			} else {
-				// Never paired - perfect match
-				bestPartner = j
-				oldestPoll = 0 // Reset to indicate "never paired"
-				break
-			}
+// formatPairReason returns the reason of the match as a suffix of the pair line
+func formatPairReason(pair CoffeePair) string {
+	if pair.Reason == "" {
+		return ""
+	}
+	return fmt.Sprintf(" — <i>%s</i>", pair.Reason)
+}
+
+// createPairsFromShuffled creates pairs from already shuffled participants skipping excluded pairs (fallback method)
+func (s *RandomCoffeeService) createPairsFromShuffled(
+	participants []repositories.User,
+	excluded map[[2]int]bool,
+) ([]CoffeePair, []repositories.User) {
+	usersByID := make(map[int]repositories.User, len(participants))
+	userIDs := make([]int, len(participants))
+	for i, user := range participants {
+		userIDs[i] = user.ID
+		usersByID[user.ID] = user
+	}
+
+	groups, unpairedIDs := utils.PairCoffeeUsersInOrder(userIDs, func(user1ID, user2ID int) bool {
+		return excluded[utils.CoffeePairKey(user1ID, user2ID)]
+	}, s.config.RandomCoffeeAllowTriples)
+
+	var pairs []CoffeePair
+	for _, group := range groups {
+		pair := CoffeePair{User1: usersByID[group[0]], User2: usersByID[group[1]]}
+		if len(group) > 2 {
+			user3 := usersByID[group[2]]
+			pair.User3 = &user3
 		}
+		pairs = append(pairs, pair)
+	}
 
-		// If we found a partner, create the pair
-		if bestPartner >= 0 {
-			user2 := availableUsers[bestPartner]
-			pairs = append(pairs, CoffeePair{User1: user1, User2: user2})
+	var unpaired []repositories.User
+	for _, userID := range unpairedIDs {
+		unpaired = append(unpaired, usersByID[userID])
+	}
 
-			// Log pairing decision
-			if oldestPoll == 0 {
-				log.Printf("%s: Created NEW pair: %s x %s (never paired before)",
-					utils.GetCurrentTypeName(), user1.Firstname, user2.Firstname)
-			} else {
-				log.Printf("%s: Created REPEAT pair: %s x %s (last paired in poll %d)",
-					utils.GetCurrentTypeName(), user1.Firstname, user2.Firstname, oldestPoll)
-			}
+	return pairs, unpaired
+}
 
-			// Save to database
-			if s.pairRepo != nil {
-				u1ID, u2ID := user1.ID, user2.ID
-				if u1ID > u2ID {
-					u1ID, u2ID = u2ID, u1ID
-				}
-				err := s.pairRepo.CreatePair(pollID, u1ID, u2ID)
+// draftMemberIDs returns user IDs of the pair or group of three in ascending order, as draft pairs are stored
+func draftMemberIDs(pair CoffeePair) []int {
+	var userIDs []int
+	for _, member := range pair.Members() {
+		userIDs = append(userIDs, member.ID)
+	}
+	sort.Ints(userIDs)
+	return userIDs
+}
+
+// ErrCoffeeExcludedPair is returned when a change of the draft puts together users who asked not to be paired.
+// The message is deliberately neutral, so admins can't learn exclusion lists by trying swaps.
+var ErrCoffeeExcludedPair = errors.New("обмен нарушает ограничения подбора пар")
+
+// errNoCoffeeDraft is returned when the poll has no draft pairs, e.g. they were already published
+var errNoCoffeeDraft = errors.New("черновик пар не найден — возможно, пары уже опубликованы")
+
+// CoffeeDraft is the pairs of a poll waiting for admin approval
+type CoffeeDraft struct {
+	Poll  *repositories.RandomCoffeePoll
+	Pairs []CoffeeDraftPair
+	// Unpaired are participants who are in no draft pair
+	Unpaired []repositories.User
+	// AutoPublishAt is zero if the draft waits for approval without a timeout
+	AutoPublishAt time.Time
+}
+
+// CoffeeDraftPair is a draft pair or group of three
+type CoffeeDraftPair struct {
+	ID      int
+	Members []repositories.User
+}
+
+// GetDraft returns draft pairs of the poll with the participants left without a pair
+func (s *RandomCoffeeService) GetDraft(pollID int) (*CoffeeDraft, error) {
+	poll, err := s.pollRepo.GetPollByID(int64(pollID))
+	if err != nil {
+		return nil, err
+	}
+	if poll == nil {
+		return nil, fmt.Errorf("%s: poll %d not found", utils.GetCurrentTypeName(), pollID)
+	}
+
+	draftPairs, err := s.pairRepo.GetDraftPairs(pollID)
+	if err != nil {
+		return nil, err
+	}
+	if len(draftPairs) == 0 {
+		return nil, errNoCoffeeDraft
+	}
+
+	participants, err := s.participantRepo.GetParticipatingUsers(poll.ID)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[int]repositories.User, len(participants))
+	for _, participant := range participants {
+		usersByID[participant.ID] = participant
+	}
+
+	draft := &CoffeeDraft{Poll: poll}
+	paired := make(map[int]bool)
+	firstCreatedAt := draftPairs[0].CreatedAt
+	for _, draftPair := range draftPairs {
+		pair := CoffeeDraftPair{ID: draftPair.ID}
+		for _, userID := range draftPair.MemberIDs() {
+			user, ok := usersByID[userID]
+			if !ok {
+				stored, err := s.userRepo.GetByID(userID)
 				if err != nil {
-					log.Printf("%s: failed to save smart pair to DB: %v", utils.GetCurrentTypeName(), err)
+					return nil, err
 				}
+				user = *stored
 			}
+			pair.Members = append(pair.Members, user)
+			paired[userID] = true
+		}
+		draft.Pairs = append(draft.Pairs, pair)
 
-			used[user1.ID] = true
-			used[user2.ID] = true
+		if draftPair.CreatedAt.Before(firstCreatedAt) {
+			firstCreatedAt = draftPair.CreatedAt
 		}
 	}
 
-	// Find any unpaired user
-	var unpaired *repositories.User
-	for _, user := range availableUsers {
-		if !used[user.ID] {
-			unpaired = &user
-			break
+	for _, participant := range participants {
+		if !paired[participant.ID] {
+			draft.Unpaired = append(draft.Unpaired, participant)
 		}
 	}
 
-	return pairs, unpaired, nil
+	if s.isDraftAutoPublishEnabled() {
+		draft.AutoPublishAt = firstCreatedAt.Add(s.config.RandomCoffeePairsApprovalTimeout)
+	}
+	return draft, nil
 }
 
-// createPairsFromShuffled creates pairs from already shuffled participants (fallback method)
-func (s *RandomCoffeeService) createPairsFromShuffled(participants []repositories.User, pollID int) ([]CoffeePair, *repositories.User) {
-	var pairs []CoffeePair
-	var unpaired *repositories.User
+// SendDraftPreview sends the draft to the chat with buttons to edit and publish it
+func (s *RandomCoffeeService) SendDraftPreview(chatID int64, pollID int) error {
+	draft, err := s.GetDraft(pollID)
+	if err != nil {
+		return fmt.Errorf("%s: error getting draft of poll %d: %w", utils.GetCurrentTypeName(), pollID, err)
+	}
 
-	for i := 0; i < len(participants); i += 2 {
-		user1 := participants[i]
+	return s.messageSender.SendHtml(chatID, s.FormatDraft(draft), &gotgbot.SendMessageOpts{
+		ReplyMarkup: buttons.CoffeeDraftButtons(pollID),
+	})
+}
 
-		if i+1 < len(participants) {
-			user2 := participants[i+1]
-			pairs = append(pairs, CoffeePair{User1: user1, User2: user2})
+// FormatDraft formats the draft preview for admins
+func (s *RandomCoffeeService) FormatDraft(draft *CoffeeDraft) string {
+	groups := make([][]repositories.User, 0, len(draft.Pairs))
+	for _, pair := range draft.Pairs {
+		groups = append(groups, pair.Members)
+	}
+	return formatters.FormatCoffeeDraftView(draft.Poll.WeekStartDate, groups, draft.Unpaired, draft.AutoPublishAt, s.config.ClubTimezone)
+}
 
-			if s.pairRepo != nil {
-				u1ID, u2ID := user1.ID, user2.ID
-				if u1ID > u2ID {
-					u1ID, u2ID = u2ID, u1ID
-				}
-				err := s.pairRepo.CreatePair(pollID, u1ID, u2ID)
-				if err != nil {
-					log.Printf("%s: failed to save fallback pair to DB: %v", utils.GetCurrentTypeName(), err)
+// RegenerateDraft matches the participants of the poll again, replacing the current draft
+func (s *RandomCoffeeService) RegenerateDraft(pollID int) error {
+	draft, err := s.GetDraft(pollID)
+	if err != nil {
+		return err
+	}
+
+	participants, err := s.participantRepo.GetParticipatingUsers(draft.Poll.ID)
+	if err != nil {
+		return fmt.Errorf("%s: error getting participants for poll ID %d: %w", utils.GetCurrentTypeName(), pollID, err)
+	}
+	if len(participants) < 2 {
+		return fmt.Errorf("недостаточно участников для создания пар (нужно минимум 2, осталось %d)", len(participants))
+	}
+
+	return s.saveDraftPairs(draft.Poll, participants)
+}
+
+// SwapDraftMembers exchanges places of two participants, a participant without a pair takes the place of the other one
+func (s *RandomCoffeeService) SwapDraftMembers(pollID int, user1ID, user2ID int) error {
+	draft, err := s.GetDraft(pollID)
+	if err != nil {
+		return err
+	}
+
+	pair1, pair2 := findDraftPair(draft, user1ID), findDraftPair(draft, user2ID)
+	if pair1 == nil && pair2 == nil {
+		return fmt.Errorf("%s: users %d and %d are both without a pair", utils.GetCurrentTypeName(), user1ID, user2ID)
+	}
+	if pair1 == pair2 {
+		return nil
+	}
+
+	type draftChange struct {
+		pair    *CoffeeDraftPair
+		userIDs []int
+	}
+	var changes []draftChange
+	var changedUserIDs []int
+	for _, swap := range []struct {
+		pair     *CoffeeDraftPair
+		from, to int
+	}{
+		{pair1, user1ID, user2ID},
+		{pair2, user2ID, user1ID},
+	} {
+		if swap.pair == nil {
+			continue
+		}
+		userIDs := replaceDraftMember(*swap.pair, swap.from, swap.to)
+		changes = append(changes, draftChange{pair: swap.pair, userIDs: userIDs})
+		changedUserIDs = append(changedUserIDs, userIDs...)
+	}
+
+	// Admins don't see exclusion lists, but a swap must not break them
+	excluded, err := s.exclusionRepo.GetExcludedPairs(changedUserIDs)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		for i := range change.userIDs {
+			for j := i + 1; j < len(change.userIDs); j++ {
+				if excluded[utils.CoffeePairKey(change.userIDs[i], change.userIDs[j])] {
+					return ErrCoffeeExcludedPair
 				}
 			}
-		} else {
-			unpaired = &user1
 		}
 	}
 
-	return pairs, unpaired
+	for _, change := range changes {
+		if err := s.pairRepo.UpdateDraftMembers(change.pair.ID, change.userIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExcludeFromDraft removes the participant from this round, a partner left alone stays without a pair
+func (s *RandomCoffeeService) ExcludeFromDraft(pollID int, userID int) error {
+	draft, err := s.GetDraft(pollID)
+	if err != nil {
+		return err
+	}
+
+	err = s.participantRepo.UpsertParticipant(repositories.RandomCoffeeParticipant{
+		PollID:          draft.Poll.ID,
+		UserID:          int64(userID),
+		IsParticipating: false,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: error excluding participant %d: %w", utils.GetCurrentTypeName(), userID, err)
+	}
+
+	pair := findDraftPair(draft, userID)
+	if pair == nil {
+		return nil
+	}
+
+	var remaining []int
+	for _, member := range pair.Members {
+		if member.ID != userID {
+			remaining = append(remaining, member.ID)
+		}
+	}
+	if len(remaining) < 2 {
+		return s.pairRepo.DeleteDraftPair(pair.ID)
+	}
+	sort.Ints(remaining)
+	return s.pairRepo.UpdateDraftMembers(pair.ID, remaining)
+}
+
+// ExcludeFromLatestDraft removes the user from the draft of the latest poll waiting for approval, if there is one
+func (s *RandomCoffeeService) ExcludeFromLatestDraft(userID int) error {
+	latestPoll, err := s.pollRepo.GetLatestPoll()
+	if err != nil || latestPoll == nil {
+		return err
+	}
+
+	draft, err := s.GetDraft(int(latestPoll.ID))
+	if errors.Is(err, errNoCoffeeDraft) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if findDraftPair(draft, userID) == nil && !slices.ContainsFunc(draft.Unpaired, func(user repositories.User) bool {
+		return user.ID == userID
+	}) {
+		return nil
+	}
+	return s.ExcludeFromDraft(int(latestPoll.ID), userID)
+}
+
+// PublishDraft publishes draft pairs of the poll and announces them in the random coffee topic
+func (s *RandomCoffeeService) PublishDraft(ctx context.Context, pollID int) error {
+	draft, err := s.GetDraft(pollID)
+	if err != nil {
+		return err
+	}
+
+	// Pairs are marked as published first, so an approval and the timeout never announce them twice
+	published, err := s.pairRepo.PublishDraftPairs(pollID)
+	if err != nil {
+		return err
+	}
+	if published == 0 {
+		return errNoCoffeeDraft
+	}
+
+	return s.announcePairs(ctx, draft.Poll, s.buildCoffeePairs(draft.Pairs), draft.Unpaired)
+}
+
+// PublishExpiredDrafts publishes drafts that were not approved within the approval timeout
+func (s *RandomCoffeeService) PublishExpiredDrafts(ctx context.Context) error {
+	if !s.isDraftAutoPublishEnabled() {
+		return nil
+	}
+
+	pollIDs, err := s.pairRepo.GetPollIDsWithDraftsCreatedBefore(time.Now().Add(-s.config.RandomCoffeePairsApprovalTimeout))
+	if err != nil {
+		return fmt.Errorf("%s: error getting expired drafts: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	for _, pollID := range pollIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := s.PublishDraft(ctx, pollID); err != nil {
+			log.Printf("%s: Failed to publish expired draft of poll %d: %v", utils.GetCurrentTypeName(), pollID, err)
+			continue
+		}
+
+		log.Printf("%s: Published expired draft of poll %d", utils.GetCurrentTypeName(), pollID)
+		if err := s.messageSender.SendHtml(
+			s.config.AdminUserID,
+			"☕️ Черновик пар Random Coffee не был подтверждён вовремя, поэтому пары опубликованы автоматически.",
+			nil,
+		); err != nil {
+			log.Printf("%s: Failed to notify admin about auto-published pairs: %v", utils.GetCurrentTypeName(), err)
+		}
+	}
+	return nil
+}
+
+// isDraftAutoPublishEnabled reports whether not approved drafts are published automatically
+func (s *RandomCoffeeService) isDraftAutoPublishEnabled() bool {
+	return s.config.RandomCoffeePairsRequireApproval && s.config.RandomCoffeePairsApprovalTimeout > 0
+}
+
+// buildCoffeePairs converts draft pairs into pairs for the announcement, explaining what the members have in common
+func (s *RandomCoffeeService) buildCoffeePairs(draftPairs []CoffeeDraftPair) []CoffeePair {
+	var userIDs []int
+	for _, draftPair := range draftPairs {
+		for _, member := range draftPair.Members {
+			userIDs = append(userIDs, member.ID)
+		}
+	}
+	preferences := s.getMatchingPreferences(userIDs)
+
+	pairs := make([]CoffeePair, 0, len(draftPairs))
+	for _, draftPair := range draftPairs {
+		pair := CoffeePair{ID: draftPair.ID, User1: draftPair.Members[0], User2: draftPair.Members[1]}
+		if len(draftPair.Members) > 2 {
+			user3 := draftPair.Members[2]
+			pair.User3 = &user3
+		}
+
+		members := make([]utils.CoffeePreferences, 0, len(draftPair.Members))
+		for _, member := range draftPair.Members {
+			members = append(members, preferences[member.ID])
+		}
+		pair.Reason = formatters.FormatCoffeeMatchReason(utils.CoffeeGroupOverlap(members...), len(draftPair.Members))
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// findDraftPair returns the draft pair of the user, nil if the user is without a pair
+func findDraftPair(draft *CoffeeDraft, userID int) *CoffeeDraftPair {
+	for i := range draft.Pairs {
+		for _, member := range draft.Pairs[i].Members {
+			if member.ID == userID {
+				return &draft.Pairs[i]
+			}
+		}
+	}
+	return nil
+}
+
+// replaceDraftMember returns member IDs of the pair in ascending order with one member replaced
+func replaceDraftMember(pair CoffeeDraftPair, fromUserID, toUserID int) []int {
+	userIDs := make([]int, 0, len(pair.Members))
+	for _, member := range pair.Members {
+		if member.ID == fromUserID {
+			userIDs = append(userIDs, toUserID)
+			continue
+		}
+		userIDs = append(userIDs, member.ID)
+	}
+	sort.Ints(userIDs)
+	return userIDs
+}
+
+// ErrCoffeeRematchExpired is returned when a new partner is requested for a pair of a past week
+var ErrCoffeeRematchExpired = errors.New("эта пара уже неактуальна — нового партнёра можно подобрать только на текущей неделе")
+
+// ErrCoffeeRematchDone is returned when the user already got a new partner instead of the pair
+var ErrCoffeeRematchDone = errors.New("новый партнёр уже подобран — проверь личные сообщения")
+
+// RequestRematch marks the pair of the user as failed and pairs the user with another participant of the same poll:
+// first somebody who also reported a silent partner, then somebody still without a pair.
+// Returns nil if nobody is available, the request then waits for the next one and the user gets a DM when matched.
+func (s *RandomCoffeeService) RequestRematch(ctx context.Context, pairID int, tgUserID int64) (*repositories.User, error) {
+	poll, rematchPair, err := s.createRematch(pairID, tgUserID)
+	if err != nil || rematchPair == nil {
+		return nil, err
+	}
+
+	// Introductions wait for the LLM, so they are sent without holding the rematch lock
+	s.sendPairIntroductions(ctx, utils.ChatIdToFullChatId(s.config.SuperGroupChatID), poll, []CoffeePair{*rematchPair})
+	return &rematchPair.User2, nil
+}
+
+// createRematch saves the rematch request of the user and returns the supplementary pair with the user as User1,
+// nil if the request waits for a partner
+func (s *RandomCoffeeService) createRematch(pairID int, tgUserID int64) (*repositories.RandomCoffeePoll, *CoffeePair, error) {
+	s.rematchMu.Lock()
+	defer s.rematchMu.Unlock()
+
+	user, err := s.userRepo.GetByTelegramID(tgUserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pair, err := s.pairRepo.GetByID(pairID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pair.IsDraft || !pair.HasMember(user.ID) {
+		return nil, nil, fmt.Errorf("%s: pair %d doesn't belong to user %d", utils.GetCurrentTypeName(), pairID, user.ID)
+	}
+
+	latestPoll, err := s.pollRepo.GetLatestPoll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if latestPoll == nil || int(latestPoll.ID) != pair.PollID {
+		return nil, nil, ErrCoffeeRematchExpired
+	}
+
+	request, err := s.rematchRepo.GetRequest(pair.ID, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if request != nil {
+		if request.RematchPairID.Valid {
+			return nil, nil, ErrCoffeeRematchDone
+		}
+		return nil, nil, nil
+	}
+
+	partnerID, partnerRequest, err := s.findRematchPartner(user.ID, pair)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var partner *repositories.User
+	if partnerID != 0 {
+		partner, err = s.userRepo.GetByID(partnerID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	rematchPairID, err := s.rematchRepo.CreateRequest(repositories.RandomCoffeeRematch{
+		FailedPairID:   pair.ID,
+		UserID:         user.ID,
+		PollID:         pair.PollID,
+		PartnerID:      partnerID,
+		PartnerRequest: partnerRequest,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if partner == nil {
+		log.Printf("%s: No partner for rematch of user %d in poll %d yet", utils.GetCurrentTypeName(), user.ID, pair.PollID)
+		return nil, nil, nil
+	}
+
+	log.Printf("%s: Created supplementary pair %d for users %d and %d in poll %d",
+		utils.GetCurrentTypeName(), rematchPairID, user.ID, partnerID, pair.PollID)
+
+	return latestPoll, &CoffeePair{
+		ID:              rematchPairID,
+		User1:           *user,
+		User2:           *partner,
+		IsSupplementary: true,
+	}, nil
+}
+
+// findRematchPartner returns a new partner for the user instead of the failed pair together with the partner's
+// own waiting request, nil if the partner has no pair at all. Returns zero ID if nobody is available.
+func (s *RandomCoffeeService) findRematchPartner(
+	userID int,
+	failedPair *repositories.RandomCoffeePair,
+) (int, *repositories.RandomCoffeeRematchRequest, error) {
+	pending, err := s.rematchRepo.GetPendingRequests(failedPair.PollID)
+	if err != nil {
+		return 0, nil, err
+	}
+	unpaired, err := s.pairRepo.GetUnpairedParticipantIDs(failedPair.PollID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	userIDs := append([]int{userID}, unpaired...)
+	for _, request := range pending {
+		userIDs = append(userIDs, request.UserID)
+	}
+	excluded, err := s.exclusionRepo.GetExcludedPairs(userIDs)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	allowed := func(candidateID int) bool {
+		return candidateID != userID &&
+			!failedPair.HasMember(candidateID) &&
+			!excluded[utils.CoffeePairKey(userID, candidateID)]
+	}
+
+	for _, request := range pending {
+		if allowed(request.UserID) {
+			return request.UserID, &request, nil
+		}
+	}
+
+	rand.Shuffle(len(unpaired), func(i, j int) { unpaired[i], unpaired[j] = unpaired[j], unpaired[i] })
+	for _, candidateID := range unpaired {
+		if allowed(candidateID) {
+			return candidateID, nil, nil
+		}
+	}
+	return 0, nil, nil
 }
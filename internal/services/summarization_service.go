@@ -17,6 +17,9 @@ import (
 	"github.com/PaulSonOfLars/gotgbot/v2"
 )
 
+// summaryCacheTTL is how long cached on-demand summaries are kept
+const summaryCacheTTL = 7 * 24 * time.Hour
+
 // SummarizationService handles the daily summarization of messages
 type SummarizationService struct {
 	config                      *config.Config
@@ -25,6 +28,9 @@ type SummarizationService struct {
 	groupTopicRepository        *repositories.GroupTopicRepository
 	promptingTemplateRepository *repositories.PromptingTemplateRepository
 	groupMessageRepository      *repositories.GroupMessageRepository
+	summaryCacheRepository      *repositories.SummaryCacheRepository
+	topicSettingsRepository     *repositories.TopicSummarizationSettingsRepository
+	userRepository              *repositories.UserRepository
 }
 
 // NewSummarizationService creates a new summarization service
@@ -35,6 +41,9 @@ func NewSummarizationService(
 	groupTopicRepository *repositories.GroupTopicRepository,
 	promptingTemplateRepository *repositories.PromptingTemplateRepository,
 	groupMessageRepository *repositories.GroupMessageRepository,
+	summaryCacheRepository *repositories.SummaryCacheRepository,
+	topicSettingsRepository *repositories.TopicSummarizationSettingsRepository,
+	userRepository *repositories.UserRepository,
 ) *SummarizationService {
 	return &SummarizationService{
 		config:                      config,
@@ -43,17 +52,28 @@ func NewSummarizationService(
 		groupTopicRepository:        groupTopicRepository,
 		promptingTemplateRepository: promptingTemplateRepository,
 		groupMessageRepository:      groupMessageRepository,
+		summaryCacheRepository:      summaryCacheRepository,
+		topicSettingsRepository:     topicSettingsRepository,
+		userRepository:              userRepository,
 	}
 }
 
-// RunDailySummarization runs the daily summarization process
+// RunDailySummarization runs the summarization for all enabled topics regardless of their schedule
 func (s *SummarizationService) RunDailySummarization(ctx context.Context, sendToDM bool) error {
 	log.Printf("%s: Starting daily summarization process", utils.GetCurrentTypeName())
 
-	// Process each monitored topic
-	for _, topicID := range s.config.MonitoredTopicsIDs {
-		if err := s.summarizeTopicMessages(ctx, topicID, sendToDM); err != nil {
-			log.Printf("%s: Error summarizing topic %d: %v", utils.GetCurrentTypeName(), topicID, err)
+	topicsSettings, err := s.GetTopicsSettings()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get topics settings: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	// Process each enabled topic
+	for _, settings := range topicsSettings {
+		if !settings.Enabled {
+			continue
+		}
+		if err := s.summarizeTopicMessages(ctx, settings, time.Now(), sendToDM); err != nil {
+			log.Printf("%s: Error summarizing topic %d: %v", utils.GetCurrentTypeName(), settings.TopicID, err)
 			// Continue with other chats even if one fails
 			continue
 		}
@@ -63,31 +83,222 @@ func (s *SummarizationService) RunDailySummarization(ctx context.Context, sendTo
 	return nil
 }
 
-// summarizeTopicMessages summarizes a single topic
-func (s *SummarizationService) summarizeTopicMessages(ctx context.Context, topicID int, sendToDM bool) error {
-	// Get topic name
-	topicName := "	Topic name"
+// RunTopicSummarization summarizes the topic for the 24 hours before the scheduled time and publishes it
+// according to its settings
+func (s *SummarizationService) RunTopicSummarization(
+	ctx context.Context,
+	settings repositories.TopicSummarizationSettings,
+	scheduledFor time.Time,
+) error {
+	return s.summarizeTopicMessages(ctx, settings, scheduledFor, false)
+}
+
+// DefaultTopicSettings returns the settings used for a topic without own settings
+func (s *SummarizationService) DefaultTopicSettings(topicID int64) repositories.TopicSummarizationSettings {
+	return repositories.TopicSummarizationSettings{
+		TopicID:            topicID,
+		Enabled:            true,
+		SummaryTime:        s.config.SummaryTime.Format("15:04"),
+		PromptTemplateKey:  prompts.DailySummarizationPromptKey,
+		MinMessages:        1,
+		DestinationTopicID: int64(s.config.SummaryTopicID),
+	}
+}
+
+// GetTopicSettings returns the own settings of the topic or the defaults
+func (s *SummarizationService) GetTopicSettings(topicID int64) (repositories.TopicSummarizationSettings, error) {
+	settings, err := s.topicSettingsRepository.GetByTopicID(topicID)
+	if err != nil {
+		return repositories.TopicSummarizationSettings{}, err
+	}
+	if settings == nil {
+		return s.DefaultTopicSettings(topicID), nil
+	}
+	return *settings, nil
+}
+
+// GetTopicsSettings returns settings for the monitored topics followed by other topics with own settings
+func (s *SummarizationService) GetTopicsSettings() ([]repositories.TopicSummarizationSettings, error) {
+	storedSettings, err := s.topicSettingsRepository.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	storedByTopicID := make(map[int64]repositories.TopicSummarizationSettings, len(storedSettings))
+	for _, settings := range storedSettings {
+		storedByTopicID[settings.TopicID] = settings
+	}
+
+	topicsSettings := make([]repositories.TopicSummarizationSettings, 0, len(s.config.MonitoredTopicsIDs)+len(storedSettings))
+	seen := make(map[int64]bool)
+	for _, topicID := range s.config.MonitoredTopicsIDs {
+		id := int64(topicID)
+		if settings, ok := storedByTopicID[id]; ok {
+			topicsSettings = append(topicsSettings, settings)
+		} else {
+			topicsSettings = append(topicsSettings, s.DefaultTopicSettings(id))
+		}
+		seen[id] = true
+	}
+	for _, settings := range storedSettings {
+		if !seen[settings.TopicID] {
+			topicsSettings = append(topicsSettings, settings)
+		}
+	}
+
+	return topicsSettings, nil
+}
+
+// InvalidateTopicSummaries drops cached summaries of the topic, so they are generated again with its current settings
+func (s *SummarizationService) InvalidateTopicSummaries(topicID int64) error {
+	return s.summaryCacheRepository.DeleteByTopicID(topicID)
+}
+
+// SetHideInSummaries sets whether the user may be named in summaries. Cached summaries are dropped,
+// since any of them may name the user
+func (s *SummarizationService) SetHideInSummaries(userID int, hide bool) error {
+	if err := s.userRepository.SetHideInSummaries(userID, hide); err != nil {
+		return err
+	}
+	return s.summaryCacheRepository.DeleteAll()
+}
+
+// CleanSummaryCache removes cached summaries older than summaryCacheTTL
+func (s *SummarizationService) CleanSummaryCache() error {
+	deleted, err := s.summaryCacheRepository.DeleteOlderThan(time.Now().Add(-summaryCacheTTL))
+	if err != nil {
+		return err
+	}
+	log.Printf("%s: Removed %d expired cached summaries", utils.GetCurrentTypeName(), deleted)
+	return nil
+}
+
+// SummarizeTopicForPeriod returns a summary of the topic messages created within the period.
+// The result is cached by the period widened to whole hours, so repeated requests are cheap,
+// and reused only while it covers the same messages.
+// Returns an empty string if there are no messages in the period.
+func (s *SummarizationService) SummarizeTopicForPeriod(ctx context.Context, topicID int, from time.Time, to time.Time) (string, error) {
+	periodFrom := from.UTC().Truncate(time.Hour)
+	periodTo := to.UTC().Truncate(time.Hour)
+	if periodTo.Before(to.UTC()) {
+		periodTo = periodTo.Add(time.Hour)
+	}
+
+	messages, err := s.groupMessageRepository.GetByGroupTopicIDForPeriod(int64(topicID), from.UTC(), to.UTC())
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to get messages: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	if len(messages) == 0 {
+		log.Printf("%s: No messages found for topic %d in period %s - %s", utils.GetCurrentTypeName(), topicID, from.UTC(), to.UTC())
+		return "", nil
+	}
+
+	firstMessageID := messages[0].MessageID
+	lastMessageID := messages[len(messages)-1].MessageID
+
+	cached, err := s.summaryCacheRepository.Get(int64(topicID), periodFrom, periodTo)
+	if err != nil {
+		log.Printf("%s: Error getting cached summary: %v", utils.GetCurrentTypeName(), err)
+	} else if cached != nil && cached.FirstMessageID == firstMessageID && cached.LastMessageID == lastMessageID {
+		log.Printf("%s: Using cached summary for topic %d", utils.GetCurrentTypeName(), topicID)
+		return cached.Summary, nil
+	}
+
+	log.Printf("%s: Found %d messages for topic %d", utils.GetCurrentTypeName(), len(messages), topicID)
+
+	promptTemplateKey := prompts.DailySummarizationPromptKey
+	if settings, err := s.GetTopicSettings(int64(topicID)); err != nil {
+		log.Printf("%s: Error getting topic settings, using default prompt: %v", utils.GetCurrentTypeName(), err)
+	} else {
+		promptTemplateKey = settings.PromptTemplateKey
+	}
+
+	summary, err := s.generateSummary(ctx, topicID, promptTemplateKey, messages)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.summaryCacheRepository.Upsert(int64(topicID), periodFrom, periodTo, firstMessageID, lastMessageID, summary); err != nil {
+		log.Printf("%s: Error caching summary: %v", utils.GetCurrentTypeName(), err)
+	}
+
+	return summary, nil
+}
+
+// GetTopicName returns the stored name of the topic or a placeholder if it is unknown
+func (s *SummarizationService) GetTopicName(topicID int) string {
 	groupTopic, err := s.groupTopicRepository.GetGroupTopicByTopicID(int64(topicID))
 	if err != nil {
 		log.Printf("%s: failed to get topic name: %v", utils.GetCurrentTypeName(), err)
-	} else {
-		topicName = groupTopic.Name
+		return "	Topic name"
 	}
+	return groupTopic.Name
+}
+
+// summarizeTopicMessages summarizes a single topic for the 24 hours before periodTo
+func (s *SummarizationService) summarizeTopicMessages(
+	ctx context.Context,
+	settings repositories.TopicSummarizationSettings,
+	periodTo time.Time,
+	sendToDM bool,
+) error {
+	topicID := int(settings.TopicID)
+	topicName := s.GetTopicName(topicID)
 
-	// Get messages directly from Telegram with retry logic for rate limiting
-	var messages []*repositories.GroupMessage
-	messages, err = s.groupMessageRepository.GetByGroupTopicIdForpreviousTwentyFourHours(int64(topicID))
+	periodFrom := periodTo.Add(-24 * time.Hour)
+	messages, err := s.groupMessageRepository.GetByGroupTopicIDForPeriod(settings.TopicID, periodFrom.UTC(), periodTo.UTC())
 	if err != nil {
 		return fmt.Errorf("%s: failed to get messages: %w", utils.GetCurrentTypeName(), err)
 	}
 
-	if len(messages) == 0 {
-		log.Printf("%s: No messages found for topic %d", utils.GetCurrentTypeName(), topicID)
+	if len(messages) == 0 || len(messages) < settings.MinMessages {
+		log.Printf("%s: Not enough messages found for topic %d: %d (min %d)",
+			utils.GetCurrentTypeName(), topicID, len(messages), settings.MinMessages)
 		return nil
 	}
 
 	log.Printf("%s: Found %d messages for topic %d", utils.GetCurrentTypeName(), len(messages), topicID)
 
+	summary, err := s.generateSummary(ctx, topicID, settings.PromptTemplateKey, messages)
+	if err != nil {
+		return err
+	}
+
+	// Format the final summary message using the title format from the prompts package
+	periodDate := periodTo.In(s.config.ClubTimezone).Format("02.01.2006")
+	title := fmt.Sprintf("📋 Сводка чата <b>\"%s\"</b> за %s", topicName, periodDate)
+	finalSummary := fmt.Sprintf("%s\n\n%s", title, summary)
+
+	// Determine the target chat ID and options with destination topic ID
+	var targetChatID int64 = utils.ChatIdToFullChatId(int64(s.config.SuperGroupChatID))
+	var opts *gotgbot.SendMessageOpts = &gotgbot.SendMessageOpts{
+		MessageThreadId: settings.DestinationTopicID,
+	}
+	if sendToDM {
+		// If sendToDM is true, try to get the user ID from context
+		if userID, ok := ctx.Value("userID").(int64); ok {
+			targetChatID = userID
+			opts = nil
+		} else {
+			log.Printf("%s: Warning: sendToDM is true but userID not found in context, using destination topic instead", utils.GetCurrentTypeName())
+		}
+	}
+
+	// Send the summary to the target chat, a failed publish fails the run, so it is retried
+	if err := s.messageSenderService.SendHtml(targetChatID, finalSummary, opts); err != nil {
+		return fmt.Errorf("%s: failed to send summary: %w", utils.GetCurrentTypeName(), err)
+	}
+
+	log.Printf("%s: Summary sent successfully", utils.GetCurrentTypeName())
+	return nil
+}
+
+// generateSummary builds the prompt for the topic messages and asks OpenAI for a summary
+func (s *SummarizationService) generateSummary(ctx context.Context, topicID int, promptTemplateKey string, messages []*repositories.GroupMessage) (string, error) {
+	// Names are added only for users who allow being named in summaries
+	authorNames := s.getAuthorNames(messages)
+
 	// Build context directly from all messages without using RAG
 	context := ""
 	for _, msg := range messages {
@@ -96,19 +307,29 @@ func (s *SummarizationService) summarizeTopicMessages(ctx context.Context, topic
 
 		replyToMessage := ""
 
-		context += fmt.Sprintf("\n---\nMessageID: %d\n%sUserID: user_%d\nTimestamp: %s\nText: %s",
+		author := ""
+		if name, ok := authorNames[msg.UserTgID]; ok {
+			author = fmt.Sprintf("Author: %s\n", name)
+		}
+
+		context += fmt.Sprintf("\n---\nMessageID: %d\n%sUserID: user_%d\n%sTimestamp: %s\nText: %s",
 			msg.MessageID,
 			replyToMessage,
 			msg.UserTgID,
+			author,
 			msgTime.Format("2006-01-02 15:04:05"),
 			msg.MessageText)
 
 	}
 
 	// Get the prompt template from the database with fallback to default
-	templateText, err := s.promptingTemplateRepository.Get(prompts.DailySummarizationPromptKey, prompts.DailySummarizationPromptDefaultValue)
+	defaultTemplateText, ok := prompts.SummarizationPromptDefaultValues[promptTemplateKey]
+	if !ok {
+		defaultTemplateText = prompts.DailySummarizationPromptDefaultValue
+	}
+	templateText, err := s.promptingTemplateRepository.Get(promptTemplateKey, defaultTemplateText)
 	if err != nil {
-		return fmt.Errorf("%s: failed to get prompt template: %w", utils.GetCurrentTypeName(), err)
+		return "", fmt.Errorf("%s: failed to get prompt template: %w", utils.GetCurrentTypeName(), err)
 	}
 
 	superGroupChatIDStr := strconv.Itoa(int(s.config.SuperGroupChatID))
@@ -138,32 +359,28 @@ func (s *SummarizationService) summarizeTopicMessages(ctx context.Context, topic
 
 	summary, err := s.openaiClient.GetCompletion(ctx, prompt)
 	if err != nil {
-		return fmt.Errorf("Summarization Service: failed to generate summary: %w", err)
+		return "", fmt.Errorf("Summarization Service: failed to generate summary: %w", err)
 	}
 
-	// Format the final summary message using the title format from the prompts package
-	dateNowWithMonth := time.Now().Format("02.01.2006")
-	title := fmt.Sprintf("📋 Сводка чата <b>\"%s\"</b> за %s", topicName, dateNowWithMonth)
-	finalSummary := fmt.Sprintf("%s\n\n%s", title, summary)
+	return summary, nil
+}
 
-	// Determine the target chat ID and options with summary topic ID
-	var targetChatID int64 = utils.ChatIdToFullChatId(int64(s.config.SuperGroupChatID))
-	var opts *gotgbot.SendMessageOpts = &gotgbot.SendMessageOpts{
-		MessageThreadId: int64(s.config.SummaryTopicID),
-	}
-	if sendToDM {
-		// If sendToDM is true, try to get the user ID from context
-		if userID, ok := ctx.Value("userID").(int64); ok {
-			targetChatID = userID
-			opts = nil
-		} else {
-			log.Printf("%s: Warning: sendToDM is true but userID not found in context, using SummaryTopicID instead", utils.GetCurrentTypeName())
+// getAuthorNames returns display names of the message authors who allow being named in summaries
+func (s *SummarizationService) getAuthorNames(messages []*repositories.GroupMessage) map[int64]string {
+	tgIDs := make([]int64, 0, len(messages))
+	seen := make(map[int64]bool)
+	for _, msg := range messages {
+		if !seen[msg.UserTgID] {
+			seen[msg.UserTgID] = true
+			tgIDs = append(tgIDs, msg.UserTgID)
 		}
 	}
 
-	// Send the summary to the target chat
-	s.messageSenderService.SendHtml(targetChatID, finalSummary, opts)
-
-	log.Printf("%s: Summary sent successfully", utils.GetCurrentTypeName())
-	return nil
+	names, err := s.userRepository.GetSummaryDisplayNames(tgIDs)
+	if err != nil {
+		// Without names the summary is still useful, so authors just stay anonymous
+		log.Printf("%s: Error getting author names, authors will stay anonymous: %v", utils.GetCurrentTypeName(), err)
+		return map[int64]string{}
+	}
+	return names
 }
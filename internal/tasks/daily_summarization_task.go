@@ -2,98 +2,49 @@ package tasks
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"time"
 
 	"evo-bot-go/internal/config"
 	"evo-bot-go/internal/services"
-	"evo-bot-go/internal/utils"
 )
 
-// DailySummarizationTask handles scheduling of daily summarization tasks
-type DailySummarizationTask struct {
-	config               *config.Config
-	summarizationService *services.SummarizationService
-	stop                 chan struct{}
-}
-
-// NewDailySummarizationTask creates a new daily summarization task
-func NewDailySummarizationTask(config *config.Config, summarizationService *services.SummarizationService) *DailySummarizationTask {
-	return &DailySummarizationTask{
-		config:               config,
-		summarizationService: summarizationService,
-		stop:                 make(chan struct{}),
-	}
-}
-
-// Start starts the daily summarization task
-func (s *DailySummarizationTask) Start() {
-	if !s.config.SummarizationTaskEnabled {
-		log.Printf("%s: Daily summarization task is disabled", utils.GetCurrentTypeName())
-		return
-	}
-	log.Printf("%s: Starting daily summarization task with time %02d:%02d UTC", utils.GetCurrentTypeName(), s.config.SummaryTime.Hour(), s.config.SummaryTime.Minute())
-	go s.run()
-}
-
-// Stop stops the daily summarization task
-func (s *DailySummarizationTask) Stop() {
-	log.Printf("%s: Stopping daily summarization task", utils.GetCurrentTypeName())
-	close(s.stop)
-}
-
-// run runs the daily summarization task
-func (s *DailySummarizationTask) run() {
-	// Calculate time until next run
-	nextRun := s.calculateNextRun()
-	log.Printf("%s: Next summarization scheduled for: %v", utils.GetCurrentTypeName(), nextRun)
-
-	ticker := time.NewTicker(time.Minute) // Check every minute
-	defer ticker.Stop()
+const (
+	// DailySummarizationJobNamePrefix is followed by the topic ID
+	DailySummarizationJobNamePrefix = "daily_summarization_topic_"
 
-	for {
-		select {
-		case <-s.stop:
-			return
-		case now := <-ticker.C:
-			// Check if it's time to run
-			if now.After(nextRun) {
-				log.Printf("%s: Running scheduled summarization", utils.GetCurrentTypeName())
-
-				// Run summarization in a separate goroutine
-				go func() {
-					ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
-					defer cancel()
-
-					// For scheduled tasks, always send to the chat (not to DM)
-					if err := s.summarizationService.RunDailySummarization(ctx, false); err != nil {
-						log.Printf("%s: Error running daily summarization: %v", utils.GetCurrentTypeName(), err)
-					}
-				}()
+	dailySummarizationTimeout = 30 * time.Minute
+	// dailySummarizationJitter spreads LLM requests of topics scheduled at the same time
+	dailySummarizationJitter = time.Minute
+)
 
-				// Calculate next run time
-				nextRun = s.calculateNextRun()
-				log.Printf("%s: Next summarization scheduled for: %v", utils.GetCurrentTypeName(), nextRun)
-			}
+// NewDailySummarizationJobProvider creates a provider of daily summarization jobs, one per topic with its own schedule
+func NewDailySummarizationJobProvider(config *config.Config, summarizationService *services.SummarizationService) JobProvider {
+	return func() ([]Job, error) {
+		topicsSettings, err := summarizationService.GetTopicsSettings()
+		if err != nil {
+			return nil, err
 		}
-	}
-}
 
-// calculateNextRun calculates the next run time
-func (s *DailySummarizationTask) calculateNextRun() time.Time {
-	now := time.Now().UTC()
-
-	// Get the configured hour and minute
-	targetHour := s.config.SummaryTime.Hour()
-	targetMinute := s.config.SummaryTime.Minute()
-
-	// Create a time for today at the target hour and minute
-	targetToday := time.Date(now.Year(), now.Month(), now.Day(), targetHour, targetMinute, 0, 0, now.Location())
+		jobs := make([]Job, 0, len(topicsSettings))
+		for _, settings := range topicsSettings {
+			summaryTime, err := time.Parse("15:04", settings.SummaryTime)
+			if err != nil {
+				return nil, fmt.Errorf("invalid summary time %q of topic %d: %w", settings.SummaryTime, settings.TopicID, err)
+			}
 
-	// If the target time has already passed today, schedule for tomorrow
-	if now.After(targetToday) {
-		targetToday = targetToday.Add(24 * time.Hour)
+			jobs = append(jobs, Job{
+				Name:          fmt.Sprintf("%s%d", DailySummarizationJobNamePrefix, settings.TopicID),
+				Schedule:      CronDaily(summaryTime),
+				Enabled:       config.SummarizationTaskEnabled && settings.Enabled,
+				Jitter:        dailySummarizationJitter,
+				Timeout:       dailySummarizationTimeout,
+				CatchUpWindow: config.SummarizationCatchUpWindow,
+				Run: func(ctx context.Context) error {
+					return summarizationService.RunTopicSummarization(ctx, settings, ScheduledFor(ctx))
+				},
+			})
+		}
+		return jobs, nil
 	}
-
-	return targetToday
 }
@@ -0,0 +1,32 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/services"
+)
+
+const (
+	EventRemindersJobName = "event_reminders"
+
+	// Reminders are checked every 5 minutes, see services.EventReminderCheckInterval
+	eventRemindersSchedule      = "*/5 * * * *"
+	eventRemindersTimeout       = 5 * time.Minute
+	eventRemindersCatchUpWindow = 5 * time.Minute
+)
+
+// NewEventRemindersJob creates the job that reminds about upcoming events
+func NewEventRemindersJob(config *config.Config, eventReminderService *services.EventReminderService) Job {
+	return Job{
+		Name:          EventRemindersJobName,
+		Schedule:      eventRemindersSchedule,
+		Enabled:       config.EventRemindersTaskEnabled,
+		Timeout:       eventRemindersTimeout,
+		CatchUpWindow: eventRemindersCatchUpWindow,
+		Run: func(ctx context.Context) error {
+			return eventReminderService.SendDueReminders(ctx)
+		},
+	}
+}
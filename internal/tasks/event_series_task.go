@@ -0,0 +1,32 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/services"
+)
+
+const (
+	EventSeriesJobName = "event_series"
+
+	// Occurrences are generated services.EventSeriesGenerationHorizon ahead, so a daily run is enough
+	eventSeriesSchedule      = "0 3 * * *"
+	eventSeriesTimeout       = 5 * time.Minute
+	eventSeriesCatchUpWindow = 24 * time.Hour
+)
+
+// NewEventSeriesJob creates the job that generates upcoming occurrences of recurring events
+func NewEventSeriesJob(config *config.Config, eventSeriesService *services.EventSeriesService) Job {
+	return Job{
+		Name:          EventSeriesJobName,
+		Schedule:      eventSeriesSchedule,
+		Enabled:       config.EventSeriesTaskEnabled,
+		Timeout:       eventSeriesTimeout,
+		CatchUpWindow: eventSeriesCatchUpWindow,
+		Run: func(ctx context.Context) error {
+			return eventSeriesService.GenerateOccurrences(ctx)
+		},
+	}
+}
@@ -0,0 +1,32 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/services"
+)
+
+const (
+	EventStatusJobName = "event_status"
+
+	// Statuses are updated every 5 minutes, so an event goes live at most that late
+	eventStatusSchedule      = "*/5 * * * *"
+	eventStatusTimeout       = time.Minute
+	eventStatusCatchUpWindow = 5 * time.Minute
+)
+
+// NewEventStatusJob creates the job that moves events to live and finished by time
+func NewEventStatusJob(config *config.Config, eventLifecycleService *services.EventLifecycleService) Job {
+	return Job{
+		Name:          EventStatusJobName,
+		Schedule:      eventStatusSchedule,
+		Enabled:       config.EventStatusTaskEnabled,
+		Timeout:       eventStatusTimeout,
+		CatchUpWindow: eventStatusCatchUpWindow,
+		Run: func(ctx context.Context) error {
+			return eventLifecycleService.UpdateStatuses(ctx)
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"evo-bot-go/internal/config"
+	"evo-bot-go/internal/services"
+)
+
+const (
+	RandomCoffeeFeedbackJobName = "random_coffee_feedback"
+
+	randomCoffeeFeedbackTimeout       = 10 * time.Minute
+	randomCoffeeFeedbackCatchUpWindow = 6 * time.Hour
+)
+
+// NewRandomCoffeeFeedbackJob creates the weekly job that asks random coffee pairs whether they met
+func NewRandomCoffeeFeedbackJob(config *config.Config, feedbackService *services.RandomCoffeeFeedbackService) Job {
+	return Job{
+		Name:          RandomCoffeeFeedbackJobName,
+		Schedule:      CronWeekly(config.RandomCoffeeFeedbackDay, config.RandomCoffeeFeedbackTime),
+		Enabled:       config.RandomCoffeeFeedbackTaskEnabled,
+		Timeout:       randomCoffeeFeedbackTimeout,
+		CatchUpWindow: randomCoffeeFeedbackCatchUpWindow,
+		Run: func(ctx context.Context) error {
+			return feedbackService.SendFeedbackRequests(ctx)
+		},
+	}
+}
@@ -0,0 +1,459 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/utils"
+)
+
+// Job is a periodic unit of work run by the Scheduler
+type Job struct {
+	// Name identifies the job in logs, locks and the task_runs history, must be unique
+	Name string
+	// Schedule is a 5-field cron expression evaluated in the scheduler location
+	Schedule string
+	// Enabled jobs are scheduled, disabled ones are only kept for visibility
+	Enabled bool
+	// Jitter delays every run by a random duration up to this value
+	Jitter time.Duration
+	// Timeout limits a single run, zero means no limit
+	Timeout time.Duration
+	// CatchUpWindow allows running a run missed during downtime, if it was scheduled no longer ago than this
+	CatchUpWindow time.Duration
+	// Run does the work
+	Run func(ctx context.Context) error
+}
+
+// JobProvider returns jobs whose set can change at runtime, e.g. per-topic jobs built from settings in the database.
+// It is called on every scheduler tick.
+type JobProvider func() ([]Job, error)
+
+// JobInfo describes the current state of a scheduled job
+type JobInfo struct {
+	Name     string
+	Schedule string
+	Enabled  bool
+	Paused   bool
+	Running  bool
+	// NextRun is zero if the schedule has no future occurrences
+	NextRun time.Time
+	// SkipNext is true if the next occurrence will be skipped
+	SkipNext bool
+}
+
+// consumedSkip is a skip flag cleared in memory that still has to be cleared in the database
+type consumedSkip struct {
+	name         string
+	scheduledFor time.Time
+	skipped      bool
+}
+
+type scheduledJob struct {
+	job         Job
+	schedule    *utils.CronSchedule
+	provided    bool
+	lastChecked time.Time
+	running     bool
+}
+
+// Scheduler runs registered jobs by their cron schedules.
+// Runs of the same job never overlap, and every run goes through the TaskRunner,
+// so it is recorded in the shared history and executed once across bot instances.
+type Scheduler struct {
+	taskRunner         *TaskRunner
+	jobStateRepository *repositories.ScheduledJobStateRepository
+	location           *time.Location
+	providers          []JobProvider
+	jobs               map[string]*scheduledJob
+	// states hold pause and skip flags set by admins, shared between bot instances via the database
+	states   map[string]repositories.ScheduledJobState
+	mu       sync.Mutex
+	stop     chan struct{}
+	stopOnce sync.Once
+	started  bool
+}
+
+// NewScheduler creates a new scheduler evaluating schedules in the given location
+func NewScheduler(
+	taskRunner *TaskRunner,
+	jobStateRepository *repositories.ScheduledJobStateRepository,
+	location *time.Location,
+) *Scheduler {
+	return &Scheduler{
+		taskRunner:         taskRunner,
+		jobStateRepository: jobStateRepository,
+		location:           location,
+		jobs:               make(map[string]*scheduledJob),
+		states:             make(map[string]repositories.ScheduledJobState),
+		stop:               make(chan struct{}),
+	}
+}
+
+// Register adds a job with a fixed definition. Must be called before Start.
+func (s *Scheduler) Register(job Job) error {
+	schedule, err := utils.ParseCronExpression(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("%s: invalid schedule of job %s: %w", utils.GetCurrentTypeName(), job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("%s: job %s is already registered", utils.GetCurrentTypeName(), job.Name)
+	}
+	s.jobs[job.Name] = &scheduledJob{job: job, schedule: schedule}
+	return nil
+}
+
+// RegisterProvider adds a source of jobs that is re-read on every tick. Must be called before Start.
+func (s *Scheduler) RegisterProvider(provider JobProvider) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providers = append(s.providers, provider)
+}
+
+// Start starts the scheduler
+func (s *Scheduler) Start() {
+	log.Printf("%s: Starting scheduler", utils.GetCurrentTypeName())
+	go s.run()
+}
+
+// Stop stops the scheduler, runs in progress are finished by their timeouts. Safe to call more than once.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		log.Printf("%s: Stopping scheduler", utils.GetCurrentTypeName())
+		close(s.stop)
+	})
+}
+
+// run checks the schedules every minute
+func (s *Scheduler) run() {
+	s.tick(s.now())
+
+	ticker := time.NewTicker(time.Minute) // Check every minute
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.tick(now.In(s.location))
+		}
+	}
+}
+
+// tick refreshes provided jobs and launches the ones that are due
+func (s *Scheduler) tick(now time.Time) {
+	providedJobs := s.collectProvidedJobs()
+	states, statesErr := s.jobStateRepository.GetAll()
+	if statesErr != nil {
+		log.Printf("%s: Error getting job states, using the previous ones: %v", utils.GetCurrentTypeName(), statesErr)
+	}
+
+	s.mu.Lock()
+	if providedJobs != nil {
+		s.syncProvidedJobs(providedJobs, now)
+	}
+	if statesErr == nil {
+		s.states = make(map[string]repositories.ScheduledJobState, len(states))
+		for _, state := range states {
+			s.states[state.JobName] = state
+		}
+	}
+
+	if !s.started {
+		// Start from the past, so runs missed during downtime are caught up within the window
+		for _, sj := range s.jobs {
+			sj.lastChecked = now.Add(-sj.job.CatchUpWindow)
+		}
+		s.started = true
+		log.Printf("%s: Scheduler started with %d jobs", utils.GetCurrentTypeName(), len(s.jobs))
+	}
+
+	var consumedSkips []consumedSkip
+	for name, sj := range s.jobs {
+		scheduledFor := s.latestDueTime(sj, now)
+		sj.lastChecked = now
+		if scheduledFor.IsZero() || !sj.job.Enabled || s.states[name].Paused {
+			continue
+		}
+		if consumed, ok := s.consumeSkip(name, scheduledFor); ok {
+			consumedSkips = append(consumedSkips, consumed)
+			if consumed.skipped {
+				continue
+			}
+		}
+		s.launch(sj, scheduledFor, true)
+	}
+	s.mu.Unlock()
+
+	// The database is written outside of the lock, so a slow query doesn't block admin commands and finishing runs
+	for _, consumed := range consumedSkips {
+		s.persistConsumedSkip(consumed)
+	}
+}
+
+// consumeSkip clears the skip flag in memory once its occurrence is reached and reports whether this run must be skipped.
+// Returns false if there was no flag to clear. Must be called with the lock held.
+func (s *Scheduler) consumeSkip(name string, scheduledFor time.Time) (consumedSkip, bool) {
+	state := s.states[name]
+	if !state.SkipScheduledFor.Valid || state.SkipScheduledFor.Time.After(scheduledFor) {
+		return consumedSkip{}, false
+	}
+
+	skipped := state.SkipScheduledFor.Time.Equal(scheduledFor)
+	state.SkipScheduledFor = sql.NullTime{}
+	s.states[name] = state
+	return consumedSkip{name: name, scheduledFor: scheduledFor, skipped: skipped}, true
+}
+
+// persistConsumedSkip clears the consumed skip flag in the database and records the skipped run.
+// Must be called without the lock held.
+func (s *Scheduler) persistConsumedSkip(consumed consumedSkip) {
+	if err := s.jobStateRepository.SetSkipScheduledFor(consumed.name, sql.NullTime{}); err != nil {
+		log.Printf("%s: Failed to clear skip flag of job %s: %v", utils.GetCurrentTypeName(), consumed.name, err)
+	}
+
+	if consumed.skipped {
+		log.Printf("%s: Skipping run of job %s for %v as requested",
+			utils.GetCurrentTypeName(), consumed.name, consumed.scheduledFor)
+		if err := s.taskRunner.RecordSkipped(consumed.name, consumed.scheduledFor); err != nil {
+			log.Printf("%s: Failed to record skipped run of job %s: %v", utils.GetCurrentTypeName(), consumed.name, err)
+		}
+	}
+}
+
+// Jobs returns the state of all known jobs sorted by name
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	jobs := make([]JobInfo, 0, len(s.jobs))
+	for name, sj := range s.jobs {
+		state := s.states[name]
+		nextRun := sj.schedule.Next(now)
+		jobs = append(jobs, JobInfo{
+			Name:     name,
+			Schedule: sj.schedule.String(),
+			Enabled:  sj.job.Enabled,
+			Paused:   state.Paused,
+			Running:  sj.running,
+			NextRun:  nextRun,
+			SkipNext: state.SkipScheduledFor.Valid && state.SkipScheduledFor.Time.Equal(nextRun),
+		})
+	}
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// Job returns the state of the job by name
+func (s *Scheduler) Job(name string) (JobInfo, bool) {
+	for _, job := range s.Jobs() {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return JobInfo{}, false
+}
+
+// RunNow starts the job immediately, regardless of its schedule, pause and enabled state
+func (s *Scheduler) RunNow(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sj, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("%s: job %s not found", utils.GetCurrentTypeName(), name)
+	}
+	if sj.running {
+		return fmt.Errorf("%s: job %s is already running", utils.GetCurrentTypeName(), name)
+	}
+
+	// Manual runs use the current time, so they never collide with scheduled ones in the history
+	s.launch(sj, s.now(), false)
+	return nil
+}
+
+// SetPaused pauses or resumes scheduled runs of the job
+func (s *Scheduler) SetPaused(name string, paused bool) error {
+	s.mu.Lock()
+	_, ok := s.jobs[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%s: job %s not found", utils.GetCurrentTypeName(), name)
+	}
+	if err := s.jobStateRepository.SetPaused(name, paused); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.states[name]
+	state.JobName = name
+	state.Paused = paused
+	s.states[name] = state
+	return nil
+}
+
+// SetSkipNext marks the next occurrence of the job to be skipped, or removes the mark
+func (s *Scheduler) SetSkipNext(name string, skip bool) error {
+	s.mu.Lock()
+	sj, ok := s.jobs[name]
+	var nextRun time.Time
+	if ok {
+		nextRun = sj.schedule.Next(s.now())
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%s: job %s not found", utils.GetCurrentTypeName(), name)
+	}
+
+	var skipScheduledFor sql.NullTime
+	if skip {
+		if nextRun.IsZero() {
+			return fmt.Errorf("%s: job %s has no next run to skip", utils.GetCurrentTypeName(), name)
+		}
+		skipScheduledFor = sql.NullTime{Time: nextRun, Valid: true}
+	}
+	if err := s.jobStateRepository.SetSkipScheduledFor(name, skipScheduledFor); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.states[name]
+	state.JobName = name
+	state.SkipScheduledFor = skipScheduledFor
+	s.states[name] = state
+	return nil
+}
+
+// collectProvidedJobs calls the providers outside of the lock, returns nil if any of them failed
+func (s *Scheduler) collectProvidedJobs() map[string]Job {
+	s.mu.Lock()
+	providers := append([]JobProvider(nil), s.providers...)
+	s.mu.Unlock()
+
+	jobs := make(map[string]Job)
+	for _, provider := range providers {
+		providedJobs, err := provider()
+		if err != nil {
+			// Keep the previous jobs, a temporary database error shouldn't drop the schedules
+			log.Printf("%s: Error getting jobs from provider: %v", utils.GetCurrentTypeName(), err)
+			return nil
+		}
+		for _, job := range providedJobs {
+			jobs[job.Name] = job
+		}
+	}
+	return jobs
+}
+
+// syncProvidedJobs adds new provided jobs, updates changed ones and removes the ones that are gone.
+// Must be called with the lock held.
+func (s *Scheduler) syncProvidedJobs(providedJobs map[string]Job, now time.Time) {
+	for name, job := range providedJobs {
+		sj, exists := s.jobs[name]
+		if exists && !sj.provided {
+			log.Printf("%s: Provided job %s conflicts with a registered job, ignoring", utils.GetCurrentTypeName(), name)
+			continue
+		}
+
+		schedule, err := utils.ParseCronExpression(job.Schedule)
+		if err != nil {
+			log.Printf("%s: Invalid schedule of job %s: %v", utils.GetCurrentTypeName(), name, err)
+			continue
+		}
+
+		if !exists {
+			// Jobs added after the start aren't caught up, otherwise a newly enabled job would run immediately
+			s.jobs[name] = &scheduledJob{job: job, schedule: schedule, provided: true, lastChecked: now}
+			continue
+		}
+		sj.job = job
+		sj.schedule = schedule
+	}
+
+	for name, sj := range s.jobs {
+		if _, ok := providedJobs[name]; sj.provided && !ok && !sj.running {
+			delete(s.jobs, name)
+		}
+	}
+}
+
+// latestDueTime returns the latest scheduled time in (lastChecked, now], or zero time if none.
+// Several missed times are coalesced into one run.
+func (s *Scheduler) latestDueTime(sj *scheduledJob, now time.Time) time.Time {
+	var due time.Time
+	for next := sj.schedule.Next(sj.lastChecked); !next.IsZero() && !next.After(now); next = sj.schedule.Next(next) {
+		due = next
+	}
+	return due
+}
+
+// launch runs the job in a separate goroutine unless its previous run is still in progress.
+// Must be called with the lock held.
+func (s *Scheduler) launch(sj *scheduledJob, scheduledFor time.Time, withJitter bool) {
+	job := sj.job
+	if sj.running {
+		log.Printf("%s: Previous run of job %s is still in progress, skipping run for %v",
+			utils.GetCurrentTypeName(), job.Name, scheduledFor)
+		return
+	}
+	sj.running = true
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			sj.running = false
+			s.mu.Unlock()
+		}()
+
+		if withJitter && job.Jitter > 0 {
+			select {
+			case <-s.stop:
+				return
+			case <-time.After(time.Duration(rand.Int63n(int64(job.Jitter)))):
+			}
+		}
+
+		ctx, cancel := context.Background(), context.CancelFunc(func() {})
+		if job.Timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		}
+		defer cancel()
+
+		if err := s.taskRunner.Run(ctx, job.Name, scheduledFor, job.Run); err != nil {
+			log.Printf("%s: Error running job %s: %v", utils.GetCurrentTypeName(), job.Name, err)
+		}
+	}()
+}
+
+// now returns the current time in the scheduler location, so cron fields match the local wall clock
+func (s *Scheduler) now() time.Time {
+	return time.Now().In(s.location)
+}
+
+// CronDaily builds a cron expression for every day at the given wall clock time
+func CronDaily(at time.Time) string {
+	return fmt.Sprintf("%d %d * * *", at.Minute(), at.Hour())
+}
+
+// CronWeekly builds a cron expression for the given weekday at the given wall clock time
+func CronWeekly(day time.Weekday, at time.Time) string {
+	return fmt.Sprintf("%d %d * * %d", at.Minute(), at.Hour(), int(day))
+}
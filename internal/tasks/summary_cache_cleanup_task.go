@@ -0,0 +1,31 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"evo-bot-go/internal/services"
+)
+
+const (
+	SummaryCacheCleanupJobName = "summary_cache_cleanup"
+
+	// Cached summaries live for days, so a daily cleanup is enough
+	summaryCacheCleanupSchedule      = "30 4 * * *"
+	summaryCacheCleanupTimeout       = 5 * time.Minute
+	summaryCacheCleanupCatchUpWindow = 24 * time.Hour
+)
+
+// NewSummaryCacheCleanupJob creates the job that removes expired on-demand summaries
+func NewSummaryCacheCleanupJob(summarizationService *services.SummarizationService) Job {
+	return Job{
+		Name:          SummaryCacheCleanupJobName,
+		Schedule:      summaryCacheCleanupSchedule,
+		Enabled:       true,
+		Timeout:       summaryCacheCleanupTimeout,
+		CatchUpWindow: summaryCacheCleanupCatchUpWindow,
+		Run: func(ctx context.Context) error {
+			return summarizationService.CleanSummaryCache()
+		},
+	}
+}
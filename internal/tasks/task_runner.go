@@ -0,0 +1,81 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"evo-bot-go/internal/database/repositories"
+	"evo-bot-go/internal/utils"
+)
+
+// scheduledForContextKey keeps the scheduled time of the run in the context of the task
+type scheduledForContextKey struct{}
+
+// ScheduledFor returns the time the running task was scheduled for, or the current time outside of a task run
+func ScheduledFor(ctx context.Context) time.Time {
+	if scheduledFor, ok := ctx.Value(scheduledForContextKey{}).(time.Time); ok {
+		return scheduledFor
+	}
+	return time.Now()
+}
+
+// TaskRunner executes scheduled runs at most once per task name and scheduled time,
+// even when several bot instances share the database
+type TaskRunner struct {
+	taskRunRepository *repositories.TaskRunRepository
+}
+
+// NewTaskRunner creates a new task runner
+func NewTaskRunner(taskRunRepository *repositories.TaskRunRepository) *TaskRunner {
+	return &TaskRunner{
+		taskRunRepository: taskRunRepository,
+	}
+}
+
+// Run executes fn for the scheduled time unless another instance is running the task
+// or the run for this time has already succeeded. The result is stored in the task_runs table.
+// fn gets the scheduled time with ScheduledFor, e.g. to process a run caught up after downtime for its own period.
+func (r *TaskRunner) Run(ctx context.Context, taskName string, scheduledFor time.Time, fn func(ctx context.Context) error) error {
+	release, acquired, err := r.taskRunRepository.TryLock(ctx, taskName)
+	if err != nil {
+		return fmt.Errorf("%s: failed to lock task %s: %w", utils.GetCurrentTypeName(), taskName, err)
+	}
+	if !acquired {
+		log.Printf("%s: Task %s is already running on another instance, skipping run for %v",
+			utils.GetCurrentTypeName(), taskName, scheduledFor)
+		return nil
+	}
+	defer release()
+
+	run, err := r.taskRunRepository.Start(taskName, scheduledFor)
+	if err != nil {
+		return err
+	}
+	if run == nil {
+		log.Printf("%s: Task %s has already run or was skipped for %v, skipping", utils.GetCurrentTypeName(), taskName, scheduledFor)
+		return nil
+	}
+
+	log.Printf("%s: Running task %s scheduled for %v", utils.GetCurrentTypeName(), taskName, scheduledFor)
+	runErr := fn(context.WithValue(ctx, scheduledForContextKey{}, scheduledFor))
+
+	status := repositories.TaskRunStatusSuccess
+	if runErr != nil {
+		status = repositories.TaskRunStatusFailed
+	}
+	if err := r.taskRunRepository.Finish(run.ID, status, runErr); err != nil {
+		log.Printf("%s: Failed to record result of task %s: %v", utils.GetCurrentTypeName(), taskName, err)
+	}
+
+	if runErr != nil {
+		return fmt.Errorf("%s: task %s failed: %w", utils.GetCurrentTypeName(), taskName, runErr)
+	}
+	return nil
+}
+
+// RecordSkipped stores in the history that the run for the scheduled time was skipped on purpose
+func (r *TaskRunner) RecordSkipped(taskName string, scheduledFor time.Time) error {
+	return r.taskRunRepository.RecordSkipped(taskName, scheduledFor)
+}
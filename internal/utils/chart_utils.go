@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+// ChartBar is a single bar of a bar chart
+type ChartBar struct {
+	// Label is shown under the bar, only digits and ".:/%-" are drawn
+	Label string
+	Value float64
+	// ValueLabel is shown above the bar, with the same restrictions as Label
+	ValueLabel string
+}
+
+const (
+	chartGlyphScale   = 2
+	chartGlyphWidth   = 3 * chartGlyphScale
+	chartGlyphHeight  = 5 * chartGlyphScale
+	chartGlyphAdvance = chartGlyphWidth + chartGlyphScale
+	chartSlotWidth    = 48
+	chartBarWidth     = 32
+	chartMargin       = 16
+	chartPlotHeight   = 200
+	chartTextPadding  = 6
+)
+
+var (
+	chartBackgroundColor = color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	chartBarColor        = color.RGBA{R: 0x4C, G: 0x8B, B: 0xF5, A: 0xFF}
+	chartAxisColor       = color.RGBA{R: 0x9E, G: 0x9E, B: 0x9E, A: 0xFF}
+	chartTextColor       = color.RGBA{R: 0x33, G: 0x33, B: 0x33, A: 0xFF}
+)
+
+// chartGlyphs is a 3x5 bitmap font for the characters used in chart labels
+var chartGlyphs = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", ".#.", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'.': {"...", "...", "...", "...", ".#."},
+	':': {"...", ".#.", "...", ".#.", "..."},
+	'/': {"..#", "..#", ".#.", "#..", "#.."},
+	'-': {"...", "...", "###", "...", "..."},
+	'%': {"#.#", "..#", ".#.", "#..", "#.#"},
+}
+
+// RenderBarChartPNG draws a bar chart with value labels above the bars and labels under them
+func RenderBarChartPNG(bars []ChartBar) ([]byte, error) {
+	if len(bars) == 0 {
+		return nil, errors.New("chart has no bars")
+	}
+
+	maxValue := 0.0
+	for _, bar := range bars {
+		if bar.Value < 0 {
+			return nil, errors.New("chart values must not be negative")
+		}
+		if bar.Value > maxValue {
+			maxValue = bar.Value
+		}
+	}
+
+	textHeight := chartGlyphHeight + 2*chartTextPadding
+	width := 2*chartMargin + len(bars)*chartSlotWidth
+	height := 2*chartMargin + 2*textHeight + chartPlotHeight
+	baseline := chartMargin + textHeight + chartPlotHeight
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: chartBackgroundColor}, image.Point{}, draw.Src)
+
+	for i, bar := range bars {
+		slotX := chartMargin + i*chartSlotWidth
+		barX := slotX + (chartSlotWidth-chartBarWidth)/2
+
+		barHeight := 0
+		if maxValue > 0 {
+			barHeight = int(bar.Value / maxValue * chartPlotHeight)
+		}
+		barRect := image.Rect(barX, baseline-barHeight, barX+chartBarWidth, baseline)
+		draw.Draw(img, barRect, &image.Uniform{C: chartBarColor}, image.Point{}, draw.Src)
+
+		drawChartText(img, bar.ValueLabel, slotX+chartSlotWidth/2, baseline-barHeight-chartTextPadding-chartGlyphHeight)
+		drawChartText(img, bar.Label, slotX+chartSlotWidth/2, baseline+chartTextPadding)
+	}
+
+	axisRect := image.Rect(chartMargin, baseline, width-chartMargin, baseline+1)
+	draw.Draw(img, axisRect, &image.Uniform{C: chartAxisColor}, image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawChartText draws the text centered horizontally at centerX with the top edge at top
+func drawChartText(img *image.RGBA, text string, centerX, top int) {
+	runes := []rune(text)
+	x := centerX - (len(runes)*chartGlyphAdvance-chartGlyphScale)/2
+
+	for _, r := range runes {
+		if glyph, ok := chartGlyphs[r]; ok {
+			for row, line := range glyph {
+				for col, pixel := range line {
+					if pixel != '#' {
+						continue
+					}
+					dotX := x + col*chartGlyphScale
+					dotY := top + row*chartGlyphScale
+					dot := image.Rect(dotX, dotY, dotX+chartGlyphScale, dotY+chartGlyphScale)
+					draw.Draw(img, dot, &image.Uniform{C: chartTextColor}, image.Point{}, draw.Src)
+				}
+			}
+		}
+		x += chartGlyphAdvance
+	}
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderBarChartPNG(t *testing.T) {
+	bars := []ChartBar{
+		{Label: "01.09", Value: 4, ValueLabel: "4"},
+		{Label: "08.09", Value: 8, ValueLabel: "8"},
+		{Label: "15.09", Value: 0, ValueLabel: "0"},
+	}
+
+	data, err := RenderBarChartPNG(bars)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	bounds := img.Bounds()
+	assert.Equal(t, 2*chartMargin+len(bars)*chartSlotWidth, bounds.Dx())
+
+	baseline := chartMargin + chartGlyphHeight + 2*chartTextPadding + chartPlotHeight
+	barCenterX := func(i int) int { return chartMargin + i*chartSlotWidth + chartSlotWidth/2 }
+	isBar := func(x, y int) bool {
+		r, g, b, _ := img.At(x, y).RGBA()
+		br, bg, bb, _ := chartBarColor.RGBA()
+		return r == br && g == bg && b == bb
+	}
+
+	// The highest bar fills the whole plot, the half-sized one only the lower half
+	assert.True(t, isBar(barCenterX(1), baseline-chartPlotHeight+1))
+	assert.False(t, isBar(barCenterX(0), baseline-chartPlotHeight+1))
+	assert.True(t, isBar(barCenterX(0), baseline-chartPlotHeight/2+1))
+	// A zero value has no bar
+	assert.False(t, isBar(barCenterX(2), baseline-1))
+}
+
+func TestRenderBarChartPNG_AllZero(t *testing.T) {
+	data, err := RenderBarChartPNG([]ChartBar{{Label: "1", Value: 0}})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, data)
+}
+
+func TestRenderBarChartPNG_InvalidInput(t *testing.T) {
+	_, err := RenderBarChartPNG(nil)
+	assert.Error(t, err)
+
+	_, err = RenderBarChartPNG([]ChartBar{{Value: -1}})
+	assert.Error(t, err)
+}
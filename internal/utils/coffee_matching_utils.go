@@ -0,0 +1,249 @@
+package utils
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// CoffeePairHistory is a past meeting of two random coffee participants
+type CoffeePairHistory struct {
+	User1ID int
+	User2ID int
+	// WeeksAgo is how many weeks before the current round the pair met
+	WeeksAgo float64
+}
+
+// CoffeeMatchingOptions configures the soft constraints of random coffee matching
+type CoffeeMatchingOptions struct {
+	// RepeatPenalty is the cost of repeating a pair that met in the current round, it decays with time
+	RepeatPenalty float64
+	// HalfLifeWeeks is the number of weeks after which the repeat penalty halves, zero disables the decay
+	HalfLifeWeeks float64
+	// Seed makes the result deterministic: the same participants, history and seed give the same pairs
+	Seed int64
+	// PairCost returns an additional cost of pairing two users, math.Inf(1) forbids the pair. Optional.
+	// In a group of three the costs of all member combinations are summed up.
+	PairCost func(user1ID, user2ID int) float64
+	// AllowTriple forms one group of three instead of leaving a user unpaired when the number of users is odd
+	AllowTriple bool
+}
+
+// CoffeePairKey returns a key of the pair that doesn't depend on the order of users
+func CoffeePairKey(user1ID, user2ID int) [2]int {
+	if user1ID > user2ID {
+		user1ID, user2ID = user2ID, user1ID
+	}
+	return [2]int{user1ID, user2ID}
+}
+
+// CoffeeRepeatCost returns the decayed cost of all past meetings of the pair
+func CoffeeRepeatCost(meetingsWeeksAgo []float64, options CoffeeMatchingOptions) float64 {
+	var cost float64
+	for _, weeksAgo := range meetingsWeeksAgo {
+		decay := 1.0
+		if options.HalfLifeWeeks > 0 {
+			decay = math.Exp2(-math.Max(weeksAgo, 0) / options.HalfLifeWeeks)
+		}
+		cost += options.RepeatPenalty * decay
+	}
+	return cost
+}
+
+// MatchCoffeeGroups splits users into pairs with the minimum total cost of repeats and soft constraints.
+// Users stay unpaired when their number is odd or forbidden pairs make a full matching impossible,
+// with options.AllowTriple one of them joins the pair where the group of three costs least instead.
+// Ties are broken by a shuffle seeded with options.Seed.
+func MatchCoffeeGroups(userIDs []int, history []CoffeePairHistory, options CoffeeMatchingOptions) ([][]int, []int) {
+	users := make([]int, len(userIDs))
+	copy(users, userIDs)
+	sort.Ints(users)
+	r := rand.New(rand.NewSource(options.Seed))
+	r.Shuffle(len(users), func(i, j int) {
+		users[i], users[j] = users[j], users[i]
+	})
+
+	meetings := make(map[[2]int][]float64)
+	for _, h := range history {
+		key := CoffeePairKey(h.User1ID, h.User2ID)
+		meetings[key] = append(meetings[key], h.WeeksAgo)
+	}
+
+	n := len(users)
+	costs := make([][]float64, n)
+	for i := range costs {
+		costs[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			cost := CoffeeRepeatCost(meetings[CoffeePairKey(users[i], users[j])], options)
+			if options.PairCost != nil {
+				cost += options.PairCost(users[i], users[j])
+			}
+			costs[i][j], costs[j][i] = cost, cost
+		}
+	}
+
+	groups, unpaired := matchPairs(costs, allIndexes(n))
+	if options.AllowTriple && len(unpaired) > 0 && n >= 3 {
+		groups, unpaired = matchWithTriple(costs, groups, unpaired)
+	}
+
+	for _, group := range groups {
+		for k := range group {
+			group[k] = users[group[k]]
+		}
+	}
+	for k := range unpaired {
+		unpaired[k] = users[unpaired[k]]
+	}
+	return groups, unpaired
+}
+
+// matchPairs finds the cheapest pairs among the given vertexes, returns groups and unpaired ones as vertex indexes
+func matchPairs(costs [][]float64, vertexes []int) ([][]int, []int) {
+	// With an odd number of vertexes a dummy vertex with zero costs picks the one left without a pair
+	n := len(vertexes)
+	size := n
+	if n%2 == 1 {
+		size++
+	}
+	subCosts := make([][]float64, size)
+	for i := range subCosts {
+		subCosts[i] = make([]float64, size)
+		for j := 0; j < n && i < n; j++ {
+			subCosts[i][j] = costs[vertexes[i]][vertexes[j]]
+		}
+	}
+
+	mate := MinCostMatching(subCosts)
+
+	var groups [][]int
+	var unpaired []int
+	for i := 0; i < n; i++ {
+		switch {
+		case mate[i] == -1 || mate[i] >= n:
+			unpaired = append(unpaired, vertexes[i])
+		case i < mate[i]:
+			groups = append(groups, []int{vertexes[i], vertexes[mate[i]]})
+		}
+	}
+	return groups, unpaired
+}
+
+// matchWithTriple tries every vertex as the third member of a group: the rest is matched into pairs
+// and the vertex joins the pair where it adds the least cost. Returns the option with the fewest unpaired
+// vertexes and the least cost, or the given pairs when no triple makes it better.
+func matchWithTriple(costs [][]float64, pairs [][]int, pairsUnpaired []int) ([][]int, []int) {
+	n := len(costs)
+	bestGroups, bestUnpaired := pairs, pairsUnpaired
+	bestCost := groupsCost(costs, pairs)
+
+	for extra := 0; extra < n; extra++ {
+		rest := make([]int, 0, n-1)
+		for v := 0; v < n; v++ {
+			if v != extra {
+				rest = append(rest, v)
+			}
+		}
+
+		groups, unpaired := matchPairs(costs, rest)
+		bestPair, bestPairCost := -1, math.Inf(1)
+		for k, group := range groups {
+			added := costs[extra][group[0]] + costs[extra][group[1]]
+			if added < bestPairCost {
+				bestPair, bestPairCost = k, added
+			}
+		}
+		// The vertex can't join any pair, e.g. because of forbidden pairs
+		if bestPair == -1 || math.IsInf(bestPairCost, 1) {
+			continue
+		}
+		groups[bestPair] = append(groups[bestPair], extra)
+
+		cost := groupsCost(costs, groups)
+		if len(unpaired) < len(bestUnpaired) || (len(unpaired) == len(bestUnpaired) && cost < bestCost) {
+			bestGroups, bestUnpaired, bestCost = groups, unpaired, cost
+		}
+	}
+
+	return bestGroups, bestUnpaired
+}
+
+// groupsCost sums up the costs of all member combinations of the groups
+func groupsCost(costs [][]float64, groups [][]int) float64 {
+	var cost float64
+	for _, group := range groups {
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				cost += costs[group[i]][group[j]]
+			}
+		}
+	}
+	return cost
+}
+
+func allIndexes(n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+// CoffeeUnreliability estimates how likely the user leaves the partner without a response, from 0 to 1.
+// Users without reports of partners are considered reliable.
+func CoffeeUnreliability(confirmed, noResponse int) float64 {
+	return float64(noResponse) / float64(confirmed+noResponse+2)
+}
+
+// CoffeeReliabilityCost returns the cost of a pair by its less reliable member. Minimizing it leaves the least
+// reliable participant without a pair and pairs unreliable participants with each other rather than with reliable ones.
+func CoffeeReliabilityCost(unreliability1, unreliability2, penalty float64) float64 {
+	return penalty * math.Max(unreliability1, unreliability2)
+}
+
+// PairCoffeeUsersInOrder pairs users in the given order, every user gets the next free user the pair is allowed with.
+// With allowTriple the first user left over joins the last pair allowed for both its members. Users that can't be
+// placed stay unpaired. It is the fallback when the matching by cost fails, so the order is expected to be shuffled.
+func PairCoffeeUsersInOrder(userIDs []int, forbidden func(user1ID, user2ID int) bool, allowTriple bool) ([][]int, []int) {
+	allowed := func(user1ID, user2ID int) bool {
+		return forbidden == nil || !forbidden(user1ID, user2ID)
+	}
+
+	var groups [][]int
+	var unpaired []int
+	taken := make([]bool, len(userIDs))
+	for i, userID := range userIDs {
+		if taken[i] {
+			continue
+		}
+		taken[i] = true
+
+		partner := -1
+		for j := i + 1; j < len(userIDs); j++ {
+			if !taken[j] && allowed(userID, userIDs[j]) {
+				partner = j
+				break
+			}
+		}
+		if partner == -1 {
+			unpaired = append(unpaired, userID)
+			continue
+		}
+		taken[partner] = true
+		groups = append(groups, []int{userID, userIDs[partner]})
+	}
+
+	if !allowTriple || len(unpaired) == 0 {
+		return groups, unpaired
+	}
+
+	for i := len(groups) - 1; i >= 0; i-- {
+		if allowed(unpaired[0], groups[i][0]) && allowed(unpaired[0], groups[i][1]) {
+			groups[i] = append(groups[i], unpaired[0])
+			return groups, unpaired[1:]
+		}
+	}
+	return groups, unpaired
+}
@@ -0,0 +1,377 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pairKeys converts groups to order independent keys of all member combinations for comparisons
+func pairKeys(groups [][]int) map[[2]int]bool {
+	keys := make(map[[2]int]bool, len(groups))
+	for _, group := range groups {
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				keys[CoffeePairKey(group[i], group[j])] = true
+			}
+		}
+	}
+	return keys
+}
+
+// countRepeats returns how many of the pairs met within the given number of weeks
+func countRepeats(pairs [][]int, history []CoffeePairHistory, withinWeeks float64) int {
+	met := make(map[[2]int]bool)
+	for _, h := range history {
+		if h.WeeksAgo <= withinWeeks {
+			met[CoffeePairKey(h.User1ID, h.User2ID)] = true
+		}
+	}
+	repeats := 0
+	for key := range pairKeys(pairs) {
+		if met[key] {
+			repeats++
+		}
+	}
+	return repeats
+}
+
+// roundRobinHistory builds a synthetic history where each week users are paired by the circle method
+func roundRobinHistory(userIDs []int, weeks int) []CoffeePairHistory {
+	var history []CoffeePairHistory
+	n := len(userIDs)
+	circle := make([]int, n)
+	copy(circle, userIDs)
+	for week := 1; week <= weeks; week++ {
+		for i := 0; i < n/2; i++ {
+			history = append(history, CoffeePairHistory{
+				User1ID:  circle[i],
+				User2ID:  circle[n-1-i],
+				WeeksAgo: float64(week),
+			})
+		}
+		// Rotate all but the first user
+		last := circle[n-1]
+		copy(circle[2:], circle[1:n-1])
+		circle[1] = last
+	}
+	return history
+}
+
+func defaultCoffeeMatchingOptions(seed int64) CoffeeMatchingOptions {
+	return CoffeeMatchingOptions{RepeatPenalty: 100, HalfLifeWeeks: 8, Seed: seed}
+}
+
+func TestCoffeeRepeatCost(t *testing.T) {
+	options := CoffeeMatchingOptions{RepeatPenalty: 100, HalfLifeWeeks: 4}
+
+	assert.Equal(t, 0.0, CoffeeRepeatCost(nil, options))
+	assert.InDelta(t, 100.0, CoffeeRepeatCost([]float64{0}, options), 1e-9)
+	assert.InDelta(t, 50.0, CoffeeRepeatCost([]float64{4}, options), 1e-9)
+	assert.InDelta(t, 75.0, CoffeeRepeatCost([]float64{4, 8}, options), 1e-9)
+	assert.Greater(t, CoffeeRepeatCost([]float64{1}, options), CoffeeRepeatCost([]float64{10}, options))
+
+	options.HalfLifeWeeks = 0
+	assert.InDelta(t, 200.0, CoffeeRepeatCost([]float64{1, 50}, options), 1e-9)
+}
+
+func TestMatchCoffeeGroupsWithoutHistory(t *testing.T) {
+	userIDs := []int{1, 2, 3, 4, 5, 6}
+
+	pairs, unpaired := MatchCoffeeGroups(userIDs, nil, defaultCoffeeMatchingOptions(1))
+
+	assert.Len(t, pairs, 3)
+	assert.Empty(t, unpaired)
+	seen := make(map[int]bool)
+	for _, pair := range pairs {
+		assert.Len(t, pair, 2)
+		assert.NotEqual(t, pair[0], pair[1])
+		seen[pair[0]], seen[pair[1]] = true, true
+	}
+	assert.Len(t, seen, len(userIDs))
+}
+
+func TestMatchCoffeeGroupsAvoidsLastWeekPairs(t *testing.T) {
+	userIDs := []int{1, 2, 3, 4}
+	history := []CoffeePairHistory{
+		{User1ID: 1, User2ID: 2, WeeksAgo: 1},
+		{User1ID: 3, User2ID: 4, WeeksAgo: 1},
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		pairs, unpaired := MatchCoffeeGroups(userIDs, history, defaultCoffeeMatchingOptions(seed))
+		assert.Empty(t, unpaired)
+		assert.Equal(t, 0, countRepeats(pairs, history, 1), "seed %d", seed)
+	}
+}
+
+func TestMatchCoffeeGroupsPrefersOlderRepeats(t *testing.T) {
+	// Everybody already met everybody, the oldest meetings must be repeated
+	userIDs := []int{1, 2, 3, 4}
+	history := []CoffeePairHistory{
+		{User1ID: 1, User2ID: 2, WeeksAgo: 1},
+		{User1ID: 3, User2ID: 4, WeeksAgo: 1},
+		{User1ID: 1, User2ID: 3, WeeksAgo: 2},
+		{User1ID: 2, User2ID: 4, WeeksAgo: 2},
+		{User1ID: 1, User2ID: 4, WeeksAgo: 20},
+		{User1ID: 2, User2ID: 3, WeeksAgo: 20},
+	}
+
+	pairs, _ := MatchCoffeeGroups(userIDs, history, defaultCoffeeMatchingOptions(7))
+
+	assert.Equal(t, map[[2]int]bool{{1, 4}: true, {2, 3}: true}, pairKeys(pairs))
+}
+
+func TestMatchCoffeeGroupsCountsAllMeetings(t *testing.T) {
+	// 1-2 met twice long ago, 3-4 once, so 1-2 is more expensive than 3-4 with the same recency
+	userIDs := []int{1, 2, 3, 4}
+	history := []CoffeePairHistory{
+		{User1ID: 1, User2ID: 2, WeeksAgo: 30},
+		{User1ID: 2, User2ID: 1, WeeksAgo: 30},
+		{User1ID: 3, User2ID: 4, WeeksAgo: 30},
+		{User1ID: 1, User2ID: 3, WeeksAgo: 1},
+		{User1ID: 2, User2ID: 4, WeeksAgo: 1},
+		{User1ID: 1, User2ID: 4, WeeksAgo: 1},
+		{User1ID: 2, User2ID: 3, WeeksAgo: 1},
+	}
+
+	pairs, _ := MatchCoffeeGroups(userIDs, history, defaultCoffeeMatchingOptions(3))
+
+	assert.Equal(t, map[[2]int]bool{{1, 2}: true, {3, 4}: true}, pairKeys(pairs))
+}
+
+func TestMatchCoffeeGroupsNoRepeatsWhenPossible(t *testing.T) {
+	// With 10 users and 5 weeks of round robin history there are still 4 unused rounds,
+	// a greedy pass over a shuffled list often leaves the last users with repeats
+	userIDs := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	history := roundRobinHistory(userIDs, 5)
+
+	for seed := int64(0); seed < 50; seed++ {
+		pairs, unpaired := MatchCoffeeGroups(userIDs, history, defaultCoffeeMatchingOptions(seed))
+		assert.Empty(t, unpaired)
+		assert.Len(t, pairs, 5)
+		assert.Equal(t, 0, countRepeats(pairs, history, math.Inf(1)), "seed %d", seed)
+	}
+}
+
+func TestMatchCoffeeGroupsOddNumberOfUsers(t *testing.T) {
+	userIDs := []int{10, 20, 30, 40, 50}
+	history := []CoffeePairHistory{
+		{User1ID: 10, User2ID: 20, WeeksAgo: 1},
+	}
+
+	pairs, unpaired := MatchCoffeeGroups(userIDs, history, defaultCoffeeMatchingOptions(5))
+
+	assert.Len(t, pairs, 2)
+	assert.Len(t, unpaired, 1)
+	assert.Equal(t, 0, countRepeats(pairs, history, 1))
+}
+
+func TestMatchCoffeeGroupsIsDeterministicWithSeed(t *testing.T) {
+	userIDs := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	history := roundRobinHistory(userIDs, 2)
+	shuffledUserIDs := []int{8, 3, 5, 1, 7, 2, 6, 4}
+
+	pairs, unpaired := MatchCoffeeGroups(userIDs, history, defaultCoffeeMatchingOptions(99))
+	samePairs, sameUnpaired := MatchCoffeeGroups(shuffledUserIDs, history, defaultCoffeeMatchingOptions(99))
+
+	assert.Equal(t, pairs, samePairs)
+	assert.Equal(t, unpaired, sameUnpaired)
+
+	differentSeedResults := make(map[string]bool)
+	for seed := int64(0); seed < 20; seed++ {
+		p, _ := MatchCoffeeGroups(userIDs, nil, defaultCoffeeMatchingOptions(seed))
+		differentSeedResults[formatPairsForTest(pairKeys(p))] = true
+	}
+	assert.Greater(t, len(differentSeedResults), 1, "different seeds should give different pairs")
+}
+
+func TestMatchCoffeeGroupsPairCost(t *testing.T) {
+	userIDs := []int{1, 2, 3, 4}
+	options := defaultCoffeeMatchingOptions(11)
+	options.PairCost = func(user1ID, user2ID int) float64 {
+		key := CoffeePairKey(user1ID, user2ID)
+		if key == [2]int{1, 2} || key == [2]int{3, 4} {
+			return 0
+		}
+		return 10
+	}
+
+	pairs, _ := MatchCoffeeGroups(userIDs, nil, options)
+	assert.Equal(t, map[[2]int]bool{{1, 2}: true, {3, 4}: true}, pairKeys(pairs))
+}
+
+func TestMatchCoffeeGroupsForbiddenPairs(t *testing.T) {
+	userIDs := []int{1, 2, 3, 4}
+	options := defaultCoffeeMatchingOptions(0)
+	// User 1 can only be paired with user 2, which leaves user 3 with user 4
+	options.PairCost = func(user1ID, user2ID int) float64 {
+		key := CoffeePairKey(user1ID, user2ID)
+		if key[0] == 1 && key[1] != 2 {
+			return math.Inf(1)
+		}
+		return 0
+	}
+	history := []CoffeePairHistory{{User1ID: 1, User2ID: 2, WeeksAgo: 1}}
+
+	pairs, unpaired := MatchCoffeeGroups(userIDs, history, options)
+	assert.Empty(t, unpaired)
+	assert.Equal(t, map[[2]int]bool{{1, 2}: true, {3, 4}: true}, pairKeys(pairs))
+
+	// Nobody may meet user 1, so the user stays unpaired
+	options.PairCost = func(user1ID, user2ID int) float64 {
+		if user1ID == 1 || user2ID == 1 {
+			return math.Inf(1)
+		}
+		return 0
+	}
+	pairs, unpaired = MatchCoffeeGroups(userIDs, nil, options)
+	assert.Len(t, pairs, 1)
+	assert.Len(t, unpaired, 2)
+	assert.Contains(t, unpaired, 1)
+}
+
+func TestMatchCoffeeGroupsTriple(t *testing.T) {
+	userIDs := []int{10, 20, 30, 40, 50}
+	options := defaultCoffeeMatchingOptions(5)
+	options.AllowTriple = true
+
+	groups, unpaired := MatchCoffeeGroups(userIDs, nil, options)
+
+	assert.Empty(t, unpaired)
+	assert.Len(t, groups, 2)
+	sizes := []int{len(groups[0]), len(groups[1])}
+	sort.Ints(sizes)
+	assert.Equal(t, []int{2, 3}, sizes)
+
+	// With an even number of users there is no triple
+	groups, unpaired = MatchCoffeeGroups(userIDs[:4], nil, options)
+	assert.Empty(t, unpaired)
+	for _, group := range groups {
+		assert.Len(t, group, 2)
+	}
+}
+
+func TestMatchCoffeeGroupsTripleMinimisesRepeats(t *testing.T) {
+	// Every member combination of the triple counts, so the triple has to avoid all recent pairs
+	userIDs := []int{1, 2, 3, 4, 5}
+	history := []CoffeePairHistory{
+		{User1ID: 1, User2ID: 2, WeeksAgo: 1},
+		{User1ID: 3, User2ID: 4, WeeksAgo: 1},
+		{User1ID: 1, User2ID: 5, WeeksAgo: 1},
+	}
+	options := defaultCoffeeMatchingOptions(0)
+	options.AllowTriple = true
+
+	for seed := int64(0); seed < 20; seed++ {
+		options.Seed = seed
+		groups, unpaired := MatchCoffeeGroups(userIDs, history, options)
+		assert.Empty(t, unpaired)
+		assert.Equal(t, 0, countRepeats(groups, history, 1), "seed %d", seed)
+	}
+}
+
+func TestMatchCoffeeGroupsTripleIsDeterministicWithSeed(t *testing.T) {
+	userIDs := []int{1, 2, 3, 4, 5, 6, 7}
+	history := roundRobinHistory(userIDs[:6], 2)
+	options := defaultCoffeeMatchingOptions(42)
+	options.AllowTriple = true
+
+	groups, unpaired := MatchCoffeeGroups(userIDs, history, options)
+	sameGroups, sameUnpaired := MatchCoffeeGroups([]int{7, 5, 3, 1, 2, 4, 6}, history, options)
+
+	assert.Equal(t, groups, sameGroups)
+	assert.Equal(t, unpaired, sameUnpaired)
+}
+
+func TestMatchCoffeeGroupsTripleWithForbiddenPairs(t *testing.T) {
+	// Nobody may meet user 1, so a triple is formed from the others and user 1 stays unpaired
+	userIDs := []int{1, 2, 3, 4, 5, 6}
+	options := defaultCoffeeMatchingOptions(0)
+	options.AllowTriple = true
+	options.PairCost = func(user1ID, user2ID int) float64 {
+		if user1ID == 1 || user2ID == 1 {
+			return math.Inf(1)
+		}
+		return 0
+	}
+
+	groups, unpaired := MatchCoffeeGroups(userIDs, nil, options)
+	assert.Equal(t, []int{1}, unpaired)
+	assert.Len(t, groups, 2)
+}
+
+func TestCoffeeUnreliability(t *testing.T) {
+	assert.Equal(t, 0.0, CoffeeUnreliability(0, 0))
+	assert.Equal(t, 0.0, CoffeeUnreliability(5, 0))
+	assert.InDelta(t, 1.0/3, CoffeeUnreliability(0, 1), 1e-9)
+	assert.Greater(t, CoffeeUnreliability(0, 3), CoffeeUnreliability(3, 3))
+	assert.Less(t, CoffeeUnreliability(0, 10), 1.0)
+}
+
+func TestMatchCoffeeGroupsPrioritisesReliableUsers(t *testing.T) {
+	unreliability := map[int]float64{1: 0, 2: 0, 3: 0.5, 4: 0.6, 5: 0.9}
+	options := defaultCoffeeMatchingOptions(0)
+	options.PairCost = func(user1ID, user2ID int) float64 {
+		return CoffeeReliabilityCost(unreliability[user1ID], unreliability[user2ID], 50)
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		options.Seed = seed
+		groups, unpaired := MatchCoffeeGroups([]int{1, 2, 3, 4, 5}, nil, options)
+
+		// The least reliable user stays unpaired, reliable users are paired with each other
+		assert.Equal(t, []int{5}, unpaired, "seed %d", seed)
+		assert.Equal(t, map[[2]int]bool{{1, 2}: true, {3, 4}: true}, pairKeys(groups), "seed %d", seed)
+	}
+}
+
+func formatPairsForTest(keys map[[2]int]bool) string {
+	var sorted [][2]int
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+	return fmt.Sprint(sorted)
+}
+
+func TestPairCoffeeUsersInOrder(t *testing.T) {
+	groups, unpaired := PairCoffeeUsersInOrder([]int{4, 1, 3, 2}, nil, false)
+	assert.Equal(t, [][]int{{4, 1}, {3, 2}}, groups)
+	assert.Empty(t, unpaired)
+
+	// An odd user joins the last pair or stays unpaired
+	groups, unpaired = PairCoffeeUsersInOrder([]int{1, 2, 3, 4, 5}, nil, true)
+	assert.Equal(t, [][]int{{1, 2}, {3, 4, 5}}, groups)
+	assert.Empty(t, unpaired)
+
+	groups, unpaired = PairCoffeeUsersInOrder([]int{1, 2, 3, 4, 5}, nil, false)
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}}, groups)
+	assert.Equal(t, []int{5}, unpaired)
+}
+
+func TestPairCoffeeUsersInOrderSkipsForbiddenPairs(t *testing.T) {
+	forbidden := func(user1ID, user2ID int) bool {
+		key := CoffeePairKey(user1ID, user2ID)
+		return key == [2]int{1, 2} || key == [2]int{4, 5}
+	}
+
+	groups, unpaired := PairCoffeeUsersInOrder([]int{1, 2, 3, 4}, forbidden, false)
+	assert.Equal(t, [][]int{{1, 3}, {2, 4}}, groups)
+	assert.Empty(t, unpaired)
+
+	// User 5 may not join the last pair with user 4, so it joins the first one
+	groups, unpaired = PairCoffeeUsersInOrder([]int{1, 3, 2, 4, 5}, forbidden, true)
+	assert.Equal(t, [][]int{{1, 3, 5}, {2, 4}}, groups)
+	assert.Empty(t, unpaired)
+
+	// Nobody may meet user 1
+	groups, unpaired = PairCoffeeUsersInOrder([]int{1, 2, 3}, func(user1ID, user2ID int) bool {
+		return user1ID == 1 || user2ID == 1
+	}, true)
+	assert.Equal(t, [][]int{{2, 3}}, groups)
+	assert.Equal(t, []int{1}, unpaired)
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"evo-bot-go/internal/constants"
+)
+
+const (
+	// MaxCoffeePreferenceItems is the maximum number of languages or interests a participant can declare
+	MaxCoffeePreferenceItems = 10
+	// MaxCoffeePreferenceItemLength is the maximum length of a single language or interest
+	MaxCoffeePreferenceItemLength = 50
+	// MaxCoffeeExclusions is the maximum number of users a participant can ask not to be paired with
+	MaxCoffeeExclusions = 20
+	// maxCommonInterestsBonus limits how many common interests make a pair cheaper
+	maxCommonInterestsBonus = 3
+)
+
+// CoffeePreferences are wishes of a random coffee participant about the meeting
+type CoffeePreferences struct {
+	Format    constants.CoffeeMeetingFormat
+	City      string
+	Languages []string
+	Interests []string
+	// Strict turns the format, city and language wishes into hard constraints
+	Strict bool
+}
+
+// CoffeePreferencesOverlap is what all members of a group have in common
+type CoffeePreferencesOverlap struct {
+	// City is set when all members live in the same city and nobody wants to meet online only
+	City string
+	// Online is true when all members want to meet online
+	Online    bool
+	Languages []string
+	Interests []string
+}
+
+// CoffeePreferencesCost returns the matching cost of pairing two participants by their preferences.
+// Every broken wish costs penalty, every common interest reduces the cost by interestBonus (up to 3 interests).
+// A broken wish of a strict participant forbids the pair.
+func CoffeePreferencesCost(p1, p2 CoffeePreferences, penalty, interestBonus float64) float64 {
+	broken1, broken2 := 0, 0
+
+	// Offline only and online only participants can't agree on the format
+	if (p1.Format == constants.CoffeeMeetingFormatOffline && p2.Format == constants.CoffeeMeetingFormatOnline) ||
+		(p1.Format == constants.CoffeeMeetingFormatOnline && p2.Format == constants.CoffeeMeetingFormatOffline) {
+		broken1++
+		broken2++
+	}
+
+	// Offline meetings need the same city
+	sameCity := p1.City != "" && normalizeCoffeePreference(p1.City) == normalizeCoffeePreference(p2.City)
+	if p1.Format == constants.CoffeeMeetingFormatOffline && !sameCity {
+		broken1++
+	}
+	if p2.Format == constants.CoffeeMeetingFormatOffline && !sameCity {
+		broken2++
+	}
+
+	// Participants who declared languages need a common one
+	if len(p1.Languages) > 0 && len(p2.Languages) > 0 && len(commonCoffeePreferences(p1.Languages, p2.Languages)) == 0 {
+		broken1++
+		broken2++
+	}
+
+	if (p1.Strict && broken1 > 0) || (p2.Strict && broken2 > 0) {
+		return math.Inf(1)
+	}
+
+	commonInterests := len(commonCoffeePreferences(p1.Interests, p2.Interests))
+	if commonInterests > maxCommonInterestsBonus {
+		commonInterests = maxCommonInterestsBonus
+	}
+
+	return float64(broken1+broken2)*penalty - float64(commonInterests)*interestBonus
+}
+
+// CoffeeGroupOverlap returns what all members of a group have in common, values are taken from the first member
+func CoffeeGroupOverlap(members ...CoffeePreferences) CoffeePreferencesOverlap {
+	var overlap CoffeePreferencesOverlap
+	if len(members) == 0 {
+		return overlap
+	}
+
+	overlap.City = members[0].City
+	overlap.Online = true
+	overlap.Languages = members[0].Languages
+	overlap.Interests = members[0].Interests
+	for _, member := range members {
+		if member.Format != constants.CoffeeMeetingFormatOnline {
+			overlap.Online = false
+		}
+		if member.Format == constants.CoffeeMeetingFormatOnline ||
+			normalizeCoffeePreference(member.City) != normalizeCoffeePreference(overlap.City) {
+			overlap.City = ""
+		}
+		overlap.Languages = commonCoffeePreferences(overlap.Languages, member.Languages)
+		overlap.Interests = commonCoffeePreferences(overlap.Interests, member.Interests)
+	}
+
+	return overlap
+}
+
+// ParseCoffeePreferenceList splits a comma, semicolon or newline separated list of languages or interests,
+// drops empty and duplicate items and applies the limits
+func ParseCoffeePreferenceList(text string) []string {
+	parts := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	})
+
+	items := []string{}
+	seen := make(map[string]bool)
+	for _, part := range parts {
+		item := strings.Join(strings.Fields(part), " ")
+		if item == "" || utf8.RuneCountInString(item) > MaxCoffeePreferenceItemLength {
+			continue
+		}
+		key := normalizeCoffeePreference(item)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		items = append(items, item)
+		if len(items) == MaxCoffeePreferenceItems {
+			break
+		}
+	}
+	return items
+}
+
+// commonCoffeePreferences returns items of the first list that are present in the second one ignoring case
+func commonCoffeePreferences(first, second []string) []string {
+	secondKeys := make(map[string]bool, len(second))
+	for _, item := range second {
+		secondKeys[normalizeCoffeePreference(item)] = true
+	}
+
+	var common []string
+	for _, item := range first {
+		if secondKeys[normalizeCoffeePreference(item)] {
+			common = append(common, item)
+		}
+	}
+	return common
+}
+
+func normalizeCoffeePreference(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
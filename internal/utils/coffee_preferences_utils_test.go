@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"evo-bot-go/internal/constants"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoffeePreferencesCost(t *testing.T) {
+	const penalty, bonus = 50.0, 10.0
+
+	tests := []struct {
+		name     string
+		p1       CoffeePreferences
+		p2       CoffeePreferences
+		expected float64
+	}{
+		{
+			name:     "No preferences",
+			expected: 0,
+		},
+		{
+			name:     "Online and offline only",
+			p1:       CoffeePreferences{Format: constants.CoffeeMeetingFormatOnline},
+			p2:       CoffeePreferences{Format: constants.CoffeeMeetingFormatOffline, City: "Berlin"},
+			expected: 3 * penalty,
+		},
+		{
+			name:     "Offline in the same city ignoring case",
+			p1:       CoffeePreferences{Format: constants.CoffeeMeetingFormatOffline, City: "Berlin"},
+			p2:       CoffeePreferences{Format: constants.CoffeeMeetingFormatAny, City: " berlin "},
+			expected: 0,
+		},
+		{
+			name:     "Offline in different cities",
+			p1:       CoffeePreferences{Format: constants.CoffeeMeetingFormatOffline, City: "Berlin"},
+			p2:       CoffeePreferences{Format: constants.CoffeeMeetingFormatOffline, City: "Moscow"},
+			expected: 2 * penalty,
+		},
+		{
+			name:     "Offline with unknown city of the partner",
+			p1:       CoffeePreferences{Format: constants.CoffeeMeetingFormatOffline, City: "Berlin"},
+			p2:       CoffeePreferences{},
+			expected: penalty,
+		},
+		{
+			name:     "No common language",
+			p1:       CoffeePreferences{Languages: []string{"русский"}},
+			p2:       CoffeePreferences{Languages: []string{"English"}},
+			expected: 2 * penalty,
+		},
+		{
+			name:     "Common language and unknown languages",
+			p1:       CoffeePreferences{Languages: []string{"русский", "English"}},
+			p2:       CoffeePreferences{Languages: []string{"english"}},
+			expected: 0,
+		},
+		{
+			name:     "Common interests are capped",
+			p1:       CoffeePreferences{Interests: []string{"LLM", "Go", "Rust", "Kotlin"}},
+			p2:       CoffeePreferences{Interests: []string{"llm", "go", "rust", "kotlin"}},
+			expected: -3 * bonus,
+		},
+		{
+			name:     "Strict participant with a broken wish",
+			p1:       CoffeePreferences{Format: constants.CoffeeMeetingFormatOffline, City: "Berlin", Strict: true},
+			p2:       CoffeePreferences{City: "Moscow"},
+			expected: math.Inf(1),
+		},
+		{
+			name:     "Strict participant without broken wishes",
+			p1:       CoffeePreferences{Format: constants.CoffeeMeetingFormatOnline, Strict: true},
+			p2:       CoffeePreferences{Format: constants.CoffeeMeetingFormatAny, Interests: []string{"LLM"}},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CoffeePreferencesCost(tt.p1, tt.p2, penalty, bonus))
+			assert.Equal(t, tt.expected, CoffeePreferencesCost(tt.p2, tt.p1, penalty, bonus))
+		})
+	}
+}
+
+func TestCoffeeGroupOverlap(t *testing.T) {
+	overlap := CoffeeGroupOverlap(
+		CoffeePreferences{City: "Берлин", Languages: []string{"русский", "English"}, Interests: []string{"LLM-агенты", "Go"}},
+		CoffeePreferences{Format: constants.CoffeeMeetingFormatOffline, City: "берлин", Interests: []string{"go", "llm-агенты"}},
+	)
+	assert.Equal(t, "Берлин", overlap.City)
+	assert.False(t, overlap.Online)
+	assert.Empty(t, overlap.Languages)
+	assert.Equal(t, []string{"LLM-агенты", "Go"}, overlap.Interests)
+
+	overlap = CoffeeGroupOverlap(
+		CoffeePreferences{Format: constants.CoffeeMeetingFormatOnline, City: "Берлин", Languages: []string{"English"}},
+		CoffeePreferences{Format: constants.CoffeeMeetingFormatOnline, City: "Берлин", Languages: []string{"english"}},
+		CoffeePreferences{Format: constants.CoffeeMeetingFormatOnline, Languages: []string{"English", "русский"}},
+	)
+	assert.Equal(t, "", overlap.City)
+	assert.True(t, overlap.Online)
+	assert.Equal(t, []string{"English"}, overlap.Languages)
+	assert.Empty(t, overlap.Interests)
+
+	assert.Equal(t, CoffeePreferencesOverlap{}, CoffeeGroupOverlap())
+}
+
+func TestParseCoffeePreferenceList(t *testing.T) {
+	assert.Equal(t, []string{"LLM-агенты", "Go", "system design"},
+		ParseCoffeePreferenceList("LLM-агенты, Go;  system   design\n go ,,"))
+	assert.Equal(t, []string{}, ParseCoffeePreferenceList(" , ; "))
+	assert.Equal(t, []string{"ok"}, ParseCoffeePreferenceList(strings.Repeat("x", MaxCoffeePreferenceItemLength+1)+", ok"))
+
+	var many []string
+	for i := 0; i < MaxCoffeePreferenceItems+5; i++ {
+		many = append(many, strings.Repeat("a", i+1))
+	}
+	assert.Len(t, ParseCoffeePreferenceList(strings.Join(many, ",")), MaxCoffeePreferenceItems)
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"sort"
+	"time"
+)
+
+// CoffeeWeekGroups is a random coffee round with the groups formed in it
+type CoffeeWeekGroups struct {
+	WeekStartDate time.Time
+	Groups        [][]int
+}
+
+// CoffeeWeekStats summarises a random coffee round for the admin statistics
+type CoffeeWeekStats struct {
+	WeekStartDate time.Time
+	Participants  int
+	Groups        int
+	// RepeatGroups is the number of groups in which some members already met before
+	RepeatGroups int
+}
+
+// CoffeeStreak counts consecutive rounds, starting from the most recent one, in which the user had a meeting.
+// rounds are week start dates of all rounds that produced pairs, userRounds are the rounds of the user.
+func CoffeeStreak(rounds []time.Time, userRounds []time.Time) int {
+	met := make(map[string]bool, len(userRounds))
+	for _, week := range userRounds {
+		met[week.Format(time.DateOnly)] = true
+	}
+
+	sorted := make([]time.Time, len(rounds))
+	copy(sorted, rounds)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].After(sorted[j]) })
+
+	streak := 0
+	for _, week := range sorted {
+		if !met[week.Format(time.DateOnly)] {
+			break
+		}
+		streak++
+	}
+	return streak
+}
+
+// CoffeeRepeatGroups returns for every round the number of groups in which some members already met in an earlier round.
+// Rounds are processed in chronological order, the result follows the order of the input.
+func CoffeeRepeatGroups(rounds []CoffeeWeekGroups) []int {
+	order := allIndexes(len(rounds))
+	sort.SliceStable(order, func(i, j int) bool {
+		return rounds[order[i]].WeekStartDate.Before(rounds[order[j]].WeekStartDate)
+	})
+
+	met := make(map[[2]int]bool)
+	repeats := make([]int, len(rounds))
+	for _, roundIndex := range order {
+		groups := rounds[roundIndex].Groups
+		for _, group := range groups {
+			for _, key := range coffeeGroupPairKeys(group) {
+				if met[key] {
+					repeats[roundIndex]++
+					break
+				}
+			}
+		}
+		// Pairs of the round count as met only after the whole round is checked
+		for _, group := range groups {
+			for _, key := range coffeeGroupPairKeys(group) {
+				met[key] = true
+			}
+		}
+	}
+	return repeats
+}
+
+func coffeeGroupPairKeys(group []int) [][2]int {
+	var keys [][2]int
+	for i := 0; i < len(group); i++ {
+		for j := i + 1; j < len(group); j++ {
+			keys = append(keys, CoffeePairKey(group[i], group[j]))
+		}
+	}
+	return keys
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func coffeeStatsWeek(day int) time.Time {
+	return time.Date(2025, time.September, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCoffeeStreak(t *testing.T) {
+	rounds := []time.Time{coffeeStatsWeek(1), coffeeStatsWeek(8), coffeeStatsWeek(15), coffeeStatsWeek(22)}
+
+	tests := []struct {
+		name       string
+		userRounds []time.Time
+		expected   int
+	}{
+		{"no meetings", nil, 0},
+		{"all rounds", rounds, 4},
+		{"missed the latest round", []time.Time{coffeeStatsWeek(1), coffeeStatsWeek(8), coffeeStatsWeek(15)}, 0},
+		{"gap before the latest rounds", []time.Time{coffeeStatsWeek(1), coffeeStatsWeek(15), coffeeStatsWeek(22)}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CoffeeStreak(rounds, tt.userRounds))
+		})
+	}
+}
+
+func TestCoffeeStreak_UnsortedRounds(t *testing.T) {
+	rounds := []time.Time{coffeeStatsWeek(15), coffeeStatsWeek(1), coffeeStatsWeek(22), coffeeStatsWeek(8)}
+	assert.Equal(t, 2, CoffeeStreak(rounds, []time.Time{coffeeStatsWeek(22), coffeeStatsWeek(15), coffeeStatsWeek(1)}))
+}
+
+func TestCoffeeRepeatGroups(t *testing.T) {
+	rounds := []CoffeeWeekGroups{
+		{WeekStartDate: coffeeStatsWeek(15), Groups: [][]int{{1, 2}, {3, 4, 5}}},
+		{WeekStartDate: coffeeStatsWeek(1), Groups: [][]int{{1, 2}, {3, 4}}},
+		{WeekStartDate: coffeeStatsWeek(8), Groups: [][]int{{1, 3}, {2, 4}}},
+	}
+
+	// Week 15 repeats 1-2 and 3-4 (inside the triple), the first week has nothing to repeat
+	assert.Equal(t, []int{2, 0, 0}, CoffeeRepeatGroups(rounds))
+}
+
+func TestCoffeeRepeatGroups_SameRoundIsNotARepeat(t *testing.T) {
+	rounds := []CoffeeWeekGroups{
+		{WeekStartDate: coffeeStatsWeek(1), Groups: [][]int{{1, 2}, {2, 1}}},
+	}
+	assert.Equal(t, []int{0}, CoffeeRepeatGroups(rounds))
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"math"
+	"time"
+)
+
+// NextCoffeeWeekStart returns the start of the week of the upcoming random coffee round:
+// the next Monday after the given day (a week later if it is Monday) as a date in UTC
+func NextCoffeeWeekStart(now time.Time) time.Time {
+	daysUntilMonday := (8 - int(now.Weekday())) % 7
+	if daysUntilMonday == 0 {
+		daysUntilMonday = 7 // Next Monday if today is Monday
+	}
+
+	next := now.AddDate(0, 0, daysUntilMonday)
+	return time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// IsCoffeeSubscriptionActive reports whether a subscription takes part in the round of the given week.
+// The subscription joins every everyWeeks-th round counting from startWeek and skips rounds before pausedUntil,
+// a zero pausedUntil means the subscription is not paused.
+func IsCoffeeSubscriptionActive(startWeek time.Time, everyWeeks int, pausedUntil time.Time, week time.Time) bool {
+	if !pausedUntil.IsZero() && week.Before(pausedUntil) {
+		return false
+	}
+	if everyWeeks <= 1 {
+		return true
+	}
+
+	weeks := int(math.Round(week.Sub(startWeek).Hours() / (24 * 7)))
+	return ((weeks%everyWeeks)+everyWeeks)%everyWeeks == 0
+}
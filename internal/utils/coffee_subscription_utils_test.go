@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextCoffeeWeekStart(t *testing.T) {
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"friday", time.Date(2025, 10, 24, 14, 0, 0, 0, time.UTC), time.Date(2025, 10, 27, 0, 0, 0, 0, time.UTC)},
+		{"sunday", time.Date(2025, 10, 26, 23, 0, 0, 0, time.UTC), time.Date(2025, 10, 27, 0, 0, 0, 0, time.UTC)},
+		{"monday is followed by the next monday", time.Date(2025, 10, 27, 9, 0, 0, 0, time.UTC), time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)},
+		{"club timezone date is used", time.Date(2025, 10, 26, 23, 30, 0, 0, time.UTC).In(moscow), time.Date(2025, 11, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NextCoffeeWeekStart(tt.now))
+		})
+	}
+}
+
+func TestIsCoffeeSubscriptionActive(t *testing.T) {
+	start := time.Date(2025, 10, 27, 0, 0, 0, 0, time.UTC)
+	week := func(n int) time.Time { return start.AddDate(0, 0, 7*n) }
+
+	tests := []struct {
+		name        string
+		everyWeeks  int
+		pausedUntil time.Time
+		week        time.Time
+		want        bool
+	}{
+		{"weekly", 1, time.Time{}, week(3), true},
+		{"weekly before start", 1, time.Time{}, week(-1), true},
+		{"biweekly first round", 2, time.Time{}, week(0), true},
+		{"biweekly skipped round", 2, time.Time{}, week(1), false},
+		{"biweekly next round", 2, time.Time{}, week(2), true},
+		{"biweekly round before start", 2, time.Time{}, week(-1), false},
+		{"paused", 1, week(2), week(1), false},
+		{"pause is over", 1, week(2), week(2), true},
+		{"biweekly after pause", 2, week(2), week(4), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsCoffeeSubscriptionActive(start, tt.everyWeeks, tt.pausedUntil, tt.week))
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit bounds the search for the next run, so impossible expressions like "0 0 31 2 *" don't loop forever
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// CronSchedule is a parsed standard 5-field cron expression: minute hour day-of-month month day-of-week
+type CronSchedule struct {
+	expression string
+	minutes    uint64
+	hours      uint64
+	daysOfMon  uint64
+	months     uint64
+	daysOfWeek uint64
+	// domStar and dowStar keep the classic cron rule: if both day fields are restricted, a day matches either of them
+	domStar bool
+	dowStar bool
+}
+
+type cronField struct {
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronMinuteField = cronField{min: 0, max: 59}
+	cronHourField   = cronField{min: 0, max: 23}
+	cronDomField    = cronField{min: 1, max: 31}
+	cronMonthField  = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day of week allows 7 as an alias for Sunday
+	cronDowField = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// ParseCronExpression parses a 5-field cron expression.
+// Supported syntax per field: "*", numbers, names (jan, mon), ranges "1-5", steps "*/15" or "1-30/5" and lists "1,15,30".
+func ParseCronExpression(expression string) (*CronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expression, len(fields))
+	}
+
+	schedule := &CronSchedule{
+		expression: strings.Join(fields, " "),
+		domStar:    fields[2] == "*",
+		dowStar:    fields[4] == "*",
+	}
+
+	var err error
+	if schedule.minutes, err = parseCronField(fields[0], cronMinuteField); err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	if schedule.hours, err = parseCronField(fields[1], cronHourField); err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	if schedule.daysOfMon, err = parseCronField(fields[2], cronDomField); err != nil {
+		return nil, fmt.Errorf("invalid day of month field: %w", err)
+	}
+	if schedule.months, err = parseCronField(fields[3], cronMonthField); err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	if schedule.daysOfWeek, err = parseCronField(fields[4], cronDowField); err != nil {
+		return nil, fmt.Errorf("invalid day of week field: %w", err)
+	}
+	// Sunday can be written both as 0 and 7
+	if schedule.daysOfWeek&(1<<7) != 0 {
+		schedule.daysOfWeek |= 1
+	}
+
+	return schedule, nil
+}
+
+// String returns the normalized cron expression
+func (c *CronSchedule) String() string {
+	return c.expression
+}
+
+// Next returns the first scheduled time strictly after the given time, in the location of the given time.
+// Fields match the wall clock of that location, so wall clock times skipped by a DST change never match.
+// Returns zero time if nothing matches within several years.
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(cronSearchLimit)
+
+	for !t.After(limit) {
+		if c.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hours&(1<<uint(t.Hour())) == 0 {
+			// Built from the wall clock, as truncating the absolute time breaks zones with non-hour offsets
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (c *CronSchedule) matchesDay(t time.Time) bool {
+	domMatch := c.daysOfMon&(1<<uint(t.Day())) != 0
+	dowMatch := c.daysOfWeek&(1<<uint(t.Weekday())) != 0
+
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// parseCronField parses a single field into a bit set of allowed values
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		if part == "" {
+			return 0, fmt.Errorf("empty value in %q", field)
+		}
+
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx != -1 {
+			rangePart = part[:idx]
+			var err error
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+
+		var from, to int
+		switch {
+		case rangePart == "*":
+			from, to = spec.min, spec.max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if from, err = parseCronValue(bounds[0], spec); err != nil {
+				return 0, err
+			}
+			if to, err = parseCronValue(bounds[1], spec); err != nil {
+				return 0, err
+			}
+			if from > to {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			value, err := parseCronValue(rangePart, spec)
+			if err != nil {
+				return 0, err
+			}
+			from, to = value, value
+			// "5/15" means starting at 5 with step 15 up to the max
+			if step > 1 {
+				to = spec.max
+			}
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(value string, spec cronField) (int, error) {
+	if n, ok := spec.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if n < spec.min || n > spec.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, spec.min, spec.max)
+	}
+	return n, nil
+}
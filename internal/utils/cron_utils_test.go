@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCronExpression(t *testing.T) {
+	tests := []struct {
+		name        string
+		expression  string
+		expectError bool
+	}{
+		{name: "Every minute", expression: "* * * * *"},
+		{name: "Daily at fixed time", expression: "0 3 * * *"},
+		{name: "Steps, ranges and lists", expression: "*/15 9-18 1,15 * mon-fri"},
+		{name: "Range with step", expression: "0-30/10 * * * *"},
+		{name: "Month and weekday names", expression: "0 12 * JAN,jul sun"},
+		{name: "Sunday as 7", expression: "0 0 * * 7"},
+		{name: "Extra spaces", expression: "  0   3 * *   * "},
+		{name: "Too few fields", expression: "0 3 * *", expectError: true},
+		{name: "Too many fields", expression: "0 3 * * * *", expectError: true},
+		{name: "Minute out of range", expression: "60 * * * *", expectError: true},
+		{name: "Hour out of range", expression: "0 24 * * *", expectError: true},
+		{name: "Day of month zero", expression: "0 0 0 * *", expectError: true},
+		{name: "Inverted range", expression: "0 18-9 * * *", expectError: true},
+		{name: "Zero step", expression: "*/0 * * * *", expectError: true},
+		{name: "Unknown name", expression: "0 0 * * funday", expectError: true},
+		{name: "Empty list item", expression: "1,,2 * * * *", expectError: true},
+		{name: "Empty string", expression: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronExpression(tt.expression)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, schedule)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, schedule)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// 2025-10-15 is Wednesday
+	base := time.Date(2025, 10, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		expression string
+		after      time.Time
+		expected   time.Time
+	}{
+		{
+			name:       "Every minute goes to the next minute",
+			expression: "* * * * *",
+			after:      base,
+			expected:   time.Date(2025, 10, 15, 10, 8, 0, 0, time.UTC),
+		},
+		{
+			name:       "Exact match time is excluded",
+			expression: "0 3 * * *",
+			after:      time.Date(2025, 10, 15, 3, 0, 0, 0, time.UTC),
+			expected:   time.Date(2025, 10, 16, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "Daily later today",
+			expression: "30 18 * * *",
+			after:      base,
+			expected:   time.Date(2025, 10, 15, 18, 30, 0, 0, time.UTC),
+		},
+		{
+			name:       "Daily already passed goes to tomorrow",
+			expression: "0 3 * * *",
+			after:      base,
+			expected:   time.Date(2025, 10, 16, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "Step in minutes",
+			expression: "*/15 * * * *",
+			after:      base,
+			expected:   time.Date(2025, 10, 15, 10, 15, 0, 0, time.UTC),
+		},
+		{
+			name:       "Weekly on Friday",
+			expression: "0 14 * * fri",
+			after:      base,
+			expected:   time.Date(2025, 10, 17, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "Sunday as 7",
+			expression: "0 12 * * 7",
+			after:      base,
+			expected:   time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "Next month",
+			expression: "0 0 1 * *",
+			after:      base,
+			expected:   time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "Next year",
+			expression: "0 0 1 jan *",
+			after:      base,
+			expected:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "Day of month or day of week when both are restricted",
+			expression: "0 9 20 * mon",
+			after:      base,
+			expected:   time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "Leap day",
+			expression: "0 0 29 2 *",
+			after:      base,
+			expected:   time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "Impossible date returns zero time",
+			expression: "0 0 31 2 *",
+			after:      base,
+			expected:   time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronExpression(tt.expression)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.Next(tt.after))
+		})
+	}
+}
+
+func TestCronScheduleNextKeepsLocation(t *testing.T) {
+	location := time.FixedZone("UTC+3", 3*60*60)
+	schedule, err := ParseCronExpression("0 9 * * *")
+	assert.NoError(t, err)
+
+	next := schedule.Next(time.Date(2025, 10, 15, 10, 0, 0, 0, location))
+	assert.Equal(t, time.Date(2025, 10, 16, 9, 0, 0, 0, location), next)
+	assert.Equal(t, location, next.Location())
+}
+
+func TestCronScheduleNextWithNonHourOffset(t *testing.T) {
+	location := time.FixedZone("UTC+5:30", 5*60*60+30*60)
+	schedule, err := ParseCronExpression("0 12 * * *")
+	assert.NoError(t, err)
+
+	next := schedule.Next(time.Date(2025, 10, 15, 10, 7, 0, 0, location))
+	assert.Equal(t, time.Date(2025, 10, 15, 12, 0, 0, 0, location), next)
+}
+
+func TestCronScheduleNextAcrossDST(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	// 2025-03-30 02:00 doesn't exist in Berlin, clocks jump to 03:00
+	schedule, err := ParseCronExpression("0 9 * * *")
+	assert.NoError(t, err)
+	next := schedule.Next(time.Date(2025, 3, 29, 10, 0, 0, 0, location))
+	assert.Equal(t, time.Date(2025, 3, 30, 9, 0, 0, 0, location), next)
+	assert.Equal(t, 7*time.Hour, next.Sub(time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC)))
+
+	// Skipped wall clock time is not matched on the day of the change
+	schedule, err = ParseCronExpression("30 2 * * *")
+	assert.NoError(t, err)
+	next = schedule.Next(time.Date(2025, 3, 30, 0, 0, 0, 0, location))
+	assert.Equal(t, time.Date(2025, 3, 31, 2, 30, 0, 0, location), next)
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayout is the date format users type in bot dialogs
+const DateLayout = "02.01.2006"
+
+// ParseDateRange parses a range like "01.10.2025 - 05.10.2025" in the given location.
+// The end date is inclusive, so the returned end is the start of the next day.
+// A single date is treated as a one-day range.
+func ParseDateRange(s string, location *time.Location) (time.Time, time.Time, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) > 2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date range format: %q", s)
+	}
+
+	from, err := time.ParseInLocation(DateLayout, strings.TrimSpace(parts[0]), location)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %w", err)
+	}
+
+	to := from
+	if len(parts) == 2 {
+		to, err = time.ParseInLocation(DateLayout, strings.TrimSpace(parts[1]), location)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %w", err)
+		}
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date %s is before start date %s", to.Format(DateLayout), from.Format(DateLayout))
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
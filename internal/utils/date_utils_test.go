@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDateRange(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectedFrom time.Time
+		expectedTo   time.Time
+		expectError  bool
+	}{
+		{
+			name:         "Range with spaces",
+			input:        "01.10.2025 - 05.10.2025",
+			expectedFrom: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:   time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Range without spaces",
+			input:        "30.09.2025-01.10.2025",
+			expectedFrom: time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC),
+			expectedTo:   time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Single date",
+			input:        " 31.12.2025 ",
+			expectedFrom: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+			expectedTo:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:        "End before start",
+			input:       "05.10.2025 - 01.10.2025",
+			expectError: true,
+		},
+		{
+			name:        "Invalid start date",
+			input:       "2025-10-01",
+			expectError: true,
+		},
+		{
+			name:        "Invalid end date",
+			input:       "01.10.2025 - tomorrow",
+			expectError: true,
+		},
+		{
+			name:        "Empty string",
+			input:       "",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := ParseDateRange(tt.input, time.UTC)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFrom, from)
+			assert.Equal(t, tt.expectedTo, to)
+		})
+	}
+}
+
+func TestParseDateRangeInLocation(t *testing.T) {
+	location := time.FixedZone("UTC+3", 3*60*60)
+
+	from, to, err := ParseDateRange("01.10.2025 - 05.10.2025", location)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 9, 30, 21, 0, 0, 0, time.UTC), from.UTC())
+	assert.Equal(t, time.Date(2025, 10, 5, 21, 0, 0, 0, time.UTC), to.UTC())
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// iCalMaxLineOctets is the longest content line allowed by RFC 5545 without the line break
+const iCalMaxLineOctets = 75
+
+// EscapeICalText escapes a TEXT property value according to RFC 5545
+func EscapeICalText(text string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	)
+	return replacer.Replace(text)
+}
+
+// FoldICalLine splits a content line into lines of at most 75 octets joined with CRLF and a space,
+// multi-byte characters are never split
+func FoldICalLine(line string) string {
+	if len(line) <= iCalMaxLineOctets {
+		return line
+	}
+
+	var folded strings.Builder
+	limit := iCalMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		folded.WriteString(line[:cut])
+		folded.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space of a continuation line counts towards its length
+		limit = iCalMaxLineOctets - 1
+	}
+	folded.WriteString(line)
+	return folded.String()
+}
+
+// FormatICalTime formats the time as a UTC DATE-TIME value
+func FormatICalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// ICalBuilder builds an iCalendar document line by line
+type ICalBuilder struct {
+	builder strings.Builder
+}
+
+// WriteProperty writes a folded content line terminated with CRLF, the value must already be escaped
+func (c *ICalBuilder) WriteProperty(name string, value string) {
+	c.builder.WriteString(FoldICalLine(name + ":" + value))
+	c.builder.WriteString("\r\n")
+}
+
+// String returns the built document
+func (c *ICalBuilder) String() string {
+	return c.builder.String()
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeICalText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "Plain text",
+			text:     "Клубный созвон",
+			expected: "Клубный созвон",
+		},
+		{
+			name:     "Special characters",
+			text:     `a;b,c\d`,
+			expected: `a\;b\,c\\d`,
+		},
+		{
+			name:     "Line breaks",
+			text:     "first\r\nsecond\nthird",
+			expected: `first\nsecond\nthird`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, EscapeICalText(tt.text))
+		})
+	}
+}
+
+func TestFoldICalLine(t *testing.T) {
+	t.Run("Short line is unchanged", func(t *testing.T) {
+		line := "SUMMARY:Meetup"
+		assert.Equal(t, line, FoldICalLine(line))
+	})
+
+	t.Run("Long ASCII line", func(t *testing.T) {
+		line := "DESCRIPTION:" + strings.Repeat("a", 200)
+		folded := FoldICalLine(line)
+
+		parts := strings.Split(folded, "\r\n")
+		assert.Greater(t, len(parts), 1)
+		for i, part := range parts {
+			assert.LessOrEqual(t, len(part), 75)
+			if i > 0 {
+				assert.True(t, strings.HasPrefix(part, " "))
+			}
+		}
+		assert.Equal(t, line, strings.ReplaceAll(folded, "\r\n ", ""))
+	})
+
+	t.Run("Multi-byte characters are not split", func(t *testing.T) {
+		line := "SUMMARY:" + strings.Repeat("Мероприятие ", 20)
+		folded := FoldICalLine(line)
+
+		for _, part := range strings.Split(folded, "\r\n") {
+			assert.LessOrEqual(t, len(part), 75)
+			assert.True(t, utf8.ValidString(part))
+		}
+		assert.Equal(t, line, strings.ReplaceAll(folded, "\r\n ", ""))
+	})
+}
+
+func TestFormatICalTime(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	assert.Equal(t, "20251105T160000Z", FormatICalTime(time.Date(2025, 11, 5, 19, 0, 0, 0, moscow)))
+}
+
+func TestICalBuilder(t *testing.T) {
+	var c ICalBuilder
+	c.WriteProperty("BEGIN", "VCALENDAR")
+	c.WriteProperty("END", "VCALENDAR")
+	assert.Equal(t, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", c.String())
+}
@@ -33,3 +33,19 @@ func EscapeMarkdown(s string) string {
 
 	return result
 }
+
+// FormatUserDisplayName builds a human readable name from user fields.
+// Example: "Ivan Petrov (@ivan)", "@ivan" or "Ivan", empty string if all fields are empty
+func FormatUserDisplayName(firstname, lastname, username string) string {
+	name := strings.TrimSpace(strings.TrimSpace(firstname) + " " + strings.TrimSpace(lastname))
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+
+	switch {
+	case name != "" && username != "":
+		return name + " (@" + username + ")"
+	case username != "":
+		return "@" + username
+	default:
+		return name
+	}
+}
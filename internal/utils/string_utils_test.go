@@ -281,4 +281,54 @@ func TestEscapeMarkdown(t *testing.T) {
 			assert.Equal(t, tt.expected, result, "Escaped string should match expected value")
 		})
 	}
-}
\ No newline at end of file
+}
+func TestFormatUserDisplayName(t *testing.T) {
+	tests := []struct {
+		name      string
+		firstname string
+		lastname  string
+		username  string
+		expected  string
+	}{
+		{
+			name:      "Full name and username",
+			firstname: "Иван",
+			lastname:  "Петров",
+			username:  "ivan",
+			expected:  "Иван Петров (@ivan)",
+		},
+		{
+			name:      "Only first name",
+			firstname: "Иван",
+			expected:  "Иван",
+		},
+		{
+			name:     "Only username with at sign",
+			username: "@ivan",
+			expected: "@ivan",
+		},
+		{
+			name:     "Only last name",
+			lastname: "Петров",
+			expected: "Петров",
+		},
+		{
+			name:      "Whitespace is trimmed",
+			firstname: " Иван ",
+			lastname:  " ",
+			username:  " ivan ",
+			expected:  "Иван (@ivan)",
+		},
+		{
+			name:     "All fields empty",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatUserDisplayName(tt.firstname, tt.lastname, tt.username)
+			assert.Equal(t, tt.expected, result, "Display name should match expected value")
+		})
+	}
+}
@@ -0,0 +1,685 @@
+package utils
+
+import (
+	"math"
+)
+
+// MatchingEdge is an undirected edge of a graph with an integer weight
+type MatchingEdge struct {
+	U      int
+	V      int
+	Weight int64
+}
+
+// MinCostMatching finds a maximum cardinality matching with the minimum total cost in a general graph.
+// costs is a symmetric n*n matrix, math.Inf(1) or NaN marks a forbidden pair, the diagonal is ignored.
+// Costs are rounded to 1/1000. Returns mate[v] for every vertex, -1 if the vertex stays unmatched.
+func MinCostMatching(costs [][]float64) []int {
+	n := len(costs)
+	const scale = 1000
+
+	var maxCost int64
+	scaled := make([][]int64, n)
+	allowed := make([][]bool, n)
+	for i := range costs {
+		scaled[i] = make([]int64, n)
+		allowed[i] = make([]bool, n)
+		for j := i + 1; j < n; j++ {
+			cost := costs[i][j]
+			if math.IsInf(cost, 1) || math.IsNaN(cost) {
+				continue
+			}
+			allowed[i][j] = true
+			scaled[i][j] = int64(math.Round(cost * scale))
+			if scaled[i][j] > maxCost {
+				maxCost = scaled[i][j]
+			}
+		}
+	}
+
+	// Maximizing (maxCost + 1 - cost) over maximum cardinality matchings minimizes the total cost,
+	// since all weights are positive and every matching of the same cardinality gets the same offset
+	var edges []MatchingEdge
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if allowed[i][j] {
+				edges = append(edges, MatchingEdge{U: i, V: j, Weight: maxCost + 1 - scaled[i][j]})
+			}
+		}
+	}
+
+	mate := MaxWeightMatching(edges, true)
+	result := make([]int, n)
+	for v := range result {
+		result[v] = -1
+		if v < len(mate) {
+			result[v] = mate[v]
+		}
+	}
+	return result
+}
+
+// MaxWeightMatching computes a maximum weight matching in a general graph with Edmonds' blossom algorithm
+// and a primal-dual method, in O(n^3) time. If maxCardinality is true, only maximum cardinality matchings
+// are considered. Returns mate[v] for every vertex, -1 if the vertex is unmatched.
+//
+// This is a port of the well-known reference implementation by Joris van Rantwijk (mwmatching.py),
+// based on "Efficient Algorithms for Finding Maximum Matching in Graphs" by Zvi Galil.
+func MaxWeightMatching(edges []MatchingEdge, maxCardinality bool) []int {
+	if len(edges) == 0 {
+		return nil
+	}
+
+	m := newWeightedMatching(edges, maxCardinality)
+	m.solve()
+
+	mate := make([]int, m.nvertex)
+	for v := range mate {
+		mate[v] = -1
+		if m.mate[v] >= 0 {
+			mate[v] = m.endpoint[m.mate[v]]
+		}
+	}
+	return mate
+}
+
+// weightedMatching keeps the state of MaxWeightMatching.
+// Vertices are 0..n-1, blossoms are n..2n-1. Edge k has endpoints 2k (U) and 2k+1 (V).
+type weightedMatching struct {
+	edges          []MatchingEdge
+	maxCardinality bool
+	nvertex        int
+
+	endpoint  []int
+	neighbend [][]int
+	// mate[v] is the remote endpoint of the matched edge of vertex v, or -1
+	mate []int
+	// label is 0 for unlabeled, 1 for S, 2 for T; 5 temporarily marks breadcrumbs in scanBlossom
+	label            []int
+	labelend         []int
+	inblossom        []int
+	blossomparent    []int
+	blossomchilds    [][]int
+	blossombase      []int
+	blossomendps     [][]int
+	bestedge         []int
+	blossombestedges [][]int
+	unusedblossoms   []int
+	dualvar          []int64
+	allowedge        []bool
+	queue            []int
+}
+
+func newWeightedMatching(edges []MatchingEdge, maxCardinality bool) *weightedMatching {
+	nvertex := 0
+	var maxWeight int64
+	for _, e := range edges {
+		if e.U >= nvertex {
+			nvertex = e.U + 1
+		}
+		if e.V >= nvertex {
+			nvertex = e.V + 1
+		}
+		if e.Weight > maxWeight {
+			maxWeight = e.Weight
+		}
+	}
+
+	m := &weightedMatching{
+		edges:            edges,
+		maxCardinality:   maxCardinality,
+		nvertex:          nvertex,
+		endpoint:         make([]int, 2*len(edges)),
+		neighbend:        make([][]int, nvertex),
+		mate:             make([]int, nvertex),
+		label:            make([]int, 2*nvertex),
+		labelend:         make([]int, 2*nvertex),
+		inblossom:        make([]int, nvertex),
+		blossomparent:    make([]int, 2*nvertex),
+		blossomchilds:    make([][]int, 2*nvertex),
+		blossombase:      make([]int, 2*nvertex),
+		blossomendps:     make([][]int, 2*nvertex),
+		bestedge:         make([]int, 2*nvertex),
+		blossombestedges: make([][]int, 2*nvertex),
+		dualvar:          make([]int64, 2*nvertex),
+		allowedge:        make([]bool, len(edges)),
+	}
+
+	for p := range m.endpoint {
+		if p%2 == 0 {
+			m.endpoint[p] = edges[p/2].U
+		} else {
+			m.endpoint[p] = edges[p/2].V
+		}
+	}
+	for k, e := range edges {
+		m.neighbend[e.U] = append(m.neighbend[e.U], 2*k+1)
+		m.neighbend[e.V] = append(m.neighbend[e.V], 2*k)
+	}
+	for v := 0; v < nvertex; v++ {
+		m.mate[v] = -1
+		m.inblossom[v] = v
+		m.blossombase[v] = v
+		m.dualvar[v] = maxWeight
+	}
+	for b := 0; b < 2*nvertex; b++ {
+		m.labelend[b] = -1
+		m.blossomparent[b] = -1
+		m.bestedge[b] = -1
+		if b >= nvertex {
+			m.blossombase[b] = -1
+			m.unusedblossoms = append(m.unusedblossoms, b)
+		}
+	}
+
+	return m
+}
+
+func (m *weightedMatching) slack(k int) int64 {
+	e := m.edges[k]
+	return m.dualvar[e.U] + m.dualvar[e.V] - 2*e.Weight
+}
+
+func (m *weightedMatching) blossomLeaves(b int) []int {
+	if b < m.nvertex {
+		return []int{b}
+	}
+	var leaves []int
+	for _, t := range m.blossomchilds[b] {
+		leaves = append(leaves, m.blossomLeaves(t)...)
+	}
+	return leaves
+}
+
+// assignLabel labels the top-level blossom of vertex w with t, reached through endpoint p
+func (m *weightedMatching) assignLabel(w, t, p int) {
+	b := m.inblossom[w]
+	m.label[w], m.label[b] = t, t
+	m.labelend[w], m.labelend[b] = p, p
+	m.bestedge[w], m.bestedge[b] = -1, -1
+	if t == 1 {
+		m.queue = append(m.queue, m.blossomLeaves(b)...)
+	} else if t == 2 {
+		base := m.blossombase[b]
+		m.assignLabel(m.endpoint[m.mate[base]], 1, m.mate[base]^1)
+	}
+}
+
+// scanBlossom traces back from v and w to find a new blossom or an augmenting path.
+// Returns the base vertex of the new blossom or -1.
+func (m *weightedMatching) scanBlossom(v, w int) int {
+	var path []int
+	base := -1
+	for v != -1 || w != -1 {
+		b := m.inblossom[v]
+		if m.label[b]&4 != 0 {
+			base = m.blossombase[b]
+			break
+		}
+		path = append(path, b)
+		m.label[b] = 5
+		if m.labelend[b] == -1 {
+			v = -1
+		} else {
+			v = m.endpoint[m.labelend[b]]
+			b = m.inblossom[v]
+			v = m.endpoint[m.labelend[b]]
+		}
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+	for _, b := range path {
+		m.label[b] = 1
+	}
+	return base
+}
+
+// addBlossom constructs a new blossom with the given base through the edge k
+func (m *weightedMatching) addBlossom(base, k int) {
+	v, w := m.edges[k].U, m.edges[k].V
+	bb := m.inblossom[base]
+	bv := m.inblossom[v]
+	bw := m.inblossom[w]
+
+	b := m.unusedblossoms[len(m.unusedblossoms)-1]
+	m.unusedblossoms = m.unusedblossoms[:len(m.unusedblossoms)-1]
+	m.blossombase[b] = base
+	m.blossomparent[b] = -1
+	m.blossomparent[bb] = b
+
+	var path, endps []int
+	for bv != bb {
+		m.blossomparent[bv] = b
+		path = append(path, bv)
+		endps = append(endps, m.labelend[bv])
+		v = m.endpoint[m.labelend[bv]]
+		bv = m.inblossom[v]
+	}
+	path = append(path, bb)
+	reverseInts(path)
+	reverseInts(endps)
+	endps = append(endps, 2*k)
+	for bw != bb {
+		m.blossomparent[bw] = b
+		path = append(path, bw)
+		endps = append(endps, m.labelend[bw]^1)
+		w = m.endpoint[m.labelend[bw]]
+		bw = m.inblossom[w]
+	}
+	m.blossomchilds[b] = path
+	m.blossomendps[b] = endps
+
+	m.label[b] = 1
+	m.labelend[b] = m.labelend[bb]
+	m.dualvar[b] = 0
+	for _, leaf := range m.blossomLeaves(b) {
+		if m.label[m.inblossom[leaf]] == 2 {
+			m.queue = append(m.queue, leaf)
+		}
+		m.inblossom[leaf] = b
+	}
+
+	bestedgeto := make([]int, 2*m.nvertex)
+	for i := range bestedgeto {
+		bestedgeto[i] = -1
+	}
+	for _, child := range path {
+		var nblists [][]int
+		if m.blossombestedges[child] == nil {
+			for _, leaf := range m.blossomLeaves(child) {
+				nblist := make([]int, 0, len(m.neighbend[leaf]))
+				for _, p := range m.neighbend[leaf] {
+					nblist = append(nblist, p/2)
+				}
+				nblists = append(nblists, nblist)
+			}
+		} else {
+			nblists = [][]int{m.blossombestedges[child]}
+		}
+		for _, nblist := range nblists {
+			for _, ek := range nblist {
+				i, j := m.edges[ek].U, m.edges[ek].V
+				if m.inblossom[j] == b {
+					i, j = j, i
+				}
+				_ = i
+				bj := m.inblossom[j]
+				if bj != b && m.label[bj] == 1 &&
+					(bestedgeto[bj] == -1 || m.slack(ek) < m.slack(bestedgeto[bj])) {
+					bestedgeto[bj] = ek
+				}
+			}
+		}
+		m.blossombestedges[child] = nil
+		m.bestedge[child] = -1
+	}
+
+	var best []int
+	for _, ek := range bestedgeto {
+		if ek != -1 {
+			best = append(best, ek)
+		}
+	}
+	// An empty but non-nil list means "no best edges", unlike nil that means "not computed"
+	if best == nil {
+		best = []int{}
+	}
+	m.blossombestedges[b] = best
+	m.bestedge[b] = -1
+	for _, ek := range best {
+		if m.bestedge[b] == -1 || m.slack(ek) < m.slack(m.bestedge[b]) {
+			m.bestedge[b] = ek
+		}
+	}
+}
+
+// expandBlossom expands the top-level blossom b
+func (m *weightedMatching) expandBlossom(b int, endstage bool) {
+	for _, s := range m.blossomchilds[b] {
+		m.blossomparent[s] = -1
+		if s < m.nvertex {
+			m.inblossom[s] = s
+		} else if endstage && m.dualvar[s] == 0 {
+			m.expandBlossom(s, endstage)
+		} else {
+			for _, leaf := range m.blossomLeaves(s) {
+				m.inblossom[leaf] = s
+			}
+		}
+	}
+
+	if !endstage && m.label[b] == 2 {
+		childs := m.blossomchilds[b]
+		endps := m.blossomendps[b]
+		entrychild := m.inblossom[m.endpoint[m.labelend[b]^1]]
+		j := indexOfInt(childs, entrychild)
+		var jstep, endptrick int
+		if j&1 != 0 {
+			j -= len(childs)
+			jstep = 1
+			endptrick = 0
+		} else {
+			jstep = -1
+			endptrick = 1
+		}
+
+		p := m.labelend[b]
+		for j != 0 {
+			m.label[m.endpoint[p^1]] = 0
+			m.label[m.endpoint[circularAt(endps, j-endptrick)^endptrick^1]] = 0
+			m.assignLabel(m.endpoint[p^1], 2, p)
+			m.allowedge[circularAt(endps, j-endptrick)/2] = true
+			j += jstep
+			p = circularAt(endps, j-endptrick) ^ endptrick
+			m.allowedge[p/2] = true
+			j += jstep
+		}
+
+		bv := circularAt(childs, j)
+		m.label[m.endpoint[p^1]], m.label[bv] = 2, 2
+		m.labelend[m.endpoint[p^1]], m.labelend[bv] = p, p
+		m.bestedge[bv] = -1
+		j += jstep
+		for circularAt(childs, j) != entrychild {
+			bv = circularAt(childs, j)
+			if m.label[bv] == 1 {
+				j += jstep
+				continue
+			}
+			leaves := m.blossomLeaves(bv)
+			v := leaves[len(leaves)-1]
+			for _, leaf := range leaves {
+				if m.label[leaf] != 0 {
+					v = leaf
+					break
+				}
+			}
+			if m.label[v] != 0 {
+				m.label[v] = 0
+				m.label[m.endpoint[m.mate[m.blossombase[bv]]]] = 0
+				m.assignLabel(v, 2, m.labelend[v])
+			}
+			j += jstep
+		}
+	}
+
+	m.label[b], m.labelend[b] = -1, -1
+	m.blossomchilds[b], m.blossomendps[b] = nil, nil
+	m.blossombase[b] = -1
+	m.blossombestedges[b] = nil
+	m.bestedge[b] = -1
+	m.unusedblossoms = append(m.unusedblossoms, b)
+}
+
+// augmentBlossom swaps matched and unmatched edges along the even path inside blossom b from vertex v to the base
+func (m *weightedMatching) augmentBlossom(b, v int) {
+	t := v
+	for m.blossomparent[t] != b {
+		t = m.blossomparent[t]
+	}
+	if t >= m.nvertex {
+		m.augmentBlossom(t, v)
+	}
+
+	childs := m.blossomchilds[b]
+	endps := m.blossomendps[b]
+	i := indexOfInt(childs, t)
+	j := i
+	var jstep, endptrick int
+	if i&1 != 0 {
+		j -= len(childs)
+		jstep = 1
+		endptrick = 0
+	} else {
+		jstep = -1
+		endptrick = 1
+	}
+
+	for j != 0 {
+		j += jstep
+		t = circularAt(childs, j)
+		p := circularAt(endps, j-endptrick) ^ endptrick
+		if t >= m.nvertex {
+			m.augmentBlossom(t, m.endpoint[p])
+		}
+		j += jstep
+		t = circularAt(childs, j)
+		if t >= m.nvertex {
+			m.augmentBlossom(t, m.endpoint[p^1])
+		}
+		m.mate[m.endpoint[p]] = p ^ 1
+		m.mate[m.endpoint[p^1]] = p
+	}
+
+	m.blossomchilds[b] = append(append([]int{}, childs[i:]...), childs[:i]...)
+	m.blossomendps[b] = append(append([]int{}, endps[i:]...), endps[:i]...)
+	m.blossombase[b] = m.blossombase[m.blossomchilds[b][0]]
+}
+
+// augmentMatching swaps matched and unmatched edges along the augmenting path through edge k
+func (m *weightedMatching) augmentMatching(k int) {
+	v, w := m.edges[k].U, m.edges[k].V
+	for _, start := range [][2]int{{v, 2*k + 1}, {w, 2 * k}} {
+		s, p := start[0], start[1]
+		for {
+			bs := m.inblossom[s]
+			if bs >= m.nvertex {
+				m.augmentBlossom(bs, s)
+			}
+			m.mate[s] = p
+			if m.labelend[bs] == -1 {
+				break
+			}
+			t := m.endpoint[m.labelend[bs]]
+			bt := m.inblossom[t]
+			s = m.endpoint[m.labelend[bt]]
+			j := m.endpoint[m.labelend[bt]^1]
+			if bt >= m.nvertex {
+				m.augmentBlossom(bt, j)
+			}
+			m.mate[j] = m.labelend[bt]
+			p = m.labelend[bt] ^ 1
+		}
+	}
+}
+
+func (m *weightedMatching) solve() {
+	for stage := 0; stage < m.nvertex; stage++ {
+		for i := range m.label {
+			m.label[i] = 0
+			m.bestedge[i] = -1
+		}
+		for b := m.nvertex; b < 2*m.nvertex; b++ {
+			m.blossombestedges[b] = nil
+		}
+		for k := range m.allowedge {
+			m.allowedge[k] = false
+		}
+		m.queue = m.queue[:0]
+
+		for v := 0; v < m.nvertex; v++ {
+			if m.mate[v] == -1 && m.label[m.inblossom[v]] == 0 {
+				m.assignLabel(v, 1, -1)
+			}
+		}
+
+		augmented := false
+		for {
+			for len(m.queue) > 0 && !augmented {
+				v := m.queue[len(m.queue)-1]
+				m.queue = m.queue[:len(m.queue)-1]
+
+				for _, p := range m.neighbend[v] {
+					k := p / 2
+					w := m.endpoint[p]
+					if m.inblossom[v] == m.inblossom[w] {
+						continue
+					}
+					var kslack int64
+					if !m.allowedge[k] {
+						kslack = m.slack(k)
+						if kslack <= 0 {
+							m.allowedge[k] = true
+						}
+					}
+					if m.allowedge[k] {
+						if m.label[m.inblossom[w]] == 0 {
+							m.assignLabel(w, 2, p^1)
+						} else if m.label[m.inblossom[w]] == 1 {
+							base := m.scanBlossom(v, w)
+							if base >= 0 {
+								m.addBlossom(base, k)
+							} else {
+								m.augmentMatching(k)
+								augmented = true
+								break
+							}
+						} else if m.label[w] == 0 {
+							m.label[w] = 2
+							m.labelend[w] = p ^ 1
+						}
+					} else if m.label[m.inblossom[w]] == 1 {
+						b := m.inblossom[v]
+						if m.bestedge[b] == -1 || kslack < m.slack(m.bestedge[b]) {
+							m.bestedge[b] = k
+						}
+					} else if m.label[w] == 0 {
+						if m.bestedge[w] == -1 || kslack < m.slack(m.bestedge[w]) {
+							m.bestedge[w] = k
+						}
+					}
+				}
+			}
+			if augmented {
+				break
+			}
+
+			// No augmenting path found, compute the dual update
+			deltatype := -1
+			var delta int64
+			deltaedge, deltablossom := -1, -1
+
+			if !m.maxCardinality {
+				deltatype = 1
+				delta = m.minVertexDual()
+			}
+			for v := 0; v < m.nvertex; v++ {
+				if m.label[m.inblossom[v]] == 0 && m.bestedge[v] != -1 {
+					d := m.slack(m.bestedge[v])
+					if deltatype == -1 || d < delta {
+						delta = d
+						deltatype = 2
+						deltaedge = m.bestedge[v]
+					}
+				}
+			}
+			for b := 0; b < 2*m.nvertex; b++ {
+				if m.blossomparent[b] == -1 && m.label[b] == 1 && m.bestedge[b] != -1 {
+					d := m.slack(m.bestedge[b]) / 2
+					if deltatype == -1 || d < delta {
+						delta = d
+						deltatype = 3
+						deltaedge = m.bestedge[b]
+					}
+				}
+			}
+			for b := m.nvertex; b < 2*m.nvertex; b++ {
+				if m.blossombase[b] >= 0 && m.blossomparent[b] == -1 && m.label[b] == 2 &&
+					(deltatype == -1 || m.dualvar[b] < delta) {
+					delta = m.dualvar[b]
+					deltatype = 4
+					deltablossom = b
+				}
+			}
+			if deltatype == -1 {
+				// No further improvement possible with max cardinality, do a final delta update
+				deltatype = 1
+				delta = m.minVertexDual()
+				if delta < 0 {
+					delta = 0
+				}
+			}
+
+			for v := 0; v < m.nvertex; v++ {
+				switch m.label[m.inblossom[v]] {
+				case 1:
+					m.dualvar[v] -= delta
+				case 2:
+					m.dualvar[v] += delta
+				}
+			}
+			for b := m.nvertex; b < 2*m.nvertex; b++ {
+				if m.blossombase[b] >= 0 && m.blossomparent[b] == -1 {
+					switch m.label[b] {
+					case 1:
+						m.dualvar[b] += delta
+					case 2:
+						m.dualvar[b] -= delta
+					}
+				}
+			}
+
+			if deltatype == 1 {
+				break
+			} else if deltatype == 2 {
+				m.allowedge[deltaedge] = true
+				i, j := m.edges[deltaedge].U, m.edges[deltaedge].V
+				if m.label[m.inblossom[i]] == 0 {
+					i, j = j, i
+				}
+				_ = j
+				m.queue = append(m.queue, i)
+			} else if deltatype == 3 {
+				m.allowedge[deltaedge] = true
+				m.queue = append(m.queue, m.edges[deltaedge].U)
+			} else if deltatype == 4 {
+				m.expandBlossom(deltablossom, false)
+			}
+		}
+
+		if !augmented {
+			break
+		}
+
+		// Expand all S-blossoms with zero dual at the end of the stage
+		for b := m.nvertex; b < 2*m.nvertex; b++ {
+			if m.blossomparent[b] == -1 && m.blossombase[b] >= 0 && m.label[b] == 1 && m.dualvar[b] == 0 {
+				m.expandBlossom(b, true)
+			}
+		}
+	}
+}
+
+func (m *weightedMatching) minVertexDual() int64 {
+	minDual := m.dualvar[0]
+	for v := 1; v < m.nvertex; v++ {
+		if m.dualvar[v] < minDual {
+			minDual = m.dualvar[v]
+		}
+	}
+	return minDual
+}
+
+// circularAt indexes the slice like Python does, negative indexes count from the end
+func circularAt(s []int, i int) int {
+	if i < 0 {
+		i += len(s)
+	}
+	return s[i]
+}
+
+func indexOfInt(s []int, value int) int {
+	for i, v := range s {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bruteForceMinCostMatching returns the cardinality and cost of the best matching by trying all matchings
+func bruteForceMinCostMatching(costs [][]float64) (int, float64) {
+	n := len(costs)
+	bestCardinality, bestCost := -1, math.Inf(1)
+	used := make([]bool, n)
+
+	var search func(cardinality int, cost float64)
+	search = func(cardinality int, cost float64) {
+		i := 0
+		for i < n && used[i] {
+			i++
+		}
+		if i == n {
+			if cardinality > bestCardinality || (cardinality == bestCardinality && cost < bestCost) {
+				bestCardinality, bestCost = cardinality, cost
+			}
+			return
+		}
+
+		used[i] = true
+		search(cardinality, cost)
+		for j := i + 1; j < n; j++ {
+			if !used[j] && !math.IsInf(costs[i][j], 1) {
+				used[j] = true
+				search(cardinality+1, cost+costs[i][j])
+				used[j] = false
+			}
+		}
+		used[i] = false
+	}
+
+	search(0, 0)
+	return bestCardinality, bestCost
+}
+
+func TestMinCostMatchingAgainstBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	for iteration := 0; iteration < 2000; iteration++ {
+		n := 1 + r.Intn(10)
+		costs := make([][]float64, n)
+		for i := range costs {
+			costs[i] = make([]float64, n)
+		}
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				cost := float64(r.Intn(20))
+				if r.Intn(5) == 0 {
+					cost = math.Inf(1)
+				}
+				costs[i][j], costs[j][i] = cost, cost
+			}
+		}
+
+		mate := MinCostMatching(costs)
+		assert.Len(t, mate, n)
+
+		cardinality, cost := 0, 0.0
+		for v, w := range mate {
+			if w == -1 {
+				continue
+			}
+			if !assert.Equal(t, v, mate[w], "matching must be symmetric") {
+				return
+			}
+			assert.False(t, math.IsInf(costs[v][w], 1), "forbidden pair must not be matched")
+			if v < w {
+				cardinality++
+				cost += costs[v][w]
+			}
+		}
+
+		expectedCardinality, expectedCost := bruteForceMinCostMatching(costs)
+		if !assert.Equal(t, expectedCardinality, cardinality, "iteration %d, costs %v", iteration, costs) ||
+			!assert.Equal(t, expectedCost, cost, "iteration %d, costs %v", iteration, costs) {
+			return
+		}
+	}
+}
+
+func TestMinCostMatchingPrefersCheaperPerfectMatching(t *testing.T) {
+	// Greedy would take the cheapest pair 0-1 and be forced into the expensive pair 2-3
+	costs := [][]float64{
+		{0, 1, 2, 100},
+		{1, 0, 100, 2},
+		{2, 100, 0, 100},
+		{100, 2, 100, 0},
+	}
+
+	assert.Equal(t, []int{2, 3, 0, 1}, MinCostMatching(costs))
+}
+
+func TestMaxWeightMatching(t *testing.T) {
+	tests := []struct {
+		name           string
+		edges          []MatchingEdge
+		maxCardinality bool
+		expected       []int
+	}{
+		{
+			name:     "No edges",
+			edges:    nil,
+			expected: nil,
+		},
+		{
+			name:     "Single edge",
+			edges:    []MatchingEdge{{U: 0, V: 1, Weight: 1}},
+			expected: []int{1, 0},
+		},
+		{
+			name:     "Heavier middle edge",
+			edges:    []MatchingEdge{{U: 1, V: 2, Weight: 10}, {U: 2, V: 3, Weight: 11}},
+			expected: []int{-1, -1, 3, 2},
+		},
+		{
+			name:           "Max cardinality over weight",
+			edges:          []MatchingEdge{{U: 0, V: 1, Weight: 2}, {U: 1, V: 2, Weight: 5}, {U: 2, V: 3, Weight: 2}},
+			maxCardinality: true,
+			expected:       []int{1, 0, 3, 2},
+		},
+		{
+			name: "Blossom with augmenting path",
+			edges: []MatchingEdge{
+				{U: 1, V: 2, Weight: 8}, {U: 1, V: 3, Weight: 9}, {U: 2, V: 3, Weight: 10}, {U: 3, V: 4, Weight: 7},
+			},
+			expected: []int{-1, 2, 1, 4, 3},
+		},
+		{
+			name: "Nested blossoms",
+			edges: []MatchingEdge{
+				{U: 1, V: 2, Weight: 9}, {U: 1, V: 3, Weight: 9}, {U: 2, V: 3, Weight: 10}, {U: 2, V: 4, Weight: 8},
+				{U: 3, V: 5, Weight: 8}, {U: 4, V: 5, Weight: 10}, {U: 5, V: 6, Weight: 6},
+			},
+			expected: []int{-1, 3, 4, 1, 2, 6, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, MaxWeightMatching(tt.edges, tt.maxCardinality))
+		})
+	}
+}